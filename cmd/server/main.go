@@ -12,6 +12,7 @@ import (
 
 	"github.com/RubenPari/clear-songs/internal/domain/shared/utils"
 	"github.com/RubenPari/clear-songs/internal/infrastructure/di"
+	"github.com/RubenPari/clear-songs/internal/infrastructure/persistence"
 	"github.com/RubenPari/clear-songs/internal/infrastructure/persistence/postgres"
 	httptransport "github.com/RubenPari/clear-songs/internal/infrastructure/transport/http"
 	"github.com/gin-contrib/cors"
@@ -19,6 +20,11 @@ import (
 )
 
 func main() {
+	// "clear-songs migrate ..." manages the database schema instead of serving
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(os.Args[2:]))
+	}
+
 	// Initialize environment and DI
 	utils.LoadEnvVariables()
 
@@ -31,8 +37,8 @@ func main() {
 
 	// Initialize Database with Pooling
 	log.Println("Initializing database...")
-	if errConnectDb := postgres.Init(); errConnectDb != nil {
-		log.Printf("WARNING: Database initialization failed: %v", errConnectDb)
+	if errConnectDb := persistence.Init(); errConnectDb != nil {
+		log.Fatalf("Database initialization failed: %v", errConnectDb)
 	}
 
 	log.Println("Initializing DI container...")
@@ -41,6 +47,15 @@ func main() {
 		log.Fatalf("Failed to initialize DI container: %v", err)
 	}
 
+	// Start background job workers
+	container.JobManager.Start(context.Background())
+
+	// Start the cleanup rule scheduler
+	container.RuleScheduler.Start(context.Background())
+
+	// Start the periodic library snapshots
+	container.LibrarySnapshotScheduler.Start(context.Background())
+
 	// Setup Gin Router
 	log.Println("Setting up router...")
 	router := gin.Default()
@@ -98,6 +113,20 @@ func main() {
 		log.Fatal("Server forced to shutdown:", err)
 	}
 
+	// Stop scheduling rule runs before the job workers go away
+	if err := container.RuleScheduler.Stop(ctx); err != nil {
+		log.Printf("WARNING: Rule scheduler did not stop in time: %v", err)
+	}
+
+	if err := container.LibrarySnapshotScheduler.Stop(ctx); err != nil {
+		log.Printf("WARNING: Library snapshot scheduler did not stop in time: %v", err)
+	}
+
+	// Stop background jobs; unfinished ones are marked interrupted
+	if err := container.JobManager.Shutdown(ctx); err != nil {
+		log.Printf("WARNING: Background jobs did not stop in time: %v", err)
+	}
+
 	// Properly close database connections
 	if postgres.Db != nil {
 		sqlDB, err := postgres.Db.DB()
@@ -0,0 +1,86 @@
+package album
+
+import (
+	"context"
+
+	appOperation "github.com/RubenPari/clear-songs/internal/application/operation"
+	"github.com/RubenPari/clear-songs/internal/domain/job"
+	domainOperation "github.com/RubenPari/clear-songs/internal/domain/operation"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+// ConvertAlbumUseCase handles the business logic for converting a saved album into saved tracks
+type ConvertAlbumUseCase struct {
+	spotifyRepo shared.SpotifyRepository
+	cacheRepo   shared.CacheRepository
+	recorder    *appOperation.Recorder
+}
+
+// NewConvertAlbumUseCase creates a new ConvertAlbumUseCase
+func NewConvertAlbumUseCase(
+	spotifyRepo shared.SpotifyRepository,
+	cacheRepo shared.CacheRepository,
+	recorder *appOperation.Recorder,
+) *ConvertAlbumUseCase {
+	return &ConvertAlbumUseCase{
+		spotifyRepo: spotifyRepo,
+		cacheRepo:   cacheRepo,
+		recorder:    recorder,
+	}
+}
+
+// Execute saves every track of an album to the user library and, if removeAlbum is set,
+// removes the album from the saved albums. The removal is recorded as an operation, so
+// undoing it saves the album again. No track is removed, so nothing is backed up.
+func (uc *ConvertAlbumUseCase) Execute(ctx context.Context, albumID spotifyAPI.ID, removeAlbum bool) (*ConvertResult, error) {
+	// 1. Get album details
+	album, err := uc.spotifyRepo.GetAlbum(ctx, albumID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Get all album tracks
+	tracks, err := uc.spotifyRepo.GetAlbumTracks(ctx, albumID)
+	if err != nil {
+		return nil, err
+	}
+
+	trackIDs := make([]spotifyAPI.ID, 0, len(tracks))
+	result := &ConvertResult{
+		AlbumID:   albumID.String(),
+		AlbumName: album.Name,
+		TrackIDs:  make([]string, 0, len(tracks)),
+	}
+	for _, track := range tracks {
+		trackIDs = append(trackIDs, track.ID)
+		result.TrackIDs = append(result.TrackIDs, track.ID.String())
+	}
+	job.ReportTotal(ctx, len(trackIDs))
+
+	// 3. Save tracks to library
+	if err := uc.spotifyRepo.AddTracksToLibrary(ctx, trackIDs); err != nil {
+		return nil, err
+	}
+	result.TracksSaved = len(trackIDs)
+
+	// 4. Remove the album and record the removal
+	if removeAlbum {
+		if err := uc.spotifyRepo.RemoveAlbumsFromLibrary(ctx, []spotifyAPI.ID{albumID}); err != nil {
+			return nil, err
+		}
+		result.AlbumRemoved = true
+
+		uc.recorder.Record(ctx, &domainOperation.Operation{
+			Kind:   domainOperation.KindConvertAlbum,
+			Params: map[string]string{domainOperation.ParamAlbumID: albumID.String()},
+		})
+	}
+
+	// 5. Invalidate cache
+	if uc.cacheRepo != nil {
+		_ = uc.cacheRepo.InvalidateUserTracks(ctx)
+	}
+
+	return result, nil
+}
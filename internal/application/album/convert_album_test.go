@@ -0,0 +1,107 @@
+package album
+
+import (
+	"context"
+	"testing"
+
+	appOperation "github.com/RubenPari/clear-songs/internal/application/operation"
+	domainOperation "github.com/RubenPari/clear-songs/internal/domain/operation"
+	"github.com/RubenPari/clear-songs/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+func TestConvertAlbumUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	albumID := spotifyAPI.ID("album_1")
+	album := &spotifyAPI.FullAlbum{
+		SimpleAlbum: spotifyAPI.SimpleAlbum{
+			ID:      albumID,
+			Name:    "A Night at the Opera",
+			Artists: []spotifyAPI.SimpleArtist{{ID: "artist_1", Name: "Queen"}},
+		},
+	}
+	tracks := []spotifyAPI.SimpleTrack{
+		{ID: "track_1", Name: "Death on Two Legs", Artists: []spotifyAPI.SimpleArtist{{Name: "Queen"}}},
+		{ID: "track_2", Name: "Lazing on a Sunday Afternoon", Artists: []spotifyAPI.SimpleArtist{{Name: "Queen"}}},
+	}
+
+	t.Run("Success - should save tracks and keep the album", func(t *testing.T) {
+		mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+		mockCacheRepo := new(mocks.MockCacheRepository)
+		mockOperationRepo := new(mocks.MockOperationRepository)
+		useCase := NewConvertAlbumUseCase(mockSpotifyRepo, mockCacheRepo, appOperation.NewRecorder(mockOperationRepo))
+
+		mockSpotifyRepo.On("GetAlbum", mock.Anything, albumID).Return(album, nil)
+		mockSpotifyRepo.On("GetAlbumTracks", mock.Anything, albumID).Return(tracks, nil)
+		mockSpotifyRepo.On("AddTracksToLibrary", mock.Anything, []spotifyAPI.ID{"track_1", "track_2"}).Return(nil)
+		mockCacheRepo.On("InvalidateUserTracks", mock.Anything).Return(nil)
+
+		result, err := useCase.Execute(ctx, albumID, false)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.TracksSaved)
+		assert.False(t, result.AlbumRemoved)
+		mockSpotifyRepo.AssertNotCalled(t, "RemoveAlbumsFromLibrary", mock.Anything, mock.Anything)
+		mockOperationRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success - should record the album removal so it can be undone", func(t *testing.T) {
+		mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+		mockCacheRepo := new(mocks.MockCacheRepository)
+		mockOperationRepo := new(mocks.MockOperationRepository)
+		useCase := NewConvertAlbumUseCase(mockSpotifyRepo, mockCacheRepo, appOperation.NewRecorder(mockOperationRepo))
+
+		mockSpotifyRepo.On("GetAlbum", mock.Anything, albumID).Return(album, nil)
+		mockSpotifyRepo.On("GetAlbumTracks", mock.Anything, albumID).Return(tracks, nil)
+		mockSpotifyRepo.On("AddTracksToLibrary", mock.Anything, mock.Anything).Return(nil)
+		mockSpotifyRepo.On("RemoveAlbumsFromLibrary", mock.Anything, []spotifyAPI.ID{albumID}).Return(nil)
+		mockOperationRepo.On("Create", mock.Anything, mock.MatchedBy(func(op *domainOperation.Operation) bool {
+			return op.Kind == domainOperation.KindConvertAlbum &&
+				op.RemovedAlbumID() == albumID.String() &&
+				len(op.LibraryTrackIDs) == 0
+		})).Return(nil)
+		mockCacheRepo.On("InvalidateUserTracks", mock.Anything).Return(nil)
+
+		result, err := useCase.Execute(ctx, albumID, true)
+
+		assert.NoError(t, err)
+		assert.True(t, result.AlbumRemoved)
+		mockOperationRepo.AssertExpectations(t)
+		mockSpotifyRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - failing to remove the album should record nothing", func(t *testing.T) {
+		mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+		mockCacheRepo := new(mocks.MockCacheRepository)
+		mockOperationRepo := new(mocks.MockOperationRepository)
+		useCase := NewConvertAlbumUseCase(mockSpotifyRepo, mockCacheRepo, appOperation.NewRecorder(mockOperationRepo))
+
+		mockSpotifyRepo.On("GetAlbum", mock.Anything, albumID).Return(album, nil)
+		mockSpotifyRepo.On("GetAlbumTracks", mock.Anything, albumID).Return(tracks, nil)
+		mockSpotifyRepo.On("AddTracksToLibrary", mock.Anything, mock.Anything).Return(nil)
+		mockSpotifyRepo.On("RemoveAlbumsFromLibrary", mock.Anything, mock.Anything).Return(assert.AnError)
+
+		_, err := useCase.Execute(ctx, albumID, true)
+
+		assert.ErrorIs(t, err, assert.AnError)
+		mockOperationRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - failing to save tracks should keep the album", func(t *testing.T) {
+		mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+		mockCacheRepo := new(mocks.MockCacheRepository)
+		mockOperationRepo := new(mocks.MockOperationRepository)
+		useCase := NewConvertAlbumUseCase(mockSpotifyRepo, mockCacheRepo, appOperation.NewRecorder(mockOperationRepo))
+
+		mockSpotifyRepo.On("GetAlbum", mock.Anything, albumID).Return(album, nil)
+		mockSpotifyRepo.On("GetAlbumTracks", mock.Anything, albumID).Return(tracks, nil)
+		mockSpotifyRepo.On("AddTracksToLibrary", mock.Anything, mock.Anything).Return(assert.AnError)
+
+		_, err := useCase.Execute(ctx, albumID, true)
+
+		assert.Error(t, err)
+		mockSpotifyRepo.AssertNotCalled(t, "RemoveAlbumsFromLibrary", mock.Anything, mock.Anything)
+	})
+}
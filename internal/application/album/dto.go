@@ -0,0 +1,26 @@
+package album
+
+// ConvertRequest is used for validating the query parameters of an album conversion
+type ConvertRequest struct {
+	ID          string `form:"id_album" binding:"required"`
+	RemoveAlbum bool   `form:"remove_album"`
+}
+
+// AlbumResponse represents a saved album in API responses
+type AlbumResponse struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Artists     []string `json:"artists"`
+	TotalTracks int      `json:"total_tracks"`
+	ImageURL    string   `json:"image_url,omitempty"`
+	AddedAt     string   `json:"added_at"`
+}
+
+// ConvertResult summarises an album conversion
+type ConvertResult struct {
+	AlbumID      string   `json:"album_id"`
+	AlbumName    string   `json:"album_name"`
+	TracksSaved  int      `json:"tracks_saved"`
+	TrackIDs     []string `json:"track_ids"`
+	AlbumRemoved bool     `json:"album_removed"`
+}
@@ -0,0 +1,25 @@
+package album
+
+import (
+	"context"
+
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+// GetUserAlbumsUseCase handles the business logic for getting the user's saved albums
+type GetUserAlbumsUseCase struct {
+	spotifyRepo shared.SpotifyRepository
+}
+
+// NewGetUserAlbumsUseCase creates a new GetUserAlbumsUseCase
+func NewGetUserAlbumsUseCase(spotifyRepo shared.SpotifyRepository) *GetUserAlbumsUseCase {
+	return &GetUserAlbumsUseCase{
+		spotifyRepo: spotifyRepo,
+	}
+}
+
+// Execute retrieves all albums saved by the user
+func (uc *GetUserAlbumsUseCase) Execute(ctx context.Context) ([]spotifyAPI.SavedAlbum, error) {
+	return uc.spotifyRepo.GetAllUserAlbums(ctx)
+}
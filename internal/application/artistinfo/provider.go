@@ -0,0 +1,87 @@
+package artistinfo
+
+import (
+	"context"
+	"time"
+
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+// CacheTTL is how long artist metadata (images, genres, popularity) is served from
+// cache. It changes rarely, so it outlives the per-session caches by far.
+const CacheTTL = 24 * time.Hour
+
+// Provider serves artist metadata to the read use cases. Artists are read from the
+// shared artist cache and only the missing ones are fetched, in batches.
+type Provider struct {
+	spotifyRepo shared.SpotifyRepository
+	cacheRepo   shared.CacheRepository
+}
+
+// NewProvider creates a new artist metadata provider
+func NewProvider(spotifyRepo shared.SpotifyRepository, cacheRepo shared.CacheRepository) *Provider {
+	return &Provider{
+		spotifyRepo: spotifyRepo,
+		cacheRepo:   cacheRepo,
+	}
+}
+
+// GetArtists returns the artists among ids keyed by ID. Artists unknown to Spotify are
+// left out of the result.
+func (p *Provider) GetArtists(ctx context.Context, ids []spotifyAPI.ID) (map[spotifyAPI.ID]spotifyAPI.FullArtist, error) {
+	ids = unique(ids)
+	artists := make(map[spotifyAPI.ID]spotifyAPI.FullArtist, len(ids))
+	if len(ids) == 0 {
+		return artists, nil
+	}
+
+	// 1. Read what the cache already holds (if available)
+	if p.cacheRepo != nil {
+		if cached, err := p.cacheRepo.GetArtists(ctx, ids); err == nil {
+			for id, artist := range cached {
+				artists[id] = artist
+			}
+		}
+	}
+
+	var missing []spotifyAPI.ID
+	for _, id := range ids {
+		if _, found := artists[id]; !found {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return artists, nil
+	}
+
+	// 2. Fetch the others in batches
+	fetched, err := p.spotifyRepo.GetArtists(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	for _, artist := range fetched {
+		artists[artist.ID] = artist
+	}
+
+	// 3. Cache them for every session (if cache is available)
+	if p.cacheRepo != nil {
+		_ = p.cacheRepo.SetArtists(ctx, fetched, CacheTTL)
+	}
+
+	return artists, nil
+}
+
+// unique drops empty and repeated IDs, keeping the first occurrence
+func unique(ids []spotifyAPI.ID) []spotifyAPI.ID {
+	seen := make(map[spotifyAPI.ID]bool, len(ids))
+	var result []spotifyAPI.ID
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		result = append(result, id)
+	}
+	return result
+}
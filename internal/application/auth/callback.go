@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"log"
 	"os"
 
 	domainAuth "github.com/RubenPari/clear-songs/internal/domain/auth"
@@ -11,50 +12,47 @@ import (
 
 // CallbackUseCase handles the business logic for OAuth callback
 type CallbackUseCase struct {
-	oauthConfig *oauth2.Config
-	spotifyRepo shared.SpotifyRepository
-	cacheRepo   shared.CacheRepository
-	userRepo    domainAuth.UserRepository
+	oauthConfig    *oauth2.Config
+	spotifyFactory shared.SpotifyRepositoryFactory
+	cacheRepo      shared.CacheRepository
+	userRepo       domainAuth.UserRepository
+	credentialRepo domainAuth.CredentialRepository
 }
 
 // NewCallbackUseCase creates a new CallbackUseCase
 func NewCallbackUseCase(
 	oauthConfig *oauth2.Config,
-	spotifyRepo shared.SpotifyRepository,
+	spotifyFactory shared.SpotifyRepositoryFactory,
 	cacheRepo shared.CacheRepository,
 	userRepo domainAuth.UserRepository,
+	credentialRepo domainAuth.CredentialRepository,
 ) *CallbackUseCase {
 	return &CallbackUseCase{
-		oauthConfig: oauthConfig,
-		spotifyRepo: spotifyRepo,
-		cacheRepo:   cacheRepo,
-		userRepo:    userRepo,
+		oauthConfig:    oauthConfig,
+		spotifyFactory: spotifyFactory,
+		cacheRepo:      cacheRepo,
+		userRepo:       userRepo,
+		credentialRepo: credentialRepo,
 	}
 }
 
-// Execute processes the OAuth callback and returns the frontend redirect URL
-func (uc *CallbackUseCase) Execute(ctx context.Context, code string, localUserID string) (string, error) {
+// Execute processes the OAuth callback for the given session and returns the frontend redirect URL
+func (uc *CallbackUseCase) Execute(ctx context.Context, code string, sessionID string, localUserID string) (string, error) {
 	// 1. Exchange code for token
 	token, err := uc.oauthConfig.Exchange(ctx, code)
 	if err != nil {
 		return "", err
 	}
 
-	// 2. Save token to cache
-	if uc.cacheRepo != nil {
-		if err := uc.cacheRepo.SetToken(ctx, token); err != nil {
-			// Log error but continue
-		}
-	}
-
-	// 3. Set token in Spotify repository
-	if err := uc.spotifyRepo.SetAccessToken(token); err != nil {
+	// 2. Verify authentication with a client bound to this token only
+	spotifyRepo := uc.spotifyFactory.NewRepository(token)
+	spotifyUser, err := spotifyRepo.GetCurrentUser(ctx)
+	if err != nil {
 		return "", err
 	}
 
-	// 4. Verify authentication by getting current user
-	spotifyUser, err := uc.spotifyRepo.GetCurrentUser(ctx)
-	if err != nil {
+	// 3. Save token to cache, keyed by session
+	if err := uc.cacheRepo.SetToken(ctx, sessionID, token); err != nil {
 		return "", err
 	}
 
@@ -67,7 +65,19 @@ func (uc *CallbackUseCase) Execute(ctx context.Context, code string, localUserID
 		}
 	}
 
-	// 5. Get frontend URL
+	// Keep the refresh token beyond the session, so scheduled runs can act for the user
+	owner := shared.SpotifyUserOwner(spotifyUser.ID)
+	if localUserID != "" {
+		owner = shared.LocalUserOwner(localUserID)
+	}
+	if token.RefreshToken != "" && uc.credentialRepo != nil {
+		credential := &domainAuth.SpotifyCredential{Owner: owner, RefreshToken: token.RefreshToken}
+		if err := uc.credentialRepo.Save(ctx, credential); err != nil {
+			log.Printf("WARNING: Failed to store Spotify credential, scheduled runs will not work: %v", err)
+		}
+	}
+
+	// 4. Get frontend URL
 	frontendURL := os.Getenv("FRONTEND_URL")
 	if frontendURL == "" {
 		frontendURL = "http://localhost:4200"
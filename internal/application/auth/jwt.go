@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	domainAuth "github.com/RubenPari/clear-songs/internal/domain/auth"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TokenTTL is how long a JWT issued at login stays valid
+const TokenTTL = 7 * 24 * time.Hour
+
+// jwtSecret returns the key signing and verifying local user JWTs
+func jwtSecret() []byte {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		secret = "fallback-secret-for-dev"
+	}
+	return []byte(secret)
+}
+
+// IssueToken returns a signed JWT identifying user by its `sub` claim
+func IssueToken(user *domainAuth.User) (string, error) {
+	claims := jwt.MapClaims{
+		"sub":   user.ID,
+		"email": user.Email,
+		"exp":   time.Now().Add(TokenTTL).Unix(),
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(jwtSecret())
+}
+
+// ParseToken verifies a JWT issued by IssueToken and returns its claims
+func ParseToken(tokenString string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return jwtSecret(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
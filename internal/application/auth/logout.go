@@ -8,30 +8,29 @@ import (
 
 // LogoutUseCase handles the business logic for user logout
 type LogoutUseCase struct {
-	spotifyRepo shared.SpotifyRepository
-	cacheRepo   shared.CacheRepository
+	cacheRepo shared.CacheRepository
 }
 
 // NewLogoutUseCase creates a new LogoutUseCase
-func NewLogoutUseCase(
-	spotifyRepo shared.SpotifyRepository,
-	cacheRepo shared.CacheRepository,
-) *LogoutUseCase {
+func NewLogoutUseCase(cacheRepo shared.CacheRepository) *LogoutUseCase {
 	return &LogoutUseCase{
-		spotifyRepo: spotifyRepo,
-		cacheRepo:   cacheRepo,
+		cacheRepo: cacheRepo,
 	}
 }
 
-// Execute logs out the user by clearing tokens
-func (uc *LogoutUseCase) Execute(ctx context.Context) error {
-	// Clear token from Spotify repository
-	_ = uc.spotifyRepo.SetAccessToken(nil)
+// Execute logs out the session by clearing its token and cached library
+func (uc *LogoutUseCase) Execute(ctx context.Context, sessionID string) error {
+	if uc.cacheRepo == nil || sessionID == "" {
+		return nil
+	}
 
-	// Clear token from cache
-	if uc.cacheRepo != nil {
-		_ = uc.cacheRepo.ClearToken(ctx)
+	// Clear token of this session only
+	if err := uc.cacheRepo.ClearToken(ctx, sessionID); err != nil {
+		return err
 	}
 
+	// Drop the cached library of the user
+	_ = uc.cacheRepo.InvalidateUserTracks(shared.WithSession(ctx, sessionID))
+
 	return nil
 }
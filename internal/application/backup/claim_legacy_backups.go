@@ -0,0 +1,87 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	domainAuth "github.com/RubenPari/clear-songs/internal/domain/auth"
+	"github.com/RubenPari/clear-songs/internal/domain/backup"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+)
+
+// ClaimLegacyBackupsUseCase hands the backups owned by backup.LegacyOwner to the user
+// claiming them. An admin may always claim them; any user may on a single-user
+// deployment, where every stored Spotify credential belongs to them.
+type ClaimLegacyBackupsUseCase struct {
+	databaseRepo   shared.DatabaseRepository
+	credentialRepo domainAuth.CredentialRepository
+	admins         map[string]bool
+}
+
+// NewClaimLegacyBackupsUseCase creates a new ClaimLegacyBackupsUseCase. admins are the
+// owners (see shared.WithOwner) allowed to claim legacy backups on any deployment.
+func NewClaimLegacyBackupsUseCase(
+	databaseRepo shared.DatabaseRepository,
+	credentialRepo domainAuth.CredentialRepository,
+	admins []string,
+) *ClaimLegacyBackupsUseCase {
+	uc := &ClaimLegacyBackupsUseCase{
+		databaseRepo:   databaseRepo,
+		credentialRepo: credentialRepo,
+		admins:         make(map[string]bool, len(admins)),
+	}
+	for _, admin := range admins {
+		uc.admins[admin] = true
+	}
+	return uc
+}
+
+// ParseAdmins parses a BACKUP_ADMINS value: a comma-separated list of owners
+func ParseAdmins(value string) []string {
+	var admins []string
+	for _, admin := range strings.Split(value, ",") {
+		if admin = strings.TrimSpace(admin); admin != "" {
+			admins = append(admins, admin)
+		}
+	}
+	return admins
+}
+
+// Execute moves the legacy backups to the owner of ctx
+func (uc *ClaimLegacyBackupsUseCase) Execute(ctx context.Context) (*ClaimLegacyResult, error) {
+	owner := shared.OwnerFromContext(ctx)
+	if owner == "" {
+		return nil, shared.ErrUnauthorized
+	}
+
+	if !uc.admins[owner] {
+		single, err := uc.isOnlyUser(ctx, owner)
+		if err != nil {
+			return nil, err
+		}
+		if !single {
+			return nil, fmt.Errorf("%w: legacy backups can only be claimed by a BACKUP_ADMINS owner when several users logged in", shared.ErrForbidden)
+		}
+	}
+
+	claimed, err := uc.databaseRepo.ReassignTracksBackup(ctx, backup.LegacyOwner, owner)
+	if err != nil {
+		return nil, err
+	}
+	return &ClaimLegacyResult{Claimed: claimed, Owner: owner}, nil
+}
+
+// isOnlyUser reports whether no other user than owner stored a Spotify credential
+func (uc *ClaimLegacyBackupsUseCase) isOnlyUser(ctx context.Context, owner string) (bool, error) {
+	owners, err := uc.credentialRepo.ListOwners(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, other := range owners {
+		if other != owner {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,67 @@
+package backup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/RubenPari/clear-songs/internal/domain/backup"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	"github.com/RubenPari/clear-songs/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestClaimLegacyBackupsUseCase_Execute(t *testing.T) {
+	ctx := shared.WithOwner(context.Background(), "spotify:user_1")
+
+	t.Run("Success - should hand legacy backups to the only user", func(t *testing.T) {
+		mockDatabaseRepo := new(mocks.MockDatabaseRepository)
+		mockCredentialRepo := new(mocks.MockCredentialRepository)
+		useCase := NewClaimLegacyBackupsUseCase(mockDatabaseRepo, mockCredentialRepo, nil)
+
+		mockCredentialRepo.On("ListOwners", mock.Anything).Return([]string{"spotify:user_1"}, nil)
+		mockDatabaseRepo.On("ReassignTracksBackup", mock.Anything, backup.LegacyOwner, "spotify:user_1").Return(3, nil)
+
+		result, err := useCase.Execute(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &ClaimLegacyResult{Claimed: 3, Owner: "spotify:user_1"}, result)
+		mockDatabaseRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - should let an admin claim legacy backups among several users", func(t *testing.T) {
+		mockDatabaseRepo := new(mocks.MockDatabaseRepository)
+		mockCredentialRepo := new(mocks.MockCredentialRepository)
+		useCase := NewClaimLegacyBackupsUseCase(mockDatabaseRepo, mockCredentialRepo, ParseAdmins(" user:7 , spotify:user_1 "))
+
+		mockDatabaseRepo.On("ReassignTracksBackup", mock.Anything, backup.LegacyOwner, "spotify:user_1").Return(2, nil)
+
+		result, err := useCase.Execute(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Claimed)
+		mockCredentialRepo.AssertNotCalled(t, "ListOwners", mock.Anything)
+	})
+
+	t.Run("Error - should refuse a user that is not alone nor an admin", func(t *testing.T) {
+		mockDatabaseRepo := new(mocks.MockDatabaseRepository)
+		mockCredentialRepo := new(mocks.MockCredentialRepository)
+		useCase := NewClaimLegacyBackupsUseCase(mockDatabaseRepo, mockCredentialRepo, []string{"user:7"})
+
+		mockCredentialRepo.On("ListOwners", mock.Anything).Return([]string{"spotify:user_1", "spotify:user_2"}, nil)
+
+		result, err := useCase.Execute(ctx)
+
+		assert.ErrorIs(t, err, shared.ErrForbidden)
+		assert.Nil(t, result)
+		mockDatabaseRepo.AssertNotCalled(t, "ReassignTracksBackup", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - should require an owner", func(t *testing.T) {
+		useCase := NewClaimLegacyBackupsUseCase(new(mocks.MockDatabaseRepository), new(mocks.MockCredentialRepository), nil)
+
+		_, err := useCase.Execute(context.Background())
+
+		assert.ErrorIs(t, err, shared.ErrUnauthorized)
+	})
+}
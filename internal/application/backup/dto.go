@@ -0,0 +1,67 @@
+package backup
+
+import (
+	"time"
+
+	"github.com/RubenPari/clear-songs/internal/domain/backup"
+)
+
+// ListRequest is used for validating the query parameters of the backup listing
+type ListRequest struct {
+	OperationID     string     `form:"operation_id"`
+	PlaylistID      string     `form:"playlist_id"`
+	Artist          string     `form:"artist"`
+	Album           string     `form:"album"`
+	DeletedFrom     *time.Time `form:"deleted_from" time_format:"2006-01-02"`
+	DeletedTo       *time.Time `form:"deleted_to" time_format:"2006-01-02"`
+	IncludeRestored bool       `form:"include_restored"`
+}
+
+// RestoreRequest is the body of a restore request: explicit backups, tracks or
+// operation, or all the backed-up tracks matching the optional filters
+type RestoreRequest struct {
+	BackupIDs   []string   `json:"backup_ids"`
+	TrackIDs    []string   `json:"track_ids"`
+	OperationID string     `json:"operation_id"`
+	All         bool       `json:"all"`
+	PlaylistID  string     `json:"playlist_id"`
+	Artist      string     `json:"artist"`
+	Album       string     `json:"album"`
+	DeletedFrom *time.Time `json:"deleted_from"`
+	DeletedTo   *time.Time `json:"deleted_to"`
+}
+
+// TrackBackupResponse represents a backed-up track in API responses
+type TrackBackupResponse struct {
+	BackupID    string          `json:"backup_id"`
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Artist      string          `json:"artist"`
+	Artists     []backup.Artist `json:"artists"`
+	Album       string          `json:"album"`
+	AlbumID     string          `json:"album_id,omitempty"`
+	ISRC        string          `json:"isrc,omitempty"`
+	DurationMs  int             `json:"duration_ms,omitempty"`
+	URI         string          `json:"uri"`
+	SpotifyURL  string          `json:"spotify_url,omitempty"`
+	OperationID string          `json:"operation_id,omitempty"`
+	Reason      string          `json:"reason,omitempty"`
+	PlaylistID  string          `json:"playlist_id,omitempty"`
+	Position    *int            `json:"position,omitempty"`
+	DeletedAt   time.Time       `json:"deleted_at"`
+	RestoredAt  *time.Time      `json:"restored_at,omitempty"`
+}
+
+// RestoreResult summarises a restore operation. Restored and TrackIDs count the tracks
+// re-saved to the library; tracks removed from a playlist are re-inserted into it.
+type RestoreResult struct {
+	Restored               int      `json:"restored"`
+	TrackIDs               []string `json:"track_ids"`
+	PlaylistTracksRestored int      `json:"playlist_tracks_restored"`
+}
+
+// ClaimLegacyResult summarises a claim of the legacy backups
+type ClaimLegacyResult struct {
+	Claimed int    `json:"claimed"`
+	Owner   string `json:"owner"`
+}
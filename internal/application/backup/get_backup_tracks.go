@@ -0,0 +1,26 @@
+package backup
+
+import (
+	"context"
+
+	"github.com/RubenPari/clear-songs/internal/domain/backup"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+)
+
+// GetBackupTracksUseCase handles the business logic for listing backed-up tracks
+type GetBackupTracksUseCase struct {
+	databaseRepo shared.DatabaseRepository
+}
+
+// NewGetBackupTracksUseCase creates a new GetBackupTracksUseCase
+func NewGetBackupTracksUseCase(databaseRepo shared.DatabaseRepository) *GetBackupTracksUseCase {
+	return &GetBackupTracksUseCase{
+		databaseRepo: databaseRepo,
+	}
+}
+
+// Execute retrieves the backed-up tracks of the owner matching the filter
+func (uc *GetBackupTracksUseCase) Execute(ctx context.Context, filter backup.Filter) ([]backup.TrackBackup, error) {
+	filter.Owner = shared.OwnerFromContext(ctx)
+	return uc.databaseRepo.GetTracksBackup(ctx, filter)
+}
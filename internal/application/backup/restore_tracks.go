@@ -0,0 +1,116 @@
+package backup
+
+import (
+	"context"
+	"math"
+
+	appOperation "github.com/RubenPari/clear-songs/internal/application/operation"
+	"github.com/RubenPari/clear-songs/internal/domain/backup"
+	"github.com/RubenPari/clear-songs/internal/domain/operation"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+// RestoreTracksUseCase handles the business logic for re-saving backed-up tracks to the library
+type RestoreTracksUseCase struct {
+	spotifyRepo  shared.SpotifyRepository
+	cacheRepo    shared.CacheRepository
+	databaseRepo shared.DatabaseRepository
+}
+
+// NewRestoreTracksUseCase creates a new RestoreTracksUseCase
+func NewRestoreTracksUseCase(
+	spotifyRepo shared.SpotifyRepository,
+	cacheRepo shared.CacheRepository,
+	databaseRepo shared.DatabaseRepository,
+) *RestoreTracksUseCase {
+	return &RestoreTracksUseCase{
+		spotifyRepo:  spotifyRepo,
+		cacheRepo:    cacheRepo,
+		databaseRepo: databaseRepo,
+	}
+}
+
+// Execute restores the backed-up tracks of the owner matching the filter and marks
+// their backups as restored: tracks removed from the library are re-saved to it, and
+// tracks removed from a playlist are re-inserted into it at their original position.
+// Tracks already restored are skipped.
+func (uc *RestoreTracksUseCase) Execute(ctx context.Context, filter backup.Filter) (*RestoreResult, error) {
+	filter.Owner = shared.OwnerFromContext(ctx)
+	filter.IncludeRestored = false
+
+	// 1. Get the tracks to restore from the backup table
+	backups, err := uc.databaseRepo.GetTracksBackup(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RestoreResult{TrackIDs: []string{}}
+	if len(backups) == 0 {
+		return result, nil // Nothing to restore
+	}
+
+	// A track deleted several times from the library has several backups but is saved
+	// once; every occurrence removed from a playlist is re-inserted
+	trackIDs := make([]spotifyAPI.ID, 0, len(backups))
+	backupIDs := make([]string, 0, len(backups))
+	playlistTracks := make(map[spotifyAPI.ID][]operation.PlaylistTrack)
+	var playlistIDs []spotifyAPI.ID
+	seen := make(map[string]bool, len(backups))
+	for _, t := range backups {
+		backupIDs = append(backupIDs, t.BackupID)
+
+		if t.PlaylistID != "" {
+			playlistID := spotifyAPI.ID(t.PlaylistID)
+			if _, exists := playlistTracks[playlistID]; !exists {
+				playlistIDs = append(playlistIDs, playlistID)
+			}
+			// Without a known position the track is appended
+			position := math.MaxInt
+			if t.Position != nil {
+				position = *t.Position
+			}
+			playlistTracks[playlistID] = append(playlistTracks[playlistID], operation.PlaylistTrack{TrackID: t.ID, Position: position})
+		}
+
+		if !t.RemovedFromLibrary() || seen[t.ID] {
+			continue
+		}
+		seen[t.ID] = true
+		trackIDs = append(trackIDs, spotifyAPI.ID(t.ID))
+		result.TrackIDs = append(result.TrackIDs, t.ID)
+	}
+
+	// 2. Save tracks back to the library
+	if len(trackIDs) > 0 {
+		if err := uc.spotifyRepo.AddTracksToLibrary(ctx, trackIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	// 3. Re-insert playlist tracks at their original positions
+	for _, playlistID := range playlistIDs {
+		if err := appOperation.RestorePlaylistTracks(ctx, uc.spotifyRepo, playlistID, playlistTracks[playlistID]); err != nil {
+			return nil, err
+		}
+		result.PlaylistTracksRestored += len(playlistTracks[playlistID])
+	}
+
+	// 4. Mark backup rows as restored
+	if err := uc.databaseRepo.MarkTracksRestored(ctx, filter.Owner, backupIDs); err != nil {
+		return nil, err
+	}
+
+	// 5. Invalidate cache
+	if uc.cacheRepo != nil {
+		if len(trackIDs) > 0 {
+			_ = uc.cacheRepo.InvalidateUserTracks(ctx)
+		}
+		for _, playlistID := range playlistIDs {
+			_ = uc.cacheRepo.InvalidatePlaylistTracks(ctx, playlistID)
+		}
+	}
+
+	result.Restored = len(result.TrackIDs)
+	return result, nil
+}
@@ -0,0 +1,99 @@
+package backup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/RubenPari/clear-songs/internal/domain/backup"
+	"github.com/RubenPari/clear-songs/internal/domain/operation"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	"github.com/RubenPari/clear-songs/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+func TestRestoreTracksUseCase_Execute(t *testing.T) {
+	ctx := shared.WithOwner(context.Background(), "spotify:user_1")
+
+	t.Run("Success - should re-save tracks and mark them restored", func(t *testing.T) {
+		mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+		mockCacheRepo := new(mocks.MockCacheRepository)
+		mockDatabaseRepo := new(mocks.MockDatabaseRepository)
+		useCase := NewRestoreTracksUseCase(mockSpotifyRepo, mockCacheRepo, mockDatabaseRepo)
+
+		// track_1 was deleted twice
+		backups := []backup.TrackBackup{
+			{BackupID: "backup_1", ID: "track_1"},
+			{BackupID: "backup_2", ID: "track_2"},
+			{BackupID: "backup_3", ID: "track_1"},
+		}
+
+		mockDatabaseRepo.On("GetTracksBackup", mock.Anything, backup.Filter{Owner: "spotify:user_1", Artist: "Queen"}).Return(backups, nil)
+		mockSpotifyRepo.On("AddTracksToLibrary", mock.Anything, []spotifyAPI.ID{"track_1", "track_2"}).Return(nil)
+		mockDatabaseRepo.On("MarkTracksRestored", mock.Anything, "spotify:user_1", []string{"backup_1", "backup_2", "backup_3"}).Return(nil)
+		mockCacheRepo.On("InvalidateUserTracks", mock.Anything).Return(nil)
+
+		result, err := useCase.Execute(ctx, backup.Filter{Artist: "Queen", IncludeRestored: true})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Restored)
+		mockDatabaseRepo.AssertExpectations(t)
+		mockSpotifyRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - should re-insert tracks removed from a playlist instead of saving them", func(t *testing.T) {
+		mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+		mockCacheRepo := new(mocks.MockCacheRepository)
+		mockDatabaseRepo := new(mocks.MockDatabaseRepository)
+		useCase := NewRestoreTracksUseCase(mockSpotifyRepo, mockCacheRepo, mockDatabaseRepo)
+
+		position := func(p int) *int { return &p }
+		backups := []backup.TrackBackup{
+			{BackupID: "backup_1", ID: "library_track"},
+			// Removed from the playlist only: still in the library
+			{BackupID: "backup_2", ID: "duplicate", Position: position(3), Origin: backup.Origin{PlaylistID: "playlist_1", Reason: string(operation.KindDedupePlaylist)}},
+			// Removed from both the playlist and the library
+			{BackupID: "backup_3", ID: "both", Position: position(0), Origin: backup.Origin{PlaylistID: "playlist_2", Reason: string(operation.KindDeletePlaylistAndLibraryTracks)}},
+		}
+		playlist := func(total int) *spotifyAPI.FullPlaylist {
+			playlist := &spotifyAPI.FullPlaylist{}
+			playlist.Tracks.Total = total
+			return playlist
+		}
+
+		mockDatabaseRepo.On("GetTracksBackup", mock.Anything, mock.Anything).Return(backups, nil)
+		mockSpotifyRepo.On("AddTracksToLibrary", mock.Anything, []spotifyAPI.ID{"library_track", "both"}).Return(nil)
+		mockSpotifyRepo.On("GetPlaylist", mock.Anything, spotifyAPI.ID("playlist_1")).Return(playlist(5), nil)
+		mockSpotifyRepo.On("AddTracksToPlaylist", mock.Anything, spotifyAPI.ID("playlist_1"), []spotifyAPI.ID{"duplicate"}, 3).Return(nil)
+		mockSpotifyRepo.On("GetPlaylist", mock.Anything, spotifyAPI.ID("playlist_2")).Return(playlist(2), nil)
+		mockSpotifyRepo.On("AddTracksToPlaylist", mock.Anything, spotifyAPI.ID("playlist_2"), []spotifyAPI.ID{"both"}, 0).Return(nil)
+		mockDatabaseRepo.On("MarkTracksRestored", mock.Anything, "spotify:user_1", []string{"backup_1", "backup_2", "backup_3"}).Return(nil)
+		mockCacheRepo.On("InvalidateUserTracks", mock.Anything).Return(nil)
+		mockCacheRepo.On("InvalidatePlaylistTracks", mock.Anything, mock.Anything).Return(nil)
+
+		result, err := useCase.Execute(ctx, backup.Filter{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Restored)
+		assert.Equal(t, []string{"library_track", "both"}, result.TrackIDs)
+		assert.Equal(t, 2, result.PlaylistTracksRestored)
+		mockDatabaseRepo.AssertExpectations(t)
+		mockSpotifyRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - Spotify failure should not mark tracks restored", func(t *testing.T) {
+		mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+		mockCacheRepo := new(mocks.MockCacheRepository)
+		mockDatabaseRepo := new(mocks.MockDatabaseRepository)
+		useCase := NewRestoreTracksUseCase(mockSpotifyRepo, mockCacheRepo, mockDatabaseRepo)
+
+		mockDatabaseRepo.On("GetTracksBackup", mock.Anything, mock.Anything).Return([]backup.TrackBackup{{BackupID: "backup_1", ID: "track_1"}}, nil)
+		mockSpotifyRepo.On("AddTracksToLibrary", mock.Anything, mock.Anything).Return(assert.AnError)
+
+		_, err := useCase.Execute(ctx, backup.Filter{})
+
+		assert.Error(t, err)
+		mockDatabaseRepo.AssertNotCalled(t, "MarkTracksRestored", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,71 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/RubenPari/clear-songs/internal/domain/backup"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+)
+
+// Policy decides what a destructive use case does when the tracks it is about to
+// remove cannot be backed up
+type Policy string
+
+const (
+	// PolicyRequired aborts the deletion when the backup cannot be written
+	PolicyRequired Policy = "required"
+	// PolicyBestEffort logs the failure and removes the tracks anyway
+	PolicyBestEffort Policy = "best_effort"
+	// PolicyOff removes tracks without backing them up
+	PolicyOff Policy = "off"
+)
+
+// ParsePolicy parses a BACKUP_POLICY value. An empty value is best_effort.
+func ParsePolicy(value string) (Policy, error) {
+	switch policy := Policy(strings.ToLower(strings.TrimSpace(value))); policy {
+	case "":
+		return PolicyBestEffort, nil
+	case PolicyRequired, PolicyBestEffort, PolicyOff:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("%w: unknown backup policy %q (expected required, best_effort or off)", shared.ErrValidation, value)
+	}
+}
+
+// Writer backs up the tracks destructive use cases are about to remove, following the
+// backup policy. A nil Writer backs up nothing.
+type Writer struct {
+	databaseRepo shared.DatabaseRepository
+	policy       Policy
+}
+
+// NewWriter creates a new backup writer
+func NewWriter(databaseRepo shared.DatabaseRepository, policy Policy) *Writer {
+	return &Writer{databaseRepo: databaseRepo, policy: policy}
+}
+
+// Save backs up tracks before they are removed. It only fails under the required
+// policy, in which case the caller must not remove them.
+func (w *Writer) Save(ctx context.Context, tracks []backup.TrackBackup) error {
+	if w == nil || w.policy == PolicyOff || len(tracks) == 0 {
+		return nil
+	}
+
+	err := errors.New("database not available")
+	if w.databaseRepo != nil {
+		err = w.databaseRepo.SaveTracksBackup(ctx, tracks)
+	}
+	if err == nil {
+		return nil
+	}
+
+	if w.policy == PolicyRequired {
+		return fmt.Errorf("%w: %v", shared.ErrBackupFailed, err)
+	}
+	log.Printf("WARNING: Failed to back up %d tracks, removing them anyway: %v", len(tracks), err)
+	return nil
+}
@@ -0,0 +1,81 @@
+package backup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/RubenPari/clear-songs/internal/domain/backup"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	"github.com/RubenPari/clear-songs/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestParsePolicy(t *testing.T) {
+	t.Run("Success - should default to best_effort", func(t *testing.T) {
+		policy, err := ParsePolicy("")
+		assert.NoError(t, err)
+		assert.Equal(t, PolicyBestEffort, policy)
+	})
+
+	t.Run("Success - should accept every policy regardless of case", func(t *testing.T) {
+		for value, expected := range map[string]Policy{"required": PolicyRequired, " Best_Effort ": PolicyBestEffort, "OFF": PolicyOff} {
+			policy, err := ParsePolicy(value)
+			assert.NoError(t, err, value)
+			assert.Equal(t, expected, policy, value)
+		}
+	})
+
+	t.Run("Error - should reject an unknown policy", func(t *testing.T) {
+		_, err := ParsePolicy("always")
+		assert.ErrorIs(t, err, shared.ErrValidation)
+	})
+}
+
+func TestWriter_Save(t *testing.T) {
+	ctx := context.Background()
+	tracks := []backup.TrackBackup{{ID: "track_1"}}
+
+	t.Run("Success - should save the backups", func(t *testing.T) {
+		mockDatabaseRepo := new(mocks.MockDatabaseRepository)
+		mockDatabaseRepo.On("SaveTracksBackup", mock.Anything, tracks).Return(nil)
+
+		err := NewWriter(mockDatabaseRepo, PolicyRequired).Save(ctx, tracks)
+
+		assert.NoError(t, err)
+		mockDatabaseRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - best_effort should ignore a failed backup", func(t *testing.T) {
+		mockDatabaseRepo := new(mocks.MockDatabaseRepository)
+		mockDatabaseRepo.On("SaveTracksBackup", mock.Anything, tracks).Return(assert.AnError)
+
+		err := NewWriter(mockDatabaseRepo, PolicyBestEffort).Save(ctx, tracks)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Success - off should not save anything", func(t *testing.T) {
+		mockDatabaseRepo := new(mocks.MockDatabaseRepository)
+
+		err := NewWriter(mockDatabaseRepo, PolicyOff).Save(ctx, tracks)
+
+		assert.NoError(t, err)
+		mockDatabaseRepo.AssertNotCalled(t, "SaveTracksBackup", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - required should fail when the backup cannot be written", func(t *testing.T) {
+		mockDatabaseRepo := new(mocks.MockDatabaseRepository)
+		mockDatabaseRepo.On("SaveTracksBackup", mock.Anything, tracks).Return(assert.AnError)
+
+		err := NewWriter(mockDatabaseRepo, PolicyRequired).Save(ctx, tracks)
+
+		assert.ErrorIs(t, err, shared.ErrBackupFailed)
+	})
+
+	t.Run("Error - required should fail without a database", func(t *testing.T) {
+		err := NewWriter(nil, PolicyRequired).Save(ctx, tracks)
+
+		assert.ErrorIs(t, err, shared.ErrBackupFailed)
+	})
+}
@@ -0,0 +1,42 @@
+package job
+
+import (
+	"time"
+
+	domainJob "github.com/RubenPari/clear-songs/internal/domain/job"
+)
+
+// JobResponse represents a job in API responses
+type JobResponse struct {
+	ID              string     `json:"id"`
+	Type            string     `json:"type"`
+	Status          string     `json:"status"`
+	TracksTotal     int        `json:"tracks_total"`
+	TracksProcessed int        `json:"tracks_processed"`
+	BatchesDone     int        `json:"batches_done"`
+	Errors          []string   `json:"errors"`
+	CreatedAt       time.Time  `json:"created_at"`
+	StartedAt       *time.Time `json:"started_at,omitempty"`
+	FinishedAt      *time.Time `json:"finished_at,omitempty"`
+}
+
+// NewJobResponse converts a job to its API representation
+func NewJobResponse(j *domainJob.Job) JobResponse {
+	jobErrors := j.Errors
+	if jobErrors == nil {
+		jobErrors = []string{}
+	}
+
+	return JobResponse{
+		ID:              j.ID,
+		Type:            j.Type,
+		Status:          string(j.Status),
+		TracksTotal:     j.TracksTotal,
+		TracksProcessed: j.TracksProcessed,
+		BatchesDone:     j.BatchesDone,
+		Errors:          jobErrors,
+		CreatedAt:       j.CreatedAt,
+		StartedAt:       j.StartedAt,
+		FinishedAt:      j.FinishedAt,
+	}
+}
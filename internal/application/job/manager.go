@@ -0,0 +1,333 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	domainJob "github.com/RubenPari/clear-songs/internal/domain/job"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	"github.com/google/uuid"
+)
+
+// queueSize bounds the number of jobs waiting for a worker
+const queueSize = 256
+
+// Task is the work performed by a job
+type Task func(ctx context.Context) error
+
+// Manager runs destructive operations in the background on a fixed pool of workers
+type Manager struct {
+	repo    domainJob.Repository
+	workers int
+	queue   chan *entry
+
+	mu       sync.Mutex
+	running  map[string]*entry
+	stopping bool
+	wg       sync.WaitGroup
+}
+
+// entry is a job owned by this process
+type entry struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	task    Task
+	tracker *tracker
+}
+
+// NewManager creates a new job manager
+func NewManager(repo domainJob.Repository, workers int) *Manager {
+	if workers < 1 {
+		workers = 1
+	}
+	return &Manager{
+		repo:    repo,
+		workers: workers,
+		queue:   make(chan *entry, queueSize),
+		running: make(map[string]*entry),
+	}
+}
+
+// Start flags jobs left unfinished by a previous process and starts the workers
+func (m *Manager) Start(ctx context.Context) {
+	if err := m.repo.MarkUnfinishedInterrupted(ctx); err != nil {
+		log.Printf("WARNING: Failed to mark unfinished jobs as interrupted: %v", err)
+	}
+
+	for i := 0; i < m.workers; i++ {
+		m.wg.Add(1)
+		go m.work()
+	}
+}
+
+// Shutdown cancels the jobs still owned by this process and waits for the workers to stop
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	if m.stopping {
+		m.mu.Unlock()
+		return nil
+	}
+	m.stopping = true
+	for _, e := range m.running {
+		e.cancel()
+	}
+	close(m.queue)
+	m.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Enqueue schedules task as a new job belonging to the owner in ctx.
+// The task keeps the request's session and owner values but not its deadline or cancellation.
+func (m *Manager) Enqueue(ctx context.Context, jobType string, task Task) (*domainJob.Job, error) {
+	j := &domainJob.Job{
+		ID:     uuid.NewString(),
+		Type:   jobType,
+		Owner:  shared.OwnerFromContext(ctx),
+		Status: domainJob.StatusPending,
+	}
+
+	if err := m.repo.Create(ctx, j); err != nil {
+		return nil, fmt.Errorf("%w: failed to create job: %v", shared.ErrInternal, err)
+	}
+
+	t := &tracker{repo: m.repo, job: j}
+	taskCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	e := &entry{
+		ctx:     domainJob.WithReporter(taskCtx, t),
+		cancel:  cancel,
+		task:    task,
+		tracker: t,
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.stopping {
+		cancel()
+		t.finish(domainJob.StatusInterrupted, errors.New("server is shutting down"))
+		return nil, fmt.Errorf("%w: server is shutting down", shared.ErrInternal)
+	}
+
+	select {
+	case m.queue <- e:
+	default:
+		cancel()
+		t.finish(domainJob.StatusFailed, errors.New("job queue is full"))
+		return nil, fmt.Errorf("%w: too many queued jobs, try again later", shared.ErrValidation)
+	}
+	m.running[j.ID] = e
+
+	return t.snapshot(), nil
+}
+
+// Get returns a job belonging to the owner in ctx
+func (m *Manager) Get(ctx context.Context, id string) (*domainJob.Job, error) {
+	m.mu.Lock()
+	e, exists := m.running[id]
+	m.mu.Unlock()
+
+	var j *domainJob.Job
+	if exists {
+		j = e.tracker.snapshot()
+	} else {
+		stored, err := m.repo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		j = stored
+	}
+
+	if j.Owner != shared.OwnerFromContext(ctx) {
+		return nil, shared.ErrNotFound
+	}
+
+	return j, nil
+}
+
+// Cancel stops a pending or running job belonging to the owner in ctx.
+// Batches already sent to Spotify are not rolled back.
+func (m *Manager) Cancel(ctx context.Context, id string) (*domainJob.Job, error) {
+	j, err := m.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if j.IsFinished() {
+		return nil, fmt.Errorf("%w: job already %s", shared.ErrValidation, j.Status)
+	}
+
+	m.mu.Lock()
+	e, exists := m.running[id]
+	m.mu.Unlock()
+
+	if !exists {
+		// Owned by a process that is gone; it will never make progress again
+		return nil, fmt.Errorf("%w: job is not running on this server", shared.ErrValidation)
+	}
+
+	e.tracker.requestCancel()
+	e.cancel()
+
+	return e.tracker.snapshot(), nil
+}
+
+// work executes queued jobs until the queue is closed
+func (m *Manager) work() {
+	defer m.wg.Done()
+
+	for e := range m.queue {
+		m.run(e)
+	}
+}
+
+func (m *Manager) run(e *entry) {
+	defer func() {
+		e.cancel()
+		m.mu.Lock()
+		delete(m.running, e.tracker.job.ID)
+		m.mu.Unlock()
+	}()
+
+	if e.ctx.Err() != nil && !e.tracker.cancelRequested() {
+		e.tracker.finish(domainJob.StatusInterrupted, errors.New("server is shutting down"))
+		return
+	}
+
+	if !e.tracker.start() {
+		return // Cancelled while pending
+	}
+
+	err := e.task(e.ctx)
+
+	switch {
+	case e.tracker.cancelRequested():
+		e.tracker.finish(domainJob.StatusCancelled, nil)
+	case e.ctx.Err() != nil:
+		e.tracker.finish(domainJob.StatusInterrupted, errors.New("server is shutting down"))
+	case err != nil:
+		e.tracker.finish(domainJob.StatusFailed, err)
+	default:
+		e.tracker.finish(domainJob.StatusCompleted, nil)
+	}
+}
+
+// tracker records the progress of one job and persists every change
+type tracker struct {
+	repo domainJob.Repository
+
+	mu        sync.Mutex
+	job       *domainJob.Job
+	cancelled bool
+}
+
+// AddTotal implements domainJob.Reporter
+func (t *tracker) AddTotal(tracks int) {
+	t.update(func(j *domainJob.Job) {
+		j.TracksTotal += tracks
+	})
+}
+
+// BatchDone implements domainJob.Reporter
+func (t *tracker) BatchDone(tracks int) {
+	t.update(func(j *domainJob.Job) {
+		j.TracksProcessed += tracks
+		j.BatchesDone++
+	})
+}
+
+// Error implements domainJob.Reporter
+func (t *tracker) Error(err error) {
+	t.update(func(j *domainJob.Job) {
+		j.Errors = append(j.Errors, err.Error())
+	})
+}
+
+// start moves a pending job to running; it returns false if the job was cancelled first
+func (t *tracker) start() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.cancelled {
+		return false
+	}
+
+	now := time.Now()
+	t.job.Status = domainJob.StatusRunning
+	t.job.StartedAt = &now
+	t.persist()
+	return true
+}
+
+// requestCancel flags the job as cancelled, finishing it right away if it has not started
+func (t *tracker) requestCancel() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.cancelled = true
+	if t.job.Status == domainJob.StatusPending {
+		t.setFinished(domainJob.StatusCancelled, nil)
+	}
+}
+
+func (t *tracker) cancelRequested() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.cancelled
+}
+
+func (t *tracker) finish(status domainJob.Status, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.setFinished(status, err)
+}
+
+func (t *tracker) setFinished(status domainJob.Status, err error) {
+	now := time.Now()
+	t.job.Status = status
+	t.job.FinishedAt = &now
+	if err != nil {
+		t.job.Errors = append(t.job.Errors, err.Error())
+	}
+	t.persist()
+}
+
+func (t *tracker) update(apply func(j *domainJob.Job)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	apply(t.job)
+	t.persist()
+}
+
+// persist saves the job; callers must hold t.mu
+func (t *tracker) persist() {
+	if err := t.repo.Update(context.Background(), t.job); err != nil {
+		log.Printf("WARNING: Failed to persist job %s: %v", t.job.ID, err)
+	}
+}
+
+// snapshot returns a copy of the job safe to hand out
+func (t *tracker) snapshot() *domainJob.Job {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	copied := *t.job
+	copied.Errors = append([]string(nil), t.job.Errors...)
+	return &copied
+}
@@ -0,0 +1,149 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	domainJob "github.com/RubenPari/clear-songs/internal/domain/job"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeJobRepository keeps jobs in memory for the tests
+type fakeJobRepository struct {
+	mu          sync.Mutex
+	jobs        map[string]domainJob.Job
+	interrupted bool
+}
+
+func newFakeJobRepository() *fakeJobRepository {
+	return &fakeJobRepository{jobs: make(map[string]domainJob.Job)}
+}
+
+func (r *fakeJobRepository) Create(ctx context.Context, j *domainJob.Job) error {
+	return r.Update(ctx, j)
+}
+
+func (r *fakeJobRepository) Update(ctx context.Context, j *domainJob.Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobs[j.ID] = *j
+	return nil
+}
+
+func (r *fakeJobRepository) GetByID(ctx context.Context, id string) (*domainJob.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	j, exists := r.jobs[id]
+	if !exists {
+		return nil, shared.ErrNotFound
+	}
+	return &j, nil
+}
+
+func (r *fakeJobRepository) MarkUnfinishedInterrupted(ctx context.Context) error {
+	r.interrupted = true
+	return nil
+}
+
+func waitFinished(t *testing.T, manager *Manager, ctx context.Context, id string) *domainJob.Job {
+	t.Helper()
+
+	var j *domainJob.Job
+	require.Eventually(t, func() bool {
+		var err error
+		j, err = manager.Get(ctx, id)
+		return err == nil && j.IsFinished()
+	}, 2*time.Second, 5*time.Millisecond)
+	return j
+}
+
+func TestManager(t *testing.T) {
+	repo := newFakeJobRepository()
+	manager := NewManager(repo, 2)
+	manager.Start(context.Background())
+	defer manager.Shutdown(context.Background())
+
+	ctx := shared.WithOwner(shared.WithSession(context.Background(), "session:abc"), "spotify:owner")
+
+	t.Run("Success - should report progress and complete", func(t *testing.T) {
+		j, err := manager.Enqueue(ctx, "delete_tracks_by_range", func(ctx context.Context) error {
+			domainJob.ReportTotal(ctx, 120)
+			domainJob.ReportBatch(ctx, 50)
+			domainJob.ReportBatch(ctx, 50)
+			domainJob.ReportBatch(ctx, 20)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, domainJob.StatusPending, j.Status)
+
+		finished := waitFinished(t, manager, ctx, j.ID)
+		assert.Equal(t, domainJob.StatusCompleted, finished.Status)
+		assert.Equal(t, 120, finished.TracksTotal)
+		assert.Equal(t, 120, finished.TracksProcessed)
+		assert.Equal(t, 3, finished.BatchesDone)
+		assert.True(t, repo.interrupted)
+	})
+
+	t.Run("Success - task keeps session and owner after request context is cancelled", func(t *testing.T) {
+		reqCtx, cancelReq := context.WithCancel(ctx)
+		sessions := make(chan string, 1)
+
+		j, err := manager.Enqueue(reqCtx, "delete_tracks_by_artist", func(ctx context.Context) error {
+			cancelReq()
+			sessions <- shared.SessionFromContext(ctx) + "|" + shared.OwnerFromContext(ctx)
+			return ctx.Err()
+		})
+		require.NoError(t, err)
+
+		finished := waitFinished(t, manager, ctx, j.ID)
+		assert.Equal(t, domainJob.StatusCompleted, finished.Status)
+		assert.Equal(t, "session:abc|spotify:owner", <-sessions)
+	})
+
+	t.Run("Error - failing task should be marked failed", func(t *testing.T) {
+		j, err := manager.Enqueue(ctx, "delete_playlist_tracks", func(ctx context.Context) error {
+			return errors.New("spotify unavailable")
+		})
+		require.NoError(t, err)
+
+		finished := waitFinished(t, manager, ctx, j.ID)
+		assert.Equal(t, domainJob.StatusFailed, finished.Status)
+		assert.Equal(t, []string{"spotify unavailable"}, finished.Errors)
+	})
+
+	t.Run("Success - running job should stop when cancelled", func(t *testing.T) {
+		started := make(chan struct{})
+		j, err := manager.Enqueue(ctx, "delete_tracks_by_range", func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		require.NoError(t, err)
+		<-started
+
+		_, err = manager.Cancel(ctx, j.ID)
+		require.NoError(t, err)
+
+		finished := waitFinished(t, manager, ctx, j.ID)
+		assert.Equal(t, domainJob.StatusCancelled, finished.Status)
+
+		_, err = manager.Cancel(ctx, j.ID)
+		assert.ErrorIs(t, err, shared.ErrValidation)
+	})
+
+	t.Run("Error - other owners should not see the job", func(t *testing.T) {
+		j, err := manager.Enqueue(ctx, "delete_tracks_by_range", func(ctx context.Context) error { return nil })
+		require.NoError(t, err)
+
+		other := shared.WithOwner(shared.WithSession(context.Background(), "session:abc"), "spotify:other")
+		_, err = manager.Get(other, j.ID)
+		assert.ErrorIs(t, err, shared.ErrNotFound)
+		_, err = manager.Cancel(other, j.ID)
+		assert.ErrorIs(t, err, shared.ErrNotFound)
+	})
+}
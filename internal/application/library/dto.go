@@ -0,0 +1,201 @@
+package library
+
+import (
+	"strings"
+
+	"github.com/RubenPari/clear-songs/internal/domain/track"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+// Export formats
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+	FormatM3U8 = "m3u8"
+	FormatXSPF = "xspf"
+)
+
+// ExportRequest holds the query parameters of the export endpoint
+type ExportRequest struct {
+	Format     string `form:"format,default=csv" binding:"oneof=csv json m3u8 xspf"`
+	PlaylistID string `form:"playlist_id"` // exports the saved library when empty
+}
+
+// ExportRow is a single exported track
+type ExportRow struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Artists    []string `json:"artists"`
+	Album      string   `json:"album"`
+	ISRC       string   `json:"isrc"`
+	DurationMs int      `json:"duration_ms"`
+	AddedAt    string   `json:"added_at"`
+	URL        string   `json:"url"`
+}
+
+// NewExportRow converts a track and the date it was added to an export row
+func NewExportRow(track spotifyAPI.FullTrack, addedAt string) ExportRow {
+	artists := make([]string, 0, len(track.Artists))
+	for _, artist := range track.Artists {
+		artists = append(artists, artist.Name)
+	}
+
+	return ExportRow{
+		ID:         track.ID.String(),
+		Name:       track.Name,
+		Artists:    artists,
+		Album:      track.Album.Name,
+		ISRC:       track.ExternalIDs["isrc"],
+		DurationMs: track.Duration,
+		AddedAt:    addedAt,
+		URL:        track.ExternalURLs["spotify"],
+	}
+}
+
+// ArtistNames joins the artists of a row for single-field formats
+func (r ExportRow) ArtistNames() string {
+	return strings.Join(r.Artists, ", ")
+}
+
+// Import row statuses
+const (
+	ImportMatched   = "matched"
+	ImportAmbiguous = "ambiguous"
+	ImportNotFound  = "not_found"
+)
+
+// ImportRequest holds the form fields of the import endpoint
+type ImportRequest struct {
+	Format     string `form:"format" binding:"omitempty,oneof=csv json"` // detected from the file name when empty
+	PlaylistID string `form:"playlist_id"`                               // saves to the library when empty
+	DryRun     bool   `form:"dry_run"`
+}
+
+// ImportCandidate is a catalog track that may correspond to an imported row
+type ImportCandidate struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Artists []string `json:"artists"`
+	Album   string   `json:"album"`
+}
+
+// NewImportCandidate converts a catalog track to a candidate
+func NewImportCandidate(track spotifyAPI.FullTrack) ImportCandidate {
+	row := NewExportRow(track, "")
+	return ImportCandidate{ID: row.ID, Name: row.Name, Artists: row.Artists, Album: row.Album}
+}
+
+// ImportRowResult reports how a row was resolved. Method is how a matched row was
+// identified: id, uri, url, isrc or search.
+type ImportRowResult struct {
+	Row        int               `json:"row"`
+	Status     string            `json:"status"`
+	Method     string            `json:"method,omitempty"`
+	TrackID    string            `json:"track_id,omitempty"`
+	Name       string            `json:"name,omitempty"`
+	Artist     string            `json:"artist,omitempty"`
+	Reason     string            `json:"reason,omitempty"`
+	Candidates []ImportCandidate `json:"candidates,omitempty"`
+}
+
+// ImportResult summarises an import
+type ImportResult struct {
+	DryRun     bool              `json:"dry_run"`
+	PlaylistID string            `json:"playlist_id,omitempty"`
+	Total      int               `json:"total"`
+	Matched    int               `json:"matched"`
+	Ambiguous  int               `json:"ambiguous"`
+	NotFound   int               `json:"not_found"`
+	Saved      int               `json:"saved"` // distinct matched tracks saved
+	Rows       []ImportRowResult `json:"rows"`
+}
+
+// DuplicatesRequest holds the query parameters of the duplicate endpoints
+type DuplicatesRequest struct {
+	Prefer      string `form:"prefer"` // comma-separated preferences in priority order, defaults when empty
+	ToleranceMs int    `form:"tolerance_ms,default=2000" binding:"min=0,max=60000"`
+}
+
+// MergeDuplicatesRequest holds the query parameters of the merge endpoint
+type MergeDuplicatesRequest struct {
+	DuplicatesRequest
+	DryRun bool `form:"dry_run"`
+	Async  bool `form:"async"`
+}
+
+// DuplicateTrack is a version of a duplicated song
+type DuplicateTrack struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Artists    []string `json:"artists"`
+	Album      string   `json:"album"`
+	AlbumType  string   `json:"album_type"`
+	ISRC       string   `json:"isrc,omitempty"`
+	DurationMs int      `json:"duration_ms"`
+	Explicit   bool     `json:"explicit"`
+	Popularity int      `json:"popularity"`
+	AddedAt    string   `json:"added_at"`
+}
+
+// NewDuplicateTrack converts a saved track to a duplicate version
+func NewDuplicateTrack(track spotifyAPI.SavedTrack) DuplicateTrack {
+	row := NewExportRow(track.FullTrack, track.AddedAt)
+	return DuplicateTrack{
+		ID:         row.ID,
+		Name:       row.Name,
+		Artists:    row.Artists,
+		Album:      row.Album,
+		AlbumType:  track.Album.AlbumType,
+		ISRC:       row.ISRC,
+		DurationMs: row.DurationMs,
+		Explicit:   track.Explicit,
+		Popularity: track.Popularity,
+		AddedAt:    row.AddedAt,
+	}
+}
+
+// DuplicateGroupResponse is a duplicated song with the version kept and the ones removed on merge
+type DuplicateGroupResponse struct {
+	Key       string           `json:"key"`
+	MatchedBy string           `json:"matched_by"`
+	Keep      DuplicateTrack   `json:"keep"`
+	Remove    []DuplicateTrack `json:"remove"`
+}
+
+// NewDuplicateGroupResponse converts a duplicate group
+func NewDuplicateGroupResponse(group track.DuplicateGroup) DuplicateGroupResponse {
+	response := DuplicateGroupResponse{
+		Key:       group.Key,
+		MatchedBy: group.MatchedBy,
+		Keep:      NewDuplicateTrack(group.Keep),
+		Remove:    make([]DuplicateTrack, 0, len(group.Remove)),
+	}
+	for _, version := range group.Remove {
+		response.Remove = append(response.Remove, NewDuplicateTrack(version))
+	}
+	return response
+}
+
+// DuplicatesResult lists the duplicated songs of the library
+type DuplicatesResult struct {
+	TotalGroups     int                      `json:"total_groups"`
+	TotalDuplicates int                      `json:"total_duplicates"` // versions that a merge removes
+	Groups          []DuplicateGroupResponse `json:"groups"`
+}
+
+// MergePlaylistResult reports the duplicates removed from an owned playlist and the kept
+// versions inserted in their place
+type MergePlaylistResult struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	TracksRemoved int    `json:"tracks_removed"`
+	TracksAdded   int    `json:"tracks_added"`
+}
+
+// MergeDuplicatesResult summarises a merge
+type MergeDuplicatesResult struct {
+	DryRun               bool                     `json:"dry_run"`
+	LibraryTracksRemoved int                      `json:"library_tracks_removed"`
+	Playlists            []MergePlaylistResult    `json:"playlists"`
+	Groups               []DuplicateGroupResponse `json:"groups"`
+}
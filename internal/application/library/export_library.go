@@ -0,0 +1,126 @@
+package library
+
+import (
+	"context"
+	"io"
+
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+const (
+	userTracksPageSize     = 50
+	playlistTracksPageSize = 100
+)
+
+// fetchPage returns the export rows of the page starting at offset and whether more pages follow
+type fetchPage func(ctx context.Context, offset int) (rows []ExportRow, more bool, err error)
+
+// ExportLibraryUseCase handles the business logic for exporting the saved library or a playlist
+type ExportLibraryUseCase struct {
+	spotifyRepo shared.SpotifyRepository
+}
+
+// NewExportLibraryUseCase creates a new ExportLibraryUseCase
+func NewExportLibraryUseCase(spotifyRepo shared.SpotifyRepository) *ExportLibraryUseCase {
+	return &ExportLibraryUseCase{
+		spotifyRepo: spotifyRepo,
+	}
+}
+
+// Execute streams the saved library, or the playlist in req, to w. Tracks are fetched
+// with the same pagination as GetAllUserTracks and GetAllPlaylistTracks but written
+// one page at a time, so only a single page is held in memory; w is flushed after
+// every page when it supports it.
+//
+// Nothing is written before the playlist and the first page have been fetched, so
+// errors returned before any output can still be reported to the client.
+func (uc *ExportLibraryUseCase) Execute(ctx context.Context, req ExportRequest, w io.Writer) error {
+	writer, err := NewExportWriter(req.Format, w)
+	if err != nil {
+		return err
+	}
+
+	// 1. Resolve what to export
+	title := "Liked Songs"
+	fetch := uc.userTracksPage
+	if req.PlaylistID != "" {
+		playlistID := spotifyAPI.ID(req.PlaylistID)
+		playlist, err := uc.spotifyRepo.GetPlaylist(ctx, playlistID)
+		if err != nil {
+			return err
+		}
+		if playlist != nil {
+			title = playlist.Name
+		}
+		fetch = func(ctx context.Context, offset int) ([]ExportRow, bool, error) {
+			return uc.playlistTracksPage(ctx, playlistID, offset)
+		}
+	}
+
+	// 2. Fetch the first page before writing anything
+	rows, more, err := fetch(ctx, 0)
+	if err != nil {
+		return err
+	}
+
+	// 3. Stream the pages
+	if err := writer.Begin(title); err != nil {
+		return err
+	}
+	for offset := 0; ; {
+		for _, row := range rows {
+			if err := writer.WriteRow(row); err != nil {
+				return err
+			}
+		}
+		if flusher, ok := w.(interface{ Flush() }); ok {
+			flusher.Flush()
+		}
+
+		if !more {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		offset += len(rows)
+		rows, more, err = fetch(ctx, offset)
+		if err != nil {
+			return err
+		}
+	}
+
+	return writer.End()
+}
+
+// userTracksPage fetches a page of saved tracks
+func (uc *ExportLibraryUseCase) userTracksPage(ctx context.Context, offset int) ([]ExportRow, bool, error) {
+	tracks, err := uc.spotifyRepo.GetUserTracks(ctx, userTracksPageSize, offset)
+	if err != nil {
+		return nil, false, err
+	}
+
+	rows := make([]ExportRow, 0, len(tracks))
+	for _, track := range tracks {
+		rows = append(rows, NewExportRow(track.FullTrack, track.AddedAt))
+	}
+
+	return rows, len(tracks) == userTracksPageSize, nil
+}
+
+// playlistTracksPage fetches a page of playlist items
+func (uc *ExportLibraryUseCase) playlistTracksPage(ctx context.Context, playlistID spotifyAPI.ID, offset int) ([]ExportRow, bool, error) {
+	tracks, err := uc.spotifyRepo.GetPlaylistTracks(ctx, playlistID, playlistTracksPageSize, offset)
+	if err != nil {
+		return nil, false, err
+	}
+
+	rows := make([]ExportRow, 0, len(tracks))
+	for _, track := range tracks {
+		rows = append(rows, NewExportRow(track.Track, track.AddedAt))
+	}
+
+	return rows, len(tracks) == playlistTracksPageSize, nil
+}
@@ -0,0 +1,162 @@
+package library
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/RubenPari/clear-songs/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+func exportTrack(i int) spotifyAPI.FullTrack {
+	id := fmt.Sprintf("track_%d", i)
+	return spotifyAPI.FullTrack{
+		SimpleTrack: spotifyAPI.SimpleTrack{
+			ID:           spotifyAPI.ID(id),
+			Name:         fmt.Sprintf("Song %d", i),
+			Artists:      []spotifyAPI.SimpleArtist{{Name: "Queen"}, {Name: "David Bowie"}},
+			Duration:     248000,
+			ExternalURLs: map[string]string{"spotify": "https://open.spotify.com/track/" + id},
+		},
+		Album:       spotifyAPI.SimpleAlbum{Name: "Hot Space"},
+		ExternalIDs: map[string]string{"isrc": fmt.Sprintf("GBUM7110%04d", i)},
+	}
+}
+
+func savedTracks(from, to int) []spotifyAPI.SavedTrack {
+	tracks := []spotifyAPI.SavedTrack{}
+	for i := from; i < to; i++ {
+		tracks = append(tracks, spotifyAPI.SavedTrack{AddedAt: "2024-01-02T03:04:05Z", FullTrack: exportTrack(i)})
+	}
+	return tracks
+}
+
+func TestExportLibraryUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success - should stream the library page by page as CSV", func(t *testing.T) {
+		mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+		useCase := NewExportLibraryUseCase(mockSpotifyRepo)
+
+		mockSpotifyRepo.On("GetUserTracks", mock.Anything, 50, 0).Return(savedTracks(0, 50), nil)
+		mockSpotifyRepo.On("GetUserTracks", mock.Anything, 50, 50).Return(savedTracks(50, 53), nil)
+
+		var out bytes.Buffer
+		err := useCase.Execute(ctx, ExportRequest{Format: FormatCSV}, &out)
+
+		assert.NoError(t, err)
+		records, err := csv.NewReader(&out).ReadAll()
+		assert.NoError(t, err)
+		assert.Len(t, records, 54) // header + 53 tracks
+		assert.Equal(t, []string{"id", "name", "artists", "album", "isrc", "duration_ms", "added_at", "url"}, records[0])
+		assert.Equal(t, []string{
+			"track_0", "Song 0", "Queen, David Bowie", "Hot Space", "GBUM71100000", "248000",
+			"2024-01-02T03:04:05Z", "https://open.spotify.com/track/track_0",
+		}, records[1])
+		mockSpotifyRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - should export a playlist as JSON", func(t *testing.T) {
+		mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+		useCase := NewExportLibraryUseCase(mockSpotifyRepo)
+		playlistID := spotifyAPI.ID("playlist_1")
+
+		mockSpotifyRepo.On("GetPlaylist", mock.Anything, playlistID).Return(&spotifyAPI.FullPlaylist{
+			SimplePlaylist: spotifyAPI.SimplePlaylist{ID: playlistID, Name: "Road trip"},
+		}, nil)
+		mockSpotifyRepo.On("GetPlaylistTracks", mock.Anything, playlistID, 100, 0).Return([]spotifyAPI.PlaylistTrack{
+			{AddedAt: "2024-02-03T04:05:06Z", Track: exportTrack(1)},
+			{AddedAt: "2024-02-03T04:05:07Z", Track: exportTrack(2)},
+		}, nil)
+
+		var out bytes.Buffer
+		err := useCase.Execute(ctx, ExportRequest{Format: FormatJSON, PlaylistID: playlistID.String()}, &out)
+
+		assert.NoError(t, err)
+		var rows []ExportRow
+		assert.NoError(t, json.Unmarshal(out.Bytes(), &rows))
+		assert.Len(t, rows, 2)
+		assert.Equal(t, "2024-02-03T04:05:07Z", rows[1].AddedAt)
+		assert.Equal(t, []string{"Queen", "David Bowie"}, rows[1].Artists)
+	})
+
+	t.Run("Success - should write an empty JSON array for an empty library", func(t *testing.T) {
+		mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+		useCase := NewExportLibraryUseCase(mockSpotifyRepo)
+
+		mockSpotifyRepo.On("GetUserTracks", mock.Anything, 50, 0).Return([]spotifyAPI.SavedTrack{}, nil)
+
+		var out bytes.Buffer
+		err := useCase.Execute(ctx, ExportRequest{Format: FormatJSON}, &out)
+
+		assert.NoError(t, err)
+		var rows []ExportRow
+		assert.NoError(t, json.Unmarshal(out.Bytes(), &rows))
+		assert.Empty(t, rows)
+	})
+
+	t.Run("Success - should write M3U8 and XSPF playlists", func(t *testing.T) {
+		mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+		useCase := NewExportLibraryUseCase(mockSpotifyRepo)
+
+		mockSpotifyRepo.On("GetUserTracks", mock.Anything, 50, 0).Return(savedTracks(0, 1), nil)
+
+		var m3u8 bytes.Buffer
+		assert.NoError(t, useCase.Execute(ctx, ExportRequest{Format: FormatM3U8}, &m3u8))
+		assert.Equal(t, "#EXTM3U\n#PLAYLIST:Liked Songs\n#EXTINF:248,Queen, David Bowie - Song 0\nhttps://open.spotify.com/track/track_0\n", m3u8.String())
+
+		var xspf bytes.Buffer
+		assert.NoError(t, useCase.Execute(ctx, ExportRequest{Format: FormatXSPF}, &xspf))
+		var document struct {
+			Title  string `xml:"title"`
+			Tracks []struct {
+				Identifier string `xml:"identifier"`
+				Creator    string `xml:"creator"`
+			} `xml:"trackList>track"`
+		}
+		assert.NoError(t, xml.Unmarshal(xspf.Bytes(), &document))
+		assert.Equal(t, "Liked Songs", document.Title)
+		assert.Equal(t, "spotify:track:track_0", document.Tracks[0].Identifier)
+		assert.Equal(t, "Queen, David Bowie", document.Tracks[0].Creator)
+	})
+
+	t.Run("Success - should keep M3U8 names on one line", func(t *testing.T) {
+		mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+		useCase := NewExportLibraryUseCase(mockSpotifyRepo)
+		playlistID := spotifyAPI.ID("playlist_1")
+		track := exportTrack(1)
+		track.Name = "Song\n#EXTINF:0,Injected"
+
+		mockSpotifyRepo.On("GetPlaylist", mock.Anything, playlistID).Return(&spotifyAPI.FullPlaylist{
+			SimplePlaylist: spotifyAPI.SimplePlaylist{ID: playlistID, Name: "Road\r\ntrip"},
+		}, nil)
+		mockSpotifyRepo.On("GetPlaylistTracks", mock.Anything, playlistID, 100, 0).Return([]spotifyAPI.PlaylistTrack{{Track: track}}, nil)
+
+		var out bytes.Buffer
+		err := useCase.Execute(ctx, ExportRequest{Format: FormatM3U8, PlaylistID: playlistID.String()}, &out)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "#EXTM3U\n#PLAYLIST:Road trip\n#EXTINF:248,Queen, David Bowie - Song #EXTINF:0,Injected\nhttps://open.spotify.com/track/track_1\n", out.String())
+	})
+
+	t.Run("Error - nothing is written when the first page fails", func(t *testing.T) {
+		mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+		useCase := NewExportLibraryUseCase(mockSpotifyRepo)
+
+		mockSpotifyRepo.On("GetUserTracks", mock.Anything, 50, 0).Return(nil, assert.AnError)
+
+		var out bytes.Buffer
+		err := useCase.Execute(ctx, ExportRequest{Format: FormatCSV}, &out)
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Empty(t, strings.TrimSpace(out.String()))
+	})
+}
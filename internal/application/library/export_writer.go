@@ -0,0 +1,188 @@
+package library
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ExportWriter encodes exported tracks in one format, one row at a time
+type ExportWriter interface {
+	// Begin writes the document header; title names the exported library or playlist
+	Begin(title string) error
+	WriteRow(row ExportRow) error
+	// End writes the document footer and flushes buffered output
+	End() error
+}
+
+// ContentType returns the MIME type of an export format
+func ContentType(format string) string {
+	switch format {
+	case FormatJSON:
+		return "application/json"
+	case FormatM3U8:
+		return "application/vnd.apple.mpegurl"
+	case FormatXSPF:
+		return "application/xspf+xml"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// NewExportWriter returns the writer of a format
+func NewExportWriter(format string, w io.Writer) (ExportWriter, error) {
+	switch format {
+	case FormatCSV:
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	case FormatJSON:
+		return &jsonWriter{w: w}, nil
+	case FormatM3U8:
+		return &m3u8Writer{w: w}, nil
+	case FormatXSPF:
+		return &xspfWriter{w: w}, nil
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (e *csvWriter) Begin(title string) error {
+	return e.w.Write([]string{"id", "name", "artists", "album", "isrc", "duration_ms", "added_at", "url"})
+}
+
+func (e *csvWriter) WriteRow(row ExportRow) error {
+	return e.w.Write([]string{
+		row.ID,
+		row.Name,
+		row.ArtistNames(),
+		row.Album,
+		row.ISRC,
+		strconv.Itoa(row.DurationMs),
+		row.AddedAt,
+		row.URL,
+	})
+}
+
+func (e *csvWriter) End() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// jsonWriter writes a JSON array without holding it in memory
+type jsonWriter struct {
+	w    io.Writer
+	rows int
+}
+
+func (e *jsonWriter) Begin(title string) error {
+	_, err := io.WriteString(e.w, "[")
+	return err
+}
+
+func (e *jsonWriter) WriteRow(row ExportRow) error {
+	separator := "\n"
+	if e.rows > 0 {
+		separator = ",\n"
+	}
+	if _, err := io.WriteString(e.w, separator); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(row)
+	if err != nil {
+		return err
+	}
+	e.rows++
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *jsonWriter) End() error {
+	_, err := io.WriteString(e.w, "\n]\n")
+	return err
+}
+
+// m3u8Writer writes an extended M3U playlist pointing at the Spotify URLs
+type m3u8Writer struct {
+	w io.Writer
+}
+
+// m3uLineBreaks turns line breaks into spaces, since every M3U entry is one line
+var m3uLineBreaks = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ")
+
+func (e *m3u8Writer) Begin(title string) error {
+	_, err := fmt.Fprintf(e.w, "#EXTM3U\n#PLAYLIST:%s\n", m3uLineBreaks.Replace(title))
+	return err
+}
+
+func (e *m3u8Writer) WriteRow(row ExportRow) error {
+	_, err := fmt.Fprintf(e.w, "#EXTINF:%d,%s - %s\n%s\n", row.DurationMs/1000,
+		m3uLineBreaks.Replace(row.ArtistNames()), m3uLineBreaks.Replace(row.Name), m3uLineBreaks.Replace(row.URL))
+	return err
+}
+
+func (e *m3u8Writer) End() error {
+	return nil
+}
+
+// xspfTrack is a track element of an XSPF playlist
+type xspfTrack struct {
+	XMLName    xml.Name `xml:"track"`
+	Location   string   `xml:"location,omitempty"`
+	Identifier string   `xml:"identifier,omitempty"`
+	Title      string   `xml:"title"`
+	Creator    string   `xml:"creator"`
+	Album      string   `xml:"album"`
+	Duration   int      `xml:"duration"`
+}
+
+// xspfWriter writes an XSPF playlist (https://xspf.org)
+type xspfWriter struct {
+	w io.Writer
+}
+
+func (e *xspfWriter) Begin(title string) error {
+	if _, err := io.WriteString(e.w, xml.Header+`<playlist version="1" xmlns="http://xspf.org/ns/0/">`+"\n<title>"); err != nil {
+		return err
+	}
+	if err := xml.EscapeText(e.w, []byte(title)); err != nil {
+		return err
+	}
+	_, err := io.WriteString(e.w, "</title>\n<trackList>\n")
+	return err
+}
+
+func (e *xspfWriter) WriteRow(row ExportRow) error {
+	track := xspfTrack{
+		Location: row.URL,
+		Title:    row.Name,
+		Creator:  row.ArtistNames(),
+		Album:    row.Album,
+		Duration: row.DurationMs,
+	}
+	if row.ID != "" {
+		track.Identifier = "spotify:track:" + row.ID
+	}
+
+	data, err := xml.Marshal(track)
+	if err != nil {
+		return err
+	}
+	if _, err := e.w.Write(data); err != nil {
+		return err
+	}
+	_, err = io.WriteString(e.w, "\n")
+	return err
+}
+
+func (e *xspfWriter) End() error {
+	_, err := io.WriteString(e.w, "</trackList>\n</playlist>\n")
+	return err
+}
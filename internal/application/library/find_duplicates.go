@@ -0,0 +1,72 @@
+package library
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/RubenPari/clear-songs/internal/application/usertracks"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	"github.com/RubenPari/clear-songs/internal/domain/track"
+)
+
+// FindDuplicatesUseCase handles the business logic for detecting the songs saved
+// more than once under different track IDs
+type FindDuplicatesUseCase struct {
+	spotifyRepo shared.SpotifyRepository
+	cacheRepo   shared.CacheRepository
+	userTracks  *usertracks.Provider
+}
+
+// NewFindDuplicatesUseCase creates a new FindDuplicatesUseCase
+func NewFindDuplicatesUseCase(
+	spotifyRepo shared.SpotifyRepository,
+	cacheRepo shared.CacheRepository,
+	userTracks *usertracks.Provider,
+) *FindDuplicatesUseCase {
+	return &FindDuplicatesUseCase{
+		spotifyRepo: spotifyRepo,
+		cacheRepo:   cacheRepo,
+		userTracks:  userTracks,
+	}
+}
+
+// Execute groups the saved tracks that are versions of the same song and reports,
+// for each group, the version kept by a merge
+func (uc *FindDuplicatesUseCase) Execute(ctx context.Context, req DuplicatesRequest) (*DuplicatesResult, error) {
+	groups, err := uc.find(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DuplicatesResult{
+		TotalGroups: len(groups),
+		Groups:      make([]DuplicateGroupResponse, 0, len(groups)),
+	}
+	for _, group := range groups {
+		result.TotalDuplicates += len(group.Remove)
+		result.Groups = append(result.Groups, NewDuplicateGroupResponse(group))
+	}
+
+	return result, nil
+}
+
+// find returns the duplicate groups of the library
+func (uc *FindDuplicatesUseCase) find(ctx context.Context, req DuplicatesRequest) ([]track.DuplicateGroup, error) {
+	// 1. Parse the preferences
+	preferences, err := track.ParsePreferences(req.Prefer)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", shared.ErrValidation, err)
+	}
+
+	// 2. Get user tracks (from cache or the library mirror)
+	tracks, err := uc.userTracks.GetUserTracks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Group the versions of each song
+	return track.FindDuplicates(tracks, track.DuplicateOptions{
+		DurationToleranceMs: req.ToleranceMs,
+		Preferences:         preferences,
+	}), nil
+}
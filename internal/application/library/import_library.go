@@ -0,0 +1,301 @@
+package library
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+const (
+	// maxImportRows bounds the catalog searches a single request can trigger
+	maxImportRows = 2000
+	// importSearchLimit is the number of search results considered per row
+	importSearchLimit = 5
+)
+
+var trackIDPattern = regexp.MustCompile(`^[0-9A-Za-z]{22}$`)
+
+// ImportLibraryUseCase handles the business logic for importing tracks from a file
+type ImportLibraryUseCase struct {
+	spotifyRepo shared.SpotifyRepository
+	cacheRepo   shared.CacheRepository
+}
+
+// NewImportLibraryUseCase creates a new ImportLibraryUseCase
+func NewImportLibraryUseCase(
+	spotifyRepo shared.SpotifyRepository,
+	cacheRepo shared.CacheRepository,
+) *ImportLibraryUseCase {
+	return &ImportLibraryUseCase{
+		spotifyRepo: spotifyRepo,
+		cacheRepo:   cacheRepo,
+	}
+}
+
+// Execute resolves every row of file to a Spotify track and, unless req is a dry run,
+// saves the matched tracks to the library or appends them to the playlist in req.
+// Ambiguous and not-found rows are only reported.
+func (uc *ImportLibraryUseCase) Execute(ctx context.Context, req ImportRequest, file io.Reader) (*ImportResult, error) {
+	// 1. Parse the file
+	rows, err := ParseImportFile(req.Format, file)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", shared.ErrValidation, err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: file has no rows", shared.ErrValidation)
+	}
+	if len(rows) > maxImportRows {
+		return nil, fmt.Errorf("%w: file has %d rows, at most %d are allowed", shared.ErrValidation, len(rows), maxImportRows)
+	}
+
+	// 2. Check the target playlist before resolving anything
+	position := 0
+	if req.PlaylistID != "" {
+		playlist, err := uc.spotifyRepo.GetPlaylist(ctx, spotifyAPI.ID(req.PlaylistID))
+		if err != nil {
+			return nil, err
+		}
+		if playlist != nil {
+			position = playlist.Tracks.Total
+		}
+	}
+
+	// 3. Resolve rows
+	result := &ImportResult{
+		DryRun:     req.DryRun,
+		PlaylistID: req.PlaylistID,
+		Total:      len(rows),
+		Rows:       make([]ImportRowResult, 0, len(rows)),
+	}
+	trackIDs := []spotifyAPI.ID{}
+	seen := make(map[spotifyAPI.ID]bool)
+	for _, row := range rows {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		rowResult, err := uc.resolve(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+		result.Rows = append(result.Rows, rowResult)
+
+		switch rowResult.Status {
+		case ImportMatched:
+			result.Matched++
+			id := spotifyAPI.ID(rowResult.TrackID)
+			if !seen[id] {
+				seen[id] = true
+				trackIDs = append(trackIDs, id)
+			}
+		case ImportAmbiguous:
+			result.Ambiguous++
+		default:
+			result.NotFound++
+		}
+	}
+
+	if req.DryRun || len(trackIDs) == 0 {
+		return result, nil
+	}
+
+	// 4. Save the matched tracks
+	if req.PlaylistID != "" {
+		if err := uc.spotifyRepo.AddTracksToPlaylist(ctx, spotifyAPI.ID(req.PlaylistID), trackIDs, position); err != nil {
+			return nil, err
+		}
+	} else if err := uc.spotifyRepo.AddTracksToLibrary(ctx, trackIDs); err != nil {
+		return nil, err
+	}
+	result.Saved = len(trackIDs)
+
+	// 5. Invalidate cache
+	if uc.cacheRepo != nil {
+		if req.PlaylistID != "" {
+			_ = uc.cacheRepo.InvalidatePlaylistTracks(ctx, spotifyAPI.ID(req.PlaylistID))
+		} else {
+			_ = uc.cacheRepo.InvalidateUserTracks(ctx)
+		}
+	}
+
+	return result, nil
+}
+
+// resolve identifies the track of a row by ID, URI or URL, then by ISRC, then by
+// name and artist search
+func (uc *ImportLibraryUseCase) resolve(ctx context.Context, row ImportRow) (ImportRowResult, error) {
+	result := ImportRowResult{Row: row.Row, Name: row.Name, Artist: row.Artist}
+
+	for _, source := range []struct{ method, value string }{
+		{"id", row.ID},
+		{"uri", row.URI},
+		{"url", row.URL},
+	} {
+		if id := parseTrackID(source.value); id != "" {
+			result.Status = ImportMatched
+			result.Method = source.method
+			result.TrackID = id
+			return result, nil
+		}
+	}
+
+	if row.ISRC != "" {
+		tracks, err := uc.spotifyRepo.SearchTracks(ctx, "isrc:"+row.ISRC, importSearchLimit)
+		if err != nil {
+			return result, err
+		}
+		if len(tracks) > 0 {
+			// An ISRC identifies a recording; prefer the release on the row's album
+			match := tracks[0]
+			if albumMatches := filterByAlbum(tracks, row.Album); len(albumMatches) > 0 {
+				match = albumMatches[0]
+			}
+			result.Status = ImportMatched
+			result.Method = "isrc"
+			result.TrackID = match.ID.String()
+			return result, nil
+		}
+	}
+
+	if row.Name == "" {
+		result.Status = ImportNotFound
+		result.Reason = "row has no track ID, URI, ISRC or name"
+		if row.ISRC != "" {
+			result.Reason = "no track found for ISRC"
+		}
+		return result, nil
+	}
+
+	tracks, err := uc.spotifyRepo.SearchTracks(ctx, searchQuery(row), importSearchLimit)
+	if err != nil {
+		return result, err
+	}
+	if len(tracks) == 0 {
+		result.Status = ImportNotFound
+		result.Reason = "no track found for name and artist"
+		return result, nil
+	}
+
+	// Keep the results with the same name and artist, then narrow by album
+	exact := []spotifyAPI.FullTrack{}
+	seen := make(map[spotifyAPI.ID]bool)
+	for _, track := range tracks {
+		if seen[track.ID] || normalize(track.Name) != normalize(row.Name) || !artistMatches(track, row.Artist) {
+			continue
+		}
+		seen[track.ID] = true
+		exact = append(exact, track)
+	}
+	if len(exact) > 1 {
+		if albumMatches := filterByAlbum(exact, row.Album); len(albumMatches) > 0 {
+			exact = albumMatches
+		}
+	}
+
+	switch {
+	case len(exact) == 1:
+		result.Status = ImportMatched
+		result.Method = "search"
+		result.TrackID = exact[0].ID.String()
+	case len(exact) > 1:
+		result.Status = ImportAmbiguous
+		result.Reason = "several tracks have this name and artist"
+		result.Candidates = candidates(exact)
+	default:
+		result.Status = ImportAmbiguous
+		result.Reason = "no exact match for name and artist"
+		result.Candidates = candidates(tracks)
+	}
+
+	return result, nil
+}
+
+// parseTrackID extracts a track ID from a bare ID, a spotify:track: URI or an open.spotify.com URL
+func parseTrackID(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return ""
+	}
+
+	if strings.HasPrefix(value, "spotify:track:") {
+		value = strings.TrimPrefix(value, "spotify:track:")
+	} else if strings.Contains(value, "/") {
+		parsed, err := url.Parse(value)
+		if err != nil {
+			return ""
+		}
+		segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+		if len(segments) < 2 || segments[len(segments)-2] != "track" {
+			return ""
+		}
+		value = segments[len(segments)-1]
+	}
+
+	if !trackIDPattern.MatchString(value) {
+		return ""
+	}
+	return value
+}
+
+// searchQuery builds a field-filtered search for the row's name and first artist
+func searchQuery(row ImportRow) string {
+	query := fmt.Sprintf(`track:"%s"`, strings.ReplaceAll(row.Name, `"`, ""))
+	if artist := firstArtist(row.Artist); artist != "" {
+		query += fmt.Sprintf(` artist:"%s"`, strings.ReplaceAll(artist, `"`, ""))
+	}
+	return query
+}
+
+func firstArtist(artist string) string {
+	for _, separator := range []string{";", ","} {
+		if i := strings.Index(artist, separator); i >= 0 {
+			return strings.TrimSpace(artist[:i])
+		}
+	}
+	return strings.TrimSpace(artist)
+}
+
+// artistMatches reports whether one of the track artists appears in the row's artist
+// field, however the file separates multiple artists
+func artistMatches(track spotifyAPI.FullTrack, artist string) bool {
+	if artist == "" {
+		return true
+	}
+	for _, a := range track.Artists {
+		if strings.Contains(normalize(artist), normalize(a.Name)) {
+			return true
+		}
+	}
+	return false
+}
+
+func filterByAlbum(tracks []spotifyAPI.FullTrack, album string) []spotifyAPI.FullTrack {
+	if album == "" {
+		return nil
+	}
+	matches := []spotifyAPI.FullTrack{}
+	for _, track := range tracks {
+		if normalize(track.Album.Name) == normalize(album) {
+			matches = append(matches, track)
+		}
+	}
+	return matches
+}
+
+func candidates(tracks []spotifyAPI.FullTrack) []ImportCandidate {
+	result := make([]ImportCandidate, 0, len(tracks))
+	for _, track := range tracks {
+		result = append(result, NewImportCandidate(track))
+	}
+	return result
+}
+
+func normalize(value string) string {
+	return strings.Join(strings.Fields(strings.ToLower(value)), " ")
+}
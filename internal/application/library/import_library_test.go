@@ -0,0 +1,166 @@
+package library
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	"github.com/RubenPari/clear-songs/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+func catalogTrack(id, name, artist, album string) spotifyAPI.FullTrack {
+	return spotifyAPI.FullTrack{
+		SimpleTrack: spotifyAPI.SimpleTrack{
+			ID:      spotifyAPI.ID(id),
+			Name:    name,
+			Artists: []spotifyAPI.SimpleArtist{{Name: artist}},
+		},
+		Album: spotifyAPI.SimpleAlbum{Name: album},
+	}
+}
+
+func TestImportLibraryUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	// Valid 22-character Spotify IDs
+	const (
+		idByID     = "4uLU6hMCjMI75M1A2tKUQC"
+		idByURI    = "7GhIk7Il098yCjg4BQjzvb"
+		idByURL    = "3n3Ppam7vgaVa1iaRUc9Lp"
+		idByISRC   = "0VjIjW4GlUZAMYd2vXMi3b"
+		idBySearch = "5ghIJDpPoe3CfHMGu71E6T"
+	)
+	file := strings.Join([]string{
+		"id,Track Name,Artist Name(s),Album Name,ISRC,Track URI,url",
+		idByID + ",Never Gonna Give You Up,Rick Astley,,,,",
+		",Bohemian Rhapsody,Queen,,,spotify:track:" + idByURI + ",",
+		",Mr. Brightside,The Killers,,,,https://open.spotify.com/intl-it/track/" + idByURL + "?si=abc",
+		",Blinding Lights,The Weeknd,,USUG11904206,,",
+		",Smells Like Teen Spirit,Nirvana,Nevermind,,,",
+		",Intro,The xx,,,,",
+		",Nonexistent Song,Nobody,,,,",
+		",,,,,,",
+	}, "\n")
+
+	setup := func() (*ImportLibraryUseCase, *mocks.MockSpotifyRepository, *mocks.MockCacheRepository) {
+		mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+		mockCacheRepo := new(mocks.MockCacheRepository)
+
+		mockSpotifyRepo.On("SearchTracks", mock.Anything, "isrc:USUG11904206", importSearchLimit).Return([]spotifyAPI.FullTrack{
+			catalogTrack(idByISRC, "Blinding Lights", "The Weeknd", "After Hours"),
+		}, nil)
+		mockSpotifyRepo.On("SearchTracks", mock.Anything, `track:"Smells Like Teen Spirit" artist:"Nirvana"`, importSearchLimit).Return([]spotifyAPI.FullTrack{
+			catalogTrack(idBySearch, "Smells Like Teen Spirit", "Nirvana", "Nevermind"),
+			catalogTrack("1111111111111111111111", "Smells Like Teen Spirit", "Nirvana", "Nirvana (Best Of)"),
+			catalogTrack("2222222222222222222222", "Smells Like Teen Spirit - Live", "Nirvana", "Live"),
+		}, nil)
+		mockSpotifyRepo.On("SearchTracks", mock.Anything, `track:"Intro" artist:"The xx"`, importSearchLimit).Return([]spotifyAPI.FullTrack{
+			catalogTrack("3333333333333333333333", "Intro", "The xx", "xx"),
+			catalogTrack("4444444444444444444444", "Intro", "The xx", "xx (Deluxe)"),
+		}, nil)
+		mockSpotifyRepo.On("SearchTracks", mock.Anything, `track:"Nonexistent Song" artist:"Nobody"`, importSearchLimit).Return([]spotifyAPI.FullTrack{}, nil)
+
+		return NewImportLibraryUseCase(mockSpotifyRepo, mockCacheRepo), mockSpotifyRepo, mockCacheRepo
+	}
+
+	t.Run("Success - should resolve rows and save the matched tracks", func(t *testing.T) {
+		useCase, mockSpotifyRepo, mockCacheRepo := setup()
+		mockSpotifyRepo.On("AddTracksToLibrary", mock.Anything, []spotifyAPI.ID{idByID, idByURI, idByURL, idByISRC, idBySearch}).Return(nil)
+		mockCacheRepo.On("InvalidateUserTracks", mock.Anything).Return(nil)
+
+		result, err := useCase.Execute(ctx, ImportRequest{Format: FormatCSV}, strings.NewReader(file))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 8, result.Total)
+		assert.Equal(t, 5, result.Matched)
+		assert.Equal(t, 1, result.Ambiguous)
+		assert.Equal(t, 2, result.NotFound)
+		assert.Equal(t, 5, result.Saved)
+
+		methods := []string{}
+		for _, row := range result.Rows[:5] {
+			methods = append(methods, row.Method)
+		}
+		assert.Equal(t, []string{"id", "uri", "url", "isrc", "search"}, methods)
+		assert.Equal(t, ImportAmbiguous, result.Rows[5].Status)
+		assert.Len(t, result.Rows[5].Candidates, 2)
+		assert.Equal(t, ImportNotFound, result.Rows[6].Status)
+		assert.Equal(t, "row has no track ID, URI, ISRC or name", result.Rows[7].Reason)
+		mockSpotifyRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - dry run should only report", func(t *testing.T) {
+		useCase, mockSpotifyRepo, _ := setup()
+
+		result, err := useCase.Execute(ctx, ImportRequest{Format: FormatCSV, DryRun: true}, strings.NewReader(file))
+
+		assert.NoError(t, err)
+		assert.True(t, result.DryRun)
+		assert.Equal(t, 0, result.Saved)
+		mockSpotifyRepo.AssertNotCalled(t, "AddTracksToLibrary", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success - should append exported JSON rows to a playlist", func(t *testing.T) {
+		useCase, mockSpotifyRepo, _ := setup()
+		playlistID := spotifyAPI.ID("playlist_1")
+		export := `{"success": true, "data": [
+			{"id": "` + idByID + `", "name": "Never Gonna Give You Up", "artists": ["Rick Astley"]},
+			{"id": "` + idByID + `", "name": "Never Gonna Give You Up", "artists": ["Rick Astley"]}
+		]}`
+
+		playlist := &spotifyAPI.FullPlaylist{}
+		playlist.Tracks.Total = 12
+		mockSpotifyRepo.On("GetPlaylist", mock.Anything, playlistID).Return(playlist, nil)
+		mockSpotifyRepo.On("AddTracksToPlaylist", mock.Anything, playlistID, []spotifyAPI.ID{idByID}, 12).Return(nil)
+
+		result, err := useCase.Execute(ctx, ImportRequest{Format: FormatJSON, PlaylistID: playlistID.String()}, strings.NewReader(export))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Matched)
+		assert.Equal(t, 1, result.Saved)
+		mockSpotifyRepo.AssertCalled(t, "AddTracksToPlaylist", mock.Anything, playlistID, []spotifyAPI.ID{idByID}, 12)
+	})
+
+	t.Run("Error - file without known columns", func(t *testing.T) {
+		useCase, _, _ := setup()
+
+		_, err := useCase.Execute(ctx, ImportRequest{Format: FormatCSV}, strings.NewReader("foo,bar\n1,2\n"))
+
+		assert.ErrorIs(t, err, shared.ErrValidation)
+	})
+}
+
+func TestParseImportFile(t *testing.T) {
+	t.Run("Success - should pick the artist by column precedence without losing it to empty fields", func(t *testing.T) {
+		file := `[{"artist_name": "", "artists": [{"name": "Queen"}, {"name": "David Bowie"}], "artist": "", "title": "Under Pressure"}]`
+
+		// Map iteration order is random, so a single run could pass by chance
+		for i := 0; i < 20; i++ {
+			rows, err := ParseImportFile(FormatJSON, strings.NewReader(file))
+
+			assert.NoError(t, err)
+			assert.Equal(t, []ImportRow{{Row: 1, Name: "Under Pressure", Artist: "Queen; David Bowie"}}, rows)
+		}
+	})
+
+	t.Run("Success - should prefer the earlier column when several are filled", func(t *testing.T) {
+		file := `[{"artist_name": "Freddie Mercury", "artist": "Queen", "track": "Other", "name": "Bohemian Rhapsody"}]`
+
+		for i := 0; i < 20; i++ {
+			rows, err := ParseImportFile(FormatJSON, strings.NewReader(file))
+
+			assert.NoError(t, err)
+			assert.Equal(t, []ImportRow{{Row: 1, Name: "Bohemian Rhapsody", Artist: "Queen"}}, rows)
+		}
+	})
+
+	t.Run("Success - an empty CSV column should not clear an earlier one", func(t *testing.T) {
+		rows, err := ParseImportFile(FormatCSV, strings.NewReader("name,artist,artist_name\nSong,Band,\n"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []ImportRow{{Row: 1, Name: "Song", Artist: "Band"}}, rows)
+	})
+}
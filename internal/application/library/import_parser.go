@@ -0,0 +1,203 @@
+package library
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ImportRow is a row of an imported file. Any of ID, URI, URL, ISRC or Name (with
+// Artist) can identify the track.
+type ImportRow struct {
+	Row  int
+	ID   string
+	URI  string
+	URL  string
+	ISRC string
+	Name string
+	// Artist is kept as written in the file, since tools separate artists differently
+	Artist string
+	Album  string
+}
+
+// importColumn is an accepted column name (lower case) and the row field it fills
+type importColumn struct {
+	name  string
+	field func(row *ImportRow) *string
+}
+
+// importColumns lists the accepted columns in order of precedence: when several
+// columns fill the same field, the first non-empty one wins
+var importColumns = []importColumn{
+	{"id", func(row *ImportRow) *string { return &row.ID }},
+	{"track_id", func(row *ImportRow) *string { return &row.ID }},
+	{"spotify_id", func(row *ImportRow) *string { return &row.ID }},
+	{"uri", func(row *ImportRow) *string { return &row.URI }},
+	{"spotify_uri", func(row *ImportRow) *string { return &row.URI }},
+	{"track_uri", func(row *ImportRow) *string { return &row.URI }},
+	{"url", func(row *ImportRow) *string { return &row.URL }},
+	{"spotify_url", func(row *ImportRow) *string { return &row.URL }},
+	{"isrc", func(row *ImportRow) *string { return &row.ISRC }},
+	{"name", func(row *ImportRow) *string { return &row.Name }},
+	{"title", func(row *ImportRow) *string { return &row.Name }},
+	{"track", func(row *ImportRow) *string { return &row.Name }},
+	{"track_name", func(row *ImportRow) *string { return &row.Name }},
+	{"artist", func(row *ImportRow) *string { return &row.Artist }},
+	{"artists", func(row *ImportRow) *string { return &row.Artist }},
+	{"artist_name", func(row *ImportRow) *string { return &row.Artist }},
+	{"album", func(row *ImportRow) *string { return &row.Album }},
+	{"album_name", func(row *ImportRow) *string { return &row.Album }},
+}
+
+// isImportColumn reports whether key is an accepted column
+func isImportColumn(key string) bool {
+	for _, column := range importColumns {
+		if column.name == key {
+			return true
+		}
+	}
+	return false
+}
+
+// fillRow copies values, keyed by column, onto row following the column precedence
+func fillRow(row *ImportRow, values map[string]string) {
+	for _, column := range importColumns {
+		if field := column.field(row); *field == "" {
+			*field = values[column.name]
+		}
+	}
+}
+
+// ParseImportFile reads the rows of a CSV file with a header line, or of a JSON
+// array of objects. The JSON array may also be wrapped in a "data" or "tracks"
+// field, as in this service's own API responses.
+func ParseImportFile(format string, r io.Reader) ([]ImportRow, error) {
+	switch format {
+	case FormatCSV:
+		return parseImportCSV(r)
+	case FormatJSON:
+		return parseImportJSON(r)
+	default:
+		return nil, fmt.Errorf("unsupported import format %q", format)
+	}
+}
+
+func parseImportCSV(r io.Reader) ([]ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("file is empty")
+		}
+		return nil, err
+	}
+
+	keys := make([]string, len(header))
+	known := false
+	for i, column := range header {
+		keys[i] = columnKey(column)
+		known = known || isImportColumn(keys[i])
+	}
+	if !known {
+		return nil, errors.New("header has none of the columns id, uri, url, isrc, name, artist")
+	}
+
+	rows := []ImportRow{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		values := make(map[string]string, len(record))
+		for i, value := range record {
+			// A repeated column only fills what the earlier ones left empty
+			if i < len(keys) && values[keys[i]] == "" {
+				values[keys[i]] = strings.TrimSpace(value)
+			}
+		}
+		row := ImportRow{Row: len(rows) + 1}
+		fillRow(&row, values)
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func parseImportJSON(r io.Reader) ([]ImportRow, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []map[string]any
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var wrapper struct {
+			Data   []map[string]any `json:"data"`
+			Tracks []map[string]any `json:"tracks"`
+		}
+		if err := json.Unmarshal(trimmed, &wrapper); err != nil {
+			return nil, err
+		}
+		objects = wrapper.Data
+		if objects == nil {
+			objects = wrapper.Tracks
+		}
+	} else if err := json.Unmarshal(trimmed, &objects); err != nil {
+		return nil, err
+	}
+
+	rows := make([]ImportRow, 0, len(objects))
+	for i, object := range objects {
+		values := make(map[string]string, len(object))
+		for key, value := range object {
+			if value := jsonValue(value); value != "" {
+				values[columnKey(key)] = value
+			}
+		}
+		row := ImportRow{Row: i + 1}
+		fillRow(&row, values)
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// jsonValue reads a JSON field as text. Lists, such as artists, are joined, taking the
+// name of object elements as in Spotify's own track objects.
+func jsonValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case []any:
+		names := make([]string, 0, len(v))
+		for _, item := range v {
+			if object, ok := item.(map[string]any); ok {
+				item = object["name"]
+			}
+			if name, ok := item.(string); ok && strings.TrimSpace(name) != "" {
+				names = append(names, strings.TrimSpace(name))
+			}
+		}
+		return strings.Join(names, "; ")
+	default:
+		return ""
+	}
+}
+
+// columnKey normalises a column name, so that headers such as "Artist Name(s)" are recognised
+func columnKey(column string) string {
+	column = strings.TrimPrefix(column, "\ufeff")
+	column = strings.ReplaceAll(column, "(s)", "")
+	return strings.Join(strings.Fields(strings.ToLower(column)), "_")
+}
@@ -0,0 +1,245 @@
+package library
+
+import (
+	"context"
+
+	appBackup "github.com/RubenPari/clear-songs/internal/application/backup"
+	appOperation "github.com/RubenPari/clear-songs/internal/application/operation"
+	"github.com/RubenPari/clear-songs/internal/domain/backup"
+	"github.com/RubenPari/clear-songs/internal/domain/job"
+	"github.com/RubenPari/clear-songs/internal/domain/operation"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+// MergeDuplicatesUseCase handles the business logic for keeping a single version of
+// each duplicated song
+type MergeDuplicatesUseCase struct {
+	spotifyRepo      shared.SpotifyRepository
+	cacheRepo        shared.CacheRepository
+	backups          *appBackup.Writer
+	findDuplicatesUC *FindDuplicatesUseCase
+	recorder         *appOperation.Recorder
+}
+
+// NewMergeDuplicatesUseCase creates a new MergeDuplicatesUseCase
+func NewMergeDuplicatesUseCase(
+	spotifyRepo shared.SpotifyRepository,
+	cacheRepo shared.CacheRepository,
+	backups *appBackup.Writer,
+	findDuplicatesUC *FindDuplicatesUseCase,
+	recorder *appOperation.Recorder,
+) *MergeDuplicatesUseCase {
+	return &MergeDuplicatesUseCase{
+		spotifyRepo:      spotifyRepo,
+		cacheRepo:        cacheRepo,
+		backups:          backups,
+		findDuplicatesUC: findDuplicatesUC,
+		recorder:         recorder,
+	}
+}
+
+// Execute removes the versions that are not kept from the library and from every
+// playlist owned by the user. In each playlist the kept version is inserted where the
+// first removed version of the song was, unless the playlist already holds it. Removed
+// tracks are backed up first, and the library and each playlist are recorded as separate
+// operations so they can be undone.
+func (uc *MergeDuplicatesUseCase) Execute(ctx context.Context, req MergeDuplicatesRequest) (*MergeDuplicatesResult, error) {
+	// 1. Find the duplicates
+	groups, err := uc.findDuplicatesUC.find(ctx, req.DuplicatesRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MergeDuplicatesResult{
+		DryRun:    req.DryRun,
+		Playlists: []MergePlaylistResult{},
+		Groups:    make([]DuplicateGroupResponse, 0, len(groups)),
+	}
+	// removed maps each version that is not kept to the version kept in its place
+	removed := make(map[spotifyAPI.ID]spotifyAPI.ID)
+	var tracks []spotifyAPI.FullTrack
+	var trackIDs []spotifyAPI.ID
+	for _, group := range groups {
+		result.Groups = append(result.Groups, NewDuplicateGroupResponse(group))
+		for _, version := range group.Remove {
+			removed[version.ID] = group.Keep.ID
+			tracks = append(tracks, version.FullTrack)
+			trackIDs = append(trackIDs, version.ID)
+		}
+	}
+	if len(trackIDs) == 0 {
+		return result, nil
+	}
+
+	// 2. Find the duplicates in owned playlists
+	playlists, err := uc.ownedPlaylists(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	type playlistRemoval struct {
+		playlist spotifyAPI.SimplePlaylist
+		items    []spotifyAPI.PlaylistTrack
+		tracks   []operation.PlaylistTrack
+		added    []operation.PlaylistTrack
+	}
+	var removals []playlistRemoval
+	for _, playlist := range playlists {
+		items, err := uc.spotifyRepo.GetAllPlaylistTracks(ctx, playlist.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		present := make(map[spotifyAPI.ID]bool, len(items))
+		for _, item := range items {
+			present[item.Track.ID] = true
+		}
+
+		removal := playlistRemoval{playlist: playlist}
+		for position, item := range items {
+			keep, ok := removed[item.Track.ID]
+			if !ok {
+				continue
+			}
+
+			// The kept version goes where the removed one was, counted in the playlist
+			// without the removed versions and with the kept ones inserted so far
+			if !present[keep] {
+				present[keep] = true
+				removal.added = append(removal.added, operation.PlaylistTrack{
+					TrackID:  keep.String(),
+					Position: position - len(removal.items) + len(removal.added),
+				})
+			}
+
+			removal.items = append(removal.items, item)
+			removal.tracks = append(removal.tracks, operation.PlaylistTrack{
+				TrackID:  item.Track.ID.String(),
+				Position: position,
+			})
+		}
+		if len(removal.items) == 0 {
+			continue
+		}
+
+		removals = append(removals, removal)
+		result.Playlists = append(result.Playlists, MergePlaylistResult{
+			ID:            playlist.ID.String(),
+			Name:          playlist.Name,
+			TracksRemoved: len(removal.items),
+			TracksAdded:   len(removal.added),
+		})
+	}
+
+	result.LibraryTracksRemoved = len(trackIDs)
+	if req.DryRun {
+		return result, nil
+	}
+	job.ReportTotal(ctx, len(trackIDs))
+
+	libraryOp := &operation.Operation{
+		Kind:            operation.KindMergeDuplicates,
+		Params:          map[string]string{"prefer": req.Prefer},
+		LibraryTrackIDs: appOperation.TrackIDs(trackIDs),
+	}
+
+	// 3. Back up the duplicates before removing them
+	if err := uc.backups.Save(ctx, backup.FromFullTracks(appOperation.BackupOrigin(ctx, libraryOp), tracks)); err != nil {
+		return nil, err
+	}
+
+	// 4. Delete the duplicates from the library
+	if err := uc.spotifyRepo.DeleteTracksFromLibrary(ctx, trackIDs); err != nil {
+		return nil, err
+	}
+	if uc.cacheRepo != nil {
+		_ = uc.cacheRepo.InvalidateUserTracks(ctx)
+	}
+	uc.recorder.Record(ctx, libraryOp)
+
+	// 5. Replace the duplicates in each owned playlist with the kept versions
+	for _, removal := range removals {
+		playlistID := removal.playlist.ID
+		job.ReportTotal(ctx, len(removal.items))
+		playlistOp := &operation.Operation{
+			Kind:           operation.KindMergeDuplicates,
+			Params:         map[string]string{"prefer": req.Prefer, "playlist_id": playlistID.String()},
+			PlaylistID:     playlistID.String(),
+			PlaylistTracks: removal.tracks,
+		}
+
+		positions := make([]int, len(removal.tracks))
+		for i, track := range removal.tracks {
+			positions[i] = track.Position
+		}
+		backups := backup.FromPlaylistTracks(appOperation.BackupOrigin(ctx, playlistOp), removal.items, positions)
+		if err := uc.backups.Save(ctx, backups); err != nil {
+			return nil, err
+		}
+
+		if err := uc.spotifyRepo.DeletePlaylistTracks(ctx, playlistID, distinctIDs(removal.items)); err != nil {
+			return nil, err
+		}
+		if uc.cacheRepo != nil {
+			_ = uc.cacheRepo.InvalidatePlaylistTracks(ctx, playlistID)
+		}
+
+		// The removal is recorded even if an insertion fails, with the kept versions
+		// inserted so far, so that it can still be undone
+		err := uc.insertKeptVersions(ctx, playlistID, removal.added, playlistOp)
+		uc.recorder.Record(ctx, playlistOp)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// insertKeptVersions inserts the kept versions at their positions, in ascending order,
+// adding each one to op once it is in the playlist
+func (uc *MergeDuplicatesUseCase) insertKeptVersions(ctx context.Context, playlistID spotifyAPI.ID, added []operation.PlaylistTrack, op *operation.Operation) error {
+	for _, track := range added {
+		if err := uc.spotifyRepo.AddTracksToPlaylist(ctx, playlistID, []spotifyAPI.ID{spotifyAPI.ID(track.TrackID)}, track.Position); err != nil {
+			return err
+		}
+		op.PlaylistTracksAdded = append(op.PlaylistTracksAdded, track)
+	}
+	return nil
+}
+
+// ownedPlaylists returns the playlists of the user that can be modified
+func (uc *MergeDuplicatesUseCase) ownedPlaylists(ctx context.Context) ([]spotifyAPI.SimplePlaylist, error) {
+	user, err := uc.spotifyRepo.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	playlists, err := uc.spotifyRepo.GetAllUserPlaylists(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	owned := make([]spotifyAPI.SimplePlaylist, 0, len(playlists))
+	for _, playlist := range playlists {
+		if playlist.Owner.ID == user.ID {
+			owned = append(owned, playlist)
+		}
+	}
+	return owned, nil
+}
+
+// distinctIDs returns the IDs of playlist items, once each: removing an ID removes
+// every occurrence
+func distinctIDs(items []spotifyAPI.PlaylistTrack) []spotifyAPI.ID {
+	seen := make(map[spotifyAPI.ID]bool, len(items))
+	ids := make([]spotifyAPI.ID, 0, len(items))
+	for _, item := range items {
+		if !seen[item.Track.ID] {
+			seen[item.Track.ID] = true
+			ids = append(ids, item.Track.ID)
+		}
+	}
+	return ids
+}
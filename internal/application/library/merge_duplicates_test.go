@@ -0,0 +1,251 @@
+package library
+
+import (
+	"context"
+	"testing"
+
+	appBackup "github.com/RubenPari/clear-songs/internal/application/backup"
+	appOperation "github.com/RubenPari/clear-songs/internal/application/operation"
+	"github.com/RubenPari/clear-songs/internal/application/usertracks"
+	"github.com/RubenPari/clear-songs/internal/domain/operation"
+	"github.com/RubenPari/clear-songs/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+func savedVersion(id, name, albumType, isrc string, durationMs int, addedAt string) spotifyAPI.SavedTrack {
+	return spotifyAPI.SavedTrack{
+		AddedAt: addedAt,
+		FullTrack: spotifyAPI.FullTrack{
+			SimpleTrack: spotifyAPI.SimpleTrack{
+				ID:       spotifyAPI.ID(id),
+				Name:     name,
+				Artists:  []spotifyAPI.SimpleArtist{{ID: "queen", Name: "Queen"}},
+				Duration: durationMs,
+			},
+			Album:       spotifyAPI.SimpleAlbum{Name: name + " " + albumType, AlbumType: albumType},
+			ExternalIDs: map[string]string{"isrc": isrc},
+		},
+	}
+}
+
+func duplicateLibrary() []spotifyAPI.SavedTrack {
+	return []spotifyAPI.SavedTrack{
+		// Same recording on an album and a compilation
+		savedVersion("album", "Bohemian Rhapsody", "album", "GBUM71029604", 354000, "2020-01-01T00:00:00Z"),
+		savedVersion("compilation", "Bohemian Rhapsody", "compilation", "GBUM71029604", 354000, "2019-01-01T00:00:00Z"),
+		// Remaster with a different ISRC and a slightly different duration
+		savedVersion("single", "Don't Stop Me Now", "single", "GBUM71029605", 209000, "2018-01-01T00:00:00Z"),
+		savedVersion("remaster", "Don't Stop Me Now - Remastered 2011", "album", "GBUM71108600", 210500, "2021-01-01T00:00:00Z"),
+		// Live version: same name but too far in duration
+		savedVersion("live", "Don't Stop Me Now", "album", "GBUM70000001", 260000, "2021-01-01T00:00:00Z"),
+		savedVersion("unique", "Somebody to Love", "album", "GBUM71029606", 296000, "2020-01-01T00:00:00Z"),
+	}
+}
+
+func TestFindDuplicatesUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+	mockSpotifyRepo.On("GetAllUserTracks", ctx).Return(duplicateLibrary(), nil)
+
+	useCase := NewFindDuplicatesUseCase(mockSpotifyRepo, nil, usertracks.NewProvider(mockSpotifyRepo, nil, nil, 0))
+
+	t.Run("Groups by ISRC and by metadata and keeps the original album", func(t *testing.T) {
+		result, err := useCase.Execute(ctx, DuplicatesRequest{ToleranceMs: 2000})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.TotalGroups)
+		assert.Equal(t, 2, result.TotalDuplicates)
+
+		groups := make(map[string]DuplicateGroupResponse)
+		for _, group := range result.Groups {
+			groups[group.Keep.ID] = group
+		}
+
+		isrcGroup, ok := groups["album"]
+		assert.True(t, ok)
+		assert.Equal(t, "isrc", isrcGroup.MatchedBy)
+		assert.Equal(t, "compilation", isrcGroup.Remove[0].ID)
+
+		metadataGroup, ok := groups["remaster"]
+		assert.True(t, ok)
+		assert.Equal(t, "metadata", metadataGroup.MatchedBy)
+		assert.Equal(t, "single", metadataGroup.Remove[0].ID)
+	})
+
+	t.Run("Preferences change the version kept", func(t *testing.T) {
+		result, err := useCase.Execute(ctx, DuplicatesRequest{Prefer: "oldest", ToleranceMs: 2000})
+
+		assert.NoError(t, err)
+		kept := []string{result.Groups[0].Keep.ID, result.Groups[1].Keep.ID}
+		assert.ElementsMatch(t, []string{"compilation", "single"}, kept)
+	})
+
+	t.Run("Unknown preference is a validation error", func(t *testing.T) {
+		_, err := useCase.Execute(ctx, DuplicatesRequest{Prefer: "shortest"})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestMergeDuplicatesUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	removedIDs := []spotifyAPI.ID{"compilation", "single"}
+
+	setup := func() (*MergeDuplicatesUseCase, *mocks.MockSpotifyRepository, *mocks.MockDatabaseRepository, *mocks.MockOperationRepository) {
+		mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+		mockDatabaseRepo := new(mocks.MockDatabaseRepository)
+		mockOperationRepo := new(mocks.MockOperationRepository)
+
+		mockSpotifyRepo.On("GetAllUserTracks", ctx).Return(duplicateLibrary(), nil)
+		mockSpotifyRepo.On("GetCurrentUser", ctx).Return(&spotifyAPI.PrivateUser{User: spotifyAPI.User{ID: "me"}}, nil)
+		mockSpotifyRepo.On("GetAllUserPlaylists", ctx).Return([]spotifyAPI.SimplePlaylist{
+			{ID: "mine", Name: "Mine", Owner: spotifyAPI.User{ID: "me"}},
+			{ID: "clean", Name: "Clean", Owner: spotifyAPI.User{ID: "me"}},
+			{ID: "solo", Name: "Solo", Owner: spotifyAPI.User{ID: "me"}},
+			{ID: "both", Name: "Both", Owner: spotifyAPI.User{ID: "me"}},
+			{ID: "followed", Name: "Followed", Owner: spotifyAPI.User{ID: "someone"}},
+		}, nil)
+		mockSpotifyRepo.On("GetAllPlaylistTracks", ctx, spotifyAPI.ID("mine")).Return([]spotifyAPI.PlaylistTrack{
+			{Track: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{ID: "unique"}}},
+			{Track: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{ID: "single"}}},
+			{Track: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{ID: "album"}}},
+			{Track: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{ID: "single"}}},
+		}, nil)
+		mockSpotifyRepo.On("GetAllPlaylistTracks", ctx, spotifyAPI.ID("clean")).Return([]spotifyAPI.PlaylistTrack{
+			{Track: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{ID: "unique"}}},
+		}, nil)
+		// Holds only a removed version of the song
+		mockSpotifyRepo.On("GetAllPlaylistTracks", ctx, spotifyAPI.ID("solo")).Return([]spotifyAPI.PlaylistTrack{
+			{Track: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{ID: "compilation"}}},
+		}, nil)
+		// Already holds the kept version
+		mockSpotifyRepo.On("GetAllPlaylistTracks", ctx, spotifyAPI.ID("both")).Return([]spotifyAPI.PlaylistTrack{
+			{Track: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{ID: "compilation"}}},
+			{Track: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{ID: "album"}}},
+		}, nil)
+
+		useCase := NewMergeDuplicatesUseCase(
+			mockSpotifyRepo,
+			nil,
+			appBackup.NewWriter(mockDatabaseRepo, appBackup.PolicyBestEffort),
+			NewFindDuplicatesUseCase(mockSpotifyRepo, nil, usertracks.NewProvider(mockSpotifyRepo, nil, nil, 0)),
+			appOperation.NewRecorder(mockOperationRepo),
+		)
+		return useCase, mockSpotifyRepo, mockDatabaseRepo, mockOperationRepo
+	}
+
+	t.Run("Dry run reports the library and playlist removals", func(t *testing.T) {
+		useCase, mockSpotifyRepo, _, _ := setup()
+
+		result, err := useCase.Execute(ctx, MergeDuplicatesRequest{
+			DuplicatesRequest: DuplicatesRequest{ToleranceMs: 2000},
+			DryRun:            true,
+		})
+
+		assert.NoError(t, err)
+		assert.True(t, result.DryRun)
+		assert.Equal(t, 2, result.LibraryTracksRemoved)
+		assert.Equal(t, []MergePlaylistResult{
+			{ID: "mine", Name: "Mine", TracksRemoved: 2, TracksAdded: 1},
+			{ID: "solo", Name: "Solo", TracksRemoved: 1, TracksAdded: 1},
+			{ID: "both", Name: "Both", TracksRemoved: 1, TracksAdded: 0},
+		}, result.Playlists)
+		mockSpotifyRepo.AssertNotCalled(t, "DeleteTracksFromLibrary", mock.Anything, mock.Anything)
+		mockSpotifyRepo.AssertNotCalled(t, "DeletePlaylistTracks", mock.Anything, mock.Anything, mock.Anything)
+		mockSpotifyRepo.AssertNotCalled(t, "AddTracksToPlaylist", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockSpotifyRepo.AssertNotCalled(t, "GetAllPlaylistTracks", ctx, spotifyAPI.ID("followed"))
+	})
+
+	t.Run("Merge backs up, removes and records each change", func(t *testing.T) {
+		useCase, mockSpotifyRepo, mockDatabaseRepo, mockOperationRepo := setup()
+		mockDatabaseRepo.On("SaveTracksBackup", mock.Anything, mock.Anything).Return(nil)
+		var deleted []spotifyAPI.ID
+		mockSpotifyRepo.On("DeleteTracksFromLibrary", ctx, mock.Anything).Run(func(args mock.Arguments) {
+			deleted = args.Get(1).([]spotifyAPI.ID)
+		}).Return(nil)
+		mockSpotifyRepo.On("DeletePlaylistTracks", ctx, spotifyAPI.ID("mine"), []spotifyAPI.ID{"single"}).Return(nil)
+		mockSpotifyRepo.On("AddTracksToPlaylist", ctx, spotifyAPI.ID("mine"), []spotifyAPI.ID{"remaster"}, 1).Return(nil)
+		mockSpotifyRepo.On("DeletePlaylistTracks", ctx, spotifyAPI.ID("solo"), []spotifyAPI.ID{"compilation"}).Return(nil)
+		mockSpotifyRepo.On("AddTracksToPlaylist", ctx, spotifyAPI.ID("solo"), []spotifyAPI.ID{"album"}, 0).Return(nil)
+		mockSpotifyRepo.On("DeletePlaylistTracks", ctx, spotifyAPI.ID("both"), []spotifyAPI.ID{"compilation"}).Return(nil)
+
+		var recorded []*operation.Operation
+		mockOperationRepo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			recorded = append(recorded, args.Get(1).(*operation.Operation))
+		}).Return(nil)
+
+		result, err := useCase.Execute(ctx, MergeDuplicatesRequest{
+			DuplicatesRequest: DuplicatesRequest{ToleranceMs: 2000},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.LibraryTracksRemoved)
+		assert.ElementsMatch(t, removedIDs, deleted)
+		// Once for the library, once per playlist
+		mockDatabaseRepo.AssertNumberOfCalls(t, "SaveTracksBackup", 4)
+		mockSpotifyRepo.AssertExpectations(t)
+
+		assert.Len(t, recorded, 4)
+		assert.Equal(t, operation.KindMergeDuplicates, recorded[0].Kind)
+		assert.ElementsMatch(t, []string{"compilation", "single"}, recorded[0].LibraryTrackIDs)
+		assert.Equal(t, "mine", recorded[1].PlaylistID)
+		assert.Equal(t, []operation.PlaylistTrack{
+			{TrackID: "single", Position: 1},
+			{TrackID: "single", Position: 3},
+		}, recorded[1].PlaylistTracks)
+		assert.Equal(t, []operation.PlaylistTrack{{TrackID: "remaster", Position: 1}}, recorded[1].PlaylistTracksAdded)
+		assert.Empty(t, recorded[3].PlaylistTracksAdded)
+	})
+
+	t.Run("A playlist holding only a removed version gets the kept version instead", func(t *testing.T) {
+		useCase, mockSpotifyRepo, mockDatabaseRepo, mockOperationRepo := setup()
+		mockDatabaseRepo.On("SaveTracksBackup", mock.Anything, mock.Anything).Return(nil)
+		mockSpotifyRepo.On("DeleteTracksFromLibrary", ctx, mock.Anything).Return(nil)
+		mockSpotifyRepo.On("DeletePlaylistTracks", ctx, mock.Anything, mock.Anything).Return(nil)
+		mockSpotifyRepo.On("AddTracksToPlaylist", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		var recorded []*operation.Operation
+		mockOperationRepo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			recorded = append(recorded, args.Get(1).(*operation.Operation))
+		}).Return(nil)
+
+		result, err := useCase.Execute(ctx, MergeDuplicatesRequest{
+			DuplicatesRequest: DuplicatesRequest{ToleranceMs: 2000},
+		})
+
+		assert.NoError(t, err)
+		assert.Contains(t, result.Playlists, MergePlaylistResult{ID: "solo", Name: "Solo", TracksRemoved: 1, TracksAdded: 1})
+		mockSpotifyRepo.AssertCalled(t, "DeletePlaylistTracks", ctx, spotifyAPI.ID("solo"), []spotifyAPI.ID{"compilation"})
+		mockSpotifyRepo.AssertCalled(t, "AddTracksToPlaylist", ctx, spotifyAPI.ID("solo"), []spotifyAPI.ID{"album"}, 0)
+		mockSpotifyRepo.AssertNotCalled(t, "AddTracksToPlaylist", ctx, spotifyAPI.ID("both"), mock.Anything, mock.Anything)
+
+		assert.Len(t, recorded, 4)
+		assert.Equal(t, "solo", recorded[2].PlaylistID)
+		assert.Equal(t, []operation.PlaylistTrack{{TrackID: "compilation", Position: 0}}, recorded[2].PlaylistTracks)
+		assert.Equal(t, []operation.PlaylistTrack{{TrackID: "album", Position: 0}}, recorded[2].PlaylistTracksAdded)
+	})
+
+	t.Run("A failed insertion still records the playlist removal", func(t *testing.T) {
+		useCase, mockSpotifyRepo, mockDatabaseRepo, mockOperationRepo := setup()
+		mockDatabaseRepo.On("SaveTracksBackup", mock.Anything, mock.Anything).Return(nil)
+		mockSpotifyRepo.On("DeleteTracksFromLibrary", ctx, mock.Anything).Return(nil)
+		mockSpotifyRepo.On("DeletePlaylistTracks", ctx, mock.Anything, mock.Anything).Return(nil)
+		mockSpotifyRepo.On("AddTracksToPlaylist", ctx, spotifyAPI.ID("mine"), mock.Anything, mock.Anything).Return(assert.AnError)
+
+		var recorded []*operation.Operation
+		mockOperationRepo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			recorded = append(recorded, args.Get(1).(*operation.Operation))
+		}).Return(nil)
+
+		_, err := useCase.Execute(ctx, MergeDuplicatesRequest{
+			DuplicatesRequest: DuplicatesRequest{ToleranceMs: 2000},
+		})
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Len(t, recorded, 2)
+		assert.Equal(t, "mine", recorded[1].PlaylistID)
+		assert.Empty(t, recorded[1].PlaylistTracksAdded)
+	})
+}
@@ -0,0 +1,52 @@
+package operation
+
+import (
+	"time"
+
+	domainOperation "github.com/RubenPari/clear-songs/internal/domain/operation"
+)
+
+// OperationResponse represents a recorded operation in API responses
+type OperationResponse struct {
+	ID              string                          `json:"id"`
+	Kind            string                          `json:"kind"`
+	Params          map[string]string               `json:"params"`
+	LibraryTrackIDs []string                        `json:"library_track_ids"`
+	PlaylistID      string                          `json:"playlist_id,omitempty"`
+	PlaylistTracks  []domainOperation.PlaylistTrack `json:"playlist_tracks"`
+	// PlaylistTracksAdded are the tracks inserted in place of the removed ones
+	PlaylistTracksAdded []domainOperation.PlaylistTrack `json:"playlist_tracks_added,omitempty"`
+	CreatedAt           time.Time                       `json:"created_at"`
+	UndoneAt            *time.Time                      `json:"undone_at,omitempty"`
+}
+
+// NewOperationResponse converts an operation to its API representation
+func NewOperationResponse(op *domainOperation.Operation) OperationResponse {
+	response := OperationResponse{
+		ID:              op.ID,
+		Kind:            string(op.Kind),
+		Params:          op.Params,
+		LibraryTrackIDs: op.LibraryTrackIDs,
+		PlaylistID:      op.PlaylistID,
+		PlaylistTracks:  op.PlaylistTracks,
+		CreatedAt:       op.CreatedAt,
+		UndoneAt:        op.UndoneAt,
+
+		PlaylistTracksAdded: op.PlaylistTracksAdded,
+	}
+	if response.LibraryTrackIDs == nil {
+		response.LibraryTrackIDs = []string{}
+	}
+	if response.PlaylistTracks == nil {
+		response.PlaylistTracks = []domainOperation.PlaylistTrack{}
+	}
+	return response
+}
+
+// UndoResult summarises an undo
+type UndoResult struct {
+	OperationID            string `json:"operation_id"`
+	LibraryTracksRestored  int    `json:"library_tracks_restored"`
+	PlaylistTracksRestored int    `json:"playlist_tracks_restored"`
+	AlbumsRestored         int    `json:"albums_restored"`
+}
@@ -0,0 +1,25 @@
+package operation
+
+import (
+	"context"
+
+	domainOperation "github.com/RubenPari/clear-songs/internal/domain/operation"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+)
+
+// ListOperationsUseCase handles the business logic for listing the owner's recent operations
+type ListOperationsUseCase struct {
+	operationRepo domainOperation.Repository
+}
+
+// NewListOperationsUseCase creates a new ListOperationsUseCase
+func NewListOperationsUseCase(operationRepo domainOperation.Repository) *ListOperationsUseCase {
+	return &ListOperationsUseCase{
+		operationRepo: operationRepo,
+	}
+}
+
+// Execute returns the most recent operations of the owner in ctx, newest first
+func (uc *ListOperationsUseCase) Execute(ctx context.Context, limit int) ([]domainOperation.Operation, error) {
+	return uc.operationRepo.ListByOwner(ctx, shared.OwnerFromContext(ctx), limit)
+}
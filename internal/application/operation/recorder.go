@@ -0,0 +1,80 @@
+package operation
+
+import (
+	"context"
+	"log"
+
+	"github.com/RubenPari/clear-songs/internal/domain/backup"
+	domainOperation "github.com/RubenPari/clear-songs/internal/domain/operation"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	"github.com/google/uuid"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+// Recorder stores the operations performed by destructive use cases.
+// A nil Recorder records nothing.
+type Recorder struct {
+	repo domainOperation.Repository
+}
+
+// NewRecorder creates a new operation recorder
+func NewRecorder(repo domainOperation.Repository) *Recorder {
+	return &Recorder{repo: repo}
+}
+
+// Record stores op for the owner in ctx, keeping the ID given to it by BackupOrigin.
+// Failures are only logged: the change on Spotify already happened and the request
+// must still report it.
+func (r *Recorder) Record(ctx context.Context, op *domainOperation.Operation) {
+	if r == nil || r.repo == nil {
+		return
+	}
+
+	if op.ID == "" {
+		op.ID = uuid.NewString()
+	}
+	op.Owner = shared.OwnerFromContext(ctx)
+
+	if err := r.repo.Create(context.WithoutCancel(ctx), op); err != nil {
+		log.Printf("WARNING: Failed to record %s operation: %v", op.Kind, err)
+	}
+}
+
+// BackupOrigin describes op as the origin of the tracks it backs up before removing
+// them. It assigns op the ID it will be recorded with, so backups point to it.
+func BackupOrigin(ctx context.Context, op *domainOperation.Operation) backup.Origin {
+	if op.ID == "" {
+		op.ID = uuid.NewString()
+	}
+	return backup.Origin{
+		Owner:       shared.OwnerFromContext(ctx),
+		OperationID: op.ID,
+		Reason:      string(op.Kind),
+		PlaylistID:  op.PlaylistID,
+	}
+}
+
+// TrackIDs converts Spotify IDs for an operation record
+func TrackIDs(ids []spotifyAPI.ID) []string {
+	trackIDs := make([]string, len(ids))
+	for i, id := range ids {
+		trackIDs[i] = id.String()
+	}
+	return trackIDs
+}
+
+// PlaylistPositions records the position of each playlist item before removal.
+// Items without an ID (local files) cannot be re-added and are skipped.
+func PlaylistPositions(tracks []spotifyAPI.PlaylistTrack) []domainOperation.PlaylistTrack {
+	positions := make([]domainOperation.PlaylistTrack, 0, len(tracks))
+	for i, track := range tracks {
+		if track.Track.ID == "" {
+			continue
+		}
+		positions = append(positions, domainOperation.PlaylistTrack{
+			TrackID:  track.Track.ID.String(),
+			Position: i,
+		})
+	}
+	return positions
+}
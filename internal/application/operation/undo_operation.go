@@ -0,0 +1,194 @@
+package operation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	domainOperation "github.com/RubenPari/clear-songs/internal/domain/operation"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+// UndoOperationUseCase handles the business logic for reverting a recorded operation
+type UndoOperationUseCase struct {
+	spotifyRepo   shared.SpotifyRepository
+	cacheRepo     shared.CacheRepository
+	operationRepo domainOperation.Repository
+}
+
+// NewUndoOperationUseCase creates a new UndoOperationUseCase
+func NewUndoOperationUseCase(
+	spotifyRepo shared.SpotifyRepository,
+	cacheRepo shared.CacheRepository,
+	operationRepo domainOperation.Repository,
+) *UndoOperationUseCase {
+	return &UndoOperationUseCase{
+		spotifyRepo:   spotifyRepo,
+		cacheRepo:     cacheRepo,
+		operationRepo: operationRepo,
+	}
+}
+
+// Execute re-adds the tracks an operation removed from the library, removes the tracks
+// it inserted in a playlist, re-inserts the tracks it removed from that playlist at their
+// original positions and re-saves the album it removed from the saved albums.
+//
+// An undo that fails part way can be retried: saving tracks already in the library
+// changes nothing, and the playlist tracks re-inserted so far are recorded on the
+// operation after every request, so the retry resumes after them. The undo is claimed
+// on the operation before Spotify is touched, so a concurrent undo of the same operation
+// fails with shared.ErrConflict instead of re-inserting the tracks a second time.
+func (uc *UndoOperationUseCase) Execute(ctx context.Context, operationID string) (_ *UndoResult, err error) {
+	// 1. Get the operation (only its owner may undo it)
+	op, err := uc.operationRepo.GetByID(ctx, operationID)
+	if err != nil {
+		return nil, err
+	}
+	if op.Owner != shared.OwnerFromContext(ctx) {
+		return nil, shared.ErrNotFound
+	}
+	if op.IsUndone() {
+		return nil, fmt.Errorf("%w: operation already undone", shared.ErrValidation)
+	}
+
+	// 2. Claim the undo, releasing the claim if it fails so that it can be retried
+	if err := uc.operationRepo.ClaimUndo(ctx, op.ID, time.Now()); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = uc.operationRepo.ReleaseUndo(context.WithoutCancel(ctx), op.ID)
+		}
+	}()
+
+	result := &UndoResult{OperationID: op.ID}
+
+	// 3. Re-add library tracks
+	if len(op.LibraryTrackIDs) > 0 {
+		if err := uc.spotifyRepo.AddTracksToLibrary(ctx, toSpotifyIDs(op.LibraryTrackIDs)); err != nil {
+			return nil, err
+		}
+		result.LibraryTracksRestored = len(op.LibraryTrackIDs)
+	}
+
+	// 4. Re-save the removed album
+	if albumID := op.RemovedAlbumID(); albumID != "" {
+		if err := uc.spotifyRepo.SaveAlbumsToLibrary(ctx, []spotifyAPI.ID{spotifyAPI.ID(albumID)}); err != nil {
+			return nil, err
+		}
+		result.AlbumsRestored = 1
+	}
+
+	// 5. Remove the tracks inserted in place of the removed ones, unless a previous attempt
+	// already got past this step. The operation only inserted tracks missing from the
+	// playlist, so removing them by ID removes no track that was there before it.
+	if op.PlaylistID != "" && len(op.PlaylistTracksAdded) > 0 && op.PlaylistTracksRestored == 0 {
+		added := make([]spotifyAPI.ID, 0, len(op.PlaylistTracksAdded))
+		for _, track := range op.PlaylistTracksAdded {
+			added = append(added, spotifyAPI.ID(track.TrackID))
+		}
+		if err := uc.spotifyRepo.DeletePlaylistTracks(ctx, spotifyAPI.ID(op.PlaylistID), added); err != nil {
+			return nil, err
+		}
+	}
+
+	// 6. Re-insert playlist tracks at their original positions, after those a previous
+	// attempt already re-inserted
+	if op.PlaylistID != "" && len(op.PlaylistTracks) > 0 {
+		progress := func(restored int) error {
+			return uc.operationRepo.MarkPlaylistTracksRestored(context.WithoutCancel(ctx), op.ID, restored)
+		}
+		err := restorePlaylistTracks(ctx, uc.spotifyRepo, spotifyAPI.ID(op.PlaylistID), op.PlaylistTracks, op.PlaylistTracksRestored, progress)
+		if err != nil {
+			return nil, err
+		}
+		result.PlaylistTracksRestored = len(op.PlaylistTracks)
+	}
+
+	// 7. Mark the operation undone
+	if err := uc.operationRepo.MarkUndone(ctx, op.ID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	// 8. Invalidate cache
+	if uc.cacheRepo != nil {
+		_ = uc.cacheRepo.InvalidateUserTracks(ctx)
+		if op.PlaylistID != "" {
+			_ = uc.cacheRepo.InvalidatePlaylistTracks(ctx, spotifyAPI.ID(op.PlaylistID))
+		}
+	}
+
+	return result, nil
+}
+
+// RestorePlaylistTracks inserts tracks at their original positions, in ascending position
+// order with one request per run of consecutive positions. Positions past the current end
+// of the playlist are appended.
+func RestorePlaylistTracks(ctx context.Context, spotifyRepo shared.SpotifyRepository, playlistID spotifyAPI.ID, tracks []domainOperation.PlaylistTrack) error {
+	return restorePlaylistTracks(ctx, spotifyRepo, playlistID, tracks, 0, nil)
+}
+
+// restorePlaylistTracks is RestorePlaylistTracks skipping the first done tracks in
+// position order, which are already back in the playlist. After every request, progress
+// (if set) is told how many tracks are back in total.
+func restorePlaylistTracks(
+	ctx context.Context,
+	spotifyRepo shared.SpotifyRepository,
+	playlistID spotifyAPI.ID,
+	tracks []domainOperation.PlaylistTrack,
+	done int,
+	progress func(restored int) error,
+) error {
+	playlist, err := spotifyRepo.GetPlaylist(ctx, playlistID)
+	if err != nil {
+		return err
+	}
+	length := 0
+	if playlist != nil {
+		length = playlist.Tracks.Total
+	}
+
+	sorted := make([]domainOperation.PlaylistTrack, len(tracks))
+	copy(sorted, tracks)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Position < sorted[j].Position
+	})
+
+	for start := min(done, len(sorted)); start < len(sorted); {
+		end := start + 1
+		for end < len(sorted) && sorted[end].Position == sorted[end-1].Position+1 {
+			end++
+		}
+
+		run := make([]spotifyAPI.ID, 0, end-start)
+		for _, track := range sorted[start:end] {
+			run = append(run, spotifyAPI.ID(track.TrackID))
+		}
+
+		position := min(sorted[start].Position, length)
+		if err := spotifyRepo.AddTracksToPlaylist(ctx, playlistID, run, position); err != nil {
+			return err
+		}
+
+		length += len(run)
+		start = end
+
+		if progress != nil {
+			if err := progress(end); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func toSpotifyIDs(ids []string) []spotifyAPI.ID {
+	spotifyIDs := make([]spotifyAPI.ID, len(ids))
+	for i, id := range ids {
+		spotifyIDs[i] = spotifyAPI.ID(id)
+	}
+	return spotifyIDs
+}
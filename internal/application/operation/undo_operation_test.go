@@ -0,0 +1,285 @@
+package operation
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	domainOperation "github.com/RubenPari/clear-songs/internal/domain/operation"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	"github.com/RubenPari/clear-songs/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+func TestUndoOperationUseCase_Execute(t *testing.T) {
+	ctx := shared.WithOwner(context.Background(), "spotify:owner")
+	playlistID := spotifyAPI.ID("playlist_1")
+
+	t.Run("Success - should restore library and playlist positions", func(t *testing.T) {
+		mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+		mockCacheRepo := new(mocks.MockCacheRepository)
+		mockOperationRepo := new(mocks.MockOperationRepository)
+		useCase := NewUndoOperationUseCase(mockSpotifyRepo, mockCacheRepo, mockOperationRepo)
+
+		op := &domainOperation.Operation{
+			ID:              "op_1",
+			Kind:            domainOperation.KindDeletePlaylistAndLibraryTracks,
+			Owner:           "spotify:owner",
+			LibraryTrackIDs: []string{"track_a", "track_b", "track_c", "track_d"},
+			PlaylistID:      playlistID.String(),
+			// Positions 2 and 3 were kept (e.g. local files), 0-1 and 4-5 were removed
+			PlaylistTracks: []domainOperation.PlaylistTrack{
+				{TrackID: "track_c", Position: 4},
+				{TrackID: "track_a", Position: 0},
+				{TrackID: "track_b", Position: 1},
+				{TrackID: "track_d", Position: 5},
+			},
+		}
+		playlist := &spotifyAPI.FullPlaylist{Tracks: spotifyAPI.PlaylistTrackPage{}}
+		playlist.Tracks.Total = 2
+
+		mockOperationRepo.On("GetByID", mock.Anything, "op_1").Return(op, nil)
+		mockOperationRepo.On("ClaimUndo", mock.Anything, "op_1", mock.AnythingOfType("time.Time")).Return(nil)
+		mockSpotifyRepo.On("AddTracksToLibrary", mock.Anything, []spotifyAPI.ID{"track_a", "track_b", "track_c", "track_d"}).Return(nil)
+		mockSpotifyRepo.On("GetPlaylist", mock.Anything, playlistID).Return(playlist, nil)
+		mockSpotifyRepo.On("AddTracksToPlaylist", mock.Anything, playlistID, []spotifyAPI.ID{"track_a", "track_b"}, 0).Return(nil).Once()
+		mockSpotifyRepo.On("AddTracksToPlaylist", mock.Anything, playlistID, []spotifyAPI.ID{"track_c", "track_d"}, 4).Return(nil).Once()
+		mockOperationRepo.On("MarkPlaylistTracksRestored", mock.Anything, "op_1", 2).Return(nil).Once()
+		mockOperationRepo.On("MarkPlaylistTracksRestored", mock.Anything, "op_1", 4).Return(nil).Once()
+		mockOperationRepo.On("MarkUndone", mock.Anything, "op_1", mock.AnythingOfType("time.Time")).Return(nil)
+		mockCacheRepo.On("InvalidateUserTracks", mock.Anything).Return(nil)
+
+		result, err := useCase.Execute(ctx, "op_1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, result.LibraryTracksRestored)
+		assert.Equal(t, 4, result.PlaylistTracksRestored)
+		mockSpotifyRepo.AssertExpectations(t)
+		mockOperationRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - positions past the end of the playlist should be appended", func(t *testing.T) {
+		mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+		mockOperationRepo := new(mocks.MockOperationRepository)
+		useCase := NewUndoOperationUseCase(mockSpotifyRepo, nil, mockOperationRepo)
+
+		op := &domainOperation.Operation{
+			ID:         "op_2",
+			Owner:      "spotify:owner",
+			PlaylistID: playlistID.String(),
+			PlaylistTracks: []domainOperation.PlaylistTrack{
+				{TrackID: "track_a", Position: 3},
+				{TrackID: "track_b", Position: 7},
+			},
+		}
+
+		mockOperationRepo.On("GetByID", mock.Anything, "op_2").Return(op, nil)
+		mockOperationRepo.On("ClaimUndo", mock.Anything, "op_2", mock.Anything).Return(nil)
+		mockSpotifyRepo.On("GetPlaylist", mock.Anything, playlistID).Return(&spotifyAPI.FullPlaylist{}, nil)
+		mockSpotifyRepo.On("AddTracksToPlaylist", mock.Anything, playlistID, []spotifyAPI.ID{"track_a"}, 0).Return(nil).Once()
+		mockSpotifyRepo.On("AddTracksToPlaylist", mock.Anything, playlistID, []spotifyAPI.ID{"track_b"}, 1).Return(nil).Once()
+		mockOperationRepo.On("MarkPlaylistTracksRestored", mock.Anything, "op_2", mock.Anything).Return(nil)
+		mockOperationRepo.On("MarkUndone", mock.Anything, "op_2", mock.Anything).Return(nil)
+
+		_, err := useCase.Execute(ctx, "op_2")
+
+		assert.NoError(t, err)
+		mockSpotifyRepo.AssertExpectations(t)
+		mockSpotifyRepo.AssertNotCalled(t, "AddTracksToLibrary", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success - should save again the album removed by a conversion", func(t *testing.T) {
+		mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+		mockOperationRepo := new(mocks.MockOperationRepository)
+		useCase := NewUndoOperationUseCase(mockSpotifyRepo, nil, mockOperationRepo)
+
+		mockOperationRepo.On("GetByID", mock.Anything, "op_7").Return(&domainOperation.Operation{
+			ID:     "op_7",
+			Kind:   domainOperation.KindConvertAlbum,
+			Owner:  "spotify:owner",
+			Params: map[string]string{domainOperation.ParamAlbumID: "album_1"},
+		}, nil)
+		mockOperationRepo.On("ClaimUndo", mock.Anything, "op_7", mock.Anything).Return(nil)
+		mockSpotifyRepo.On("SaveAlbumsToLibrary", mock.Anything, []spotifyAPI.ID{"album_1"}).Return(nil)
+		mockOperationRepo.On("MarkUndone", mock.Anything, "op_7", mock.Anything).Return(nil)
+
+		result, err := useCase.Execute(ctx, "op_7")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.AlbumsRestored)
+		mockSpotifyRepo.AssertExpectations(t)
+		mockSpotifyRepo.AssertNotCalled(t, "AddTracksToLibrary", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success - should remove the kept versions a merge inserted before re-inserting", func(t *testing.T) {
+		mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+		mockOperationRepo := new(mocks.MockOperationRepository)
+		useCase := NewUndoOperationUseCase(mockSpotifyRepo, nil, mockOperationRepo)
+
+		op := &domainOperation.Operation{
+			ID:                  "op_8",
+			Kind:                domainOperation.KindMergeDuplicates,
+			Owner:               "spotify:owner",
+			PlaylistID:          playlistID.String(),
+			PlaylistTracks:      []domainOperation.PlaylistTrack{{TrackID: "single", Position: 1}},
+			PlaylistTracksAdded: []domainOperation.PlaylistTrack{{TrackID: "album", Position: 1}},
+		}
+		playlist := &spotifyAPI.FullPlaylist{}
+		playlist.Tracks.Total = 2
+
+		var calls []string
+		mockOperationRepo.On("GetByID", mock.Anything, "op_8").Return(op, nil)
+		mockOperationRepo.On("ClaimUndo", mock.Anything, "op_8", mock.Anything).Return(nil)
+		mockSpotifyRepo.On("DeletePlaylistTracks", mock.Anything, playlistID, []spotifyAPI.ID{"album"}).Run(func(mock.Arguments) {
+			calls = append(calls, "delete")
+		}).Return(nil)
+		mockSpotifyRepo.On("GetPlaylist", mock.Anything, playlistID).Return(playlist, nil)
+		mockSpotifyRepo.On("AddTracksToPlaylist", mock.Anything, playlistID, []spotifyAPI.ID{"single"}, 1).Run(func(mock.Arguments) {
+			calls = append(calls, "add")
+		}).Return(nil)
+		mockOperationRepo.On("MarkPlaylistTracksRestored", mock.Anything, "op_8", 1).Return(nil)
+		mockOperationRepo.On("MarkUndone", mock.Anything, "op_8", mock.Anything).Return(nil)
+
+		_, err := useCase.Execute(ctx, "op_8")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"delete", "add"}, calls)
+		mockSpotifyRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - a retried undo should resume after the tracks already re-inserted", func(t *testing.T) {
+		mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+		mockOperationRepo := new(mocks.MockOperationRepository)
+		useCase := NewUndoOperationUseCase(mockSpotifyRepo, nil, mockOperationRepo)
+
+		op := &domainOperation.Operation{
+			ID:         "op_5",
+			Owner:      "spotify:owner",
+			PlaylistID: playlistID.String(),
+			PlaylistTracks: []domainOperation.PlaylistTrack{
+				{TrackID: "track_a", Position: 0},
+				{TrackID: "track_b", Position: 1},
+				{TrackID: "track_c", Position: 4},
+			},
+		}
+		playlist := &spotifyAPI.FullPlaylist{}
+		playlist.Tracks.Total = 4
+
+		// The first attempt re-inserted track_a and track_b, then failed on track_c
+		mockOperationRepo.On("GetByID", mock.Anything, "op_5").Return(op, nil)
+		mockOperationRepo.On("ClaimUndo", mock.Anything, "op_5", mock.Anything).Return(nil)
+		mockOperationRepo.On("ReleaseUndo", mock.Anything, "op_5").Return(nil).Once()
+		mockSpotifyRepo.On("GetPlaylist", mock.Anything, playlistID).Return(playlist, nil)
+		mockSpotifyRepo.On("AddTracksToPlaylist", mock.Anything, playlistID, []spotifyAPI.ID{"track_a", "track_b"}, 0).Return(nil).Once()
+		mockSpotifyRepo.On("AddTracksToPlaylist", mock.Anything, playlistID, []spotifyAPI.ID{"track_c"}, 4).Return(assert.AnError).Once()
+		mockOperationRepo.On("MarkPlaylistTracksRestored", mock.Anything, "op_5", 2).Run(func(args mock.Arguments) {
+			op.PlaylistTracksRestored = args.Int(2)
+		}).Return(nil).Once()
+
+		_, err := useCase.Execute(ctx, "op_5")
+		assert.ErrorIs(t, err, assert.AnError)
+
+		mockSpotifyRepo.On("AddTracksToPlaylist", mock.Anything, playlistID, []spotifyAPI.ID{"track_c"}, 4).Return(nil).Once()
+		mockOperationRepo.On("MarkPlaylistTracksRestored", mock.Anything, "op_5", 3).Return(nil).Once()
+		mockOperationRepo.On("MarkUndone", mock.Anything, "op_5", mock.Anything).Return(nil)
+
+		result, err := useCase.Execute(ctx, "op_5")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, result.PlaylistTracksRestored)
+		mockSpotifyRepo.AssertNumberOfCalls(t, "AddTracksToPlaylist", 3)
+		mockOperationRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - a concurrent undo of the same operation should be rejected", func(t *testing.T) {
+		mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+		mockOperationRepo := new(mocks.MockOperationRepository)
+		useCase := NewUndoOperationUseCase(mockSpotifyRepo, nil, mockOperationRepo)
+
+		op := &domainOperation.Operation{
+			ID:         "op_6",
+			Owner:      "spotify:owner",
+			PlaylistID: playlistID.String(),
+			PlaylistTracks: []domainOperation.PlaylistTrack{
+				{TrackID: "track_a", Position: 0},
+				{TrackID: "track_b", Position: 3},
+			},
+		}
+		playlist := &spotifyAPI.FullPlaylist{}
+		playlist.Tracks.Total = 2
+
+		// Both undos read the operation before either claims it
+		var read sync.WaitGroup
+		read.Add(2)
+		mockOperationRepo.On("GetByID", mock.Anything, "op_6").Run(func(mock.Arguments) {
+			read.Done()
+			read.Wait()
+		}).Return(op, nil)
+		mockOperationRepo.On("ClaimUndo", mock.Anything, "op_6", mock.Anything).Return(nil).Once()
+		mockOperationRepo.On("ClaimUndo", mock.Anything, "op_6", mock.Anything).
+			Return(fmt.Errorf("%w: operation is already being undone", shared.ErrConflict)).Once()
+		mockSpotifyRepo.On("GetPlaylist", mock.Anything, playlistID).Return(playlist, nil)
+		mockSpotifyRepo.On("AddTracksToPlaylist", mock.Anything, playlistID, []spotifyAPI.ID{"track_a"}, 0).Return(nil).Once()
+		mockSpotifyRepo.On("AddTracksToPlaylist", mock.Anything, playlistID, []spotifyAPI.ID{"track_b"}, 3).Return(nil).Once()
+		mockOperationRepo.On("MarkPlaylistTracksRestored", mock.Anything, "op_6", mock.Anything).Return(nil)
+		mockOperationRepo.On("MarkUndone", mock.Anything, "op_6", mock.Anything).Return(nil)
+
+		errs := make([]error, 2)
+		var done sync.WaitGroup
+		for i := range errs {
+			done.Add(1)
+			go func() {
+				defer done.Done()
+				_, errs[i] = useCase.Execute(ctx, "op_6")
+			}()
+		}
+		done.Wait()
+
+		succeeded := 0
+		for _, err := range errs {
+			if err == nil {
+				succeeded++
+			} else {
+				assert.ErrorIs(t, err, shared.ErrConflict)
+			}
+		}
+		assert.Equal(t, 1, succeeded)
+		mockSpotifyRepo.AssertNumberOfCalls(t, "AddTracksToPlaylist", 2)
+		mockSpotifyRepo.AssertExpectations(t)
+		mockOperationRepo.AssertNotCalled(t, "ReleaseUndo", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - already undone operation should be rejected", func(t *testing.T) {
+		mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+		mockOperationRepo := new(mocks.MockOperationRepository)
+		useCase := NewUndoOperationUseCase(mockSpotifyRepo, nil, mockOperationRepo)
+
+		undoneAt := time.Now()
+		mockOperationRepo.On("GetByID", mock.Anything, "op_3").Return(&domainOperation.Operation{
+			ID: "op_3", Owner: "spotify:owner", LibraryTrackIDs: []string{"track_a"}, UndoneAt: &undoneAt,
+		}, nil)
+
+		_, err := useCase.Execute(ctx, "op_3")
+
+		assert.ErrorIs(t, err, shared.ErrValidation)
+		mockSpotifyRepo.AssertNotCalled(t, "AddTracksToLibrary", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - other owners should not undo the operation", func(t *testing.T) {
+		mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+		mockOperationRepo := new(mocks.MockOperationRepository)
+		useCase := NewUndoOperationUseCase(mockSpotifyRepo, nil, mockOperationRepo)
+
+		mockOperationRepo.On("GetByID", mock.Anything, "op_4").Return(&domainOperation.Operation{
+			ID: "op_4", Owner: "spotify:other", LibraryTrackIDs: []string{"track_a"},
+		}, nil)
+
+		_, err := useCase.Execute(ctx, "op_4")
+
+		assert.ErrorIs(t, err, shared.ErrNotFound)
+	})
+}
@@ -0,0 +1,152 @@
+package playlist
+
+import (
+	"context"
+	"strings"
+
+	appBackup "github.com/RubenPari/clear-songs/internal/application/backup"
+	appOperation "github.com/RubenPari/clear-songs/internal/application/operation"
+	"github.com/RubenPari/clear-songs/internal/application/snapshot"
+	"github.com/RubenPari/clear-songs/internal/domain/backup"
+	"github.com/RubenPari/clear-songs/internal/domain/job"
+	"github.com/RubenPari/clear-songs/internal/domain/operation"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+// DedupePlaylistUseCase handles the business logic for removing repeated tracks from a playlist
+type DedupePlaylistUseCase struct {
+	spotifyRepo shared.SpotifyRepository
+	cacheRepo   shared.CacheRepository
+	backups     *appBackup.Writer
+	recorder    *appOperation.Recorder
+	snapshots   *snapshot.CapturePlaylistUseCase
+}
+
+// NewDedupePlaylistUseCase creates a new DedupePlaylistUseCase
+func NewDedupePlaylistUseCase(
+	spotifyRepo shared.SpotifyRepository,
+	cacheRepo shared.CacheRepository,
+	backups *appBackup.Writer,
+	recorder *appOperation.Recorder,
+	snapshots *snapshot.CapturePlaylistUseCase,
+) *DedupePlaylistUseCase {
+	return &DedupePlaylistUseCase{
+		spotifyRepo: spotifyRepo,
+		cacheRepo:   cacheRepo,
+		backups:     backups,
+		recorder:    recorder,
+		snapshots:   snapshots,
+	}
+}
+
+// Execute removes, by position, every occurrence of a track already present earlier in the
+// playlist with the same ID or the same ISRC. The first occurrence is kept.
+func (uc *DedupePlaylistUseCase) Execute(ctx context.Context, playlistID spotifyAPI.ID, req DedupeRequest) (*DedupeResult, error) {
+	// 1. Read the playlist at a known snapshot
+	snapshotID, tracks, err := readPlaylistSnapshot(ctx, uc.spotifyRepo, playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Find the repeated occurrences
+	result := &DedupeResult{
+		PlaylistID: playlistID.String(),
+		DryRun:     req.DryRun,
+		SnapshotID: snapshotID,
+		Removed:    findPlaylistDuplicates(tracks),
+	}
+	result.TracksRemoved = len(result.Removed)
+	if req.DryRun || len(result.Removed) == 0 {
+		return result, nil
+	}
+	job.ReportTotal(ctx, len(result.Removed))
+	uc.snapshots.Before(ctx, playlistID, string(operation.KindDedupePlaylist))
+
+	toRemove := make([]spotifyAPI.TrackToRemove, 0, len(result.Removed))
+	removed := make([]operation.PlaylistTrack, 0, len(result.Removed))
+	items := make([]spotifyAPI.PlaylistTrack, 0, len(result.Removed))
+	positions := make([]int, 0, len(result.Removed))
+	for _, removal := range result.Removed {
+		toRemove = append(toRemove, trackToRemove(tracks[removal.Position], removal.Position))
+		removed = append(removed, operation.PlaylistTrack{TrackID: removal.TrackID, Position: removal.Position})
+		items = append(items, tracks[removal.Position])
+		positions = append(positions, removal.Position)
+	}
+
+	op := &operation.Operation{
+		Kind:           operation.KindDedupePlaylist,
+		Params:         map[string]string{"playlist_id": playlistID.String()},
+		PlaylistID:     playlistID.String(),
+		PlaylistTracks: removed,
+	}
+
+	// 3. Back up the repeated occurrences before removing them
+	if err := uc.backups.Save(ctx, backup.FromPlaylistTracks(appOperation.BackupOrigin(ctx, op), items, positions)); err != nil {
+		return nil, err
+	}
+
+	// 4. Remove them by position
+	newSnapshotID, err := uc.spotifyRepo.DeletePlaylistTracksAt(ctx, playlistID, snapshotID, toRemove)
+	if err != nil {
+		return nil, err
+	}
+	result.SnapshotID = newSnapshotID
+
+	// 5. Invalidate cache
+	if uc.cacheRepo != nil {
+		_ = uc.cacheRepo.InvalidatePlaylistTracks(ctx, playlistID)
+	}
+
+	// 6. Record the operation (with original positions) so it can be undone
+	uc.recorder.Record(ctx, op)
+
+	return result, nil
+}
+
+// findPlaylistDuplicates returns the occurrences that repeat an earlier track ID or ISRC.
+// Items without an ID (local files) are never removed.
+func findPlaylistDuplicates(tracks []spotifyAPI.PlaylistTrack) []DedupeRemoval {
+	firstByID := make(map[spotifyAPI.ID]int)
+	firstByISRC := make(map[string]int)
+	removals := []DedupeRemoval{}
+
+	for position, item := range tracks {
+		track := item.Track
+		if track.ID == "" {
+			continue
+		}
+		isrc := strings.ToUpper(track.ExternalIDs["isrc"])
+
+		kept, reason := -1, ""
+		if first, exists := firstByID[track.ID]; exists {
+			kept, reason = first, DuplicateSameID
+		} else if first, exists := firstByISRC[isrc]; exists && isrc != "" {
+			kept, reason = first, DuplicateSameISRC
+		}
+
+		if kept < 0 {
+			firstByID[track.ID] = position
+			if isrc != "" {
+				firstByISRC[isrc] = position
+			}
+			continue
+		}
+
+		artists := make([]string, len(track.Artists))
+		for i, artist := range track.Artists {
+			artists[i] = artist.Name
+		}
+		removals = append(removals, DedupeRemoval{
+			Position:     position,
+			TrackID:      track.ID.String(),
+			Name:         track.Name,
+			Artists:      artists,
+			Reason:       reason,
+			KeptPosition: kept,
+			KeptTrackID:  tracks[kept].Track.ID.String(),
+		})
+	}
+
+	return removals
+}
@@ -0,0 +1,98 @@
+package playlist
+
+import (
+	"context"
+	"testing"
+
+	appOperation "github.com/RubenPari/clear-songs/internal/application/operation"
+	"github.com/RubenPari/clear-songs/internal/domain/operation"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	"github.com/RubenPari/clear-songs/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+func playlistItem(id, isrc string) spotifyAPI.PlaylistTrack {
+	return spotifyAPI.PlaylistTrack{Track: spotifyAPI.FullTrack{
+		SimpleTrack: spotifyAPI.SimpleTrack{ID: spotifyAPI.ID(id), Name: "Song " + id},
+		ExternalIDs: map[string]string{"isrc": isrc},
+	}}
+}
+
+func TestDedupePlaylistUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	playlistID := spotifyAPI.ID("playlist_1")
+	snapshot := &spotifyAPI.FullPlaylist{SimplePlaylist: spotifyAPI.SimplePlaylist{SnapshotID: "snapshot_1"}}
+	tracks := []spotifyAPI.PlaylistTrack{
+		playlistItem("track_1", "ISRC1"),
+		playlistItem("track_2", "ISRC2"),
+		playlistItem("track_1", "ISRC1"), // same ID
+		playlistItem("", ""),             // local file
+		playlistItem("track_3", "isrc2"), // same ISRC as track_2
+		playlistItem("track_4", ""),
+		playlistItem("track_1", "ISRC1"), // same ID again
+	}
+
+	t.Run("Dry run reports the repeated occurrences", func(t *testing.T) {
+		mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+		mockSpotifyRepo.On("GetPlaylist", ctx, playlistID).Return(snapshot, nil)
+		mockSpotifyRepo.On("GetAllPlaylistTracks", ctx, playlistID).Return(tracks, nil)
+
+		useCase := NewDedupePlaylistUseCase(mockSpotifyRepo, nil, nil, nil, nil)
+		result, err := useCase.Execute(ctx, playlistID, DedupeRequest{DryRun: true})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, result.TracksRemoved)
+		assert.Equal(t, []int{2, 4, 6}, []int{result.Removed[0].Position, result.Removed[1].Position, result.Removed[2].Position})
+		assert.Equal(t, DuplicateSameID, result.Removed[0].Reason)
+		assert.Equal(t, 0, result.Removed[0].KeptPosition)
+		assert.Equal(t, DuplicateSameISRC, result.Removed[1].Reason)
+		assert.Equal(t, "track_2", result.Removed[1].KeptTrackID)
+		mockSpotifyRepo.AssertNotCalled(t, "DeletePlaylistTracksAt", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Removes by position against the snapshot and records the operation", func(t *testing.T) {
+		mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+		mockCacheRepo := new(mocks.MockCacheRepository)
+		mockOperationRepo := new(mocks.MockOperationRepository)
+		mockSpotifyRepo.On("GetPlaylist", ctx, playlistID).Return(snapshot, nil)
+		mockSpotifyRepo.On("GetAllPlaylistTracks", ctx, playlistID).Return(tracks, nil)
+		mockSpotifyRepo.On("DeletePlaylistTracksAt", ctx, playlistID, "snapshot_1", []spotifyAPI.TrackToRemove{
+			spotifyAPI.NewTrackToRemove("track_1", []int{2}),
+			spotifyAPI.NewTrackToRemove("track_3", []int{4}),
+			spotifyAPI.NewTrackToRemove("track_1", []int{6}),
+		}).Return("snapshot_2", nil)
+
+		var recorded *operation.Operation
+		mockOperationRepo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			recorded = args.Get(1).(*operation.Operation)
+		}).Return(nil)
+
+		useCase := NewDedupePlaylistUseCase(mockSpotifyRepo, mockCacheRepo, nil, appOperation.NewRecorder(mockOperationRepo), nil)
+		result, err := useCase.Execute(ctx, playlistID, DedupeRequest{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "snapshot_2", result.SnapshotID)
+		mockSpotifyRepo.AssertNotCalled(t, "DeletePlaylistTracks", mock.Anything, mock.Anything, mock.Anything)
+		assert.Equal(t, operation.KindDedupePlaylist, recorded.Kind)
+		assert.Equal(t, []operation.PlaylistTrack{
+			{TrackID: "track_1", Position: 2},
+			{TrackID: "track_3", Position: 4},
+			{TrackID: "track_1", Position: 6},
+		}, recorded.PlaylistTracks)
+	})
+
+	t.Run("Playlist modified while reading is a conflict", func(t *testing.T) {
+		mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+		mockSpotifyRepo.On("GetPlaylist", ctx, playlistID).Return(snapshot, nil).Once()
+		mockSpotifyRepo.On("GetPlaylist", ctx, playlistID).Return(&spotifyAPI.FullPlaylist{SimplePlaylist: spotifyAPI.SimplePlaylist{SnapshotID: "snapshot_2"}}, nil).Once()
+		mockSpotifyRepo.On("GetAllPlaylistTracks", ctx, playlistID).Return(tracks, nil)
+
+		useCase := NewDedupePlaylistUseCase(mockSpotifyRepo, nil, nil, nil, nil)
+		_, err := useCase.Execute(ctx, playlistID, DedupeRequest{})
+
+		assert.ErrorIs(t, err, shared.ErrConflict)
+		mockSpotifyRepo.AssertNotCalled(t, "DeletePlaylistTracksAt", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
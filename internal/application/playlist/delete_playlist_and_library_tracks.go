@@ -2,67 +2,88 @@ package playlist
 
 import (
 	"context"
-	"time"
 
+	appOperation "github.com/RubenPari/clear-songs/internal/application/operation"
+	"github.com/RubenPari/clear-songs/internal/application/shared/dto"
+	"github.com/RubenPari/clear-songs/internal/domain/job"
+	"github.com/RubenPari/clear-songs/internal/domain/operation"
+	domainPlaylist "github.com/RubenPari/clear-songs/internal/domain/playlist"
 	"github.com/RubenPari/clear-songs/internal/domain/shared"
 	spotifyAPI "github.com/zmb3/spotify"
 )
 
 // DeletePlaylistAndLibraryTracksUseCase handles the business logic for deleting tracks from both playlist and library
 type DeletePlaylistAndLibraryTracksUseCase struct {
-	spotifyRepo    shared.SpotifyRepository
-	cacheRepo      shared.CacheRepository
-	databaseRepo   shared.DatabaseRepository
+	spotifyRepo      shared.SpotifyRepository
+	cacheRepo        shared.CacheRepository
 	deletePlaylistUC *DeletePlaylistTracksUseCase
+	recorder         *appOperation.Recorder
 }
 
 // NewDeletePlaylistAndLibraryTracksUseCase creates a new DeletePlaylistAndLibraryTracksUseCase
 func NewDeletePlaylistAndLibraryTracksUseCase(
 	spotifyRepo shared.SpotifyRepository,
 	cacheRepo shared.CacheRepository,
-	databaseRepo shared.DatabaseRepository,
 	deletePlaylistUC *DeletePlaylistTracksUseCase,
+	recorder *appOperation.Recorder,
 ) *DeletePlaylistAndLibraryTracksUseCase {
 	return &DeletePlaylistAndLibraryTracksUseCase{
 		spotifyRepo:      spotifyRepo,
 		cacheRepo:        cacheRepo,
-		databaseRepo:     databaseRepo,
 		deletePlaylistUC: deletePlaylistUC,
+		recorder:         recorder,
 	}
 }
 
-// Execute deletes tracks from both playlist and user library
-func (uc *DeletePlaylistAndLibraryTracksUseCase) Execute(ctx context.Context, playlistID spotifyAPI.ID) error {
-	// 1. Get playlist tracks (from cache or API)
-	tracks, err := uc.getPlaylistTracks(ctx, playlistID)
+// Execute deletes the tracks matching filter (all of them when the filter is empty)
+// from both playlist and user library
+func (uc *DeletePlaylistAndLibraryTracksUseCase) Execute(ctx context.Context, playlistID spotifyAPI.ID, filter domainPlaylist.TrackFilter) error {
+	// 1. Select the playlist tracks to delete (reuse existing use case)
+	selection, err := uc.deletePlaylistUC.selectTracks(ctx, playlistID, filter)
 	if err != nil {
 		return err
 	}
+	tracks := selection.items
 
 	if len(tracks) == 0 {
 		return nil // No tracks to delete
 	}
 
-	// 2. Save backup to database
-	if err := uc.databaseRepo.SaveTracksBackup(tracks); err != nil {
-		// Log error but continue (backup is optional)
+	uc.deletePlaylistUC.snapshots.Before(ctx, playlistID, string(operation.KindDeletePlaylistAndLibraryTracks))
+
+	op := &operation.Operation{
+		Kind:           operation.KindDeletePlaylistAndLibraryTracks,
+		Params:         filterParams(playlistID, filter),
+		PlaylistID:     playlistID.String(),
+		PlaylistTracks: selection.positions,
+	}
+
+	// 2. Back up the tracks before removing them (reuse existing use case)
+	if err := uc.deletePlaylistUC.backupSelection(ctx, op, selection); err != nil {
+		return err
 	}
 
 	// 3. Delete tracks from playlist (reuse existing use case)
-	if err := uc.deletePlaylistUC.Execute(ctx, playlistID); err != nil {
+	if err := uc.deletePlaylistUC.removeSelection(ctx, playlistID, selection); err != nil {
 		return err
 	}
 
-	// 4. Convert tracks to IDs for library deletion
+	// 4. Convert tracks to IDs for library deletion (local files are not in the library)
 	trackIDs := make([]spotifyAPI.ID, 0, len(tracks))
 	for _, track := range tracks {
-		trackIDs = append(trackIDs, track.Track.ID)
+		if track.Track.ID != "" {
+			trackIDs = append(trackIDs, track.Track.ID)
+		}
 	}
+	job.ReportTotal(ctx, len(trackIDs)) // Library removals count on top of playlist removals
 
 	// 5. Delete tracks from user library
 	if err := uc.spotifyRepo.DeleteTracksFromLibrary(ctx, trackIDs); err != nil {
+		// The playlist is already cleared: keep that part undoable
+		uc.recorder.Record(ctx, op)
 		return err
 	}
+	op.LibraryTrackIDs = appOperation.TrackIDs(trackIDs)
 
 	// 6. Invalidate cache (both playlist and user data)
 	if uc.cacheRepo != nil {
@@ -70,29 +91,15 @@ func (uc *DeletePlaylistAndLibraryTracksUseCase) Execute(ctx context.Context, pl
 		_ = uc.cacheRepo.InvalidateUserTracks(ctx)
 	}
 
+	// 7. Record the operation so it can be undone
+	uc.recorder.Record(ctx, op)
+
 	return nil
 }
 
-// getPlaylistTracks retrieves tracks from cache or API
-func (uc *DeletePlaylistAndLibraryTracksUseCase) getPlaylistTracks(ctx context.Context, playlistID spotifyAPI.ID) ([]spotifyAPI.PlaylistTrack, error) {
-	// Try cache first (if available)
-	if uc.cacheRepo != nil {
-		cached, err := uc.cacheRepo.GetPlaylistTracks(ctx, playlistID)
-		if err == nil && cached != nil && len(cached) > 0 {
-			return cached, nil
-		}
-	}
-
-	// Fetch from API
-	tracks, err := uc.spotifyRepo.GetAllPlaylistTracks(ctx, playlistID)
-	if err != nil {
-		return nil, err
-	}
-
-	// Cache for future use (if cache is available)
-	if uc.cacheRepo != nil {
-		_ = uc.cacheRepo.SetPlaylistTracks(ctx, playlistID, tracks, 5*time.Minute)
-	}
-
-	return tracks, nil
+// Preview lists the tracks Execute would remove from both the playlist and the library,
+// without modifying either
+func (uc *DeletePlaylistAndLibraryTracksUseCase) Preview(ctx context.Context, playlistID spotifyAPI.ID, filter domainPlaylist.TrackFilter) (*dto.DeletionPreview, error) {
+	// The same tracks are removed from the playlist and the library
+	return uc.deletePlaylistUC.Preview(ctx, playlistID, filter)
 }
@@ -4,6 +4,14 @@ import (
 	"context"
 	"time"
 
+	appBackup "github.com/RubenPari/clear-songs/internal/application/backup"
+	appOperation "github.com/RubenPari/clear-songs/internal/application/operation"
+	"github.com/RubenPari/clear-songs/internal/application/shared/dto"
+	"github.com/RubenPari/clear-songs/internal/application/snapshot"
+	"github.com/RubenPari/clear-songs/internal/domain/backup"
+	"github.com/RubenPari/clear-songs/internal/domain/job"
+	"github.com/RubenPari/clear-songs/internal/domain/operation"
+	domainPlaylist "github.com/RubenPari/clear-songs/internal/domain/playlist"
 	"github.com/RubenPari/clear-songs/internal/domain/shared"
 	spotifyAPI "github.com/zmb3/spotify"
 )
@@ -12,43 +20,163 @@ import (
 type DeletePlaylistTracksUseCase struct {
 	spotifyRepo shared.SpotifyRepository
 	cacheRepo   shared.CacheRepository
+	backups     *appBackup.Writer
+	recorder    *appOperation.Recorder
+	snapshots   *snapshot.CapturePlaylistUseCase
 }
 
 // NewDeletePlaylistTracksUseCase creates a new DeletePlaylistTracksUseCase
 func NewDeletePlaylistTracksUseCase(
 	spotifyRepo shared.SpotifyRepository,
 	cacheRepo shared.CacheRepository,
+	backups *appBackup.Writer,
+	recorder *appOperation.Recorder,
+	snapshots *snapshot.CapturePlaylistUseCase,
 ) *DeletePlaylistTracksUseCase {
 	return &DeletePlaylistTracksUseCase{
 		spotifyRepo: spotifyRepo,
 		cacheRepo:   cacheRepo,
+		backups:     backups,
+		recorder:    recorder,
+		snapshots:   snapshots,
 	}
 }
 
-// Execute deletes all tracks from a playlist
-func (uc *DeletePlaylistTracksUseCase) Execute(ctx context.Context, playlistID spotifyAPI.ID) error {
-	// 1. Get playlist tracks (from cache or API)
-	tracks, err := uc.getPlaylistTracks(ctx, playlistID)
+// Execute deletes the tracks matching filter from a playlist, or all of them when the
+// filter is empty
+func (uc *DeletePlaylistTracksUseCase) Execute(ctx context.Context, playlistID spotifyAPI.ID, filter domainPlaylist.TrackFilter) error {
+	// 1. Select the playlist tracks to delete
+	selection, err := uc.selectTracks(ctx, playlistID, filter)
 	if err != nil {
 		return err
 	}
 
-	if len(tracks) == 0 {
+	if len(selection.items) == 0 {
 		return nil // No tracks to delete
 	}
 
-	// 2. Convert tracks to IDs
+	uc.snapshots.Before(ctx, playlistID, string(operation.KindDeletePlaylistTracks))
+
+	op := &operation.Operation{
+		Kind:           operation.KindDeletePlaylistTracks,
+		Params:         filterParams(playlistID, filter),
+		PlaylistID:     playlistID.String(),
+		PlaylistTracks: selection.positions,
+	}
+
+	// 2. Back up the tracks before removing them
+	if err := uc.backupSelection(ctx, op, selection); err != nil {
+		return err
+	}
+
+	// 3-5. Delete tracks from playlist
+	if err := uc.removeSelection(ctx, playlistID, selection); err != nil {
+		return err
+	}
+
+	// 6. Record the operation (with original positions) so it can be undone
+	uc.recorder.Record(ctx, op)
+
+	return nil
+}
+
+// playlistSelection holds the playlist items selected for removal
+type playlistSelection struct {
+	items []spotifyAPI.PlaylistTrack
+	// itemPositions[i] is the position of items[i] before removal, for backups
+	itemPositions []int
+	// positions are the positions of the items before removal, for undo
+	positions []operation.PlaylistTrack
+	// snapshotID is set when the items are removed by position rather than by ID
+	snapshotID string
+	toRemove   []spotifyAPI.TrackToRemove
+}
+
+// selectTracks selects the items matching filter. Without a filter every item is
+// selected and removed by ID; with a filter only some occurrences of a track may
+// match, so the items are selected by position on a snapshot of the playlist.
+func (uc *DeletePlaylistTracksUseCase) selectTracks(ctx context.Context, playlistID spotifyAPI.ID, filter domainPlaylist.TrackFilter) (*playlistSelection, error) {
+	if filter.IsEmpty() {
+		tracks, err := uc.getPlaylistTracks(ctx, playlistID)
+		if err != nil {
+			return nil, err
+		}
+		itemPositions := make([]int, len(tracks))
+		for i := range tracks {
+			itemPositions[i] = i
+		}
+		return &playlistSelection{
+			items:         tracks,
+			itemPositions: itemPositions,
+			positions:     appOperation.PlaylistPositions(tracks),
+		}, nil
+	}
+
+	snapshotID, tracks, err := readPlaylistSnapshot(ctx, uc.spotifyRepo, playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	selection := &playlistSelection{snapshotID: snapshotID}
+	for position, item := range tracks {
+		if !filter.Matches(item) {
+			continue
+		}
+
+		selection.items = append(selection.items, item)
+		selection.itemPositions = append(selection.itemPositions, position)
+		selection.toRemove = append(selection.toRemove, trackToRemove(item, position))
+		// Local files cannot be re-added, so they are not recorded for undo
+		if item.Track.ID != "" {
+			selection.positions = append(selection.positions, operation.PlaylistTrack{
+				TrackID:  item.Track.ID.String(),
+				Position: position,
+			})
+		}
+	}
+
+	return selection, nil
+}
+
+// backupSelection backs up the selected items, with their positions, as removed by op
+func (uc *DeletePlaylistTracksUseCase) backupSelection(ctx context.Context, op *operation.Operation, selection *playlistSelection) error {
+	backups := backup.FromPlaylistTracks(appOperation.BackupOrigin(ctx, op), selection.items, selection.itemPositions)
+	return uc.backups.Save(ctx, backups)
+}
+
+// removeSelection removes the selected items from the playlist
+func (uc *DeletePlaylistTracksUseCase) removeSelection(ctx context.Context, playlistID spotifyAPI.ID, selection *playlistSelection) error {
+	if selection.snapshotID == "" {
+		return uc.removeTracks(ctx, playlistID, selection.items)
+	}
+
+	job.ReportTotal(ctx, len(selection.toRemove))
+	if _, err := uc.spotifyRepo.DeletePlaylistTracksAt(ctx, playlistID, selection.snapshotID, selection.toRemove); err != nil {
+		return err
+	}
+
+	if uc.cacheRepo != nil {
+		_ = uc.cacheRepo.InvalidatePlaylistTracks(ctx, playlistID)
+	}
+
+	return nil
+}
+
+// removeTracks removes the given tracks from the playlist
+func (uc *DeletePlaylistTracksUseCase) removeTracks(ctx context.Context, playlistID spotifyAPI.ID, tracks []spotifyAPI.PlaylistTrack) error {
+	// 3. Convert tracks to IDs
 	trackIDs := make([]spotifyAPI.ID, 0, len(tracks))
 	for _, track := range tracks {
 		trackIDs = append(trackIDs, track.Track.ID)
 	}
+	job.ReportTotal(ctx, len(trackIDs))
 
-	// 3. Delete tracks from playlist
+	// 4. Delete tracks from playlist
 	if err := uc.spotifyRepo.DeletePlaylistTracks(ctx, playlistID, trackIDs); err != nil {
 		return err
 	}
 
-	// 4. Invalidate cache
+	// 5. Invalidate cache
 	if uc.cacheRepo != nil {
 		_ = uc.cacheRepo.InvalidatePlaylistTracks(ctx, playlistID)
 	}
@@ -56,6 +184,34 @@ func (uc *DeletePlaylistTracksUseCase) Execute(ctx context.Context, playlistID s
 	return nil
 }
 
+// Preview lists the tracks Execute would remove from a playlist, without modifying it
+func (uc *DeletePlaylistTracksUseCase) Preview(ctx context.Context, playlistID spotifyAPI.ID, filter domainPlaylist.TrackFilter) (*dto.DeletionPreview, error) {
+	// 1. Select the tracks the same way Execute does, so a filtered preview reads the
+	// same snapshot the removal would
+	selection, err := uc.selectTracks(ctx, playlistID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Resolve playlist name for the per-playlist totals
+	playlistName := ""
+	playlist, err := uc.spotifyRepo.GetPlaylist(ctx, playlistID)
+	if err != nil {
+		return nil, err
+	}
+	if playlist != nil {
+		playlistName = playlist.Name
+	}
+
+	// 3. Build preview
+	preview := dto.NewDeletionPreview()
+	for _, item := range selection.items {
+		preview.AddPlaylistTrack(playlistID, playlistName, item.Track)
+	}
+
+	return preview, nil
+}
+
 // getPlaylistTracks retrieves tracks from cache or API
 func (uc *DeletePlaylistTracksUseCase) getPlaylistTracks(ctx context.Context, playlistID spotifyAPI.ID) ([]spotifyAPI.PlaylistTrack, error) {
 	// Try cache first (if available)
@@ -0,0 +1,165 @@
+package playlist
+
+import (
+	"context"
+	"testing"
+
+	appBackup "github.com/RubenPari/clear-songs/internal/application/backup"
+	appOperation "github.com/RubenPari/clear-songs/internal/application/operation"
+	"github.com/RubenPari/clear-songs/internal/domain/backup"
+	"github.com/RubenPari/clear-songs/internal/domain/operation"
+	domainPlaylist "github.com/RubenPari/clear-songs/internal/domain/playlist"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	"github.com/RubenPari/clear-songs/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+func TestDeletePlaylistTracksUseCase_Preview(t *testing.T) {
+	mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+	mockCacheRepo := new(mocks.MockCacheRepository)
+
+	useCase := NewDeletePlaylistTracksUseCase(mockSpotifyRepo, mockCacheRepo, nil, nil, nil)
+	ctx := context.Background()
+	playlistID := spotifyAPI.ID("playlist_1")
+
+	t.Run("Success - should list playlist tracks without deleting them", func(t *testing.T) {
+		tracks := []spotifyAPI.PlaylistTrack{
+			{Track: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{ID: "track_1", Name: "Song 1", Artists: []spotifyAPI.SimpleArtist{{ID: "artist_1", Name: "Artist One"}}}}},
+			{Track: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{ID: "track_2", Name: "Song 2", Artists: []spotifyAPI.SimpleArtist{{ID: "artist_2", Name: "Artist Two"}}}}},
+		}
+
+		mockSpotifyRepo.On("GetAllPlaylistTracks", mock.Anything, playlistID).Return(tracks, nil)
+		mockSpotifyRepo.On("GetPlaylist", mock.Anything, playlistID).Return(&spotifyAPI.FullPlaylist{SimplePlaylist: spotifyAPI.SimplePlaylist{Name: "Road Trip"}}, nil)
+
+		preview, err := useCase.Preview(ctx, playlistID, domainPlaylist.TrackFilter{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, preview.TotalTracks)
+		assert.Len(t, preview.Artists, 2)
+		assert.Len(t, preview.Playlists, 1)
+		assert.Equal(t, "Road Trip", preview.Playlists[0].Name)
+		assert.Equal(t, 2, preview.Playlists[0].Count)
+		mockSpotifyRepo.AssertNotCalled(t, "DeletePlaylistTracks", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success - a filtered preview should read the playlist snapshot, not the cache", func(t *testing.T) {
+		mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+		mockCacheRepo := new(mocks.MockCacheRepository)
+		useCase := NewDeletePlaylistTracksUseCase(mockSpotifyRepo, mockCacheRepo, nil, nil, nil)
+
+		stale := []spotifyAPI.PlaylistTrack{
+			{Track: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{ID: "removed", Artists: []spotifyAPI.SimpleArtist{{ID: "artist_1"}}}}},
+		}
+		current := []spotifyAPI.PlaylistTrack{
+			{Track: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{ID: "track_1", Artists: []spotifyAPI.SimpleArtist{{ID: "artist_1"}}}}},
+			{Track: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{ID: "track_2", Artists: []spotifyAPI.SimpleArtist{{ID: "artist_2"}}}}},
+			{Track: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{ID: "track_1", Artists: []spotifyAPI.SimpleArtist{{ID: "artist_1"}}}}},
+		}
+		mockCacheRepo.On("GetPlaylistTracks", mock.Anything, playlistID).Return(stale, nil).Maybe()
+		mockSpotifyRepo.On("GetAllPlaylistTracks", mock.Anything, playlistID).Return(current, nil)
+		mockSpotifyRepo.On("GetPlaylist", mock.Anything, playlistID).Return(&spotifyAPI.FullPlaylist{SimplePlaylist: spotifyAPI.SimplePlaylist{Name: "Road Trip", SnapshotID: "snapshot_1"}}, nil)
+
+		preview, err := useCase.Preview(ctx, playlistID, domainPlaylist.TrackFilter{ArtistIDs: []spotifyAPI.ID{"artist_1"}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, preview.TotalTracks)
+		assert.Equal(t, 2, preview.Playlists[0].Count)
+	})
+}
+
+func TestDeletePlaylistTracksUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	playlistID := spotifyAPI.ID("playlist_1")
+
+	t.Run("Success - should back up and record removed tracks with their positions", func(t *testing.T) {
+		mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+		mockCacheRepo := new(mocks.MockCacheRepository)
+		mockDatabaseRepo := new(mocks.MockDatabaseRepository)
+		mockOperationRepo := new(mocks.MockOperationRepository)
+		useCase := NewDeletePlaylistTracksUseCase(mockSpotifyRepo, mockCacheRepo, appBackup.NewWriter(mockDatabaseRepo, appBackup.PolicyBestEffort), appOperation.NewRecorder(mockOperationRepo), nil)
+
+		tracks := []spotifyAPI.PlaylistTrack{
+			{Track: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{ID: "track_1"}}},
+			{Track: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{Name: "local file"}}},
+			{Track: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{ID: "track_2"}}},
+		}
+
+		var backedUp []backup.TrackBackup
+		mockSpotifyRepo.On("GetAllPlaylistTracks", mock.Anything, playlistID).Return(tracks, nil)
+		mockDatabaseRepo.On("SaveTracksBackup", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			backedUp = args.Get(1).([]backup.TrackBackup)
+		}).Return(nil)
+		mockSpotifyRepo.On("DeletePlaylistTracks", mock.Anything, playlistID, mock.Anything).Return(nil)
+		mockOperationRepo.On("Create", mock.Anything, mock.MatchedBy(func(op *operation.Operation) bool {
+			return op.Kind == operation.KindDeletePlaylistTracks &&
+				len(backedUp) == 2 &&
+				backedUp[0].OperationID == op.ID &&
+				backedUp[0].PlaylistID == "playlist_1" &&
+				*backedUp[0].Position == 0 &&
+				*backedUp[1].Position == 2 &&
+				op.PlaylistID == "playlist_1" &&
+				len(op.LibraryTrackIDs) == 0 &&
+				assert.ObjectsAreEqual([]operation.PlaylistTrack{
+					{TrackID: "track_1", Position: 0},
+					{TrackID: "track_2", Position: 2},
+				}, op.PlaylistTracks)
+		})).Return(nil)
+
+		err := useCase.Execute(ctx, playlistID, domainPlaylist.TrackFilter{})
+
+		assert.NoError(t, err)
+		mockOperationRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - should keep the playlist when a required backup fails", func(t *testing.T) {
+		mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+		mockCacheRepo := new(mocks.MockCacheRepository)
+		mockDatabaseRepo := new(mocks.MockDatabaseRepository)
+		mockOperationRepo := new(mocks.MockOperationRepository)
+		useCase := NewDeletePlaylistTracksUseCase(mockSpotifyRepo, mockCacheRepo, appBackup.NewWriter(mockDatabaseRepo, appBackup.PolicyRequired), appOperation.NewRecorder(mockOperationRepo), nil)
+
+		tracks := []spotifyAPI.PlaylistTrack{{Track: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{ID: "track_1"}}}}
+		mockSpotifyRepo.On("GetAllPlaylistTracks", mock.Anything, playlistID).Return(tracks, nil)
+		mockDatabaseRepo.On("SaveTracksBackup", mock.Anything, mock.Anything).Return(assert.AnError)
+
+		err := useCase.Execute(ctx, playlistID, domainPlaylist.TrackFilter{})
+
+		assert.ErrorIs(t, err, shared.ErrBackupFailed)
+		mockSpotifyRepo.AssertNotCalled(t, "DeletePlaylistTracks", mock.Anything, mock.Anything, mock.Anything)
+		mockOperationRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Filter - should remove only the matching occurrences by position", func(t *testing.T) {
+		mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+		mockCacheRepo := new(mocks.MockCacheRepository)
+		mockOperationRepo := new(mocks.MockOperationRepository)
+		useCase := NewDeletePlaylistTracksUseCase(mockSpotifyRepo, mockCacheRepo, nil, appOperation.NewRecorder(mockOperationRepo), nil)
+
+		tracks := []spotifyAPI.PlaylistTrack{
+			{AddedBy: spotifyAPI.User{ID: "friend"}, Track: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{ID: "track_1"}}},
+			{AddedBy: spotifyAPI.User{ID: "me"}, Track: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{ID: "track_1"}}},
+			{AddedBy: spotifyAPI.User{ID: "friend"}, IsLocal: true, Track: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{URI: "spotify:local:a:b:c:1"}}},
+			{AddedBy: spotifyAPI.User{ID: "me"}, Track: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{ID: "track_2"}}},
+		}
+		snapshot := &spotifyAPI.FullPlaylist{SimplePlaylist: spotifyAPI.SimplePlaylist{SnapshotID: "snapshot_1"}}
+
+		mockSpotifyRepo.On("GetPlaylist", mock.Anything, playlistID).Return(snapshot, nil)
+		mockSpotifyRepo.On("GetAllPlaylistTracks", mock.Anything, playlistID).Return(tracks, nil)
+		mockSpotifyRepo.On("DeletePlaylistTracksAt", mock.Anything, playlistID, "snapshot_1", []spotifyAPI.TrackToRemove{
+			spotifyAPI.NewTrackToRemove("track_1", []int{0}),
+			{URI: "spotify:local:a:b:c:1", Positions: []int{2}},
+		}).Return("snapshot_2", nil)
+		mockOperationRepo.On("Create", mock.Anything, mock.MatchedBy(func(op *operation.Operation) bool {
+			return op.Params["filtered"] == "true" &&
+				assert.ObjectsAreEqual([]operation.PlaylistTrack{{TrackID: "track_1", Position: 0}}, op.PlaylistTracks)
+		})).Return(nil)
+
+		err := useCase.Execute(ctx, playlistID, domainPlaylist.TrackFilter{AddedBy: "friend"})
+
+		assert.NoError(t, err)
+		mockSpotifyRepo.AssertNotCalled(t, "DeletePlaylistTracks", mock.Anything, mock.Anything, mock.Anything)
+		mockOperationRepo.AssertExpectations(t)
+	})
+}
@@ -6,3 +6,49 @@ type PlaylistResponse struct {
 	Name     string `json:"name"`
 	ImageURL string `json:"image_url,omitempty"`
 }
+
+// Reasons a playlist occurrence is a duplicate
+const (
+	DuplicateSameID   = "same_id"
+	DuplicateSameISRC = "same_isrc"
+)
+
+// DedupeRequest holds the query parameters of the dedupe endpoint
+type DedupeRequest struct {
+	DryRun bool `form:"dry_run"`
+	Async  bool `form:"async"`
+}
+
+// DedupeRemoval is an occurrence removed from a playlist, with the occurrence it repeats
+type DedupeRemoval struct {
+	Position     int      `json:"position"`
+	TrackID      string   `json:"track_id"`
+	Name         string   `json:"name"`
+	Artists      []string `json:"artists"`
+	Reason       string   `json:"reason"`
+	KeptPosition int      `json:"kept_position"`
+	KeptTrackID  string   `json:"kept_track_id"`
+}
+
+// DedupeResult reports the occurrences removed from a playlist
+type DedupeResult struct {
+	PlaylistID    string          `json:"playlist_id"`
+	DryRun        bool            `json:"dry_run"`
+	SnapshotID    string          `json:"snapshot_id"` // snapshot after the removal, or the one inspected on dry run
+	TracksRemoved int             `json:"tracks_removed"`
+	Removed       []DedupeRemoval `json:"removed"`
+}
+
+// FilterRequest holds the query parameters selecting the playlist items to remove.
+// Without any parameter every item is removed.
+type FilterRequest struct {
+	ArtistIDs     string `form:"artist_ids"`   // comma-separated, matches any credited artist
+	AlbumIDs      string `form:"album_ids"`    // comma-separated
+	AddedAfter    string `form:"added_after"`  // RFC 3339 timestamp or YYYY-MM-DD
+	AddedBefore   string `form:"added_before"` // RFC 3339 timestamp or YYYY-MM-DD
+	AddedBy       string `form:"added_by"`     // Spotify user ID
+	MinDurationMs *int   `form:"min_duration_ms" binding:"omitempty,min=0"`
+	MaxDurationMs *int   `form:"max_duration_ms" binding:"omitempty,min=0"`
+	Explicit      *bool  `form:"explicit"`
+	LocalOnly     bool   `form:"local_only"`
+}
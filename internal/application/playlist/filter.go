@@ -0,0 +1,74 @@
+package playlist
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	domainPlaylist "github.com/RubenPari/clear-songs/internal/domain/playlist"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+// ToFilter validates the request and converts it to a playlist item filter
+func (r FilterRequest) ToFilter() (domainPlaylist.TrackFilter, error) {
+	filter := domainPlaylist.TrackFilter{
+		ArtistIDs:     splitIDs(r.ArtistIDs),
+		AlbumIDs:      splitIDs(r.AlbumIDs),
+		AddedBy:       strings.TrimSpace(r.AddedBy),
+		DurationMinMs: r.MinDurationMs,
+		DurationMaxMs: r.MaxDurationMs,
+		Explicit:      r.Explicit,
+		LocalOnly:     r.LocalOnly,
+	}
+
+	var err error
+	if filter.AddedAfter, err = parseDate("added_after", r.AddedAfter); err != nil {
+		return filter, err
+	}
+	if filter.AddedBefore, err = parseDate("added_before", r.AddedBefore); err != nil {
+		return filter, err
+	}
+
+	if filter.AddedAfter != nil && filter.AddedBefore != nil && !filter.AddedAfter.Before(*filter.AddedBefore) {
+		return filter, fmt.Errorf("%w: added_after must be before added_before", shared.ErrValidation)
+	}
+	if r.MinDurationMs != nil && r.MaxDurationMs != nil && *r.MinDurationMs > *r.MaxDurationMs {
+		return filter, fmt.Errorf("%w: min_duration_ms must not be greater than max_duration_ms", shared.ErrValidation)
+	}
+
+	return filter, nil
+}
+
+// parseDate parses an RFC 3339 timestamp or a YYYY-MM-DD date (midnight UTC)
+func parseDate(name, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if date, err := time.Parse(layout, value); err == nil {
+			return &date, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s must be an RFC 3339 timestamp or a YYYY-MM-DD date", shared.ErrValidation, name)
+}
+
+func splitIDs(value string) []spotifyAPI.ID {
+	var ids []spotifyAPI.ID
+	for _, id := range strings.Split(value, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, spotifyAPI.ID(id))
+		}
+	}
+	return ids
+}
+
+// filterParams describes a playlist removal for its operation record
+func filterParams(playlistID spotifyAPI.ID, filter domainPlaylist.TrackFilter) map[string]string {
+	params := map[string]string{"playlist_id": playlistID.String()}
+	if !filter.IsEmpty() {
+		params["filtered"] = "true"
+	}
+	return params
+}
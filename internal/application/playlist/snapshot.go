@@ -0,0 +1,47 @@
+package playlist
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+// readPlaylistSnapshot returns the tracks of a playlist with the snapshot they belong to.
+// Positions are only meaningful for the snapshot they were read from, so the tracks are
+// never taken from the cache, and the snapshot is checked again after reading since the
+// tracks are paged.
+func readPlaylistSnapshot(ctx context.Context, spotifyRepo shared.SpotifyRepository, playlistID spotifyAPI.ID) (string, []spotifyAPI.PlaylistTrack, error) {
+	playlist, err := spotifyRepo.GetPlaylist(ctx, playlistID)
+	if err != nil {
+		return "", nil, err
+	}
+	if playlist == nil {
+		return "", nil, shared.ErrNotFound
+	}
+
+	tracks, err := spotifyRepo.GetAllPlaylistTracks(ctx, playlistID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	current, err := spotifyRepo.GetPlaylist(ctx, playlistID)
+	if err != nil {
+		return "", nil, err
+	}
+	if current == nil || current.SnapshotID != playlist.SnapshotID {
+		return "", nil, fmt.Errorf("%w: playlist was modified while it was read, retry", shared.ErrConflict)
+	}
+
+	return playlist.SnapshotID, tracks, nil
+}
+
+// trackToRemove identifies the item at position for a positional removal.
+// Local files have no ID and are identified by their URI.
+func trackToRemove(item spotifyAPI.PlaylistTrack, position int) spotifyAPI.TrackToRemove {
+	if item.Track.ID == "" {
+		return spotifyAPI.TrackToRemove{URI: string(item.Track.URI), Positions: []int{position}}
+	}
+	return spotifyAPI.NewTrackToRemove(item.Track.ID.String(), []int{position})
+}
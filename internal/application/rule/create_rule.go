@@ -0,0 +1,39 @@
+package rule
+
+import (
+	"context"
+
+	domainRule "github.com/RubenPari/clear-songs/internal/domain/rule"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	"github.com/google/uuid"
+)
+
+// CreateRuleUseCase handles the business logic for creating a cleanup rule
+type CreateRuleUseCase struct {
+	ruleRepo domainRule.Repository
+}
+
+// NewCreateRuleUseCase creates a new CreateRuleUseCase
+func NewCreateRuleUseCase(ruleRepo domainRule.Repository) *CreateRuleUseCase {
+	return &CreateRuleUseCase{
+		ruleRepo: ruleRepo,
+	}
+}
+
+// Execute creates a rule for the owner in ctx. The scheduler runs it with the
+// Spotify credential stored for that owner.
+func (uc *CreateRuleUseCase) Execute(ctx context.Context, req RuleRequest) (*domainRule.Rule, error) {
+	r := &domainRule.Rule{
+		ID:    uuid.NewString(),
+		Owner: shared.OwnerFromContext(ctx),
+	}
+	if err := applyRequest(r, req); err != nil {
+		return nil, err
+	}
+
+	if err := uc.ruleRepo.Create(ctx, r); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
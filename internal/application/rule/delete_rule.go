@@ -0,0 +1,42 @@
+package rule
+
+import (
+	"context"
+
+	domainRule "github.com/RubenPari/clear-songs/internal/domain/rule"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+)
+
+// DeleteRuleUseCase handles the business logic for deleting a cleanup rule
+type DeleteRuleUseCase struct {
+	ruleRepo domainRule.Repository
+}
+
+// NewDeleteRuleUseCase creates a new DeleteRuleUseCase
+func NewDeleteRuleUseCase(ruleRepo domainRule.Repository) *DeleteRuleUseCase {
+	return &DeleteRuleUseCase{
+		ruleRepo: ruleRepo,
+	}
+}
+
+// Execute deletes a rule belonging to the owner in ctx
+func (uc *DeleteRuleUseCase) Execute(ctx context.Context, ruleID string) error {
+	if _, err := getOwnedRule(ctx, uc.ruleRepo, ruleID); err != nil {
+		return err
+	}
+
+	return uc.ruleRepo.Delete(ctx, ruleID)
+}
+
+// getOwnedRule returns a rule if it belongs to the owner in ctx; rules of other
+// owners are reported as not found
+func getOwnedRule(ctx context.Context, ruleRepo domainRule.Repository, ruleID string) (*domainRule.Rule, error) {
+	r, err := ruleRepo.GetByID(ctx, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	if r.Owner != shared.OwnerFromContext(ctx) {
+		return nil, shared.ErrNotFound
+	}
+	return r, nil
+}
@@ -0,0 +1,71 @@
+package rule
+
+import (
+	"time"
+
+	"github.com/RubenPari/clear-songs/internal/application/shared/dto"
+	domainRule "github.com/RubenPari/clear-songs/internal/domain/rule"
+)
+
+// RuleRequest is the body of the create and update rule endpoints
+type RuleRequest struct {
+	Name       string              `json:"name" binding:"required,max=200"`
+	Schedule   string              `json:"schedule" binding:"required"`
+	Enabled    *bool               `json:"enabled"` // defaults to true
+	DryRun     bool                `json:"dry_run"`
+	WebhookURL string              `json:"webhook_url" binding:"omitempty,url"`
+	Criteria   domainRule.Criteria `json:"criteria"`
+}
+
+// RunRequest holds the query parameters of the manual run endpoint
+type RunRequest struct {
+	DryRun bool `form:"dry_run"`
+}
+
+// RuleResponse represents a cleanup rule in API responses
+type RuleResponse struct {
+	ID             string              `json:"id"`
+	Name           string              `json:"name"`
+	Schedule       string              `json:"schedule"`
+	Enabled        bool                `json:"enabled"`
+	DryRun         bool                `json:"dry_run"`
+	WebhookURL     string              `json:"webhook_url,omitempty"`
+	Criteria       domainRule.Criteria `json:"criteria"`
+	LastRunAt      *time.Time          `json:"last_run_at,omitempty"`
+	LastMatchCount int                 `json:"last_match_count"`
+	LastError      string              `json:"last_error,omitempty"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+}
+
+// NewRuleResponse converts a rule to its API representation
+func NewRuleResponse(r *domainRule.Rule) RuleResponse {
+	return RuleResponse{
+		ID:             r.ID,
+		Name:           r.Name,
+		Schedule:       r.Schedule,
+		Enabled:        r.Enabled,
+		DryRun:         r.DryRun,
+		WebhookURL:     r.WebhookURL,
+		Criteria:       r.Criteria,
+		LastRunAt:      r.LastRunAt,
+		LastMatchCount: r.LastMatchCount,
+		LastError:      r.LastError,
+		CreatedAt:      r.CreatedAt,
+		UpdatedAt:      r.UpdatedAt,
+	}
+}
+
+// RunOptions changes how a rule run behaves
+type RunOptions struct {
+	// DryRun only evaluates the rule, even if the rule itself is not a dry run
+	DryRun bool
+	// Scheduled marks runs started by the scheduler rather than by the user
+	Scheduled bool
+}
+
+// RunResult is the outcome of a rule run: the report sent to notifiers and the matched tracks
+type RunResult struct {
+	Report  *domainRule.RunReport `json:"report"`
+	Preview *dto.DeletionPreview  `json:"preview"`
+}
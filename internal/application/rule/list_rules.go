@@ -0,0 +1,25 @@
+package rule
+
+import (
+	"context"
+
+	domainRule "github.com/RubenPari/clear-songs/internal/domain/rule"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+)
+
+// ListRulesUseCase handles the business logic for listing the owner's cleanup rules
+type ListRulesUseCase struct {
+	ruleRepo domainRule.Repository
+}
+
+// NewListRulesUseCase creates a new ListRulesUseCase
+func NewListRulesUseCase(ruleRepo domainRule.Repository) *ListRulesUseCase {
+	return &ListRulesUseCase{
+		ruleRepo: ruleRepo,
+	}
+}
+
+// Execute returns the rules of the owner in ctx, oldest first
+func (uc *ListRulesUseCase) Execute(ctx context.Context) ([]domainRule.Rule, error) {
+	return uc.ruleRepo.ListByOwner(ctx, shared.OwnerFromContext(ctx))
+}
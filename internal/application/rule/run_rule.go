@@ -0,0 +1,166 @@
+package rule
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/RubenPari/clear-songs/internal/application/shared/dto"
+	"github.com/RubenPari/clear-songs/internal/application/track"
+	"github.com/RubenPari/clear-songs/internal/application/usertracks"
+	domainRule "github.com/RubenPari/clear-songs/internal/domain/rule"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	domainTrack "github.com/RubenPari/clear-songs/internal/domain/track"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+// RunRuleUseCase handles the business logic for evaluating a cleanup rule and
+// deleting the tracks it matches
+type RunRuleUseCase struct {
+	spotifyRepo    shared.SpotifyRepository
+	cacheRepo      shared.CacheRepository
+	userTracks     *usertracks.Provider
+	ruleRepo       domainRule.Repository
+	deleteTracksUC *track.DeleteTracksUseCase
+	notifiers      []domainRule.Notifier
+}
+
+// NewRunRuleUseCase creates a new RunRuleUseCase
+func NewRunRuleUseCase(
+	spotifyRepo shared.SpotifyRepository,
+	cacheRepo shared.CacheRepository,
+	userTracks *usertracks.Provider,
+	ruleRepo domainRule.Repository,
+	deleteTracksUC *track.DeleteTracksUseCase,
+	notifiers []domainRule.Notifier,
+) *RunRuleUseCase {
+	return &RunRuleUseCase{
+		spotifyRepo:    spotifyRepo,
+		cacheRepo:      cacheRepo,
+		userTracks:     userTracks,
+		ruleRepo:       ruleRepo,
+		deleteTracksUC: deleteTracksUC,
+		notifiers:      notifiers,
+	}
+}
+
+// Execute evaluates a rule belonging to the owner in ctx and, unless the rule or opts
+// ask for a dry run, deletes the matched tracks. The outcome is stored on the rule
+// and sent to every notifier, whether the run succeeded or not.
+func (uc *RunRuleUseCase) Execute(ctx context.Context, ruleID string, opts RunOptions) (*RunResult, error) {
+	// 1. Get the rule (only its owner may run it)
+	r, err := getOwnedRule(ctx, uc.ruleRepo, ruleID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domainRule.RunReport{
+		RuleID:    r.ID,
+		RuleName:  r.Name,
+		DryRun:    r.DryRun || opts.DryRun,
+		Scheduled: opts.Scheduled,
+		RanAt:     time.Now(),
+	}
+
+	// 2. Evaluate the criteria against the library
+	matches, runErr := uc.match(ctx, r.Criteria, report.RanAt)
+
+	preview := dto.NewDeletionPreview()
+	preview.DryRun = report.DryRun
+	for _, match := range matches {
+		preview.AddTrack(match)
+	}
+	report.MatchedCount = len(matches)
+
+	// 3. Delete the matched tracks
+	if runErr == nil && !report.DryRun && len(matches) > 0 {
+		runErr = uc.deleteTracksUC.Execute(ctx, matches, map[string]string{
+			"rule_id":   r.ID,
+			"rule_name": r.Name,
+		})
+		if runErr == nil {
+			report.DeletedCount = len(matches)
+		}
+	}
+	if runErr != nil {
+		report.Error = runErr.Error()
+	}
+
+	// 4. Store the outcome on the rule
+	r.LastRunAt = &report.RanAt
+	r.LastMatchCount = report.MatchedCount
+	r.LastError = report.Error
+	if err := uc.ruleRepo.Update(context.WithoutCancel(ctx), r); err != nil {
+		log.Printf("WARNING: Failed to store run of rule %s: %v", r.ID, err)
+	}
+
+	// 5. Notify
+	for _, notifier := range uc.notifiers {
+		if err := notifier.Notify(context.WithoutCancel(ctx), r, report); err != nil {
+			log.Printf("WARNING: Failed to notify run of rule %s: %v", r.ID, err)
+		}
+	}
+
+	if runErr != nil {
+		return nil, runErr
+	}
+
+	return &RunResult{Report: report, Preview: preview}, nil
+}
+
+// match returns the saved tracks that satisfy every criterion
+func (uc *RunRuleUseCase) match(ctx context.Context, criteria domainRule.Criteria, now time.Time) ([]spotifyAPI.FullTrack, error) {
+	tracks, err := uc.userTracks.GetUserTracks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	library := domainRule.Library{Now: now}
+	if criteria.ArtistMinTracks != nil || criteria.ArtistMaxTracks != nil {
+		counts := domainTrack.CountArtistTracks(tracks, criteria.ArtistMatch)
+		library.Artists = criteria.ArtistFilter(counts)
+	}
+
+	if criteria.NeedsPlaylists() {
+		library.PlaylistTrackIDs, err = uc.getPlaylistTrackIDs(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	matches := []spotifyAPI.FullTrack{}
+	for _, track := range tracks {
+		if criteria.Matches(track, library) {
+			matches = append(matches, track.FullTrack)
+		}
+	}
+
+	return matches, nil
+}
+
+// getPlaylistTrackIDs collects the tracks of every playlist owned or followed by the user
+func (uc *RunRuleUseCase) getPlaylistTrackIDs(ctx context.Context) (map[spotifyAPI.ID]bool, error) {
+	playlists, err := uc.spotifyRepo.GetAllUserPlaylists(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	trackIDs := make(map[spotifyAPI.ID]bool)
+	for _, playlist := range playlists {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		tracks, err := uc.spotifyRepo.GetAllPlaylistTracks(ctx, playlist.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, track := range tracks {
+			if track.Track.ID != "" {
+				trackIDs[track.Track.ID] = true
+			}
+		}
+	}
+
+	return trackIDs, nil
+}
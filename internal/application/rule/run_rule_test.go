@@ -0,0 +1,264 @@
+package rule
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appBackup "github.com/RubenPari/clear-songs/internal/application/backup"
+	"github.com/RubenPari/clear-songs/internal/application/track"
+	"github.com/RubenPari/clear-songs/internal/application/usertracks"
+	domainRule "github.com/RubenPari/clear-songs/internal/domain/rule"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	domainTrack "github.com/RubenPari/clear-songs/internal/domain/track"
+	"github.com/RubenPari/clear-songs/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+// fakeRuleRepository keeps rules in a map
+type fakeRuleRepository struct {
+	rules map[string]domainRule.Rule
+}
+
+func (r *fakeRuleRepository) Create(ctx context.Context, value *domainRule.Rule) error {
+	r.rules[value.ID] = *value
+	return nil
+}
+
+func (r *fakeRuleRepository) Update(ctx context.Context, value *domainRule.Rule) error {
+	r.rules[value.ID] = *value
+	return nil
+}
+
+func (r *fakeRuleRepository) Delete(ctx context.Context, id string) error {
+	delete(r.rules, id)
+	return nil
+}
+
+func (r *fakeRuleRepository) GetByID(ctx context.Context, id string) (*domainRule.Rule, error) {
+	value, exists := r.rules[id]
+	if !exists {
+		return nil, shared.ErrNotFound
+	}
+	return &value, nil
+}
+
+func (r *fakeRuleRepository) ListByOwner(ctx context.Context, owner string) ([]domainRule.Rule, error) {
+	return nil, nil
+}
+
+func (r *fakeRuleRepository) ListEnabled(ctx context.Context) ([]domainRule.Rule, error) {
+	return nil, nil
+}
+
+// recordingNotifier keeps the reports it receives
+type recordingNotifier struct {
+	reports []domainRule.RunReport
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, r *domainRule.Rule, report *domainRule.RunReport) error {
+	n.reports = append(n.reports, *report)
+	return nil
+}
+
+func savedTrack(id, artistID, album string, popularity int, addedAt time.Time) spotifyAPI.SavedTrack {
+	return spotifyAPI.SavedTrack{
+		AddedAt: addedAt.Format(spotifyAPI.TimestampLayout),
+		FullTrack: spotifyAPI.FullTrack{
+			SimpleTrack: spotifyAPI.SimpleTrack{
+				ID:       spotifyAPI.ID(id),
+				Name:     id,
+				Artists:  []spotifyAPI.SimpleArtist{{ID: spotifyAPI.ID(artistID), Name: artistID}},
+				Duration: 200000,
+			},
+			Album:      spotifyAPI.SimpleAlbum{Name: album},
+			Popularity: popularity,
+		},
+	}
+}
+
+func intPtr(value int) *int { return &value }
+
+func boolPtr(value bool) *bool { return &value }
+
+func TestRunRuleUseCase_Execute(t *testing.T) {
+	ctx := shared.WithOwner(context.Background(), "user:1")
+	old := time.Now().AddDate(-2, 0, 0)
+	recent := time.Now().AddDate(0, 0, -3)
+	featuring := savedTrack("track_6", "artist_5", "Single", 20, recent) // featuring artist_1, recently added
+	featuring.Artists = append(featuring.Artists, spotifyAPI.SimpleArtist{ID: "artist_1", Name: "artist_1"})
+	tracks := []spotifyAPI.SavedTrack{
+		savedTrack("track_1", "artist_1", "Single", 10, old),   // lone artist, old, in no playlist
+		savedTrack("track_2", "artist_2", "Album", 80, old),    // artist with two tracks
+		savedTrack("track_3", "artist_2", "Album", 80, old),    // artist with two tracks
+		savedTrack("track_4", "artist_3", "Single", 5, recent), // lone artist, recently added
+		savedTrack("track_5", "artist_4", "Single", 15, old),   // lone artist, in a playlist
+		featuring,
+	}
+	newRule := func() domainRule.Rule {
+		return domainRule.Rule{
+			ID:       "rule_1",
+			Owner:    "user:1",
+			Name:     "Forgotten one-offs",
+			Schedule: "@monthly",
+			Enabled:  true,
+			Criteria: domainRule.Criteria{
+				ArtistMaxTracks:   intPtr(1),
+				AddedMoreThanDays: intPtr(365),
+				InPlaylist:        boolPtr(false),
+			},
+		}
+	}
+	setup := func(r domainRule.Rule) (*RunRuleUseCase, *mocks.MockSpotifyRepository, *fakeRuleRepository, *recordingNotifier) {
+		mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+		mockCacheRepo := new(mocks.MockCacheRepository)
+		mockDatabaseRepo := new(mocks.MockDatabaseRepository)
+		ruleRepo := &fakeRuleRepository{rules: map[string]domainRule.Rule{r.ID: r}}
+		notifier := &recordingNotifier{}
+
+		mockCacheRepo.On("GetUserTracks", mock.Anything).Return(tracks, nil)
+		mockCacheRepo.On("InvalidateUserTracks", mock.Anything).Return(nil)
+		mockDatabaseRepo.On("SaveTracksBackup", mock.Anything, mock.Anything).Return(nil)
+		mockSpotifyRepo.On("GetAllUserPlaylists", mock.Anything).Return([]spotifyAPI.SimplePlaylist{{ID: "playlist_1"}}, nil)
+		mockSpotifyRepo.On("GetAllPlaylistTracks", mock.Anything, spotifyAPI.ID("playlist_1")).Return([]spotifyAPI.PlaylistTrack{
+			{Track: tracks[4].FullTrack},
+		}, nil)
+
+		deleteTracksUC := track.NewDeleteTracksUseCase(mockSpotifyRepo, mockCacheRepo, appBackup.NewWriter(mockDatabaseRepo, appBackup.PolicyBestEffort), nil)
+		useCase := NewRunRuleUseCase(mockSpotifyRepo, mockCacheRepo, usertracks.NewProvider(mockSpotifyRepo, mockCacheRepo, nil, 0), ruleRepo, deleteTracksUC, []domainRule.Notifier{notifier})
+		return useCase, mockSpotifyRepo, ruleRepo, notifier
+	}
+
+	t.Run("Success - should delete only the tracks matching every criterion", func(t *testing.T) {
+		useCase, mockSpotifyRepo, ruleRepo, notifier := setup(newRule())
+		mockSpotifyRepo.On("DeleteTracksFromLibrary", mock.Anything, []spotifyAPI.ID{"track_1"}).Return(nil)
+
+		result, err := useCase.Execute(ctx, "rule_1", RunOptions{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Report.MatchedCount)
+		assert.Equal(t, 1, result.Report.DeletedCount)
+		assert.False(t, result.Preview.DryRun)
+		assert.Equal(t, "track_1", result.Preview.Tracks[0].ID)
+		assert.NotNil(t, ruleRepo.rules["rule_1"].LastRunAt)
+		assert.Equal(t, 1, ruleRepo.rules["rule_1"].LastMatchCount)
+		assert.Len(t, notifier.reports, 1)
+		mockSpotifyRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - dry run should not delete anything", func(t *testing.T) {
+		useCase, mockSpotifyRepo, _, notifier := setup(newRule())
+
+		result, err := useCase.Execute(ctx, "rule_1", RunOptions{DryRun: true})
+
+		assert.NoError(t, err)
+		assert.True(t, result.Report.DryRun)
+		assert.Equal(t, 1, result.Report.MatchedCount)
+		assert.Equal(t, 0, result.Report.DeletedCount)
+		assert.True(t, notifier.reports[0].DryRun)
+		mockSpotifyRepo.AssertNotCalled(t, "DeleteTracksFromLibrary", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success - playlists are not fetched when no criterion needs them", func(t *testing.T) {
+		r := newRule()
+		r.Criteria = domainRule.Criteria{Album: "album", PopularityMin: intPtr(50)}
+		useCase, mockSpotifyRepo, _, _ := setup(r)
+		mockSpotifyRepo.On("DeleteTracksFromLibrary", mock.Anything, []spotifyAPI.ID{"track_2", "track_3"}).Return(nil)
+
+		result, err := useCase.Execute(ctx, "rule_1", RunOptions{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Report.DeletedCount)
+		mockSpotifyRepo.AssertNotCalled(t, "GetAllUserPlaylists", mock.Anything)
+	})
+
+	t.Run("Success - artist counts should follow the artist match mode", func(t *testing.T) {
+		for match, expected := range map[domainTrack.ArtistMatch][]string{
+			"":                             {},
+			domainTrack.ArtistMatchAny:     {"track_1", "track_6"},
+			domainTrack.ArtistMatchAll:     {"track_1", "track_6"},
+			domainTrack.ArtistMatchPrimary: {},
+		} {
+			r := newRule()
+			r.Criteria = domainRule.Criteria{ArtistMinTracks: intPtr(2), ArtistMatch: match, Album: "single"}
+			useCase, _, _, _ := setup(r)
+
+			result, err := useCase.Execute(ctx, "rule_1", RunOptions{DryRun: true})
+
+			assert.NoError(t, err)
+			ids := []string{}
+			for _, track := range result.Preview.Tracks {
+				ids = append(ids, track.ID)
+			}
+			assert.ElementsMatch(t, expected, ids, "match %q", match)
+		}
+	})
+
+	t.Run("Error - deletion failure should be stored and notified", func(t *testing.T) {
+		useCase, mockSpotifyRepo, ruleRepo, notifier := setup(newRule())
+		mockSpotifyRepo.On("DeleteTracksFromLibrary", mock.Anything, mock.Anything).Return(assert.AnError)
+
+		result, err := useCase.Execute(ctx, "rule_1", RunOptions{})
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Nil(t, result)
+		assert.Equal(t, assert.AnError.Error(), ruleRepo.rules["rule_1"].LastError)
+		assert.Equal(t, assert.AnError.Error(), notifier.reports[0].Error)
+	})
+
+	t.Run("Error - rules of other owners are not found", func(t *testing.T) {
+		useCase, _, _, notifier := setup(newRule())
+
+		_, err := useCase.Execute(shared.WithOwner(context.Background(), "user:2"), "rule_1", RunOptions{})
+
+		assert.ErrorIs(t, err, shared.ErrNotFound)
+		assert.Empty(t, notifier.reports)
+	})
+}
+
+func TestCreateRuleUseCase_Execute(t *testing.T) {
+	ctx := shared.WithOwner(context.Background(), "user:1")
+	repo := &fakeRuleRepository{rules: map[string]domainRule.Rule{}}
+	useCase := NewCreateRuleUseCase(repo)
+
+	t.Run("Success - should create an enabled rule owned by the owner", func(t *testing.T) {
+		created, err := useCase.Execute(ctx, RuleRequest{
+			Name:     "Low popularity",
+			Schedule: "0 3 * * 0",
+			Criteria: domainRule.Criteria{PopularityMax: intPtr(10)},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user:1", created.Owner)
+		assert.True(t, created.Enabled)
+		assert.Contains(t, repo.rules, created.ID)
+	})
+
+	t.Run("Error - invalid schedule", func(t *testing.T) {
+		_, err := useCase.Execute(ctx, RuleRequest{
+			Name:     "Broken",
+			Schedule: "every sunday",
+			Criteria: domainRule.Criteria{PopularityMax: intPtr(10)},
+		})
+
+		assert.ErrorIs(t, err, shared.ErrValidation)
+	})
+
+	t.Run("Error - unknown artist match", func(t *testing.T) {
+		_, err := useCase.Execute(ctx, RuleRequest{
+			Name:     "Broken",
+			Schedule: "@daily",
+			Criteria: domainRule.Criteria{ArtistMaxTracks: intPtr(1), ArtistMatch: "featured"},
+		})
+
+		assert.ErrorIs(t, err, shared.ErrValidation)
+	})
+
+	t.Run("Error - a rule without criteria would match the whole library", func(t *testing.T) {
+		_, err := useCase.Execute(ctx, RuleRequest{Name: "Everything", Schedule: "@daily"})
+
+		assert.ErrorIs(t, err, shared.ErrValidation)
+	})
+}
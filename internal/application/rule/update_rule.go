@@ -0,0 +1,37 @@
+package rule
+
+import (
+	"context"
+
+	domainRule "github.com/RubenPari/clear-songs/internal/domain/rule"
+)
+
+// UpdateRuleUseCase handles the business logic for replacing a cleanup rule
+type UpdateRuleUseCase struct {
+	ruleRepo domainRule.Repository
+}
+
+// NewUpdateRuleUseCase creates a new UpdateRuleUseCase
+func NewUpdateRuleUseCase(ruleRepo domainRule.Repository) *UpdateRuleUseCase {
+	return &UpdateRuleUseCase{
+		ruleRepo: ruleRepo,
+	}
+}
+
+// Execute replaces the definition of a rule belonging to the owner in ctx, keeping its run history
+func (uc *UpdateRuleUseCase) Execute(ctx context.Context, ruleID string, req RuleRequest) (*domainRule.Rule, error) {
+	r, err := getOwnedRule(ctx, uc.ruleRepo, ruleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyRequest(r, req); err != nil {
+		return nil, err
+	}
+
+	if err := uc.ruleRepo.Update(ctx, r); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
@@ -0,0 +1,78 @@
+package rule
+
+import (
+	"fmt"
+	"net/url"
+
+	domainRule "github.com/RubenPari/clear-songs/internal/domain/rule"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	"github.com/RubenPari/clear-songs/internal/domain/track"
+	"github.com/robfig/cron/v3"
+)
+
+// applyRequest validates req and copies it onto r
+func applyRequest(r *domainRule.Rule, req RuleRequest) error {
+	if _, err := cron.ParseStandard(req.Schedule); err != nil {
+		return fmt.Errorf("%w: invalid schedule: %v", shared.ErrValidation, err)
+	}
+	if err := validateCriteria(req.Criteria); err != nil {
+		return err
+	}
+	if err := validateWebhookURL(req.WebhookURL); err != nil {
+		return err
+	}
+
+	r.Name = req.Name
+	r.Schedule = req.Schedule
+	r.Enabled = req.Enabled == nil || *req.Enabled
+	r.DryRun = req.DryRun
+	r.WebhookURL = req.WebhookURL
+	r.Criteria = req.Criteria
+	return nil
+}
+
+// validateWebhookURL accepts an empty URL or an absolute http(s) one, so the server
+// never posts reports to another scheme
+func validateWebhookURL(value string) error {
+	if value == "" {
+		return nil
+	}
+	parsed, err := url.ParseRequestURI(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%w: webhook_url must be an http or https URL", shared.ErrValidation)
+	}
+	return nil
+}
+
+func validateCriteria(c domainRule.Criteria) error {
+	// An empty rule would delete the whole library
+	if c.IsEmpty() {
+		return fmt.Errorf("%w: at least one criterion is required", shared.ErrValidation)
+	}
+
+	if _, err := track.ParseArtistMatch(string(c.ArtistMatch)); err != nil {
+		return fmt.Errorf("%w: artist_match must be primary, any or all", shared.ErrValidation)
+	}
+
+	bounds := []struct {
+		name     string
+		min, max *int
+	}{
+		{"artist_tracks", c.ArtistMinTracks, c.ArtistMaxTracks},
+		{"popularity", c.PopularityMin, c.PopularityMax},
+		{"duration_seconds", c.DurationMinSeconds, c.DurationMaxSeconds},
+		{"added_days", c.AddedMoreThanDays, c.AddedLessThanDays},
+	}
+	for _, b := range bounds {
+		if (b.min != nil && *b.min < 0) || (b.max != nil && *b.max < 0) {
+			return fmt.Errorf("%w: %s bounds must not be negative", shared.ErrValidation, b.name)
+		}
+	}
+	for _, b := range bounds[:3] {
+		if b.min != nil && b.max != nil && *b.min > *b.max {
+			return fmt.Errorf("%w: %s minimum is greater than maximum", shared.ErrValidation, b.name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package rule
+
+import (
+	"testing"
+
+	domainRule "github.com/RubenPari/clear-songs/internal/domain/rule"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyRequest_WebhookURL(t *testing.T) {
+	days := 30
+	request := func(webhookURL string) RuleRequest {
+		return RuleRequest{
+			Name:       "old tracks",
+			Schedule:   "0 3 * * *",
+			WebhookURL: webhookURL,
+			Criteria:   domainRule.Criteria{AddedMoreThanDays: &days},
+		}
+	}
+
+	t.Run("Success - should accept an empty or http(s) webhook URL", func(t *testing.T) {
+		for _, webhookURL := range []string{"", "http://example.com/hook", "https://example.com:8443/hook?token=1"} {
+			var r domainRule.Rule
+			assert.NoError(t, applyRequest(&r, request(webhookURL)), webhookURL)
+			assert.Equal(t, webhookURL, r.WebhookURL)
+		}
+	})
+
+	t.Run("Error - should reject a webhook URL that is not http(s) or has no host", func(t *testing.T) {
+		for _, webhookURL := range []string{"file:///etc/passwd", "gopher://example.com", "http://", "example.com/hook", "https:///hook"} {
+			var r domainRule.Rule
+			assert.ErrorIs(t, applyRequest(&r, request(webhookURL)), shared.ErrValidation, webhookURL)
+			assert.Empty(t, r.WebhookURL)
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package dto
+
+import (
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+// DeletionPreview lists what a destructive operation would remove, without removing anything
+type DeletionPreview struct {
+	DryRun      bool           `json:"dry_run"`
+	TotalTracks int            `json:"total_tracks"`
+	Tracks      []PreviewTrack `json:"tracks"`
+	Artists     []PreviewTotal `json:"artists"`
+	Playlists   []PreviewTotal `json:"playlists"`
+
+	artistIndex   map[string]int
+	playlistIndex map[string]int
+}
+
+// PreviewTrack is a track that would be removed
+type PreviewTrack struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Artists    []string `json:"artists"`
+	PlaylistID string   `json:"playlist_id,omitempty"`
+}
+
+// PreviewTotal counts the tracks that would be removed for an artist or a playlist
+type PreviewTotal struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// NewDeletionPreview creates an empty preview
+func NewDeletionPreview() *DeletionPreview {
+	return &DeletionPreview{
+		DryRun:        true,
+		Tracks:        []PreviewTrack{},
+		Artists:       []PreviewTotal{},
+		Playlists:     []PreviewTotal{},
+		artistIndex:   make(map[string]int),
+		playlistIndex: make(map[string]int),
+	}
+}
+
+// AddTrack records a track removed from the library, counted under its primary artist
+func (p *DeletionPreview) AddTrack(track spotifyAPI.FullTrack) {
+	p.addTrack(track, "")
+}
+
+// AddPlaylistTrack records a track removed from a playlist
+func (p *DeletionPreview) AddPlaylistTrack(playlistID spotifyAPI.ID, playlistName string, track spotifyAPI.FullTrack) {
+	p.addTrack(track, playlistID.String())
+
+	i, exists := p.playlistIndex[playlistID.String()]
+	if !exists {
+		i = len(p.Playlists)
+		p.playlistIndex[playlistID.String()] = i
+		p.Playlists = append(p.Playlists, PreviewTotal{ID: playlistID.String(), Name: playlistName})
+	}
+	p.Playlists[i].Count++
+}
+
+// Merge appends the tracks and totals of another preview
+func (p *DeletionPreview) Merge(other *DeletionPreview) {
+	if other == nil {
+		return
+	}
+
+	p.Tracks = append(p.Tracks, other.Tracks...)
+	p.TotalTracks += other.TotalTracks
+
+	for _, artist := range other.Artists {
+		p.addTotal(&p.Artists, p.artistIndex, artist)
+	}
+	for _, playlist := range other.Playlists {
+		p.addTotal(&p.Playlists, p.playlistIndex, playlist)
+	}
+}
+
+func (p *DeletionPreview) addTrack(track spotifyAPI.FullTrack, playlistID string) {
+	artists := make([]string, len(track.Artists))
+	for i, artist := range track.Artists {
+		artists[i] = artist.Name
+	}
+
+	p.Tracks = append(p.Tracks, PreviewTrack{
+		ID:         track.ID.String(),
+		Name:       track.Name,
+		Artists:    artists,
+		PlaylistID: playlistID,
+	})
+	p.TotalTracks++
+
+	if len(track.Artists) > 0 {
+		p.addTotal(&p.Artists, p.artistIndex, PreviewTotal{
+			ID:    track.Artists[0].ID.String(),
+			Name:  track.Artists[0].Name,
+			Count: 1,
+		})
+	}
+}
+
+func (p *DeletionPreview) addTotal(totals *[]PreviewTotal, index map[string]int, total PreviewTotal) {
+	if i, exists := index[total.ID]; exists {
+		(*totals)[i].Count += total.Count
+		return
+	}
+	index[total.ID] = len(*totals)
+	*totals = append(*totals, total)
+}
@@ -20,8 +20,28 @@ type Error struct {
 
 // Meta contains metadata about the response
 type Meta struct {
-	Timestamp string `json:"timestamp"`
-	RequestID string `json:"request_id,omitempty"`
+	Timestamp  string      `json:"timestamp"`
+	RequestID  string      `json:"request_id,omitempty"`
+	Pagination *Pagination `json:"pagination,omitempty"`
+}
+
+// Pagination describes the page of a list returned in Data
+type Pagination struct {
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	// NextOffset is the offset of the next page, absent on the last page
+	NextOffset *int `json:"next_offset,omitempty"`
+}
+
+// NewPagination describes the page starting at offset, limit items long, of a list
+// of total items
+func NewPagination(total, limit, offset int) *Pagination {
+	pagination := &Pagination{Total: total, Limit: limit, Offset: offset}
+	if next := offset + limit; limit > 0 && next < total {
+		pagination.NextOffset = &next
+	}
+	return pagination
 }
 
 // NewSuccess creates a successful response with typed data
@@ -35,6 +55,13 @@ func NewSuccess[T any](data T) APIResponse[T] {
 	}
 }
 
+// NewPage creates a successful response holding a page of a list
+func NewPage[T any](data T, pagination *Pagination) APIResponse[T] {
+	response := NewSuccess(data)
+	response.Meta.Pagination = pagination
+	return response
+}
+
 // NewError creates an error response
 func NewError(code, message string) APIResponse[any] {
 	return APIResponse[any]{
@@ -0,0 +1,82 @@
+package snapshot
+
+import (
+	"context"
+	"time"
+
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	domainSnapshot "github.com/RubenPari/clear-songs/internal/domain/snapshot"
+	"github.com/google/uuid"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+// CaptureLibraryUseCase handles the business logic for taking library snapshots
+type CaptureLibraryUseCase struct {
+	spotifyRepo  shared.SpotifyRepository
+	snapshotRepo domainSnapshot.LibraryRepository
+}
+
+// NewCaptureLibraryUseCase creates a new CaptureLibraryUseCase
+func NewCaptureLibraryUseCase(
+	spotifyRepo shared.SpotifyRepository,
+	snapshotRepo domainSnapshot.LibraryRepository,
+) *CaptureLibraryUseCase {
+	return &CaptureLibraryUseCase{
+		spotifyRepo:  spotifyRepo,
+		snapshotRepo: snapshotRepo,
+	}
+}
+
+// Execute stores the tracks currently saved in the library of the owner in ctx
+func (uc *CaptureLibraryUseCase) Execute(ctx context.Context, reason string) (*domainSnapshot.LibrarySnapshot, error) {
+	// 1. Read the library
+	snapshot, err := uc.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Store it
+	snapshot.ID = uuid.NewString()
+	snapshot.Owner = shared.OwnerFromContext(ctx)
+	snapshot.Reason = reason
+	if err := uc.snapshotRepo.Create(ctx, snapshot); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// read returns the tracks currently saved in the library, without storing them. The
+// library is always read from Spotify so that changes made elsewhere are seen.
+func (uc *CaptureLibraryUseCase) read(ctx context.Context) (*domainSnapshot.LibrarySnapshot, error) {
+	tracks, err := uc.spotifyRepo.GetAllUserTracks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &domainSnapshot.LibrarySnapshot{
+		Tracks:     make([]domainSnapshot.LibraryTrack, 0, len(tracks)),
+		TrackCount: len(tracks),
+	}
+	for _, track := range tracks {
+		addedAt, _ := time.Parse(spotifyAPI.TimestampLayout, track.AddedAt)
+		snapshot.Tracks = append(snapshot.Tracks, domainSnapshot.LibraryTrack{
+			TrackID: track.ID.String(),
+			AddedAt: addedAt,
+		})
+	}
+
+	return snapshot, nil
+}
+
+// getOwnedLibrary returns a library snapshot taken by the owner in ctx
+func getOwnedLibrary(ctx context.Context, repo domainSnapshot.LibraryRepository, id string) (*domainSnapshot.LibrarySnapshot, error) {
+	snapshot, err := repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot.Owner != shared.OwnerFromContext(ctx) {
+		return nil, shared.ErrNotFound
+	}
+	return snapshot, nil
+}
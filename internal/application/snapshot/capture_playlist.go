@@ -0,0 +1,105 @@
+package snapshot
+
+import (
+	"context"
+	"log"
+
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	domainSnapshot "github.com/RubenPari/clear-songs/internal/domain/snapshot"
+	"github.com/google/uuid"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+// CapturePlaylistUseCase handles the business logic for taking playlist snapshots.
+// A nil CapturePlaylistUseCase takes no automatic snapshots.
+type CapturePlaylistUseCase struct {
+	spotifyRepo  shared.SpotifyRepository
+	snapshotRepo domainSnapshot.PlaylistRepository
+}
+
+// NewCapturePlaylistUseCase creates a new CapturePlaylistUseCase
+func NewCapturePlaylistUseCase(
+	spotifyRepo shared.SpotifyRepository,
+	snapshotRepo domainSnapshot.PlaylistRepository,
+) *CapturePlaylistUseCase {
+	return &CapturePlaylistUseCase{
+		spotifyRepo:  spotifyRepo,
+		snapshotRepo: snapshotRepo,
+	}
+}
+
+// Execute stores a snapshot of the current state of a playlist for the owner in ctx
+func (uc *CapturePlaylistUseCase) Execute(ctx context.Context, playlistID spotifyAPI.ID, reason string) (*domainSnapshot.PlaylistSnapshot, error) {
+	// 1. Read the playlist
+	snapshot, err := uc.read(ctx, playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Store it
+	snapshot.ID = uuid.NewString()
+	snapshot.Owner = shared.OwnerFromContext(ctx)
+	snapshot.Reason = reason
+	if err := uc.snapshotRepo.Create(ctx, snapshot); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// Before snapshots a playlist before the mutation named by reason. Failures are only
+// logged: like backups, snapshots must not prevent the mutation.
+func (uc *CapturePlaylistUseCase) Before(ctx context.Context, playlistID spotifyAPI.ID, reason string) {
+	if uc == nil {
+		return
+	}
+
+	if _, err := uc.Execute(ctx, playlistID, reason); err != nil {
+		log.Printf("WARNING: Failed to snapshot playlist %s before %s: %v", playlistID, reason, err)
+	}
+}
+
+// read returns the current state of a playlist, without storing it
+func (uc *CapturePlaylistUseCase) read(ctx context.Context, playlistID spotifyAPI.ID) (*domainSnapshot.PlaylistSnapshot, error) {
+	playlist, err := uc.spotifyRepo.GetPlaylist(ctx, playlistID)
+	if err != nil {
+		return nil, err
+	}
+	tracks, err := uc.spotifyRepo.GetAllPlaylistTracks(ctx, playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &domainSnapshot.PlaylistSnapshot{
+		PlaylistID:        playlistID.String(),
+		SpotifySnapshotID: playlist.SnapshotID,
+		Name:              playlist.Name,
+		Description:       playlist.Description,
+		TrackIDs:          make([]string, len(tracks)),
+		TrackCount:        len(tracks),
+	}
+	if len(playlist.Images) > 0 {
+		snapshot.ImageURL = playlist.Images[0].URL
+	}
+	for i, item := range tracks {
+		if item.Track.ID != "" {
+			snapshot.TrackIDs[i] = item.Track.ID.String()
+		} else {
+			snapshot.TrackIDs[i] = string(item.Track.URI)
+		}
+	}
+
+	return snapshot, nil
+}
+
+// getOwned returns a snapshot of the playlist taken by the owner in ctx
+func getOwned(ctx context.Context, repo domainSnapshot.PlaylistRepository, playlistID spotifyAPI.ID, id string) (*domainSnapshot.PlaylistSnapshot, error) {
+	snapshot, err := repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot.Owner != shared.OwnerFromContext(ctx) || snapshot.PlaylistID != playlistID.String() {
+		return nil, shared.ErrNotFound
+	}
+	return snapshot, nil
+}
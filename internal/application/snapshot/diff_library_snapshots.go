@@ -0,0 +1,55 @@
+package snapshot
+
+import (
+	"context"
+
+	domainSnapshot "github.com/RubenPari/clear-songs/internal/domain/snapshot"
+)
+
+// DiffLibrarySnapshotsUseCase handles the business logic for comparing library snapshots
+type DiffLibrarySnapshotsUseCase struct {
+	snapshotRepo domainSnapshot.LibraryRepository
+	captureUC    *CaptureLibraryUseCase
+}
+
+// NewDiffLibrarySnapshotsUseCase creates a new DiffLibrarySnapshotsUseCase
+func NewDiffLibrarySnapshotsUseCase(
+	snapshotRepo domainSnapshot.LibraryRepository,
+	captureUC *CaptureLibraryUseCase,
+) *DiffLibrarySnapshotsUseCase {
+	return &DiffLibrarySnapshotsUseCase{
+		snapshotRepo: snapshotRepo,
+		captureUC:    captureUC,
+	}
+}
+
+// Execute lists the tracks saved and removed between two library snapshots, or between a
+// snapshot and the current library. Removals made outside this app are included.
+func (uc *DiffLibrarySnapshotsUseCase) Execute(ctx context.Context, req LibraryDiffRequest) (*LibraryDiffResult, error) {
+	// 1. Get the snapshots
+	from, err := getOwnedLibrary(ctx, uc.snapshotRepo, req.From)
+	if err != nil {
+		return nil, err
+	}
+
+	var to *domainSnapshot.LibrarySnapshot
+	if req.To != "" {
+		to, err = getOwnedLibrary(ctx, uc.snapshotRepo, req.To)
+	} else {
+		to, err = uc.captureUC.read(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Compare them
+	result := &LibraryDiffResult{
+		From:        req.From,
+		To:          req.To,
+		LibraryDiff: domainSnapshot.DiffLibrary(from.Tracks, to.Tracks),
+	}
+	result.TotalAdded = len(result.Added)
+	result.TotalRemoved = len(result.Removed)
+
+	return result, nil
+}
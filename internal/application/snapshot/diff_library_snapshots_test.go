@@ -0,0 +1,60 @@
+package snapshot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	domainSnapshot "github.com/RubenPari/clear-songs/internal/domain/snapshot"
+	"github.com/RubenPari/clear-songs/test/mocks"
+	"github.com/stretchr/testify/assert"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+func TestDiffLibrarySnapshotsUseCase_Execute(t *testing.T) {
+	ctx := shared.WithOwner(context.Background(), "spotify:user_1")
+	savedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	mockSnapshotRepo := new(mocks.MockLibrarySnapshotRepository)
+	mockSnapshotRepo.On("GetByID", ctx, "monday").Return(&domainSnapshot.LibrarySnapshot{
+		ID: "monday", Owner: "spotify:user_1",
+		Tracks: []domainSnapshot.LibraryTrack{{TrackID: "a", AddedAt: savedAt}, {TrackID: "b", AddedAt: savedAt}},
+	}, nil)
+	mockSnapshotRepo.On("GetByID", ctx, "other").Return(&domainSnapshot.LibrarySnapshot{ID: "other", Owner: "spotify:user_2"}, nil)
+
+	// "b" was removed outside the app and "c" saved since monday
+	mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+	mockSpotifyRepo.On("GetAllUserTracks", ctx).Return([]spotifyAPI.SavedTrack{
+		{AddedAt: "2024-06-01T08:00:00Z", FullTrack: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{ID: "c"}}},
+		{AddedAt: "2024-05-01T10:00:00Z", FullTrack: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{ID: "a"}}},
+	}, nil)
+	mockSpotifyRepo.On("AddTracksToLibrary", ctx, []spotifyAPI.ID{"b"}).Return(nil)
+
+	diffUC := NewDiffLibrarySnapshotsUseCase(mockSnapshotRepo, NewCaptureLibraryUseCase(mockSpotifyRepo, mockSnapshotRepo))
+
+	t.Run("Compares a snapshot with the current library", func(t *testing.T) {
+		result, err := diffUC.Execute(ctx, LibraryDiffRequest{From: "monday"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.TotalAdded)
+		assert.Equal(t, "c", result.Added[0].TrackID)
+		assert.Equal(t, time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC), result.Added[0].AddedAt)
+		assert.Equal(t, []domainSnapshot.LibraryTrack{{TrackID: "b", AddedAt: savedAt}}, result.Removed)
+	})
+
+	t.Run("Restore saves the removed tracks again", func(t *testing.T) {
+		useCase := NewRestoreLibraryDiffUseCase(mockSpotifyRepo, nil, diffUC)
+		result, err := useCase.Execute(ctx, LibraryDiffRequest{From: "monday"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.TracksRestored)
+		mockSpotifyRepo.AssertCalled(t, "AddTracksToLibrary", ctx, []spotifyAPI.ID{"b"})
+	})
+
+	t.Run("Snapshots of other owners are not found", func(t *testing.T) {
+		_, err := diffUC.Execute(ctx, LibraryDiffRequest{From: "other"})
+
+		assert.ErrorIs(t, err, shared.ErrNotFound)
+	})
+}
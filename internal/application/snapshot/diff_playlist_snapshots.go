@@ -0,0 +1,53 @@
+package snapshot
+
+import (
+	"context"
+
+	domainSnapshot "github.com/RubenPari/clear-songs/internal/domain/snapshot"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+// DiffPlaylistSnapshotsUseCase handles the business logic for comparing playlist snapshots
+type DiffPlaylistSnapshotsUseCase struct {
+	snapshotRepo domainSnapshot.PlaylistRepository
+	captureUC    *CapturePlaylistUseCase
+}
+
+// NewDiffPlaylistSnapshotsUseCase creates a new DiffPlaylistSnapshotsUseCase
+func NewDiffPlaylistSnapshotsUseCase(
+	snapshotRepo domainSnapshot.PlaylistRepository,
+	captureUC *CapturePlaylistUseCase,
+) *DiffPlaylistSnapshotsUseCase {
+	return &DiffPlaylistSnapshotsUseCase{
+		snapshotRepo: snapshotRepo,
+		captureUC:    captureUC,
+	}
+}
+
+// Execute lists the tracks added, removed and moved between two snapshots of a playlist,
+// or between a snapshot and the current playlist
+func (uc *DiffPlaylistSnapshotsUseCase) Execute(ctx context.Context, playlistID spotifyAPI.ID, req PlaylistDiffRequest) (*PlaylistDiffResult, error) {
+	// 1. Get the snapshots
+	from, err := getOwned(ctx, uc.snapshotRepo, playlistID, req.From)
+	if err != nil {
+		return nil, err
+	}
+
+	var to *domainSnapshot.PlaylistSnapshot
+	if req.To != "" {
+		to, err = getOwned(ctx, uc.snapshotRepo, playlistID, req.To)
+	} else {
+		to, err = uc.captureUC.read(ctx, playlistID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Compare them
+	return &PlaylistDiffResult{
+		PlaylistID: playlistID.String(),
+		From:       req.From,
+		To:         req.To,
+		TrackDiff:  domainSnapshot.DiffTracks(from.TrackIDs, to.TrackIDs),
+	}, nil
+}
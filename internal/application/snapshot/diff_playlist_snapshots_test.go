@@ -0,0 +1,68 @@
+package snapshot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	domainSnapshot "github.com/RubenPari/clear-songs/internal/domain/snapshot"
+	"github.com/RubenPari/clear-songs/test/mocks"
+	"github.com/stretchr/testify/assert"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+func playlistItems(ids ...string) []spotifyAPI.PlaylistTrack {
+	items := make([]spotifyAPI.PlaylistTrack, len(ids))
+	for i, id := range ids {
+		items[i] = spotifyAPI.PlaylistTrack{Track: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{ID: spotifyAPI.ID(id)}}}
+	}
+	return items
+}
+
+func TestDiffPlaylistSnapshotsUseCase_Execute(t *testing.T) {
+	ctx := shared.WithOwner(context.Background(), "spotify:user_1")
+	playlistID := spotifyAPI.ID("playlist_1")
+
+	mockSnapshotRepo := new(mocks.MockPlaylistSnapshotRepository)
+	mockSnapshotRepo.On("GetByID", ctx, "old").Return(&domainSnapshot.PlaylistSnapshot{
+		ID: "old", Owner: "spotify:user_1", PlaylistID: "playlist_1",
+		TrackIDs: []string{"a", "b", "c", "d", "e"},
+	}, nil)
+	mockSnapshotRepo.On("GetByID", ctx, "new").Return(&domainSnapshot.PlaylistSnapshot{
+		ID: "new", Owner: "spotify:user_1", PlaylistID: "playlist_1",
+		TrackIDs: []string{"x", "a", "c", "d", "b", "e"},
+	}, nil)
+	mockSnapshotRepo.On("GetByID", ctx, "other").Return(&domainSnapshot.PlaylistSnapshot{
+		ID: "other", Owner: "spotify:user_2", PlaylistID: "playlist_1",
+	}, nil)
+
+	mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+	mockSpotifyRepo.On("GetPlaylist", ctx, playlistID).Return(&spotifyAPI.FullPlaylist{}, nil)
+	mockSpotifyRepo.On("GetAllPlaylistTracks", ctx, playlistID).Return(playlistItems("a", "b", "c", "d"), nil)
+
+	useCase := NewDiffPlaylistSnapshotsUseCase(mockSnapshotRepo, NewCapturePlaylistUseCase(mockSpotifyRepo, mockSnapshotRepo))
+
+	t.Run("Reports additions and moves, not the tracks shifted by them", func(t *testing.T) {
+		result, err := useCase.Execute(ctx, playlistID, PlaylistDiffRequest{From: "old", To: "new"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domainSnapshot.TrackChange{{TrackID: "x", Position: 0}}, result.Added)
+		assert.Empty(t, result.Removed)
+		assert.Equal(t, []domainSnapshot.TrackMove{{TrackID: "b", From: 1, To: 4}}, result.Moved)
+	})
+
+	t.Run("Without to compares with the current playlist", func(t *testing.T) {
+		result, err := useCase.Execute(ctx, playlistID, PlaylistDiffRequest{From: "old"})
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.Added)
+		assert.Equal(t, []domainSnapshot.TrackChange{{TrackID: "e", Position: 4}}, result.Removed)
+		assert.Empty(t, result.Moved)
+	})
+
+	t.Run("Snapshots of other owners are not found", func(t *testing.T) {
+		_, err := useCase.Execute(ctx, playlistID, PlaylistDiffRequest{From: "other"})
+
+		assert.ErrorIs(t, err, shared.ErrNotFound)
+	})
+}
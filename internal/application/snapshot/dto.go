@@ -0,0 +1,107 @@
+package snapshot
+
+import (
+	"time"
+
+	domainSnapshot "github.com/RubenPari/clear-songs/internal/domain/snapshot"
+)
+
+// PlaylistSnapshotResponse represents a playlist snapshot in API responses
+type PlaylistSnapshotResponse struct {
+	ID                string    `json:"id"`
+	PlaylistID        string    `json:"playlist_id"`
+	SpotifySnapshotID string    `json:"spotify_snapshot_id"`
+	Name              string    `json:"name"`
+	Description       string    `json:"description"`
+	ImageURL          string    `json:"image_url,omitempty"`
+	Reason            string    `json:"reason"`
+	TrackCount        int       `json:"track_count"`
+	TrackIDs          []string  `json:"track_ids,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// NewPlaylistSnapshotResponse converts a playlist snapshot to its API representation
+func NewPlaylistSnapshotResponse(s *domainSnapshot.PlaylistSnapshot) PlaylistSnapshotResponse {
+	return PlaylistSnapshotResponse{
+		ID:                s.ID,
+		PlaylistID:        s.PlaylistID,
+		SpotifySnapshotID: s.SpotifySnapshotID,
+		Name:              s.Name,
+		Description:       s.Description,
+		ImageURL:          s.ImageURL,
+		Reason:            s.Reason,
+		TrackCount:        s.TrackCount,
+		TrackIDs:          s.TrackIDs,
+		CreatedAt:         s.CreatedAt,
+	}
+}
+
+// PlaylistDiffRequest selects the snapshots to compare. Without To, From is compared
+// with the current playlist.
+type PlaylistDiffRequest struct {
+	From string `form:"from" binding:"required"`
+	To   string `form:"to"`
+}
+
+// PlaylistDiffResult lists the changes from one playlist snapshot to another
+type PlaylistDiffResult struct {
+	PlaylistID string `json:"playlist_id"`
+	From       string `json:"from"`
+	// To is empty when comparing with the current playlist
+	To string `json:"to,omitempty"`
+	domainSnapshot.TrackDiff
+}
+
+// RestorePlaylistResult summarises a playlist restore
+type RestorePlaylistResult struct {
+	PlaylistID string `json:"playlist_id"`
+	SnapshotID string `json:"snapshot_id"`
+	// PreviousSnapshotID is the snapshot taken before the restore, to revert it
+	PreviousSnapshotID string `json:"previous_snapshot_id"`
+	TracksRestored     int    `json:"tracks_restored"`
+	// LocalFilesSkipped counts local files, which cannot be added through the API
+	LocalFilesSkipped int `json:"local_files_skipped"`
+}
+
+// LibrarySnapshotResponse represents a library snapshot in API responses
+type LibrarySnapshotResponse struct {
+	ID         string    `json:"id"`
+	Reason     string    `json:"reason"`
+	TrackCount int       `json:"track_count"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// NewLibrarySnapshotResponse converts a library snapshot to its API representation
+func NewLibrarySnapshotResponse(s *domainSnapshot.LibrarySnapshot) LibrarySnapshotResponse {
+	return LibrarySnapshotResponse{
+		ID:         s.ID,
+		Reason:     s.Reason,
+		TrackCount: s.TrackCount,
+		CreatedAt:  s.CreatedAt,
+	}
+}
+
+// LibraryDiffRequest selects the library snapshots to compare. Without To, From is
+// compared with the current library.
+type LibraryDiffRequest struct {
+	From  string `form:"from" binding:"required"`
+	To    string `form:"to"`
+	Async bool   `form:"async"`
+}
+
+// LibraryDiffResult lists the tracks saved and removed from one library snapshot to another
+type LibraryDiffResult struct {
+	From string `json:"from"`
+	// To is empty when comparing with the current library
+	To           string `json:"to,omitempty"`
+	TotalAdded   int    `json:"total_added"`
+	TotalRemoved int    `json:"total_removed"`
+	domainSnapshot.LibraryDiff
+}
+
+// RestoreLibraryResult summarises the re-saving of the tracks removed between two snapshots
+type RestoreLibraryResult struct {
+	From           string `json:"from"`
+	To             string `json:"to,omitempty"`
+	TracksRestored int    `json:"tracks_restored"`
+}
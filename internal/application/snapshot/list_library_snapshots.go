@@ -0,0 +1,25 @@
+package snapshot
+
+import (
+	"context"
+
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	domainSnapshot "github.com/RubenPari/clear-songs/internal/domain/snapshot"
+)
+
+// ListLibrarySnapshotsUseCase handles the business logic for listing the history of the library
+type ListLibrarySnapshotsUseCase struct {
+	snapshotRepo domainSnapshot.LibraryRepository
+}
+
+// NewListLibrarySnapshotsUseCase creates a new ListLibrarySnapshotsUseCase
+func NewListLibrarySnapshotsUseCase(snapshotRepo domainSnapshot.LibraryRepository) *ListLibrarySnapshotsUseCase {
+	return &ListLibrarySnapshotsUseCase{
+		snapshotRepo: snapshotRepo,
+	}
+}
+
+// Execute returns the most recent library snapshots of the owner in ctx, newest first
+func (uc *ListLibrarySnapshotsUseCase) Execute(ctx context.Context, limit int) ([]domainSnapshot.LibrarySnapshot, error) {
+	return uc.snapshotRepo.ListByOwner(ctx, shared.OwnerFromContext(ctx), limit)
+}
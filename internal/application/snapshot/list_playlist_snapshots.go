@@ -0,0 +1,26 @@
+package snapshot
+
+import (
+	"context"
+
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	domainSnapshot "github.com/RubenPari/clear-songs/internal/domain/snapshot"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+// ListPlaylistSnapshotsUseCase handles the business logic for listing the history of a playlist
+type ListPlaylistSnapshotsUseCase struct {
+	snapshotRepo domainSnapshot.PlaylistRepository
+}
+
+// NewListPlaylistSnapshotsUseCase creates a new ListPlaylistSnapshotsUseCase
+func NewListPlaylistSnapshotsUseCase(snapshotRepo domainSnapshot.PlaylistRepository) *ListPlaylistSnapshotsUseCase {
+	return &ListPlaylistSnapshotsUseCase{
+		snapshotRepo: snapshotRepo,
+	}
+}
+
+// Execute returns the most recent snapshots of a playlist taken by the owner in ctx, newest first
+func (uc *ListPlaylistSnapshotsUseCase) Execute(ctx context.Context, playlistID spotifyAPI.ID, limit int) ([]domainSnapshot.PlaylistSnapshot, error) {
+	return uc.snapshotRepo.ListByPlaylist(ctx, shared.OwnerFromContext(ctx), playlistID.String(), limit)
+}
@@ -0,0 +1,63 @@
+package snapshot
+
+import (
+	"context"
+
+	"github.com/RubenPari/clear-songs/internal/domain/job"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+// RestoreLibraryDiffUseCase handles the business logic for re-saving the tracks that
+// disappeared from the library between two snapshots
+type RestoreLibraryDiffUseCase struct {
+	spotifyRepo shared.SpotifyRepository
+	cacheRepo   shared.CacheRepository
+	diffUC      *DiffLibrarySnapshotsUseCase
+}
+
+// NewRestoreLibraryDiffUseCase creates a new RestoreLibraryDiffUseCase
+func NewRestoreLibraryDiffUseCase(
+	spotifyRepo shared.SpotifyRepository,
+	cacheRepo shared.CacheRepository,
+	diffUC *DiffLibrarySnapshotsUseCase,
+) *RestoreLibraryDiffUseCase {
+	return &RestoreLibraryDiffUseCase{
+		spotifyRepo: spotifyRepo,
+		cacheRepo:   cacheRepo,
+		diffUC:      diffUC,
+	}
+}
+
+// Execute saves again the tracks removed between the two snapshots of req
+func (uc *RestoreLibraryDiffUseCase) Execute(ctx context.Context, req LibraryDiffRequest) (*RestoreLibraryResult, error) {
+	// 1. Find the removed tracks
+	diff, err := uc.diffUC.Execute(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RestoreLibraryResult{From: req.From, To: req.To}
+	if len(diff.Removed) == 0 {
+		return result, nil
+	}
+
+	// 2. Save them again
+	trackIDs := make([]spotifyAPI.ID, len(diff.Removed))
+	for i, track := range diff.Removed {
+		trackIDs[i] = spotifyAPI.ID(track.TrackID)
+	}
+	job.ReportTotal(ctx, len(trackIDs))
+
+	if err := uc.spotifyRepo.AddTracksToLibrary(ctx, trackIDs); err != nil {
+		return nil, err
+	}
+	result.TracksRestored = len(trackIDs)
+
+	// 3. Invalidate cache
+	if uc.cacheRepo != nil {
+		_ = uc.cacheRepo.InvalidateUserTracks(ctx)
+	}
+
+	return result, nil
+}
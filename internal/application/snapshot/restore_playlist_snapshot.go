@@ -0,0 +1,85 @@
+package snapshot
+
+import (
+	"context"
+	"strings"
+
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	domainSnapshot "github.com/RubenPari/clear-songs/internal/domain/snapshot"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+// RestorePlaylistSnapshotUseCase handles the business logic for restoring a playlist to a snapshot
+type RestorePlaylistSnapshotUseCase struct {
+	spotifyRepo  shared.SpotifyRepository
+	cacheRepo    shared.CacheRepository
+	snapshotRepo domainSnapshot.PlaylistRepository
+	captureUC    *CapturePlaylistUseCase
+}
+
+// NewRestorePlaylistSnapshotUseCase creates a new RestorePlaylistSnapshotUseCase
+func NewRestorePlaylistSnapshotUseCase(
+	spotifyRepo shared.SpotifyRepository,
+	cacheRepo shared.CacheRepository,
+	snapshotRepo domainSnapshot.PlaylistRepository,
+	captureUC *CapturePlaylistUseCase,
+) *RestorePlaylistSnapshotUseCase {
+	return &RestorePlaylistSnapshotUseCase{
+		spotifyRepo:  spotifyRepo,
+		cacheRepo:    cacheRepo,
+		snapshotRepo: snapshotRepo,
+		captureUC:    captureUC,
+	}
+}
+
+// Execute replaces the items of a playlist with those of a snapshot and restores its
+// name and description. The current state is snapshotted first so the restore can
+// itself be reverted; the image is not restored.
+func (uc *RestorePlaylistSnapshotUseCase) Execute(ctx context.Context, playlistID spotifyAPI.ID, snapshotID string) (*RestorePlaylistResult, error) {
+	// 1. Get the snapshot
+	target, err := getOwned(ctx, uc.snapshotRepo, playlistID, snapshotID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Snapshot the current state (required, it is the only way back)
+	previous, err := uc.captureUC.Execute(ctx, playlistID, domainSnapshot.ReasonRestore)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RestorePlaylistResult{
+		PlaylistID:         playlistID.String(),
+		SnapshotID:         target.ID,
+		PreviousSnapshotID: previous.ID,
+	}
+
+	// 3. Replace the playlist items (local files are stored by URI and cannot be added)
+	trackIDs := make([]spotifyAPI.ID, 0, len(target.TrackIDs))
+	for _, id := range target.TrackIDs {
+		if strings.Contains(id, ":") {
+			result.LocalFilesSkipped++
+			continue
+		}
+		trackIDs = append(trackIDs, spotifyAPI.ID(id))
+	}
+
+	if err := uc.spotifyRepo.ReplacePlaylistTracks(ctx, playlistID, trackIDs); err != nil {
+		return nil, err
+	}
+	result.TracksRestored = len(trackIDs)
+
+	// 4. Restore name and description
+	if target.Name != previous.Name || target.Description != previous.Description {
+		if err := uc.spotifyRepo.UpdatePlaylistDetails(ctx, playlistID, target.Name, target.Description); err != nil {
+			return nil, err
+		}
+	}
+
+	// 5. Invalidate cache
+	if uc.cacheRepo != nil {
+		_ = uc.cacheRepo.InvalidatePlaylistTracks(ctx, playlistID)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,53 @@
+package snapshot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	domainSnapshot "github.com/RubenPari/clear-songs/internal/domain/snapshot"
+	"github.com/RubenPari/clear-songs/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+func TestRestorePlaylistSnapshotUseCase_Execute(t *testing.T) {
+	ctx := shared.WithOwner(context.Background(), "spotify:user_1")
+	playlistID := spotifyAPI.ID("playlist_1")
+
+	mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+	mockSnapshotRepo := new(mocks.MockPlaylistSnapshotRepository)
+	mockCacheRepo := new(mocks.MockCacheRepository)
+
+	mockSnapshotRepo.On("GetByID", ctx, "target").Return(&domainSnapshot.PlaylistSnapshot{
+		ID: "target", Owner: "spotify:user_1", PlaylistID: "playlist_1",
+		Name: "Road trip", Description: "Summer",
+		TrackIDs: []string{"a", "spotify:local:artist:album:song:180", "b"},
+	}, nil)
+	mockSpotifyRepo.On("GetPlaylist", ctx, playlistID).Return(&spotifyAPI.FullPlaylist{
+		SimplePlaylist: spotifyAPI.SimplePlaylist{Name: "Renamed", SnapshotID: "spotify_2"},
+		Description:    "Summer",
+	}, nil)
+	mockSpotifyRepo.On("GetAllPlaylistTracks", ctx, playlistID).Return(playlistItems("c"), nil)
+
+	var previous *domainSnapshot.PlaylistSnapshot
+	mockSnapshotRepo.On("Create", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		previous = args.Get(1).(*domainSnapshot.PlaylistSnapshot)
+	}).Return(nil)
+	mockSpotifyRepo.On("ReplacePlaylistTracks", ctx, playlistID, []spotifyAPI.ID{"a", "b"}).Return(nil)
+	mockSpotifyRepo.On("UpdatePlaylistDetails", ctx, playlistID, "Road trip", "Summer").Return(nil)
+
+	capture := NewCapturePlaylistUseCase(mockSpotifyRepo, mockSnapshotRepo)
+	useCase := NewRestorePlaylistSnapshotUseCase(mockSpotifyRepo, mockCacheRepo, mockSnapshotRepo, capture)
+	result, err := useCase.Execute(ctx, playlistID, "target")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.TracksRestored)
+	assert.Equal(t, 1, result.LocalFilesSkipped)
+	assert.Equal(t, previous.ID, result.PreviousSnapshotID)
+	assert.Equal(t, domainSnapshot.ReasonRestore, previous.Reason)
+	assert.Equal(t, []string{"c"}, previous.TrackIDs)
+	assert.Equal(t, "spotify_2", previous.SpotifySnapshotID)
+	mockSpotifyRepo.AssertExpectations(t)
+}
@@ -2,8 +2,20 @@ package track
 
 // ArtistSummary represents an artist summary in API responses
 type ArtistSummary struct {
-	Id       string `json:"id"`
-	Name     string `json:"name"`
-	Count    int    `json:"count"`
-	ImageURL string `json:"image_url,omitempty"`
+	Id    string `json:"id"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+	// PrimaryCount and FeaturedCount split the tracks by the position of the artist
+	PrimaryCount  int    `json:"primary_count"`
+	FeaturedCount int    `json:"featured_count"`
+	ImageURL      string `json:"image_url,omitempty"`
+	LastAddedAt   string `json:"last_added_at,omitempty"`
+}
+
+// GenreSummary represents a genre summary in API responses
+type GenreSummary struct {
+	Genre       string          `json:"genre"`
+	Count       int             `json:"count"`
+	ArtistCount int             `json:"artist_count"`
+	TopArtists  []ArtistSummary `json:"top_artists"`
 }
@@ -3,27 +3,34 @@ package track
 import (
 	"context"
 
+	appBackup "github.com/RubenPari/clear-songs/internal/application/backup"
+	appOperation "github.com/RubenPari/clear-songs/internal/application/operation"
+	"github.com/RubenPari/clear-songs/internal/domain/backup"
+	"github.com/RubenPari/clear-songs/internal/domain/operation"
 	"github.com/RubenPari/clear-songs/internal/domain/shared"
 	spotifyAPI "github.com/zmb3/spotify"
 )
 
 // DeleteTrackUseCase handles the business logic for deleting a single track
 type DeleteTrackUseCase struct {
-	spotifyRepo  shared.SpotifyRepository
-	cacheRepo    shared.CacheRepository
-	databaseRepo shared.DatabaseRepository
+	spotifyRepo shared.SpotifyRepository
+	cacheRepo   shared.CacheRepository
+	backups     *appBackup.Writer
+	recorder    *appOperation.Recorder
 }
 
 // NewDeleteTrackUseCase creates a new DeleteTrackUseCase
 func NewDeleteTrackUseCase(
 	spotifyRepo shared.SpotifyRepository,
 	cacheRepo shared.CacheRepository,
-	databaseRepo shared.DatabaseRepository,
+	backups *appBackup.Writer,
+	recorder *appOperation.Recorder,
 ) *DeleteTrackUseCase {
 	return &DeleteTrackUseCase{
-		spotifyRepo:  spotifyRepo,
-		cacheRepo:    cacheRepo,
-		databaseRepo: databaseRepo,
+		spotifyRepo: spotifyRepo,
+		cacheRepo:   cacheRepo,
+		backups:     backups,
+		recorder:    recorder,
 	}
 }
 
@@ -35,9 +42,15 @@ func (uc *DeleteTrackUseCase) Execute(ctx context.Context, trackID spotifyAPI.ID
 		return err
 	}
 
-	// 2. Save backup to database
-	if err := uc.databaseRepo.SaveFullTracksBackup([]spotifyAPI.FullTrack{*track}); err != nil {
-		// Log error but continue
+	op := &operation.Operation{
+		Kind:            operation.KindDeleteTrack,
+		Params:          map[string]string{"track_id": trackID.String()},
+		LibraryTrackIDs: []string{trackID.String()},
+	}
+
+	// 2. Back up the track before removing it
+	if err := uc.backups.Save(ctx, backup.FromFullTracks(appOperation.BackupOrigin(ctx, op), []spotifyAPI.FullTrack{*track})); err != nil {
+		return err
 	}
 
 	// 3. Delete track from library
@@ -50,5 +63,8 @@ func (uc *DeleteTrackUseCase) Execute(ctx context.Context, trackID spotifyAPI.ID
 		_ = uc.cacheRepo.InvalidateUserTracks(ctx)
 	}
 
+	// 5. Record the operation so it can be undone
+	uc.recorder.Record(ctx, op)
+
 	return nil
 }
@@ -0,0 +1,82 @@
+package track
+
+import (
+	"context"
+
+	appBackup "github.com/RubenPari/clear-songs/internal/application/backup"
+	appOperation "github.com/RubenPari/clear-songs/internal/application/operation"
+	"github.com/RubenPari/clear-songs/internal/domain/backup"
+	"github.com/RubenPari/clear-songs/internal/domain/job"
+	"github.com/RubenPari/clear-songs/internal/domain/operation"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+// DeleteTracksUseCase handles the business logic for deleting an explicit list of
+// tracks, selected by another feature such as cleanup rules
+type DeleteTracksUseCase struct {
+	spotifyRepo shared.SpotifyRepository
+	cacheRepo   shared.CacheRepository
+	backups     *appBackup.Writer
+	recorder    *appOperation.Recorder
+}
+
+// NewDeleteTracksUseCase creates a new DeleteTracksUseCase
+func NewDeleteTracksUseCase(
+	spotifyRepo shared.SpotifyRepository,
+	cacheRepo shared.CacheRepository,
+	backups *appBackup.Writer,
+	recorder *appOperation.Recorder,
+) *DeleteTracksUseCase {
+	return &DeleteTracksUseCase{
+		spotifyRepo: spotifyRepo,
+		cacheRepo:   cacheRepo,
+		backups:     backups,
+		recorder:    recorder,
+	}
+}
+
+// Execute deletes tracks from the user's library. params describe why the tracks
+// were selected and are stored with the recorded operation.
+func (uc *DeleteTracksUseCase) Execute(ctx context.Context, tracks []spotifyAPI.FullTrack, params map[string]string) error {
+	return uc.deleteTracks(ctx, operation.KindDeleteTracks, tracks, params)
+}
+
+// deleteTracks backs up and deletes tracks, recording the operation under kind
+func (uc *DeleteTracksUseCase) deleteTracks(ctx context.Context, kind operation.Kind, tracks []spotifyAPI.FullTrack, params map[string]string) error {
+	if len(tracks) == 0 {
+		return nil
+	}
+	job.ReportTotal(ctx, len(tracks))
+
+	trackIDs := make([]spotifyAPI.ID, len(tracks))
+	for i, track := range tracks {
+		trackIDs[i] = track.ID
+	}
+
+	op := &operation.Operation{
+		Kind:            kind,
+		Params:          params,
+		LibraryTrackIDs: appOperation.TrackIDs(trackIDs),
+	}
+
+	// 1. Back up the tracks before removing them
+	if err := uc.backups.Save(ctx, backup.FromFullTracks(appOperation.BackupOrigin(ctx, op), tracks)); err != nil {
+		return err
+	}
+
+	// 2. Delete tracks from library
+	if err := uc.spotifyRepo.DeleteTracksFromLibrary(ctx, trackIDs); err != nil {
+		return err
+	}
+
+	// 3. Invalidate cache
+	if uc.cacheRepo != nil {
+		_ = uc.cacheRepo.InvalidateUserTracks(ctx)
+	}
+
+	// 4. Record the operation so it can be undone
+	uc.recorder.Record(ctx, op)
+
+	return nil
+}
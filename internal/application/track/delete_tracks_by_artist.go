@@ -2,9 +2,16 @@ package track
 
 import (
 	"context"
-	"time"
 
+	appBackup "github.com/RubenPari/clear-songs/internal/application/backup"
+	appOperation "github.com/RubenPari/clear-songs/internal/application/operation"
+	"github.com/RubenPari/clear-songs/internal/application/shared/dto"
+	"github.com/RubenPari/clear-songs/internal/application/usertracks"
+	"github.com/RubenPari/clear-songs/internal/domain/backup"
+	"github.com/RubenPari/clear-songs/internal/domain/operation"
+	"github.com/RubenPari/clear-songs/internal/domain/job"
 	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	domainTrack "github.com/RubenPari/clear-songs/internal/domain/track"
 	spotifyAPI "github.com/zmb3/spotify"
 )
 
@@ -12,29 +19,37 @@ import (
 type DeleteTracksByArtistUseCase struct {
 	spotifyRepo shared.SpotifyRepository
 	cacheRepo   shared.CacheRepository
+	userTracks  *usertracks.Provider
+	backups     *appBackup.Writer
+	recorder    *appOperation.Recorder
 }
 
 // NewDeleteTracksByArtistUseCase creates a new DeleteTracksByArtistUseCase
 func NewDeleteTracksByArtistUseCase(
 	spotifyRepo shared.SpotifyRepository,
 	cacheRepo   shared.CacheRepository,
+	userTracks *usertracks.Provider,
+	backups *appBackup.Writer,
+	recorder *appOperation.Recorder,
 ) *DeleteTracksByArtistUseCase {
 	return &DeleteTracksByArtistUseCase{
 		spotifyRepo: spotifyRepo,
 		cacheRepo:   cacheRepo,
+		userTracks:  userTracks,
+		backups:     backups,
+		recorder:    recorder,
 	}
 }
 
-// Execute deletes all tracks from a specific artist
-func (uc *DeleteTracksByArtistUseCase) Execute(ctx context.Context, artistID spotifyAPI.ID) error {
-	// 1. Get user tracks (from cache or API)
-	tracks, err := uc.getUserTracks(ctx)
-	if err != nil {
-		return err
+// Execute deletes all tracks matching the artist filter
+func (uc *DeleteTracksByArtistUseCase) Execute(ctx context.Context, filter domainTrack.ArtistFilter) error {
+	op := &operation.Operation{
+		Kind:   operation.KindDeleteTracksByArtist,
+		Params: map[string]string{"artist_id": filter.String(), "match": string(filter.Match)},
 	}
 
-	// 2. Filter tracks by artist
-	trackIDs, err := uc.spotifyRepo.GetTrackIDsByArtist(ctx, artistID, tracks)
+	// 1-4. Find, back up and delete the artist tracks
+	trackIDs, err := uc.deleteTracks(ctx, filter, appOperation.BackupOrigin(ctx, op))
 	if err != nil {
 		return err
 	}
@@ -43,39 +58,83 @@ func (uc *DeleteTracksByArtistUseCase) Execute(ctx context.Context, artistID spo
 		return nil // No tracks to delete
 	}
 
-	// 3. Delete tracks from library
-	if err := uc.spotifyRepo.DeleteTracksFromLibrary(ctx, trackIDs); err != nil {
-		return err
-	}
-
-	// 4. Invalidate cache
+	// 5. Invalidate cache
 	if uc.cacheRepo != nil {
 		_ = uc.cacheRepo.InvalidateUserTracks(ctx)
 	}
 
+	// 6. Record the operation so it can be undone
+	op.LibraryTrackIDs = appOperation.TrackIDs(trackIDs)
+	uc.recorder.Record(ctx, op)
+
 	return nil
 }
 
-// getUserTracks retrieves tracks from cache or API
-func (uc *DeleteTracksByArtistUseCase) getUserTracks(ctx context.Context) ([]spotifyAPI.SavedTrack, error) {
-	// Try cache first (if available)
-	if uc.cacheRepo != nil {
-		cached, err := uc.cacheRepo.GetUserTracks(ctx)
-		if err == nil && cached != nil && len(cached) > 0 {
-			return cached, nil
-		}
+// deleteTracks backs up the artist tracks as removed by origin, removes them from the
+// library and returns their IDs
+func (uc *DeleteTracksByArtistUseCase) deleteTracks(ctx context.Context, filter domainTrack.ArtistFilter, origin backup.Origin) ([]spotifyAPI.ID, error) {
+	// 1. Get user tracks (from cache or the library mirror)
+	tracks, err := uc.userTracks.GetUserTracks(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	// Fetch from API
-	tracks, err := uc.spotifyRepo.GetAllUserTracks(ctx)
+	// 2. Filter tracks by artist
+	artistTracks, err := uc.spotifyRepo.GetTracksByArtist(ctx, filter, tracks)
 	if err != nil {
 		return nil, err
 	}
 
-	// Cache for future use (if cache is available)
-	if uc.cacheRepo != nil {
-		_ = uc.cacheRepo.SetUserTracks(ctx, tracks, 5*time.Minute)
+	// 3-4. Back up and delete them
+	return uc.removeTracks(ctx, artistTracks, origin)
+}
+
+// removeTracks backs up tracks as removed by origin, removes them from the library and
+// returns their IDs
+func (uc *DeleteTracksByArtistUseCase) removeTracks(ctx context.Context, tracks []spotifyAPI.SavedTrack, origin backup.Origin) ([]spotifyAPI.ID, error) {
+	if len(tracks) == 0 {
+		return nil, nil
+	}
+	job.ReportTotal(ctx, len(tracks))
+
+	fullTracks := make([]spotifyAPI.FullTrack, len(tracks))
+	trackIDs := make([]spotifyAPI.ID, len(tracks))
+	for i, track := range tracks {
+		fullTracks[i] = track.FullTrack
+		trackIDs[i] = track.ID
+	}
+
+	// Back up the tracks before removing them
+	if err := uc.backups.Save(ctx, backup.FromFullTracks(origin, fullTracks)); err != nil {
+		return nil, err
+	}
+
+	if err := uc.spotifyRepo.DeleteTracksFromLibrary(ctx, trackIDs); err != nil {
+		return nil, err
+	}
+
+	return trackIDs, nil
+}
+
+// Preview lists the tracks Execute would delete for an artist filter, without modifying the library
+func (uc *DeleteTracksByArtistUseCase) Preview(ctx context.Context, filter domainTrack.ArtistFilter) (*dto.DeletionPreview, error) {
+	// 1. Get user tracks (from cache or the library mirror)
+	tracks, err := uc.userTracks.GetUserTracks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Filter tracks by artist
+	artistTracks, err := uc.spotifyRepo.GetTracksByArtist(ctx, filter, tracks)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Build preview
+	preview := dto.NewDeletionPreview()
+	for _, track := range artistTracks {
+		preview.AddTrack(track.FullTrack)
 	}
 
-	return tracks, nil
+	return preview, nil
 }
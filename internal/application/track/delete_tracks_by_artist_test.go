@@ -4,6 +4,11 @@ import (
 	"context"
 	"testing"
 
+	appBackup "github.com/RubenPari/clear-songs/internal/application/backup"
+	"github.com/RubenPari/clear-songs/internal/application/usertracks"
+	"github.com/RubenPari/clear-songs/internal/domain/backup"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	domainTrack "github.com/RubenPari/clear-songs/internal/domain/track"
 	"github.com/RubenPari/clear-songs/test/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -13,12 +18,13 @@ import (
 func TestDeleteTracksByArtistUseCase_Execute(t *testing.T) {
 	mockSpotifyRepo := new(mocks.MockSpotifyRepository)
 	mockCacheRepo := new(mocks.MockCacheRepository)
+	mockDatabaseRepo := new(mocks.MockDatabaseRepository)
 	
-	useCase := NewDeleteTracksByArtistUseCase(mockSpotifyRepo, mockCacheRepo)
+	useCase := NewDeleteTracksByArtistUseCase(mockSpotifyRepo, mockCacheRepo, usertracks.NewProvider(mockSpotifyRepo, mockCacheRepo, nil, 0), appBackup.NewWriter(mockDatabaseRepo, appBackup.PolicyBestEffort), nil)
 	ctx := context.Background()
-	artistID := spotifyAPI.ID("artist_1")
+	filter := domainTrack.PrimaryArtist("artist_1")
 
-	t.Run("Success - should delete tracks and invalidate cache", func(t *testing.T) {
+	t.Run("Success - should back up and delete tracks and invalidate cache", func(t *testing.T) {
 		tracks := []spotifyAPI.SavedTrack{
 			{
 				FullTrack: spotifyAPI.FullTrack{
@@ -29,31 +35,104 @@ func TestDeleteTracksByArtistUseCase_Execute(t *testing.T) {
 				},
 			},
 		}
-		trackIDs := []spotifyAPI.ID{"track_1"}
 
 		mockCacheRepo.On("GetUserTracks", mock.Anything).Return(tracks, nil)
-		mockSpotifyRepo.On("GetTrackIDsByArtist", mock.Anything, mock.Anything, mock.Anything).Return(trackIDs, nil)
-		mockSpotifyRepo.On("DeleteTracksFromLibrary", mock.Anything, mock.Anything).Return(nil)
+		mockSpotifyRepo.On("GetTracksByArtist", mock.Anything, filter, tracks).Return(tracks, nil)
+		mockDatabaseRepo.On("SaveTracksBackup", mock.Anything, mock.MatchedBy(func(backedUp []backup.TrackBackup) bool {
+			return len(backedUp) == 1 && backedUp[0].ID == "track_1" && backedUp[0].Reason == "delete_tracks_by_artist"
+		})).Return(nil)
+		mockSpotifyRepo.On("DeleteTracksFromLibrary", mock.Anything, []spotifyAPI.ID{"track_1"}).Return(nil)
 		mockCacheRepo.On("InvalidateUserTracks", mock.Anything).Return(nil)
 
-		err := useCase.Execute(ctx, artistID)
+		err := useCase.Execute(ctx, filter)
 		assert.NoError(t, err)
+		mockDatabaseRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - should still delete tracks when a best-effort backup fails", func(t *testing.T) {
+		tracks := []spotifyAPI.SavedTrack{{FullTrack: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{ID: "track_1"}}}}
+
+		mockCacheRepo.ExpectedCalls = nil
+		mockSpotifyRepo.ExpectedCalls = nil
+		mockDatabaseRepo.ExpectedCalls = nil
+
+		mockCacheRepo.On("GetUserTracks", mock.Anything).Return(tracks, nil)
+		mockSpotifyRepo.On("GetTracksByArtist", mock.Anything, mock.Anything, mock.Anything).Return(tracks, nil)
+		mockDatabaseRepo.On("SaveTracksBackup", mock.Anything, mock.Anything).Return(assert.AnError)
+		mockSpotifyRepo.On("DeleteTracksFromLibrary", mock.Anything, []spotifyAPI.ID{"track_1"}).Return(nil)
+		mockCacheRepo.On("InvalidateUserTracks", mock.Anything).Return(nil)
+
+		err := useCase.Execute(ctx, filter)
+		assert.NoError(t, err)
+		mockSpotifyRepo.AssertCalled(t, "DeleteTracksFromLibrary", mock.Anything, []spotifyAPI.ID{"track_1"})
+	})
+
+	t.Run("Error - should not delete tracks when a required backup fails", func(t *testing.T) {
+		requiredSpotifyRepo := new(mocks.MockSpotifyRepository)
+		requiredCacheRepo := new(mocks.MockCacheRepository)
+		requiredDatabaseRepo := new(mocks.MockDatabaseRepository)
+		requiredUseCase := NewDeleteTracksByArtistUseCase(requiredSpotifyRepo, requiredCacheRepo, usertracks.NewProvider(requiredSpotifyRepo, requiredCacheRepo, nil, 0), appBackup.NewWriter(requiredDatabaseRepo, appBackup.PolicyRequired), nil)
+		tracks := []spotifyAPI.SavedTrack{{FullTrack: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{ID: "track_1"}}}}
+
+		requiredCacheRepo.On("GetUserTracks", mock.Anything).Return(tracks, nil)
+		requiredSpotifyRepo.On("GetTracksByArtist", mock.Anything, mock.Anything, mock.Anything).Return(tracks, nil)
+		requiredDatabaseRepo.On("SaveTracksBackup", mock.Anything, mock.Anything).Return(assert.AnError)
+
+		err := requiredUseCase.Execute(ctx, filter)
+
+		assert.ErrorIs(t, err, shared.ErrBackupFailed)
+		requiredSpotifyRepo.AssertNotCalled(t, "DeleteTracksFromLibrary", mock.Anything, mock.Anything)
+		requiredCacheRepo.AssertNotCalled(t, "InvalidateUserTracks", mock.Anything)
 	})
 
 	t.Run("Error - Spotify API failure should return error", func(t *testing.T) {
-		tracks := []spotifyAPI.SavedTrack{{}}
-		trackIDs := []spotifyAPI.ID{"track_id"}
+		tracks := []spotifyAPI.SavedTrack{{FullTrack: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{ID: "track_id"}}}}
 		
 		mockCacheRepo.ExpectedCalls = nil
 		mockSpotifyRepo.ExpectedCalls = nil
+		mockDatabaseRepo.ExpectedCalls = nil
 
 		mockCacheRepo.On("GetUserTracks", mock.Anything).Return(tracks, nil)
-		mockSpotifyRepo.On("GetTrackIDsByArtist", mock.Anything, mock.Anything, mock.Anything).Return(trackIDs, nil)
+		mockSpotifyRepo.On("GetTracksByArtist", mock.Anything, mock.Anything, mock.Anything).Return(tracks, nil)
+		mockDatabaseRepo.On("SaveTracksBackup", mock.Anything, mock.Anything).Return(nil)
 		// Force the error on the specific call
 		mockSpotifyRepo.On("DeleteTracksFromLibrary", mock.Anything, mock.Anything).Return(assert.AnError)
 
-		err := useCase.Execute(ctx, artistID)
+		err := useCase.Execute(ctx, filter)
 
 		assert.Error(t, err, "Should return error when Spotify API fails")
 	})
 }
+
+func TestDeleteTracksByArtistUseCase_Preview(t *testing.T) {
+	mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+	mockCacheRepo := new(mocks.MockCacheRepository)
+
+	useCase := NewDeleteTracksByArtistUseCase(mockSpotifyRepo, mockCacheRepo, usertracks.NewProvider(mockSpotifyRepo, mockCacheRepo, nil, 0), nil, nil)
+	ctx := context.Background()
+	filter := domainTrack.PrimaryArtist("artist_1")
+
+	t.Run("Success - should list tracks without deleting them", func(t *testing.T) {
+		artist := spotifyAPI.SimpleArtist{ID: "artist_1", Name: "Artist One"}
+		featured := spotifyAPI.SimpleArtist{ID: "artist_2", Name: "Artist Two"}
+		tracks := []spotifyAPI.SavedTrack{
+			{FullTrack: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{ID: "track_1", Name: "Song 1", Artists: []spotifyAPI.SimpleArtist{artist}}}},
+			{FullTrack: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{ID: "track_2", Name: "Song 2", Artists: []spotifyAPI.SimpleArtist{artist, featured}}}},
+		}
+
+		mockCacheRepo.On("GetUserTracks", mock.Anything).Return(tracks, nil)
+		mockSpotifyRepo.On("GetTracksByArtist", mock.Anything, filter, tracks).Return(tracks, nil)
+
+		preview, err := useCase.Preview(ctx, filter)
+
+		assert.NoError(t, err)
+		assert.True(t, preview.DryRun)
+		assert.Equal(t, 2, preview.TotalTracks)
+		assert.Equal(t, "track_2", preview.Tracks[1].ID)
+		assert.Equal(t, []string{"Artist One", "Artist Two"}, preview.Tracks[1].Artists)
+		assert.Len(t, preview.Artists, 1)
+		assert.Equal(t, 2, preview.Artists[0].Count)
+		mockSpotifyRepo.AssertNotCalled(t, "DeleteTracksFromLibrary", mock.Anything, mock.Anything)
+		mockCacheRepo.AssertNotCalled(t, "InvalidateUserTracks", mock.Anything)
+	})
+}
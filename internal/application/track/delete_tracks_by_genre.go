@@ -0,0 +1,83 @@
+package track
+
+import (
+	"context"
+
+	"github.com/RubenPari/clear-songs/internal/application/shared/dto"
+	"github.com/RubenPari/clear-songs/internal/domain/operation"
+	domainTrack "github.com/RubenPari/clear-songs/internal/domain/track"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+// DeleteTracksByGenreUseCase handles the business logic for deleting the tracks of a genre
+type DeleteTracksByGenreUseCase struct {
+	getGenreSummaryUC *GetGenreSummaryUseCase
+	deleteTracksUC    *DeleteTracksUseCase
+}
+
+// NewDeleteTracksByGenreUseCase creates a new DeleteTracksByGenreUseCase
+func NewDeleteTracksByGenreUseCase(
+	getGenreSummaryUC *GetGenreSummaryUseCase,
+	deleteTracksUC *DeleteTracksUseCase,
+) *DeleteTracksByGenreUseCase {
+	return &DeleteTracksByGenreUseCase{
+		getGenreSummaryUC: getGenreSummaryUC,
+		deleteTracksUC:    deleteTracksUC,
+	}
+}
+
+// Execute deletes the saved tracks counted under a genre, backing them up first
+func (uc *DeleteTracksByGenreUseCase) Execute(ctx context.Context, genre string, match domainTrack.ArtistMatch) error {
+	// 1. Find the tracks of the genre
+	tracks, err := uc.genreTracks(ctx, genre, match)
+	if err != nil {
+		return err
+	}
+
+	// 2. Back up, delete and record them
+	params := map[string]string{"genre": domainTrack.NormalizeGenre(genre), "match": string(match)}
+	return uc.deleteTracksUC.deleteTracks(ctx, operation.KindDeleteTracksByGenre, tracks, params)
+}
+
+// Preview lists the tracks Execute would delete for a genre, without modifying the library
+func (uc *DeleteTracksByGenreUseCase) Preview(ctx context.Context, genre string, match domainTrack.ArtistMatch) (*dto.DeletionPreview, error) {
+	tracks, err := uc.genreTracks(ctx, genre, match)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := dto.NewDeletionPreview()
+	for _, track := range tracks {
+		preview.AddTrack(track)
+	}
+
+	return preview, nil
+}
+
+// genreTracks returns the saved tracks counted under a genre, as the genre summary counts them
+func (uc *DeleteTracksByGenreUseCase) genreTracks(ctx context.Context, genre string, match domainTrack.ArtistMatch) ([]spotifyAPI.FullTrack, error) {
+	genre = domainTrack.NormalizeGenre(genre)
+
+	// 1. Get user tracks (from cache or the library mirror)
+	tracks, err := uc.getGenreSummaryUC.userTracks.GetUserTracks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Get the genres of their artists
+	artists, err := uc.getGenreSummaryUC.getArtists(ctx, tracks)
+	if err != nil {
+		return nil, err
+	}
+	genres := artistGenres(artists)
+
+	// 3. Keep the tracks of the genre
+	var genreTracks []spotifyAPI.FullTrack
+	for _, savedTrack := range tracks {
+		if _, found := domainTrack.GenreArtists(savedTrack.Artists, genres, match)[genre]; found {
+			genreTracks = append(genreTracks, savedTrack.FullTrack)
+		}
+	}
+
+	return genreTracks, nil
+}
@@ -0,0 +1,101 @@
+package track
+
+import (
+	"context"
+	"testing"
+
+	"github.com/RubenPari/clear-songs/internal/application/artistinfo"
+	appBackup "github.com/RubenPari/clear-songs/internal/application/backup"
+	"github.com/RubenPari/clear-songs/internal/application/usertracks"
+	domainTrack "github.com/RubenPari/clear-songs/internal/domain/track"
+	"github.com/RubenPari/clear-songs/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+func TestGenreSummaryAndDeletion(t *testing.T) {
+	ctx := context.Background()
+
+	savedTrack := func(id string, artists ...spotifyAPI.SimpleArtist) spotifyAPI.SavedTrack {
+		return spotifyAPI.SavedTrack{FullTrack: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{
+			ID:      spotifyAPI.ID(id),
+			Name:    "Song " + id,
+			Artists: artists,
+		}}}
+	}
+	rock := spotifyAPI.SimpleArtist{ID: "rock", Name: "Rock Band"}
+	punk := spotifyAPI.SimpleArtist{ID: "punk", Name: "Punk Band"}
+	pop := spotifyAPI.SimpleArtist{ID: "pop", Name: "Pop Singer"}
+	tracks := []spotifyAPI.SavedTrack{
+		savedTrack("track_1", rock),
+		savedTrack("track_2", rock),
+		savedTrack("track_3", punk),
+		savedTrack("track_4", pop, rock),
+	}
+	artists := []spotifyAPI.FullArtist{
+		{SimpleArtist: rock, Genres: []string{"Rock", "alternative rock"}},
+		{SimpleArtist: punk, Genres: []string{"rock", "punk"}},
+		{SimpleArtist: pop, Genres: []string{"pop"}},
+	}
+
+	setup := func() (*GetGenreSummaryUseCase, *DeleteTracksByGenreUseCase, *mocks.MockSpotifyRepository, *mocks.MockDatabaseRepository) {
+		mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+		mockDatabaseRepo := new(mocks.MockDatabaseRepository)
+		mockSpotifyRepo.On("GetAllUserTracks", ctx).Return(tracks, nil)
+		mockSpotifyRepo.On("GetArtists", ctx, mock.Anything).Return(artists, nil)
+
+		summaryUC := NewGetGenreSummaryUseCase(nil, usertracks.NewProvider(mockSpotifyRepo, nil, nil, 0), artistinfo.NewProvider(mockSpotifyRepo, nil))
+		deleteTracksUC := NewDeleteTracksUseCase(mockSpotifyRepo, nil, appBackup.NewWriter(mockDatabaseRepo, appBackup.PolicyBestEffort), nil)
+		return summaryUC, NewDeleteTracksByGenreUseCase(summaryUC, deleteTracksUC), mockSpotifyRepo, mockDatabaseRepo
+	}
+
+	t.Run("Summary counts each track once per genre of its primary artist", func(t *testing.T) {
+		summaryUC, _, _, _ := setup()
+
+		result, err := summaryUC.Execute(ctx, domainTrack.ArtistMatchPrimary, 5)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 4)
+		assert.Equal(t, "rock", result[0].Genre)
+		assert.Equal(t, 3, result[0].Count)
+		assert.Equal(t, 2, result[0].ArtistCount)
+		assert.Equal(t, "Rock Band", result[0].TopArtists[0].Name)
+		assert.Equal(t, 2, result[0].TopArtists[0].Count)
+	})
+
+	t.Run("Summary with any counts featured artists and limits top artists", func(t *testing.T) {
+		summaryUC, _, _, _ := setup()
+
+		result, err := summaryUC.Execute(ctx, domainTrack.ArtistMatchAny, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "rock", result[0].Genre)
+		assert.Equal(t, 4, result[0].Count)
+		assert.Len(t, result[0].TopArtists, 1)
+		assert.Equal(t, 3, result[0].TopArtists[0].Count)
+	})
+
+	t.Run("Dry run lists the tracks of the genre", func(t *testing.T) {
+		_, deleteUC, mockSpotifyRepo, _ := setup()
+
+		preview, err := deleteUC.Preview(ctx, "Punk", domainTrack.ArtistMatchPrimary)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, preview.TotalTracks)
+		assert.Equal(t, "track_3", preview.Tracks[0].ID)
+		mockSpotifyRepo.AssertNotCalled(t, "DeleteTracksFromLibrary", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Deletion backs up and removes the tracks of the genre", func(t *testing.T) {
+		_, deleteUC, mockSpotifyRepo, mockDatabaseRepo := setup()
+		mockDatabaseRepo.On("SaveTracksBackup", mock.Anything, mock.Anything).Return(nil)
+		mockSpotifyRepo.On("DeleteTracksFromLibrary", ctx, []spotifyAPI.ID{"track_1", "track_2", "track_3"}).Return(nil)
+
+		err := deleteUC.Execute(ctx, "rock", domainTrack.ArtistMatchPrimary)
+
+		assert.NoError(t, err)
+		mockDatabaseRepo.AssertNumberOfCalls(t, "SaveTracksBackup", 1)
+		mockSpotifyRepo.AssertExpectations(t)
+	})
+}
@@ -2,8 +2,13 @@ package track
 
 import (
 	"context"
+	"strconv"
 
+	appOperation "github.com/RubenPari/clear-songs/internal/application/operation"
+	"github.com/RubenPari/clear-songs/internal/application/shared/dto"
+	"github.com/RubenPari/clear-songs/internal/domain/operation"
 	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	domainTrack "github.com/RubenPari/clear-songs/internal/domain/track"
 	spotifyAPI "github.com/zmb3/spotify"
 )
 
@@ -13,6 +18,7 @@ type DeleteTracksByRangeUseCase struct {
 	cacheRepo          shared.CacheRepository
 	getTrackSummaryUC  *GetTrackSummaryUseCase
 	deleteByArtistUC   *DeleteTracksByArtistUseCase
+	recorder           *appOperation.Recorder
 }
 
 // NewDeleteTracksByRangeUseCase creates a new DeleteTracksByRangeUseCase
@@ -21,36 +27,108 @@ func NewDeleteTracksByRangeUseCase(
 	cacheRepo shared.CacheRepository,
 	getTrackSummaryUC *GetTrackSummaryUseCase,
 	deleteByArtistUC *DeleteTracksByArtistUseCase,
+	recorder *appOperation.Recorder,
 ) *DeleteTracksByRangeUseCase {
 	return &DeleteTracksByRangeUseCase{
 		spotifyRepo:       spotifyRepo,
 		cacheRepo:         cacheRepo,
 		getTrackSummaryUC: getTrackSummaryUC,
 		deleteByArtistUC: deleteByArtistUC,
+		recorder:          recorder,
 	}
 }
 
-// Execute deletes tracks within a count range
-func (uc *DeleteTracksByRangeUseCase) Execute(ctx context.Context, min, max int) error {
-	// 1. Get track summary filtered by range
-	summary, err := uc.getTrackSummaryUC.Execute(ctx, min, max)
+// Execute deletes tracks of the artists whose count, under the match mode, is within a range
+func (uc *DeleteTracksByRangeUseCase) Execute(ctx context.Context, min, max int, match domainTrack.ArtistMatch) error {
+	// 1. Select the tracks of every artist in range
+	tracks, err := uc.selectTracks(ctx, min, max, match)
 	if err != nil {
 		return err
 	}
 
-	// 2. Delete tracks for each artist in the summary
-	deletionsOccurred := false
-	for _, artist := range summary {
-		if err := uc.deleteByArtistUC.Execute(ctx, spotifyAPI.ID(artist.ID)); err != nil {
-			return err
-		}
-		deletionsOccurred = true
+	// 2. Back up and delete them as a single operation
+	op := &operation.Operation{
+		Kind:   operation.KindDeleteTracksByRange,
+		Params: map[string]string{"min": strconv.Itoa(min), "max": strconv.Itoa(max), "match": string(match)},
+	}
+	trackIDs, err := uc.deleteByArtistUC.removeTracks(ctx, tracks, appOperation.BackupOrigin(ctx, op))
+	if err != nil {
+		return err
+	}
+
+	if len(trackIDs) == 0 {
+		return nil // No tracks to delete
 	}
 
-	// 3. Invalidate cache if deletions occurred
-	if deletionsOccurred && uc.cacheRepo != nil {
+	// 3. Invalidate cache
+	if uc.cacheRepo != nil {
 		_ = uc.cacheRepo.InvalidateUserTracks(ctx)
 	}
 
+	// 4. Record the operation so it can be undone
+	op.LibraryTrackIDs = appOperation.TrackIDs(trackIDs)
+	uc.recorder.Record(ctx, op)
+
 	return nil
 }
+
+// Preview lists the tracks Execute would delete for a count range, without modifying the library
+func (uc *DeleteTracksByRangeUseCase) Preview(ctx context.Context, min, max int, match domainTrack.ArtistMatch) (*dto.DeletionPreview, error) {
+	// 1. Select the tracks of every artist in range
+	tracks, err := uc.selectTracks(ctx, min, max, match)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Build preview
+	preview := dto.NewDeletionPreview()
+	for _, track := range tracks {
+		preview.AddTrack(track.FullTrack)
+	}
+
+	return preview, nil
+}
+
+// selectTracks returns the tracks of the artists whose count is within a range. With
+// featured artists counted, a track credited to several of them is selected once.
+func (uc *DeleteTracksByRangeUseCase) selectTracks(ctx context.Context, min, max int, match domainTrack.ArtistMatch) ([]spotifyAPI.SavedTrack, error) {
+	// 1. Get track summary filtered by range
+	summary, err := uc.getTrackSummaryUC.Execute(ctx, min, max, match)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(summary) == 0 {
+		return nil, nil
+	}
+
+	// 2. Get user tracks (from cache or the library mirror)
+	tracks, err := uc.deleteByArtistUC.userTracks.GetUserTracks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Collect the tracks of each artist in the summary, without repeats
+	var selected []spotifyAPI.SavedTrack
+	seen := make(map[spotifyAPI.ID]bool)
+	for _, artist := range summary {
+		artistTracks, err := uc.spotifyRepo.GetTracksByArtist(ctx, artistFilter(artist, match), tracks)
+		if err != nil {
+			return nil, err
+		}
+		for _, track := range artistTracks {
+			if !seen[track.ID] {
+				seen[track.ID] = true
+				selected = append(selected, track)
+			}
+		}
+	}
+
+	return selected, nil
+}
+
+// artistFilter returns the filter selecting the tracks counted for a summary artist.
+// With all, a single artist behaves like any.
+func artistFilter(artist domainTrack.ArtistSummary, match domainTrack.ArtistMatch) domainTrack.ArtistFilter {
+	return domainTrack.ArtistFilter{IDs: []spotifyAPI.ID{spotifyAPI.ID(artist.ID)}, Match: match}
+}
@@ -0,0 +1,101 @@
+package track
+
+import (
+	"context"
+	"testing"
+
+	"github.com/RubenPari/clear-songs/internal/application/artistinfo"
+	appBackup "github.com/RubenPari/clear-songs/internal/application/backup"
+	appOperation "github.com/RubenPari/clear-songs/internal/application/operation"
+	"github.com/RubenPari/clear-songs/internal/application/usertracks"
+	"github.com/RubenPari/clear-songs/internal/domain/backup"
+	"github.com/RubenPari/clear-songs/internal/domain/operation"
+	domainTrack "github.com/RubenPari/clear-songs/internal/domain/track"
+	"github.com/RubenPari/clear-songs/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+func TestDeleteTracksByRangeUseCase(t *testing.T) {
+	ctx := context.Background()
+
+	savedTrack := func(id string, artists ...spotifyAPI.SimpleArtist) spotifyAPI.SavedTrack {
+		return spotifyAPI.SavedTrack{FullTrack: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{
+			ID:      spotifyAPI.ID(id),
+			Name:    "Song " + id,
+			Artists: artists,
+		}}}
+	}
+	first := spotifyAPI.SimpleArtist{ID: "first", Name: "First"}
+	second := spotifyAPI.SimpleArtist{ID: "second", Name: "Second"}
+	other := spotifyAPI.SimpleArtist{ID: "other", Name: "Other"}
+	// duet is credited to both in-range artists
+	duet := savedTrack("duet", first, second)
+	tracks := []spotifyAPI.SavedTrack{
+		duet,
+		savedTrack("first_solo", first),
+		savedTrack("second_solo", second),
+		savedTrack("other_solo", other),
+	}
+
+	setup := func() (*DeleteTracksByRangeUseCase, *mocks.MockSpotifyRepository, *mocks.MockDatabaseRepository, *mocks.MockOperationRepository) {
+		mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+		mockDatabaseRepo := new(mocks.MockDatabaseRepository)
+		mockOperationRepo := new(mocks.MockOperationRepository)
+		mockSpotifyRepo.On("GetAllUserTracks", ctx).Return(tracks, nil)
+		mockSpotifyRepo.On("GetArtists", ctx, mock.Anything).Return([]spotifyAPI.FullArtist{}, nil)
+		mockSpotifyRepo.On("GetTracksByArtist", ctx, domainTrack.ArtistFilter{IDs: []spotifyAPI.ID{"first"}, Match: domainTrack.ArtistMatchAny}, tracks).
+			Return([]spotifyAPI.SavedTrack{tracks[0], tracks[1]}, nil)
+		mockSpotifyRepo.On("GetTracksByArtist", ctx, domainTrack.ArtistFilter{IDs: []spotifyAPI.ID{"second"}, Match: domainTrack.ArtistMatchAny}, tracks).
+			Return([]spotifyAPI.SavedTrack{tracks[0], tracks[2]}, nil)
+
+		userTracks := usertracks.NewProvider(mockSpotifyRepo, nil, nil, 0)
+		recorder := appOperation.NewRecorder(mockOperationRepo)
+		summaryUC := NewGetTrackSummaryUseCase(mockSpotifyRepo, nil, userTracks, artistinfo.NewProvider(mockSpotifyRepo, nil))
+		byArtistUC := NewDeleteTracksByArtistUseCase(mockSpotifyRepo, nil, userTracks, appBackup.NewWriter(mockDatabaseRepo, appBackup.PolicyRequired), recorder)
+		return NewDeleteTracksByRangeUseCase(mockSpotifyRepo, nil, summaryUC, byArtistUC, recorder), mockSpotifyRepo, mockDatabaseRepo, mockOperationRepo
+	}
+	expectedIDs := []spotifyAPI.ID{"duet", "first_solo", "second_solo"}
+
+	t.Run("Success - should back up, delete and record a track shared by in-range artists once", func(t *testing.T) {
+		useCase, mockSpotifyRepo, mockDatabaseRepo, mockOperationRepo := setup()
+		mockDatabaseRepo.On("SaveTracksBackup", ctx, mock.MatchedBy(func(backedUp []backup.TrackBackup) bool {
+			return len(backedUp) == 3 && backedUp[0].ID == "duet" && backedUp[0].Reason == string(operation.KindDeleteTracksByRange)
+		})).Return(nil).Once()
+		mockSpotifyRepo.On("DeleteTracksFromLibrary", ctx, expectedIDs).Return(nil).Once()
+		mockOperationRepo.On("Create", mock.Anything, mock.MatchedBy(func(op *operation.Operation) bool {
+			return op.Kind == operation.KindDeleteTracksByRange &&
+				assert.ObjectsAreEqual([]string{"duet", "first_solo", "second_solo"}, op.LibraryTrackIDs)
+		})).Return(nil).Once()
+
+		err := useCase.Execute(ctx, 2, 5, domainTrack.ArtistMatchAny)
+
+		assert.NoError(t, err)
+		mockDatabaseRepo.AssertExpectations(t)
+		mockSpotifyRepo.AssertExpectations(t)
+		mockOperationRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - should preview a track shared by in-range artists once", func(t *testing.T) {
+		useCase, mockSpotifyRepo, _, _ := setup()
+
+		preview, err := useCase.Preview(ctx, 2, 5, domainTrack.ArtistMatchAny)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, preview.TotalTracks)
+		assert.Len(t, preview.Tracks, 3)
+		mockSpotifyRepo.AssertNotCalled(t, "DeleteTracksFromLibrary", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - should delete nothing when the backup is required and fails", func(t *testing.T) {
+		useCase, mockSpotifyRepo, mockDatabaseRepo, mockOperationRepo := setup()
+		mockDatabaseRepo.On("SaveTracksBackup", ctx, mock.Anything).Return(assert.AnError)
+
+		err := useCase.Execute(ctx, 2, 5, domainTrack.ArtistMatchAny)
+
+		assert.Error(t, err)
+		mockSpotifyRepo.AssertNotCalled(t, "DeleteTracksFromLibrary", mock.Anything, mock.Anything)
+		mockOperationRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
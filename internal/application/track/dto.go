@@ -15,4 +15,45 @@ type TrackResponse struct {
 type RangeRequest struct {
 	Min int `form:"min" binding:"min=0"`
 	Max int `form:"max" binding:"min=0,gtefield=Min"`
+	// Match selects whether featured appearances count toward an artist
+	Match string `form:"match,default=primary" binding:"oneof=primary any all"`
+	// DryRun previews the deletion instead of performing it (destructive endpoints only)
+	DryRun bool `form:"dry_run"`
+	// Async runs the deletion as a background job (destructive endpoints only)
+	Async bool `form:"async"`
+}
+
+// SummaryRequest holds the options of the track summary: a count range, a search on
+// the artist name, the sort order and the page
+type SummaryRequest struct {
+	RangeRequest
+	// Search keeps the artists whose name contains it, case-insensitively
+	Search string `form:"q"`
+	Sort   string `form:"sort,default=count" binding:"oneof=count name last_added"`
+	// Order defaults to desc for count and last_added, asc for name
+	Order string `form:"order" binding:"omitempty,oneof=asc desc"`
+	// Limit of 0, the default, returns every artist
+	Limit  int `form:"limit" binding:"min=0,max=500"`
+	Offset int `form:"offset" binding:"min=0"`
+}
+
+// ArtistRequest holds the options of the by-artist endpoints
+type ArtistRequest struct {
+	// Match selects which artists of a track are compared with the requested IDs
+	Match string `form:"match,default=primary" binding:"oneof=primary any all"`
+}
+
+// DeleteRequest holds the options of destructive endpoints that only take path parameters
+type DeleteRequest struct {
+	ArtistRequest
+	DryRun bool `form:"dry_run"`
+	Async  bool `form:"async"`
+}
+
+// GenreRequest holds the options of the genre summary
+type GenreRequest struct {
+	// Match selects which artists of a track lend it their genres
+	Match string `form:"match,default=primary" binding:"oneof=primary any all"`
+	// Top is the number of artists listed per genre
+	Top int `form:"top,default=5" binding:"min=1,max=50"`
 }
@@ -0,0 +1,165 @@
+package track
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/RubenPari/clear-songs/internal/application/artistinfo"
+	"github.com/RubenPari/clear-songs/internal/application/usertracks"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	"github.com/RubenPari/clear-songs/internal/domain/shared/utils"
+	"github.com/RubenPari/clear-songs/internal/domain/track"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+// GetGenreSummaryUseCase handles the business logic for summarizing saved tracks by genre
+type GetGenreSummaryUseCase struct {
+	cacheRepo  shared.CacheRepository
+	userTracks *usertracks.Provider
+	artists    *artistinfo.Provider
+}
+
+// NewGetGenreSummaryUseCase creates a new GetGenreSummaryUseCase
+func NewGetGenreSummaryUseCase(
+	cacheRepo shared.CacheRepository,
+	userTracks *usertracks.Provider,
+	artists *artistinfo.Provider,
+) *GetGenreSummaryUseCase {
+	return &GetGenreSummaryUseCase{
+		cacheRepo:  cacheRepo,
+		userTracks: userTracks,
+		artists:    artists,
+	}
+}
+
+// Execute retrieves the saved tracks grouped by the genres of their artists, most listened
+// genres first, with up to top artists per genre. A track is counted once per genre.
+func (uc *GetGenreSummaryUseCase) Execute(ctx context.Context, match track.ArtistMatch, top int) ([]track.GenreSummary, error) {
+	cacheKey := fmt.Sprintf("genre_summary_%s_%d", match, top)
+
+	// 1. Check cache (if available)
+	if uc.cacheRepo != nil {
+		var cached []track.GenreSummary
+		if found, _ := uc.cacheRepo.Get(ctx, cacheKey, &cached); found {
+			return cached, nil
+		}
+	}
+
+	// 2. Get user tracks (from cache or the library mirror)
+	tracks, err := uc.userTracks.GetUserTracks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Get the artists and their genres
+	artists, err := uc.getArtists(ctx, tracks)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Calculate summary
+	summary := calculateGenreSummary(tracks, artists, match, top)
+
+	// 5. Cache the result (if cache is available)
+	if uc.cacheRepo != nil {
+		_ = uc.cacheRepo.Set(ctx, cacheKey, summary, 5*time.Minute)
+	}
+
+	return summary, nil
+}
+
+// getArtists returns the metadata of every artist of the tracks
+func (uc *GetGenreSummaryUseCase) getArtists(ctx context.Context, tracks []spotifyAPI.SavedTrack) (map[spotifyAPI.ID]spotifyAPI.FullArtist, error) {
+	var ids []spotifyAPI.ID
+	for _, savedTrack := range tracks {
+		for _, artist := range savedTrack.Artists {
+			ids = append(ids, artist.ID)
+		}
+	}
+	return uc.artists.GetArtists(ctx, ids)
+}
+
+// calculateGenreSummary groups tracks by genre, keeping the top artists of each genre
+func calculateGenreSummary(
+	tracks []spotifyAPI.SavedTrack,
+	artists map[spotifyAPI.ID]spotifyAPI.FullArtist,
+	match track.ArtistMatch,
+	top int,
+) []track.GenreSummary {
+	genres := artistGenres(artists)
+
+	type genreCounts struct {
+		count   int
+		artists map[spotifyAPI.ID]*track.ArtistSummary
+	}
+	genreMap := make(map[string]*genreCounts)
+
+	for _, savedTrack := range tracks {
+		for genre, genreArtists := range track.GenreArtists(savedTrack.Artists, genres, match) {
+			counts, exists := genreMap[genre]
+			if !exists {
+				counts = &genreCounts{artists: make(map[spotifyAPI.ID]*track.ArtistSummary)}
+				genreMap[genre] = counts
+			}
+			counts.count++
+
+			for _, artist := range genreArtists {
+				summary, exists := counts.artists[artist.ID]
+				if !exists {
+					summary = &track.ArtistSummary{
+						ID:       artist.ID.String(),
+						Name:     artist.Name,
+						ImageURL: utils.GetMediumImage(artists[artist.ID].Images),
+					}
+					counts.artists[artist.ID] = summary
+				}
+				summary.Count++
+			}
+		}
+	}
+
+	summary := make([]track.GenreSummary, 0, len(genreMap))
+	for genre, counts := range genreMap {
+		topArtists := make([]track.ArtistSummary, 0, len(counts.artists))
+		for _, artist := range counts.artists {
+			topArtists = append(topArtists, *artist)
+		}
+		sort.Slice(topArtists, func(i, j int) bool {
+			if topArtists[i].Count != topArtists[j].Count {
+				return topArtists[i].Count > topArtists[j].Count
+			}
+			return topArtists[i].Name < topArtists[j].Name
+		})
+		if top > 0 && len(topArtists) > top {
+			topArtists = topArtists[:top]
+		}
+
+		summary = append(summary, track.GenreSummary{
+			Genre:       genre,
+			Count:       counts.count,
+			ArtistCount: len(counts.artists),
+			TopArtists:  topArtists,
+		})
+	}
+
+	// Sort by count descending, then by genre for a stable order
+	sort.Slice(summary, func(i, j int) bool {
+		if summary[i].Count != summary[j].Count {
+			return summary[i].Count > summary[j].Count
+		}
+		return summary[i].Genre < summary[j].Genre
+	})
+
+	return summary
+}
+
+// artistGenres returns the genres of each artist
+func artistGenres(artists map[spotifyAPI.ID]spotifyAPI.FullArtist) map[spotifyAPI.ID][]string {
+	genres := make(map[spotifyAPI.ID][]string, len(artists))
+	for id, artist := range artists {
+		genres[id] = artist.Genres
+	}
+	return genres
+}
@@ -2,10 +2,11 @@ package track
 
 import (
 	"context"
-	"fmt"
 	"sort"
 	"time"
 
+	"github.com/RubenPari/clear-songs/internal/application/artistinfo"
+	"github.com/RubenPari/clear-songs/internal/application/usertracks"
 	"github.com/RubenPari/clear-songs/internal/domain/track"
 	"github.com/RubenPari/clear-songs/internal/domain/shared"
 	"github.com/RubenPari/clear-songs/internal/domain/shared/utils"
@@ -16,42 +17,83 @@ import (
 type GetTrackSummaryUseCase struct {
 	spotifyRepo shared.SpotifyRepository
 	cacheRepo  shared.CacheRepository
+	userTracks *usertracks.Provider
+	artists    *artistinfo.Provider
 }
 
 // NewGetTrackSummaryUseCase creates a new GetTrackSummaryUseCase
 func NewGetTrackSummaryUseCase(
 	spotifyRepo shared.SpotifyRepository,
 	cacheRepo shared.CacheRepository,
+	userTracks *usertracks.Provider,
+	artists *artistinfo.Provider,
 ) *GetTrackSummaryUseCase {
 	return &GetTrackSummaryUseCase{
 		spotifyRepo: spotifyRepo,
 		cacheRepo:  cacheRepo,
+		userTracks: userTracks,
+		artists:    artists,
 	}
 }
 
-// Execute retrieves track summary grouped by artist, optionally filtered by range
-func (uc *GetTrackSummaryUseCase) Execute(ctx context.Context, min, max int) ([]track.ArtistSummary, error) {
+// Execute retrieves track summary grouped by artist, optionally filtered by range,
+// most counted artists first. The match mode decides whether featured appearances
+// count toward an artist.
+func (uc *GetTrackSummaryUseCase) Execute(ctx context.Context, min, max int, match track.ArtistMatch) ([]track.ArtistSummary, error) {
+	summary, err := uc.summary(ctx, match)
+	if err != nil {
+		return nil, err
+	}
+
+	return track.SummaryQuery{Min: min, Max: max}.Filter(summary), nil
+}
+
+// Query retrieves a page of the track summary, filtered by range and artist name and
+// sorted by the requested field
+func (uc *GetTrackSummaryUseCase) Query(ctx context.Context, query track.SummaryQuery) (*track.SummaryPage, error) {
+	// 1. Get the whole summary (from cache if available)
+	summary, err := uc.summary(ctx, query.Match)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Filter and sort a copy, the cached summary is shared
+	artists := query.Filter(summary)
+	query.SortSummary(artists)
+
+	// 3. Cut the requested page
+	paginator := utils.Paginator{Limit: query.Limit, Offset: query.Offset}
+	start, end := paginator.Window(len(artists))
+
+	return &track.SummaryPage{
+		Artists: artists[start:end],
+		Total:   len(artists),
+		Limit:   query.Limit,
+		Offset:  query.Offset,
+	}, nil
+}
+
+// summary returns every artist of the library under a match mode, most counted first.
+// A single summary is cached per match mode; ranges, searches and pages are cut from it.
+func (uc *GetTrackSummaryUseCase) summary(ctx context.Context, match track.ArtistMatch) ([]track.ArtistSummary, error) {
+	cacheKey := summaryCacheKey(match)
+
 	// 1. Check cache (if available)
 	if uc.cacheRepo != nil {
-		cacheKey := "track_summary"
-		if min > 0 || max > 0 {
-			cacheKey = fmt.Sprintf("track_summary_%d_%d", min, max)
-		}
-		
 		var cached []track.ArtistSummary
 		if found, _ := uc.cacheRepo.Get(ctx, cacheKey, &cached); found {
 			return cached, nil
 		}
 	}
 	
-	// 2. Get user tracks (from cache or API)
-	tracks, err := uc.getUserTracks(ctx)
+	// 2. Get user tracks (from cache or the library mirror)
+	tracks, err := uc.userTracks.GetUserTracks(ctx)
 	if err != nil {
 		return nil, err
 	}
 	
 	// 3. Calculate summary
-	summary := uc.calculateSummary(ctx, tracks, min, max)
+	summary := uc.calculateSummary(ctx, tracks, match)
 	
 	// 4. Sort by count descending
 	sort.Slice(summary, func(i, j int) bool {
@@ -60,101 +102,118 @@ func (uc *GetTrackSummaryUseCase) Execute(ctx context.Context, min, max int) ([]
 	
 	// 5. Cache the result (if cache is available)
 	if uc.cacheRepo != nil {
-		cacheKey := "track_summary"
-		if min > 0 || max > 0 {
-			cacheKey = fmt.Sprintf("track_summary_%d_%d", min, max)
-		}
 		_ = uc.cacheRepo.Set(ctx, cacheKey, summary, 5*time.Minute)
 	}
 	
 	return summary, nil
 }
 
-// getUserTracks retrieves tracks from cache or API
-func (uc *GetTrackSummaryUseCase) getUserTracks(ctx context.Context) ([]spotifyAPI.SavedTrack, error) {
-	// Try cache first (if available)
-	if uc.cacheRepo != nil {
-		cached, err := uc.cacheRepo.GetUserTracks(ctx)
-		if err == nil && cached != nil && len(cached) > 0 {
-			return cached, nil
-		}
-	}
-	
-	// Fetch from API
-	tracks, err := uc.spotifyRepo.GetAllUserTracks(ctx)
-	if err != nil {
-		return nil, err
+// summaryCacheKey returns the cache key of the summary of a match mode, keeping the
+// historical key for the primary match mode
+func summaryCacheKey(match track.ArtistMatch) string {
+	cacheKey := "track_summary"
+	if match.CountsFeatured() {
+		cacheKey += "_" + string(match)
 	}
-	
-	// Cache for future use (if cache is available)
-	if uc.cacheRepo != nil {
-		_ = uc.cacheRepo.SetUserTracks(ctx, tracks, 5*time.Minute)
-	}
-	
-	return tracks, nil
+	return cacheKey
 }
 
 // calculateSummary calculates artist summary from tracks
 func (uc *GetTrackSummaryUseCase) calculateSummary(
 	ctx context.Context,
 	tracks []spotifyAPI.SavedTrack,
-	min, max int,
+	match track.ArtistMatch,
 ) []track.ArtistSummary {
-	// Group tracks by artist
-	artistMap := make(map[string]struct {
-		count int
-		id    string
-	})
+	// Group tracks by artist ID (by name when the ID is missing), keeping
+	// the order in which artists are first seen
+	type artistCounts struct {
+		id       string
+		name     string
+		primary  int
+		featured int
+		// lastPrimary and lastFeatured are the latest added_at of each kind of appearance
+		lastPrimary  string
+		lastFeatured string
+	}
+	artistMap := make(map[string]*artistCounts)
+	var order []string
 	
-	for _, track := range tracks {
-		if len(track.Artists) == 0 {
-			continue
-		}
-		
-		artistName := track.Artists[0].Name
-		artistID := string(track.Artists[0].ID)
-		
-		if existing, exists := artistMap[artistName]; exists {
-			existing.count++
-			artistMap[artistName] = existing
-		} else {
-			artistMap[artistName] = struct {
-				count int
-				id    string
-			}{
-				count: 1,
-				id:    artistID,
+	for _, savedTrack := range tracks {
+		seen := make(map[string]bool, len(savedTrack.Artists))
+		for i, artist := range savedTrack.Artists {
+			key := string(artist.ID)
+			if key == "" {
+				key = artist.Name
+			}
+			// An artist credited twice on a track is counted once
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			
+			counts, exists := artistMap[key]
+			if !exists {
+				counts = &artistCounts{id: string(artist.ID), name: artist.Name}
+				artistMap[key] = counts
+				order = append(order, key)
+			}
+			if i == 0 {
+				counts.primary++
+				counts.lastPrimary = latest(counts.lastPrimary, savedTrack.AddedAt)
+			} else {
+				counts.featured++
+				counts.lastFeatured = latest(counts.lastFeatured, savedTrack.AddedAt)
 			}
 		}
 	}
 	
 	// Convert to ArtistSummary array
 	var summary []track.ArtistSummary
-	for artistName, data := range artistMap {
-		// Apply range filter
-		if min > 0 && data.count < min {
-			continue
+	for _, key := range order {
+		data := artistMap[key]
+		
+		count := data.primary
+		lastAddedAt := data.lastPrimary
+		if match.CountsFeatured() {
+			count += data.featured
+			lastAddedAt = latest(lastAddedAt, data.lastFeatured)
 		}
-		if max > 0 && data.count > max {
+		if count == 0 {
 			continue
 		}
 		
-		// Get artist image
-		imageURL := ""
-		if data.id != "" {
-			artist, err := uc.spotifyRepo.GetArtist(ctx, spotifyAPI.ID(data.id))
-			if err == nil && artist != nil {
-				imageURL = utils.GetMediumImage(artist.Images)
-			}
-		}
-		
 		summary = append(summary, track.ArtistSummary{
-			ID:       data.id,
-			Name:     artistName,
-			Count:    data.count,
-			ImageURL: imageURL,
+			ID:            data.id,
+			Name:          data.name,
+			Count:         count,
+			PrimaryCount:  data.primary,
+			FeaturedCount: data.featured,
+			LastAddedAt:   lastAddedAt,
 		})
 	}
 	
+	// Add artist images, looked up in batches for the artists kept.
+	// Images are best effort: the summary is served without them on failure
+	ids := make([]spotifyAPI.ID, 0, len(summary))
+	for _, artist := range summary {
+		ids = append(ids, spotifyAPI.ID(artist.ID))
+	}
+	if artists, err := uc.artists.GetArtists(ctx, ids); err == nil {
+		for i := range summary {
+			if artist, found := artists[spotifyAPI.ID(summary[i].ID)]; found {
+				summary[i].ImageURL = utils.GetMediumImage(artist.Images)
+			}
+		}
+	}
+	
 	return summary
 }
+
+// latest returns the later of two added_at timestamps. Spotify formats them alike,
+// so they compare as strings.
+func latest(a, b string) string {
+	if b > a {
+		return b
+	}
+	return a
+}
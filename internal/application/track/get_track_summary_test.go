@@ -4,6 +4,9 @@ import (
 	"context"
 	"testing"
 
+	"github.com/RubenPari/clear-songs/internal/application/artistinfo"
+	"github.com/RubenPari/clear-songs/internal/application/usertracks"
+	domainTrack "github.com/RubenPari/clear-songs/internal/domain/track"
 	"github.com/RubenPari/clear-songs/test/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -15,7 +18,7 @@ func TestGetTrackSummaryUseCase_Execute(t *testing.T) {
 	mockSpotifyRepo := new(mocks.MockSpotifyRepository)
 	mockCacheRepo := new(mocks.MockCacheRepository)
 	
-	useCase := NewGetTrackSummaryUseCase(mockSpotifyRepo, mockCacheRepo)
+	useCase := NewGetTrackSummaryUseCase(mockSpotifyRepo, mockCacheRepo, usertracks.NewProvider(mockSpotifyRepo, mockCacheRepo, nil, 0), artistinfo.NewProvider(mockSpotifyRepo, mockCacheRepo))
 	ctx := context.Background()
 
 	t.Run("Success - should return grouped summary", func(t *testing.T) {
@@ -59,14 +62,24 @@ func TestGetTrackSummaryUseCase_Execute(t *testing.T) {
 		mockSpotifyRepo.On("GetAllUserTracks", ctx).Return(tracks, nil)
 		mockCacheRepo.On("SetUserTracks", ctx, tracks, mock.Anything).Return(nil)
 		
-		// Mock Artist Image calls
-		mockSpotifyRepo.On("GetArtist", ctx, spotifyAPI.ID("1")).Return(&spotifyAPI.FullArtist{}, nil)
-		mockSpotifyRepo.On("GetArtist", ctx, spotifyAPI.ID("2")).Return(&spotifyAPI.FullArtist{}, nil)
+		// Artist 1 is in the artist cache, Artist 2 is fetched in a single batch
+		cachedArtist := spotifyAPI.FullArtist{
+			SimpleArtist: spotifyAPI.SimpleArtist{ID: "1", Name: "Artist 1"},
+			Images:       []spotifyAPI.Image{{URL: "https://img/1"}},
+		}
+		fetchedArtists := []spotifyAPI.FullArtist{{
+			SimpleArtist: spotifyAPI.SimpleArtist{ID: "2", Name: "Artist 2"},
+			Images:       []spotifyAPI.Image{{URL: "https://img/2"}},
+		}}
+		mockCacheRepo.On("GetArtists", ctx, []spotifyAPI.ID{"1", "2"}).
+			Return(map[spotifyAPI.ID]spotifyAPI.FullArtist{"1": cachedArtist}, nil)
+		mockSpotifyRepo.On("GetArtists", ctx, []spotifyAPI.ID{"2"}).Return(fetchedArtists, nil).Once()
+		mockCacheRepo.On("SetArtists", ctx, fetchedArtists, artistinfo.CacheTTL).Return(nil).Once()
 		
 		mockCacheRepo.On("Set", ctx, "track_summary", mock.Anything, mock.Anything).Return(nil)
 
 		// Execute
-		result, err := useCase.Execute(ctx, 0, 0)
+		result, err := useCase.Execute(ctx, 0, 0, domainTrack.ArtistMatchPrimary)
 
 		// Assertions
 		assert.NoError(t, err)
@@ -75,9 +88,158 @@ func TestGetTrackSummaryUseCase_Execute(t *testing.T) {
 		// Check Artist 1 (should have 2 tracks)
 		assert.Equal(t, "Artist 1", result[0].Name)
 		assert.Equal(t, 2, result[0].Count)
+		assert.Equal(t, "https://img/1", result[0].ImageURL)
 		
 		// Check Artist 2 (should have 1 track)
 		assert.Equal(t, "Artist 2", result[1].Name)
 		assert.Equal(t, 1, result[1].Count)
+		assert.Equal(t, "https://img/2", result[1].ImageURL)
+		mockSpotifyRepo.AssertNotCalled(t, "GetArtist", mock.Anything, mock.Anything)
+	})
+}
+
+func TestGetTrackSummaryUseCase_ExecuteFeatured(t *testing.T) {
+	mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+
+	useCase := NewGetTrackSummaryUseCase(mockSpotifyRepo, nil, usertracks.NewProvider(mockSpotifyRepo, nil, nil, 0), artistinfo.NewProvider(mockSpotifyRepo, nil))
+	ctx := context.Background()
+
+	tracks := []spotifyAPI.SavedTrack{
+		{FullTrack: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{
+			Name:    "Solo",
+			Artists: []spotifyAPI.SimpleArtist{{Name: "Artist 1", ID: "1"}},
+		}}},
+		{FullTrack: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{
+			Name:    "Duet",
+			Artists: []spotifyAPI.SimpleArtist{{Name: "Artist 1", ID: "1"}, {Name: "Artist 2", ID: "2"}},
+		}}},
+		{FullTrack: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{
+			Name:    "Feature",
+			Artists: []spotifyAPI.SimpleArtist{{Name: "Artist 3", ID: "3"}, {Name: "Artist 2", ID: "2"}},
+		}}},
+	}
+	mockSpotifyRepo.On("GetAllUserTracks", ctx).Return(tracks, nil)
+	mockSpotifyRepo.On("GetArtists", ctx, mock.Anything).Return([]spotifyAPI.FullArtist{}, nil)
+
+	byID := func(summary []domainTrack.ArtistSummary) map[string]domainTrack.ArtistSummary {
+		result := make(map[string]domainTrack.ArtistSummary, len(summary))
+		for _, artist := range summary {
+			result[artist.ID] = artist
+		}
+		return result
+	}
+
+	t.Run("Primary - featured-only artists are left out", func(t *testing.T) {
+		result, err := useCase.Execute(ctx, 0, 0, domainTrack.ArtistMatchPrimary)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 2)
+		artists := byID(result)
+		assert.Equal(t, 2, artists["1"].Count)
+		assert.Equal(t, 1, artists["3"].Count)
+		assert.NotContains(t, artists, "2")
+	})
+
+	t.Run("Any - featured appearances are counted", func(t *testing.T) {
+		result, err := useCase.Execute(ctx, 0, 0, domainTrack.ArtistMatchAny)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 3)
+		artist := byID(result)["2"]
+		assert.Equal(t, 2, artist.Count)
+		assert.Equal(t, 0, artist.PrimaryCount)
+		assert.Equal(t, 2, artist.FeaturedCount)
+		assert.Equal(t, "Artist 2", artist.Name)
+	})
+
+	t.Run("Range applies to the count of the match mode", func(t *testing.T) {
+		result, err := useCase.Execute(ctx, 2, 2, domainTrack.ArtistMatchAny)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 2)
+		artists := byID(result)
+		assert.Contains(t, artists, "1")
+		assert.Contains(t, artists, "2")
+	})
+}
+
+func TestGetTrackSummaryUseCase_Query(t *testing.T) {
+	mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+
+	useCase := NewGetTrackSummaryUseCase(mockSpotifyRepo, nil, usertracks.NewProvider(mockSpotifyRepo, nil, nil, 0), artistinfo.NewProvider(mockSpotifyRepo, nil))
+	ctx := context.Background()
+
+	saved := func(addedAt string, artist spotifyAPI.SimpleArtist) spotifyAPI.SavedTrack {
+		return spotifyAPI.SavedTrack{AddedAt: addedAt, FullTrack: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{
+			Artists: []spotifyAPI.SimpleArtist{artist},
+		}}}
+	}
+	abba := spotifyAPI.SimpleArtist{Name: "ABBA", ID: "1"}
+	blur := spotifyAPI.SimpleArtist{Name: "Blur", ID: "2"}
+	bjork := spotifyAPI.SimpleArtist{Name: "björk", ID: "3"}
+	tracks := []spotifyAPI.SavedTrack{
+		saved("2024-03-01T00:00:00Z", blur),
+		saved("2024-02-01T00:00:00Z", abba),
+		saved("2024-01-01T00:00:00Z", bjork),
+		saved("2023-12-01T00:00:00Z", abba),
+		saved("2023-11-01T00:00:00Z", abba),
+		saved("2023-10-01T00:00:00Z", bjork),
+	}
+	mockSpotifyRepo.On("GetAllUserTracks", ctx).Return(tracks, nil)
+	mockSpotifyRepo.On("GetArtists", ctx, mock.Anything).Return([]spotifyAPI.FullArtist{}, nil)
+
+	names := func(page *domainTrack.SummaryPage) []string {
+		result := make([]string, len(page.Artists))
+		for i, artist := range page.Artists {
+			result[i] = artist.Name
+		}
+		return result
+	}
+
+	t.Run("Pages are cut after sorting", func(t *testing.T) {
+		page, err := useCase.Query(ctx, domainTrack.SummaryQuery{Sort: domainTrack.SummarySortCount, Descending: true, Limit: 2, Offset: 1})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, page.Total)
+		assert.Equal(t, []string{"björk", "Blur"}, names(page))
+	})
+
+	t.Run("Names sort case-insensitively", func(t *testing.T) {
+		page, err := useCase.Query(ctx, domainTrack.SummaryQuery{Sort: domainTrack.SummarySortName, Limit: 10})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"ABBA", "björk", "Blur"}, names(page))
+	})
+
+	t.Run("Last added sorts by the newest saved track of each artist", func(t *testing.T) {
+		page, err := useCase.Query(ctx, domainTrack.SummaryQuery{Sort: domainTrack.SummarySortLastAdded, Descending: true, Limit: 10})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Blur", "ABBA", "björk"}, names(page))
+		assert.Equal(t, "2024-03-01T00:00:00Z", page.Artists[0].LastAddedAt)
+	})
+
+	t.Run("Search matches artist names case-insensitively within the range", func(t *testing.T) {
+		page, err := useCase.Query(ctx, domainTrack.SummaryQuery{Min: 2, Search: "B", Limit: 10})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, page.Total)
+		assert.Equal(t, []string{"björk", "ABBA"}, names(page))
+	})
+
+	t.Run("A limit of 0 returns every artist from the offset", func(t *testing.T) {
+		page, err := useCase.Query(ctx, domainTrack.SummaryQuery{Sort: domainTrack.SummarySortName, Offset: 1})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, page.Total)
+		assert.Equal(t, []string{"björk", "Blur"}, names(page))
+	})
+
+	t.Run("An offset past the end returns an empty page", func(t *testing.T) {
+		page, err := useCase.Query(ctx, domainTrack.SummaryQuery{Limit: 10, Offset: 10})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, page.Total)
+		assert.Empty(t, page.Artists)
 	})
 }
@@ -0,0 +1,35 @@
+package usertracks
+
+import (
+	"context"
+
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+// mirroredRepository keeps the library mirror in line with the tracks this app unsaves.
+// Saved tracks need no hook: the incremental sync finds them.
+type mirroredRepository struct {
+	shared.SpotifyRepository
+	provider *Provider
+}
+
+// Wrap returns a repository that removes the tracks unsaved through it from the mirror,
+// so that every destructive use case keeps the mirror accurate
+func (p *Provider) Wrap(repo shared.SpotifyRepository) shared.SpotifyRepository {
+	return &mirroredRepository{SpotifyRepository: repo, provider: p}
+}
+
+// DeleteTracksFromLibrary removes tracks from the library and from the mirror. After a
+// partial failure the removed tracks are unknown, so the mirror is fully reconciled.
+func (r *mirroredRepository) DeleteTracksFromLibrary(ctx context.Context, trackIDs []spotifyAPI.ID) error {
+	if err := r.SpotifyRepository.DeleteTracksFromLibrary(ctx, trackIDs); err != nil {
+		if r.provider.mirrorRepo != nil {
+			r.provider.requireFullSync(ctx, shared.OwnerFromContext(ctx))
+		}
+		return err
+	}
+
+	r.provider.Forget(ctx, trackIDs)
+	return nil
+}
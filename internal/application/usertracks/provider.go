@@ -0,0 +1,184 @@
+package usertracks
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	domainLibrary "github.com/RubenPari/clear-songs/internal/domain/library"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+const (
+	// pageSize is the largest page of saved tracks the API returns
+	pageSize = 50
+	// cacheTTL is how long a read is served from cache before the mirror is synced again
+	cacheTTL = 5 * time.Minute
+	// DefaultFullSyncInterval is how often the mirror is reconciled with the whole library
+	DefaultFullSyncInterval = 24 * time.Hour
+)
+
+// Provider serves the saved tracks of the owner in ctx to the read use cases. Tracks are
+// read from cache, then from a local mirror of the library that is synced incrementally:
+// saved tracks are walked newest first until one already mirrored with the same
+// added_at is found. Removals made outside this app are only seen by a full
+// reconciliation, done every fullSyncInterval.
+type Provider struct {
+	spotifyRepo      shared.SpotifyRepository
+	cacheRepo        shared.CacheRepository
+	mirrorRepo       domainLibrary.MirrorRepository
+	fullSyncInterval time.Duration
+	now              func() time.Time
+}
+
+// NewProvider creates a new saved tracks provider. Without a mirror repository every
+// cache miss fetches the whole library.
+func NewProvider(
+	spotifyRepo shared.SpotifyRepository,
+	cacheRepo shared.CacheRepository,
+	mirrorRepo domainLibrary.MirrorRepository,
+	fullSyncInterval time.Duration,
+) *Provider {
+	if fullSyncInterval <= 0 {
+		fullSyncInterval = DefaultFullSyncInterval
+	}
+	return &Provider{
+		spotifyRepo:      spotifyRepo,
+		cacheRepo:        cacheRepo,
+		mirrorRepo:       mirrorRepo,
+		fullSyncInterval: fullSyncInterval,
+		now:              time.Now,
+	}
+}
+
+// GetUserTracks returns the tracks saved in the library, most recently saved first
+func (p *Provider) GetUserTracks(ctx context.Context) ([]spotifyAPI.SavedTrack, error) {
+	// Try cache first (if available)
+	if p.cacheRepo != nil {
+		cached, err := p.cacheRepo.GetUserTracks(ctx)
+		if err == nil && len(cached) > 0 {
+			return cached, nil
+		}
+	}
+
+	var tracks []spotifyAPI.SavedTrack
+	var err error
+	if p.mirrorRepo == nil {
+		tracks, err = p.spotifyRepo.GetAllUserTracks(ctx)
+	} else if err = p.Sync(ctx, false); err == nil {
+		tracks, err = p.mirrorRepo.GetTracks(ctx, shared.OwnerFromContext(ctx))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Cache for future use (if cache is available)
+	if p.cacheRepo != nil {
+		_ = p.cacheRepo.SetUserTracks(ctx, tracks, cacheTTL)
+	}
+
+	return tracks, nil
+}
+
+// Sync brings the mirror up to date with the library. The sync is full when requested,
+// when the owner was never synced or when a reconciliation is due.
+func (p *Provider) Sync(ctx context.Context, full bool) error {
+	if p.mirrorRepo == nil {
+		return nil
+	}
+	owner := shared.OwnerFromContext(ctx)
+	now := p.now()
+
+	state, err := p.mirrorRepo.GetState(ctx, owner)
+	if errors.Is(err, shared.ErrNotFound) {
+		state, err = &domainLibrary.SyncState{Owner: owner}, nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if full || state.FullSyncDue(now, p.fullSyncInterval) {
+		err = p.syncAll(ctx, owner)
+		state.LastFullSyncAt = now
+	} else {
+		err = p.syncNewest(ctx, owner)
+	}
+	if err != nil {
+		return err
+	}
+
+	state.LastSyncedAt = now
+	return p.mirrorRepo.SaveState(ctx, state)
+}
+
+// syncAll replaces the mirror with the whole library
+func (p *Provider) syncAll(ctx context.Context, owner string) error {
+	tracks, err := p.spotifyRepo.GetAllUserTracks(ctx)
+	if err != nil {
+		return err
+	}
+	return p.mirrorRepo.ReplaceTracks(ctx, owner, tracks)
+}
+
+// syncNewest mirrors the tracks saved since the last sync
+func (p *Provider) syncNewest(ctx context.Context, owner string) error {
+	mirrored, err := p.mirrorRepo.GetTracks(ctx, owner)
+	if err != nil {
+		return err
+	}
+	known := make(map[spotifyAPI.ID]string, len(mirrored))
+	for _, track := range mirrored {
+		known[track.ID] = track.AddedAt
+	}
+
+	var saved []spotifyAPI.SavedTrack
+	for offset := 0; ; offset += pageSize {
+		page, err := p.spotifyRepo.GetUserTracks(ctx, pageSize, offset)
+		if err != nil {
+			return err
+		}
+
+		for _, track := range page {
+			if addedAt, exists := known[track.ID]; exists && addedAt == track.AddedAt {
+				return p.mirrorRepo.AddTracks(ctx, owner, saved)
+			}
+			saved = append(saved, track)
+		}
+
+		if len(page) < pageSize {
+			return p.mirrorRepo.AddTracks(ctx, owner, saved)
+		}
+	}
+}
+
+// Forget removes tracks unsaved by this app from the mirror. If that fails, the next
+// read reconciles the whole library instead.
+func (p *Provider) Forget(ctx context.Context, trackIDs []spotifyAPI.ID) {
+	if p.mirrorRepo == nil {
+		return
+	}
+	owner := shared.OwnerFromContext(ctx)
+
+	ids := make([]string, len(trackIDs))
+	for i, id := range trackIDs {
+		ids[i] = id.String()
+	}
+	if err := p.mirrorRepo.RemoveTracks(ctx, owner, ids); err != nil {
+		log.Printf("WARNING: Failed to remove tracks from the library mirror: %v", err)
+		p.requireFullSync(ctx, owner)
+	}
+}
+
+// requireFullSync makes the next sync of the owner a full reconciliation
+func (p *Provider) requireFullSync(ctx context.Context, owner string) {
+	state, err := p.mirrorRepo.GetState(ctx, owner)
+	if err != nil {
+		return
+	}
+	state.LastFullSyncAt = time.Time{}
+	if err := p.mirrorRepo.SaveState(ctx, state); err != nil {
+		log.Printf("WARNING: Failed to schedule a full sync of the library mirror: %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package usertracks
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	domainLibrary "github.com/RubenPari/clear-songs/internal/domain/library"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	"github.com/RubenPari/clear-songs/test/mocks"
+	"github.com/stretchr/testify/assert"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+// fakeMirrorRepository is an in-memory domainLibrary.MirrorRepository for a single owner
+type fakeMirrorRepository struct {
+	tracks map[spotifyAPI.ID]spotifyAPI.SavedTrack
+	state  *domainLibrary.SyncState
+}
+
+func (r *fakeMirrorRepository) GetTracks(ctx context.Context, owner string) ([]spotifyAPI.SavedTrack, error) {
+	tracks := []spotifyAPI.SavedTrack{}
+	for _, track := range r.tracks {
+		tracks = append(tracks, track)
+	}
+	sort.Slice(tracks, func(i, j int) bool { return tracks[i].AddedAt > tracks[j].AddedAt })
+	return tracks, nil
+}
+
+func (r *fakeMirrorRepository) AddTracks(ctx context.Context, owner string, tracks []spotifyAPI.SavedTrack) error {
+	for _, track := range tracks {
+		r.tracks[track.ID] = track
+	}
+	return nil
+}
+
+func (r *fakeMirrorRepository) ReplaceTracks(ctx context.Context, owner string, tracks []spotifyAPI.SavedTrack) error {
+	r.tracks = make(map[spotifyAPI.ID]spotifyAPI.SavedTrack)
+	return r.AddTracks(ctx, owner, tracks)
+}
+
+func (r *fakeMirrorRepository) RemoveTracks(ctx context.Context, owner string, trackIDs []string) error {
+	for _, id := range trackIDs {
+		delete(r.tracks, spotifyAPI.ID(id))
+	}
+	return nil
+}
+
+func (r *fakeMirrorRepository) GetState(ctx context.Context, owner string) (*domainLibrary.SyncState, error) {
+	if r.state == nil {
+		return nil, shared.ErrNotFound
+	}
+	state := *r.state
+	return &state, nil
+}
+
+func (r *fakeMirrorRepository) SaveState(ctx context.Context, state *domainLibrary.SyncState) error {
+	r.state = state
+	return nil
+}
+
+func saved(id, addedAt string) spotifyAPI.SavedTrack {
+	return spotifyAPI.SavedTrack{AddedAt: addedAt, FullTrack: spotifyAPI.FullTrack{SimpleTrack: spotifyAPI.SimpleTrack{ID: spotifyAPI.ID(id)}}}
+}
+
+func trackIDs(tracks []spotifyAPI.SavedTrack) []spotifyAPI.ID {
+	ids := make([]spotifyAPI.ID, len(tracks))
+	for i, track := range tracks {
+		ids[i] = track.ID
+	}
+	return ids
+}
+
+func TestProvider_GetUserTracks(t *testing.T) {
+	ctx := shared.WithOwner(context.Background(), "spotify:user_1")
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	mockSpotifyRepo := new(mocks.MockSpotifyRepository)
+	mirror := &fakeMirrorRepository{tracks: make(map[spotifyAPI.ID]spotifyAPI.SavedTrack)}
+	provider := NewProvider(mockSpotifyRepo, nil, mirror, time.Hour)
+	provider.now = func() time.Time { return now }
+
+	t.Run("First read mirrors the whole library", func(t *testing.T) {
+		mockSpotifyRepo.On("GetAllUserTracks", ctx).Return([]spotifyAPI.SavedTrack{
+			saved("b", "2024-05-02T00:00:00Z"),
+			saved("a", "2024-05-01T00:00:00Z"),
+		}, nil).Once()
+
+		tracks, err := provider.GetUserTracks(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []spotifyAPI.ID{"b", "a"}, trackIDs(tracks))
+		assert.Equal(t, now, mirror.state.LastFullSyncAt)
+	})
+
+	t.Run("Next reads only walk the newly saved tracks", func(t *testing.T) {
+		now = now.Add(10 * time.Minute)
+		mockSpotifyRepo.On("GetUserTracks", ctx, 50, 0).Return([]spotifyAPI.SavedTrack{
+			saved("c", "2024-05-03T00:00:00Z"),
+			saved("b", "2024-05-02T00:00:00Z"),
+			saved("a", "2024-05-01T00:00:00Z"),
+		}, nil).Once()
+
+		tracks, err := provider.GetUserTracks(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []spotifyAPI.ID{"c", "b", "a"}, trackIDs(tracks))
+		assert.Equal(t, now, mirror.state.LastSyncedAt)
+		mockSpotifyRepo.AssertNumberOfCalls(t, "GetAllUserTracks", 1)
+	})
+
+	t.Run("Tracks unsaved through the wrapped repository leave the mirror", func(t *testing.T) {
+		mockSpotifyRepo.On("DeleteTracksFromLibrary", ctx, []spotifyAPI.ID{"b"}).Return(nil).Once()
+
+		err := provider.Wrap(mockSpotifyRepo).DeleteTracksFromLibrary(ctx, []spotifyAPI.ID{"b"})
+
+		assert.NoError(t, err)
+		_, exists := mirror.tracks["b"]
+		assert.False(t, exists)
+	})
+
+	t.Run("A due reconciliation catches removals made elsewhere", func(t *testing.T) {
+		now = now.Add(time.Hour)
+		mockSpotifyRepo.On("GetAllUserTracks", ctx).Return([]spotifyAPI.SavedTrack{
+			saved("c", "2024-05-03T00:00:00Z"),
+		}, nil).Once()
+
+		tracks, err := provider.GetUserTracks(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []spotifyAPI.ID{"c"}, trackIDs(tracks))
+		mockSpotifyRepo.AssertNumberOfCalls(t, "GetAllUserTracks", 2)
+	})
+}
@@ -0,0 +1,25 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+// SpotifyCredential is the Spotify refresh token stored for an owner (see
+// shared.WithOwner). Sessions and their cached access tokens expire, so scheduled work
+// acting for the owner mints its access tokens from this credential instead.
+type SpotifyCredential struct {
+	Owner        string
+	RefreshToken string
+	UpdatedAt    time.Time
+}
+
+// CredentialRepository persists one Spotify credential per owner
+type CredentialRepository interface {
+	// Save creates or replaces the credential of its owner
+	Save(ctx context.Context, credential *SpotifyCredential) error
+	// Get returns shared.ErrNotFound when no credential is stored for owner
+	Get(ctx context.Context, owner string) (*SpotifyCredential, error)
+	// ListOwners returns the owners with a stored credential
+	ListOwners(ctx context.Context) ([]string, error)
+}
@@ -0,0 +1,148 @@
+package backup
+
+import (
+	"strings"
+	"time"
+
+	"github.com/RubenPari/clear-songs/internal/domain/operation"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+// TrackBackup represents a track saved in the backup table before being deleted.
+// Every deletion is a separate backup, so a track deleted twice has two of them.
+type TrackBackup struct {
+	BackupID string
+	// ID is the Spotify track ID
+	ID         string
+	Name       string
+	Artists    []Artist
+	Album      string
+	AlbumID    string
+	ISRC       string
+	DurationMs int
+	URI        string
+	URL        string
+
+	Origin
+	// Position is the position of the track in Origin.PlaylistID before removal
+	Position *int
+
+	BackedUpAt time.Time
+	RestoredAt *time.Time
+}
+
+// LegacyOwner owns the backups taken before owners were recorded. No user has this
+// owner, so they are hidden until a user claims them with POST /backup/legacy/claim.
+const LegacyOwner = "legacy"
+
+// Artist is an artist credited on a backed-up track
+type Artist struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+}
+
+// Origin describes who removed backed-up tracks, and how
+type Origin struct {
+	// Owner is the user that removed the tracks (see shared.WithOwner). Backups taken
+	// before owners were recorded belong to LegacyOwner.
+	Owner string
+	// OperationID is the recorded operation that removed the tracks, if any
+	OperationID string
+	// Reason is the kind of operation that removed the tracks
+	Reason string
+	// PlaylistID is set when the tracks were removed from a playlist
+	PlaylistID string
+}
+
+// RemovedFromLibrary reports whether the tracks were removed from the library. Tracks
+// removed from PlaylistID alone are still saved in the library.
+func (o Origin) RemovedFromLibrary() bool {
+	return o.PlaylistID == "" || o.Reason == string(operation.KindDeletePlaylistAndLibraryTracks)
+}
+
+// IsRestored reports whether the track has already been re-saved to the library
+func (t TrackBackup) IsRestored() bool {
+	return t.RestoredAt != nil
+}
+
+// ArtistNames returns the names of the credited artists, comma-separated
+func (t TrackBackup) ArtistNames() string {
+	names := make([]string, len(t.Artists))
+	for i, artist := range t.Artists {
+		names[i] = artist.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// FromFullTracks builds the backups of tracks about to be removed from the library
+func FromFullTracks(origin Origin, tracks []spotifyAPI.FullTrack) []TrackBackup {
+	backups := make([]TrackBackup, 0, len(tracks))
+	for _, track := range tracks {
+		backups = append(backups, fromFullTrack(origin, track, nil))
+	}
+	return backups
+}
+
+// FromPlaylistTracks builds the backups of playlist items about to be removed, where
+// positions[i] is the position of items[i] in the playlist. Local files cannot be
+// restored and are skipped.
+func FromPlaylistTracks(origin Origin, items []spotifyAPI.PlaylistTrack, positions []int) []TrackBackup {
+	backups := make([]TrackBackup, 0, len(items))
+	for i, item := range items {
+		if item.Track.ID == "" {
+			continue
+		}
+
+		var position *int
+		if i < len(positions) {
+			p := positions[i]
+			position = &p
+		}
+		backups = append(backups, fromFullTrack(origin, item.Track, position))
+	}
+	return backups
+}
+
+func fromFullTrack(origin Origin, track spotifyAPI.FullTrack, position *int) TrackBackup {
+	artists := make([]Artist, len(track.Artists))
+	for i, artist := range track.Artists {
+		artists[i] = Artist{ID: artist.ID.String(), Name: artist.Name}
+	}
+
+	return TrackBackup{
+		ID:         track.ID.String(),
+		Name:       track.Name,
+		Artists:    artists,
+		Album:      track.Album.Name,
+		AlbumID:    track.Album.ID.String(),
+		ISRC:       track.ExternalIDs["isrc"],
+		DurationMs: track.Duration,
+		URI:        string(track.URI),
+		URL:        track.ExternalURLs["spotify"],
+		Origin:     origin,
+		Position:   position,
+	}
+}
+
+// Filter narrows down the backed-up tracks returned by the database
+type Filter struct {
+	// Owner restricts the result to the backups of an owner
+	Owner string
+	// BackupIDs restricts the result to the given backups (empty means all)
+	BackupIDs []string
+	// TrackIDs restricts the result to the given Spotify track IDs (empty means all)
+	TrackIDs []string
+	// OperationID restricts the result to the tracks removed by one operation
+	OperationID string
+	// PlaylistID restricts the result to the tracks removed from one playlist
+	PlaylistID string
+	// Artist matches any artist name (case-insensitive, partial match)
+	Artist string
+	// Album matches the album name (case-insensitive, partial match)
+	Album string
+	// DeletedFrom and DeletedTo bound the date the track was backed up
+	DeletedFrom *time.Time
+	DeletedTo   *time.Time
+	// IncludeRestored also returns tracks already restored to the library
+	IncludeRestored bool
+}
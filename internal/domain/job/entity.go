@@ -0,0 +1,53 @@
+package job
+
+import (
+	"context"
+	"time"
+)
+
+// Status is the lifecycle state of a job
+type Status string
+
+const (
+	StatusPending     Status = "pending"
+	StatusRunning     Status = "running"
+	StatusCompleted   Status = "completed"
+	StatusFailed      Status = "failed"
+	StatusCancelled   Status = "cancelled"
+	StatusInterrupted Status = "interrupted"
+)
+
+// Job is a destructive operation executed in the background
+type Job struct {
+	ID    string
+	Type  string
+	Owner string // user that enqueued the job (see shared.WithOwner)
+
+	Status          Status
+	TracksTotal     int
+	TracksProcessed int
+	BatchesDone     int
+	Errors          []string
+
+	CreatedAt  time.Time
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+}
+
+// IsFinished reports whether the job reached a terminal state
+func (j *Job) IsFinished() bool {
+	switch j.Status {
+	case StatusCompleted, StatusFailed, StatusCancelled, StatusInterrupted:
+		return true
+	}
+	return false
+}
+
+// Repository persists job state
+type Repository interface {
+	Create(ctx context.Context, job *Job) error
+	Update(ctx context.Context, job *Job) error
+	GetByID(ctx context.Context, id string) (*Job, error)
+	// MarkUnfinishedInterrupted flags jobs left pending or running by a previous process
+	MarkUnfinishedInterrupted(ctx context.Context) error
+}
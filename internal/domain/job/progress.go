@@ -0,0 +1,41 @@
+package job
+
+import "context"
+
+// Reporter receives progress updates from code running inside a job
+type Reporter interface {
+	AddTotal(tracks int)
+	BatchDone(tracks int)
+	Error(err error)
+}
+
+type reporterKey struct{}
+
+// WithReporter attaches a progress reporter to the context
+func WithReporter(ctx context.Context, reporter Reporter) context.Context {
+	return context.WithValue(ctx, reporterKey{}, reporter)
+}
+
+// ReportTotal adds to the number of tracks the job is expected to process.
+// It is a no-op when ctx does not belong to a job.
+func ReportTotal(ctx context.Context, tracks int) {
+	if reporter, ok := ctx.Value(reporterKey{}).(Reporter); ok {
+		reporter.AddTotal(tracks)
+	}
+}
+
+// ReportBatch records a completed batch of tracks.
+// It is a no-op when ctx does not belong to a job.
+func ReportBatch(ctx context.Context, tracks int) {
+	if reporter, ok := ctx.Value(reporterKey{}).(Reporter); ok {
+		reporter.BatchDone(tracks)
+	}
+}
+
+// ReportError records a non-fatal error.
+// It is a no-op when ctx does not belong to a job.
+func ReportError(ctx context.Context, err error) {
+	if reporter, ok := ctx.Value(reporterKey{}).(Reporter); ok {
+		reporter.Error(err)
+	}
+}
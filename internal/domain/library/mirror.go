@@ -0,0 +1,37 @@
+package library
+
+import (
+	"context"
+	"time"
+
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+// SyncState records when the library mirror of an owner was last synced
+type SyncState struct {
+	Owner        string
+	LastSyncedAt time.Time
+	// LastFullSyncAt is the last reconciliation with the whole library, which is the
+	// only way to see removals made outside this app
+	LastFullSyncAt time.Time
+}
+
+// FullSyncDue reports whether a full reconciliation is due after interval
+func (s *SyncState) FullSyncDue(now time.Time, interval time.Duration) bool {
+	return s.LastFullSyncAt.IsZero() || now.Sub(s.LastFullSyncAt) >= interval
+}
+
+// MirrorRepository persists a local copy of the tracks saved in each owner's library
+type MirrorRepository interface {
+	// GetTracks returns the mirrored tracks, most recently saved first
+	GetTracks(ctx context.Context, owner string) ([]spotifyAPI.SavedTrack, error)
+	// AddTracks inserts tracks, or updates them when already mirrored
+	AddTracks(ctx context.Context, owner string, tracks []spotifyAPI.SavedTrack) error
+	// ReplaceTracks replaces the whole mirror with tracks
+	ReplaceTracks(ctx context.Context, owner string, tracks []spotifyAPI.SavedTrack) error
+	RemoveTracks(ctx context.Context, owner string, trackIDs []string) error
+
+	// GetState returns shared.ErrNotFound when the owner was never synced
+	GetState(ctx context.Context, owner string) (*SyncState, error)
+	SaveState(ctx context.Context, state *SyncState) error
+}
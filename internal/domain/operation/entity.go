@@ -0,0 +1,93 @@
+package operation
+
+import (
+	"context"
+	"time"
+)
+
+// UndoClaimTimeout is how long an undo claim blocks other undos of the same operation.
+// After it, the claim of an undo that never finished (e.g. the server stopped) is stale
+// and the operation can be undone again.
+const UndoClaimTimeout = 15 * time.Minute
+
+// Kind identifies the destructive use case that produced an operation
+type Kind string
+
+const (
+	KindDeleteTrack                    Kind = "delete_track"
+	KindDeleteTracks                   Kind = "delete_tracks"
+	KindDeleteTracksByArtist           Kind = "delete_tracks_by_artist"
+	KindDeleteTracksByRange            Kind = "delete_tracks_by_range"
+	KindDeleteTracksByGenre            Kind = "delete_tracks_by_genre"
+	KindDeletePlaylistTracks           Kind = "delete_playlist_tracks"
+	KindDeletePlaylistAndLibraryTracks Kind = "delete_playlist_and_library_tracks"
+	KindMergeDuplicates                Kind = "merge_duplicates"
+	KindDedupePlaylist                 Kind = "dedupe_playlist"
+	KindConvertAlbum                   Kind = "convert_album"
+)
+
+// ParamAlbumID is the Params key holding the album a KindConvertAlbum operation removed
+// from the saved albums
+const ParamAlbumID = "album_id"
+
+// PlaylistTrack is a track removed from a playlist, with its position before removal
+type PlaylistTrack struct {
+	TrackID  string `json:"track_id"`
+	Position int    `json:"position"`
+}
+
+// Operation records what a destructive use case changed, so that it can be undone
+type Operation struct {
+	ID     string
+	Kind   Kind
+	Owner  string // user that performed the operation (see shared.WithOwner)
+	Params map[string]string
+
+	// LibraryTrackIDs are the tracks removed from the user library
+	LibraryTrackIDs []string
+	// PlaylistID and PlaylistTracks describe the tracks removed from a playlist
+	PlaylistID     string
+	PlaylistTracks []PlaylistTrack
+	// PlaylistTracksAdded are the tracks the operation inserted in the playlist in place of
+	// removed ones, with their position after the operation. An undo removes them again.
+	PlaylistTracksAdded []PlaylistTrack
+	// PlaylistTracksRestored is how many PlaylistTracks, in position order, an undo that
+	// failed part way already re-inserted
+	PlaylistTracksRestored int
+
+	CreatedAt time.Time
+	// UndoStartedAt is set while an undo of the operation is running (see Repository.ClaimUndo)
+	UndoStartedAt *time.Time
+	UndoneAt      *time.Time
+}
+
+// RemovedAlbumID returns the album the operation removed from the saved albums, if any
+func (o *Operation) RemovedAlbumID() string {
+	if o.Kind != KindConvertAlbum {
+		return ""
+	}
+	return o.Params[ParamAlbumID]
+}
+
+// IsUndone reports whether the operation was already reverted
+func (o *Operation) IsUndone() bool {
+	return o.UndoneAt != nil
+}
+
+// Repository persists operations
+type Repository interface {
+	Create(ctx context.Context, op *Operation) error
+	GetByID(ctx context.Context, id string) (*Operation, error)
+	// ListByOwner returns the most recent operations of an owner, newest first
+	ListByOwner(ctx context.Context, owner string, limit int) ([]Operation, error)
+	// ClaimUndo atomically marks an undo of the operation as started at the given time. It
+	// fails with shared.ErrConflict if the operation is already undone or another undo
+	// claimed it less than UndoClaimTimeout ago.
+	ClaimUndo(ctx context.Context, id string, at time.Time) error
+	// ReleaseUndo clears the claim of an undo that failed, so it can be retried
+	ReleaseUndo(ctx context.Context, id string) error
+	MarkUndone(ctx context.Context, id string, at time.Time) error
+	// MarkPlaylistTracksRestored records the progress of an undo (see
+	// Operation.PlaylistTracksRestored)
+	MarkPlaylistTracksRestored(ctx context.Context, id string, count int) error
+}
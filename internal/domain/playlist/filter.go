@@ -0,0 +1,94 @@
+package playlist
+
+import (
+	"time"
+
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+// TrackFilter selects the items of a playlist. Every criterion that is set must match;
+// an empty filter matches every item.
+type TrackFilter struct {
+	// ArtistIDs matches items crediting any of the artists
+	ArtistIDs []spotifyAPI.ID
+	AlbumIDs  []spotifyAPI.ID
+	// AddedAfter and AddedBefore bound the date the item was added to the playlist
+	AddedAfter  *time.Time
+	AddedBefore *time.Time
+	// AddedBy is the ID of the user who added the item
+	AddedBy       string
+	DurationMinMs *int
+	DurationMaxMs *int
+	Explicit      *bool
+	// LocalOnly matches local files only
+	LocalOnly bool
+}
+
+// IsEmpty reports whether the filter has no criteria
+func (f TrackFilter) IsEmpty() bool {
+	return len(f.ArtistIDs) == 0 && len(f.AlbumIDs) == 0 &&
+		f.AddedAfter == nil && f.AddedBefore == nil && f.AddedBy == "" &&
+		f.DurationMinMs == nil && f.DurationMaxMs == nil &&
+		f.Explicit == nil && !f.LocalOnly
+}
+
+// Matches reports whether a playlist item satisfies every criterion of the filter.
+// Items without an added date never match a date criterion.
+func (f TrackFilter) Matches(item spotifyAPI.PlaylistTrack) bool {
+	track := item.Track
+
+	if f.LocalOnly && !item.IsLocal {
+		return false
+	}
+	if len(f.ArtistIDs) > 0 && !anyArtist(track.Artists, f.ArtistIDs) {
+		return false
+	}
+	if len(f.AlbumIDs) > 0 && !containsID(f.AlbumIDs, track.Album.ID) {
+		return false
+	}
+	if f.AddedBy != "" && item.AddedBy.ID != f.AddedBy {
+		return false
+	}
+	if f.DurationMinMs != nil && track.Duration < *f.DurationMinMs {
+		return false
+	}
+	if f.DurationMaxMs != nil && track.Duration > *f.DurationMaxMs {
+		return false
+	}
+	if f.Explicit != nil && track.Explicit != *f.Explicit {
+		return false
+	}
+
+	if f.AddedAfter != nil || f.AddedBefore != nil {
+		addedAt, err := time.Parse(spotifyAPI.TimestampLayout, item.AddedAt)
+		if err != nil {
+			return false
+		}
+		if f.AddedAfter != nil && !addedAt.After(*f.AddedAfter) {
+			return false
+		}
+		if f.AddedBefore != nil && !addedAt.Before(*f.AddedBefore) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func anyArtist(artists []spotifyAPI.SimpleArtist, ids []spotifyAPI.ID) bool {
+	for _, artist := range artists {
+		if containsID(ids, artist.ID) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsID(ids []spotifyAPI.ID, id spotifyAPI.ID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
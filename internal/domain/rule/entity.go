@@ -0,0 +1,179 @@
+package rule
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/RubenPari/clear-songs/internal/domain/track"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+// Criteria selects saved tracks. Every criterion that is set must match (logical AND);
+// unset criteria are ignored.
+type Criteria struct {
+	// ArtistMinTracks/ArtistMaxTracks bound the number of saved tracks of the track's
+	// artists, counted under ArtistMatch like the deletion by range: with primary, the
+	// primary artist must be in range; with any or all, one of the credited artists.
+	ArtistMinTracks *int              `json:"artist_min_tracks,omitempty"`
+	ArtistMaxTracks *int              `json:"artist_max_tracks,omitempty"`
+	ArtistMatch     track.ArtistMatch `json:"artist_match,omitempty"`
+
+	// AddedMoreThanDays/AddedLessThanDays bound how long ago the track was saved
+	AddedMoreThanDays *int `json:"added_more_than_days,omitempty"`
+	AddedLessThanDays *int `json:"added_less_than_days,omitempty"`
+
+	// Album matches the album name (case-insensitive, partial match)
+	Album string `json:"album,omitempty"`
+
+	// PopularityMin/PopularityMax bound the Spotify popularity (0-100)
+	PopularityMin *int `json:"popularity_min,omitempty"`
+	PopularityMax *int `json:"popularity_max,omitempty"`
+
+	// DurationMinSeconds/DurationMaxSeconds bound the track length
+	DurationMinSeconds *int `json:"duration_min_seconds,omitempty"`
+	DurationMaxSeconds *int `json:"duration_max_seconds,omitempty"`
+
+	// InPlaylist requires the track to be (true) or not to be (false) in any of the user's playlists
+	InPlaylist *bool `json:"in_playlist,omitempty"`
+}
+
+// IsEmpty reports whether no criterion is set; an empty rule would match the whole library
+func (c Criteria) IsEmpty() bool {
+	return c.ArtistMinTracks == nil && c.ArtistMaxTracks == nil &&
+		c.AddedMoreThanDays == nil && c.AddedLessThanDays == nil &&
+		c.Album == "" &&
+		c.PopularityMin == nil && c.PopularityMax == nil &&
+		c.DurationMinSeconds == nil && c.DurationMaxSeconds == nil &&
+		c.InPlaylist == nil
+}
+
+// NeedsPlaylists reports whether matching requires the user's playlist contents
+func (c Criteria) NeedsPlaylists() bool {
+	return c.InPlaylist != nil
+}
+
+// ArtistFilter returns the filter selecting the tracks of the artists whose count, under
+// ArtistMatch, is within the artist_tracks bounds. With all, a single artist behaves
+// like any, so the filter matches a track crediting any of them.
+func (c Criteria) ArtistFilter(counts map[spotifyAPI.ID]int) track.ArtistFilter {
+	filter := track.ArtistFilter{Match: track.ArtistMatchPrimary}
+	if c.ArtistMatch.CountsFeatured() {
+		filter.Match = track.ArtistMatchAny
+	}
+	for id, count := range counts {
+		if inRange(count, c.ArtistMinTracks, c.ArtistMaxTracks) {
+			filter.IDs = append(filter.IDs, id)
+		}
+	}
+	return filter
+}
+
+// Library is the data criteria are evaluated against
+type Library struct {
+	// Artists selects the tracks of the artists within the artist_tracks bounds (see
+	// Criteria.ArtistFilter)
+	Artists track.ArtistFilter
+	// PlaylistTrackIDs holds every track found in the user's playlists
+	PlaylistTrackIDs map[spotifyAPI.ID]bool
+	Now              time.Time
+}
+
+// Matches reports whether a saved track satisfies every criterion
+func (c Criteria) Matches(track spotifyAPI.SavedTrack, library Library) bool {
+	if (c.ArtistMinTracks != nil || c.ArtistMaxTracks != nil) && !library.Artists.Matches(track.Artists) {
+		return false
+	}
+
+	if c.AddedMoreThanDays != nil || c.AddedLessThanDays != nil {
+		addedAt, err := time.Parse(spotifyAPI.TimestampLayout, track.AddedAt)
+		if err != nil {
+			return false
+		}
+		age := int(library.Now.Sub(addedAt).Hours() / 24)
+		if c.AddedMoreThanDays != nil && age <= *c.AddedMoreThanDays {
+			return false
+		}
+		if c.AddedLessThanDays != nil && age >= *c.AddedLessThanDays {
+			return false
+		}
+	}
+
+	if c.Album != "" && !strings.Contains(strings.ToLower(track.Album.Name), strings.ToLower(c.Album)) {
+		return false
+	}
+
+	if !inRange(track.Popularity, c.PopularityMin, c.PopularityMax) {
+		return false
+	}
+
+	if !inRange(track.Duration/1000, c.DurationMinSeconds, c.DurationMaxSeconds) {
+		return false
+	}
+
+	if c.InPlaylist != nil && library.PlaylistTrackIDs[track.ID] != *c.InPlaylist {
+		return false
+	}
+
+	return true
+}
+
+func inRange(value int, min, max *int) bool {
+	if min != nil && value < *min {
+		return false
+	}
+	if max != nil && value > *max {
+		return false
+	}
+	return true
+}
+
+// Rule is a persisted cleanup rule evaluated on a cron schedule
+type Rule struct {
+	ID       string
+	Owner    string // user whose stored Spotify credential is used for scheduled runs
+	Name     string
+	Criteria Criteria
+	// Schedule is a standard 5-field cron expression (or a descriptor such as @monthly)
+	Schedule string
+	Enabled  bool
+	// DryRun evaluates the rule and notifies without deleting anything
+	DryRun bool
+	// WebhookURL, if set, receives a JSON report after every run
+	WebhookURL string
+
+	LastRunAt      *time.Time
+	LastMatchCount int
+	LastError      string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Repository persists rules
+type Repository interface {
+	Create(ctx context.Context, rule *Rule) error
+	Update(ctx context.Context, rule *Rule) error
+	Delete(ctx context.Context, id string) error
+	GetByID(ctx context.Context, id string) (*Rule, error)
+	ListByOwner(ctx context.Context, owner string) ([]Rule, error)
+	// ListEnabled returns the enabled rules of every owner, for the scheduler
+	ListEnabled(ctx context.Context) ([]Rule, error)
+}
+
+// RunReport describes the outcome of a rule run
+type RunReport struct {
+	RuleID       string    `json:"rule_id"`
+	RuleName     string    `json:"rule_name"`
+	DryRun       bool      `json:"dry_run"`
+	Scheduled    bool      `json:"scheduled"`
+	MatchedCount int       `json:"matched_count"`
+	DeletedCount int       `json:"deleted_count"`
+	Error        string    `json:"error,omitempty"`
+	RanAt        time.Time `json:"ran_at"`
+}
+
+// Notifier is told about every rule run
+type Notifier interface {
+	Notify(ctx context.Context, rule *Rule, report *RunReport) error
+}
@@ -9,12 +9,12 @@ import (
 
 // CacheRepository defines the interface for caching operations
 type CacheRepository interface {
-	// Token operations
-	SetToken(ctx context.Context, token *oauth2.Token) error
-	GetToken(ctx context.Context) (*oauth2.Token, error)
-	ClearToken(ctx context.Context) error
+	// Token operations, keyed by session ID
+	SetToken(ctx context.Context, sessionID string, token *oauth2.Token) error
+	GetToken(ctx context.Context, sessionID string) (*oauth2.Token, error)
+	ClearToken(ctx context.Context, sessionID string) error
 	
-	// User tracks cache
+	// User tracks cache (scoped to the owner carried by ctx, or else its session)
 	GetUserTracks(ctx context.Context) ([]spotifyAPI.SavedTrack, error)
 	SetUserTracks(ctx context.Context, tracks []spotifyAPI.SavedTrack, ttl time.Duration) error
 	InvalidateUserTracks(ctx context.Context) error
@@ -24,7 +24,12 @@ type CacheRepository interface {
 	SetPlaylistTracks(ctx context.Context, playlistID spotifyAPI.ID, tracks []spotifyAPI.PlaylistTrack, ttl time.Duration) error
 	InvalidatePlaylistTracks(ctx context.Context, playlistID spotifyAPI.ID) error
 	
-	// Generic cache operations
+	// Artist metadata cache, shared by all sessions since catalog data is public.
+	// GetArtists returns the cached artists among ids, keyed by ID
+	GetArtists(ctx context.Context, ids []spotifyAPI.ID) (map[spotifyAPI.ID]spotifyAPI.FullArtist, error)
+	SetArtists(ctx context.Context, artists []spotifyAPI.FullArtist, ttl time.Duration) error
+	
+	// Generic cache operations (scoped to the owner carried by ctx, or else its session)
 	Get(ctx context.Context, key string, target interface{}) (bool, error)
 	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
 	Delete(ctx context.Context, key string) error
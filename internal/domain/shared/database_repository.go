@@ -1,14 +1,23 @@
 package shared
 
 import (
-	spotifyAPI "github.com/zmb3/spotify"
+	"context"
+
+	"github.com/RubenPari/clear-songs/internal/domain/backup"
 )
 
 // DatabaseRepository defines the interface for database operations
 type DatabaseRepository interface {
-	// SaveTracksBackup saves tracks to database as backup
-	SaveTracksBackup(tracks []spotifyAPI.PlaylistTrack) error
+	// SaveTracksBackup saves the backups of tracks about to be removed
+	SaveTracksBackup(ctx context.Context, tracks []backup.TrackBackup) error
+
+	// GetTracksBackup retrieves the backed-up tracks matching the filter
+	GetTracksBackup(ctx context.Context, filter backup.Filter) ([]backup.TrackBackup, error)
+
+	// MarkTracksRestored flags the given backups of owner as restored
+	MarkTracksRestored(ctx context.Context, owner string, backupIDs []string) error
 
-	// SaveFullTracksBackup saves full tracks to database as backup
-	SaveFullTracksBackup(tracks []spotifyAPI.FullTrack) error
+	// ReassignTracksBackup hands every backup of owner from to owner to and returns how
+	// many were moved
+	ReassignTracksBackup(ctx context.Context, from, to string) (int, error)
 }
@@ -12,9 +12,21 @@ var (
 	// ErrValidation indicates that the provided input is invalid.
 	ErrValidation = errors.New("validation failed")
 	
+	// ErrForbidden indicates that the user is authenticated but not allowed to perform the action.
+	ErrForbidden = errors.New("forbidden")
+	
+	// ErrConflict indicates that a resource changed while it was being processed.
+	ErrConflict = errors.New("resource changed")
+	
 	// ErrInternal indicates an unexpected internal server error.
 	ErrInternal = errors.New("internal server error")
 	
 	// ErrExternalAPI indicates a failure when communicating with an external service (e.g., Spotify).
 	ErrExternalAPI = errors.New("external API error")
+	
+	// ErrBackupFailed indicates that tracks could not be backed up before being removed.
+	ErrBackupFailed = errors.New("backup failed")
+
+	// ErrUnavailable indicates that a feature needs a backend that is not configured (e.g. the database).
+	ErrUnavailable = errors.New("service unavailable")
 )
@@ -0,0 +1,59 @@
+package shared
+
+import "context"
+
+type sessionContextKey struct{}
+
+type ownerContextKey struct{}
+
+type spotifyRepositoryContextKey struct{}
+
+// WithSession returns a copy of ctx carrying the ID of the session that issued the request.
+// The session ID is the local user ID for users logged in with email/password, or an
+// opaque random identifier for Spotify-only sessions. It only locates the session's
+// Spotify token; data is owned by the owner (see WithOwner).
+func WithSession(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, sessionContextKey{}, sessionID)
+}
+
+// SessionFromContext returns the session ID stored in ctx, or an empty string
+func SessionFromContext(ctx context.Context) string {
+	sessionID, _ := ctx.Value(sessionContextKey{}).(string)
+	return sessionID
+}
+
+// LocalUserOwner returns the owner ID of a user logged in with email/password
+func LocalUserOwner(userID string) string {
+	return "user:" + userID
+}
+
+// SpotifyUserOwner returns the owner ID of a Spotify user without a local account
+func SpotifyUserOwner(spotifyUserID string) string {
+	return "spotify:" + spotifyUserID
+}
+
+// WithOwner returns a copy of ctx carrying the owner of the request: the stable identity
+// of the user that backups, operations, rules, snapshots and the library mirror belong
+// to. Unlike the session, which ends at logout or when its cookie expires, the owner is
+// the same every time the user logs in.
+func WithOwner(ctx context.Context, owner string) context.Context {
+	return context.WithValue(ctx, ownerContextKey{}, owner)
+}
+
+// OwnerFromContext returns the owner stored in ctx, or an empty string
+func OwnerFromContext(ctx context.Context) string {
+	owner, _ := ctx.Value(ownerContextKey{}).(string)
+	return owner
+}
+
+// WithSpotifyRepository returns a copy of ctx carrying the Spotify repository bound to the
+// token of the current session
+func WithSpotifyRepository(ctx context.Context, repo SpotifyRepository) context.Context {
+	return context.WithValue(ctx, spotifyRepositoryContextKey{}, repo)
+}
+
+// SpotifyRepositoryFromContext returns the Spotify repository stored in ctx, or nil
+func SpotifyRepositoryFromContext(ctx context.Context) SpotifyRepository {
+	repo, _ := ctx.Value(spotifyRepositoryContextKey{}).(SpotifyRepository)
+	return repo
+}
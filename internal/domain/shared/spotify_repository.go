@@ -3,7 +3,9 @@ package shared
 import (
 	"context"
 
+	"github.com/RubenPari/clear-songs/internal/domain/track"
 	spotifyAPI "github.com/zmb3/spotify"
+	"golang.org/x/oauth2"
 )
 
 // SpotifyRepository defines the interface for Spotify API operations
@@ -17,15 +19,18 @@ type SpotifyRepository interface {
 	// GetAllUserTracks retrieves all user tracks with pagination
 	GetAllUserTracks(ctx context.Context) ([]spotifyAPI.SavedTrack, error)
 
-	// GetTracksByArtist filters tracks by artist ID
-	GetTracksByArtist(ctx context.Context, artistID spotifyAPI.ID, tracks []spotifyAPI.SavedTrack) ([]spotifyAPI.SavedTrack, error)
+	// GetTracksByArtist filters tracks by artist IDs
+	GetTracksByArtist(ctx context.Context, filter track.ArtistFilter, tracks []spotifyAPI.SavedTrack) ([]spotifyAPI.SavedTrack, error)
 
-	// GetTrackIDsByArtist returns only track IDs for an artist
-	GetTrackIDsByArtist(ctx context.Context, artistID spotifyAPI.ID, tracks []spotifyAPI.SavedTrack) ([]spotifyAPI.ID, error)
+	// GetTrackIDsByArtist returns only the IDs of the tracks matching the artist filter
+	GetTrackIDsByArtist(ctx context.Context, filter track.ArtistFilter, tracks []spotifyAPI.SavedTrack) ([]spotifyAPI.ID, error)
 
 	// DeleteTracksFromLibrary removes tracks from user's library
 	DeleteTracksFromLibrary(ctx context.Context, trackIDs []spotifyAPI.ID) error
 
+	// AddTracksToLibrary saves tracks to user's library
+	AddTracksToLibrary(ctx context.Context, trackIDs []spotifyAPI.ID) error
+
 	// GetPlaylist retrieves a playlist by ID
 	GetPlaylist(ctx context.Context, playlistID spotifyAPI.ID) (*spotifyAPI.FullPlaylist, error)
 
@@ -38,18 +43,58 @@ type SpotifyRepository interface {
 	// DeletePlaylistTracks removes tracks from a playlist
 	DeletePlaylistTracks(ctx context.Context, playlistID spotifyAPI.ID, trackIDs []spotifyAPI.ID) error
 
+	// DeletePlaylistTracksAt removes the occurrences of tracks at the given positions of the
+	// playlist snapshot, leaving other occurrences of the same tracks in place. It returns
+	// the snapshot ID of the playlist after the removal.
+	DeletePlaylistTracksAt(ctx context.Context, playlistID spotifyAPI.ID, snapshotID string, tracks []spotifyAPI.TrackToRemove) (string, error)
+
+	// AddTracksToPlaylist inserts tracks into a playlist starting at position
+	AddTracksToPlaylist(ctx context.Context, playlistID spotifyAPI.ID, trackIDs []spotifyAPI.ID, position int) error
+
+	// ReplacePlaylistTracks replaces every item of a playlist with the given tracks, in order
+	ReplacePlaylistTracks(ctx context.Context, playlistID spotifyAPI.ID, trackIDs []spotifyAPI.ID) error
+
+	// UpdatePlaylistDetails changes the name and description of a playlist
+	UpdatePlaylistDetails(ctx context.Context, playlistID spotifyAPI.ID, name, description string) error
+
 	// GetUserPlaylists retrieves all playlists owned or followed by the user
 	GetUserPlaylists(ctx context.Context, limit, offset int) ([]spotifyAPI.SimplePlaylist, error)
 
 	// GetAllUserPlaylists retrieves all user playlists with pagination
 	GetAllUserPlaylists(ctx context.Context) ([]spotifyAPI.SimplePlaylist, error)
 
+	// GetAllUserAlbums retrieves all albums saved by the user with pagination
+	GetAllUserAlbums(ctx context.Context) ([]spotifyAPI.SavedAlbum, error)
+
+	// GetAlbum retrieves album information
+	GetAlbum(ctx context.Context, albumID spotifyAPI.ID) (*spotifyAPI.FullAlbum, error)
+
+	// GetAlbumTracks retrieves all tracks of an album with pagination
+	GetAlbumTracks(ctx context.Context, albumID spotifyAPI.ID) ([]spotifyAPI.SimpleTrack, error)
+
+	// RemoveAlbumsFromLibrary removes albums from user's saved albums
+	RemoveAlbumsFromLibrary(ctx context.Context, albumIDs []spotifyAPI.ID) error
+
+	// SaveAlbumsToLibrary saves albums to user's saved albums
+	SaveAlbumsToLibrary(ctx context.Context, albumIDs []spotifyAPI.ID) error
+
 	// GetArtist retrieves artist information
 	GetArtist(ctx context.Context, artistID spotifyAPI.ID) (*spotifyAPI.FullArtist, error)
 
+	// GetArtists retrieves information of several artists, 50 per request.
+	// Unknown IDs are left out of the result
+	GetArtists(ctx context.Context, artistIDs []spotifyAPI.ID) ([]spotifyAPI.FullArtist, error)
+
 	// GetTrack retrieves track information
 	GetTrack(ctx context.Context, trackID spotifyAPI.ID) (*spotifyAPI.FullTrack, error)
 
-	// SetAccessToken sets the OAuth token for authenticated requests
-	SetAccessToken(token interface{}) error
+	// SearchTracks searches the catalog for tracks matching a query (field filters such as isrc: are supported)
+	SearchTracks(ctx context.Context, query string, limit int) ([]spotifyAPI.FullTrack, error)
+}
+
+// SpotifyRepositoryFactory creates Spotify repositories bound to a single user's token,
+// so that concurrent sessions never share a client
+type SpotifyRepositoryFactory interface {
+	// NewRepository returns a repository authenticated with the given token
+	NewRepository(token *oauth2.Token) SpotifyRepository
 }
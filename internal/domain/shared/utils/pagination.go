@@ -28,3 +28,21 @@ func (p *Paginator) HasMore(currentCount int) bool {
 func (p *Paginator) Reset() {
 	p.Offset = 0
 }
+
+// Window returns the bounds of the current page within total items, for slicing a list
+// already held in memory. A limit of 0 or less selects every remaining item.
+func (p *Paginator) Window(total int) (start, end int) {
+	start = p.Offset
+	if start > total {
+		start = total
+	}
+	if start < 0 {
+		start = 0
+	}
+
+	end = total
+	if p.Limit > 0 && start+p.Limit < total {
+		end = start + p.Limit
+	}
+	return start, end
+}
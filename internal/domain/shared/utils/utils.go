@@ -8,14 +8,12 @@ import (
 	"runtime"
 
 	"github.com/RubenPari/clear-songs/internal/domain/shared/constants"
-	"github.com/RubenPari/clear-songs/internal/infrastructure/persistence/postgres"
 	"github.com/RubenPari/clear-songs/internal/infrastructure/persistence/postgres/models"
 	"github.com/RubenPari/clear-songs/internal/application/shared/services/SpotifyService"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	spotifyAPI "github.com/zmb3/spotify"
 	"golang.org/x/oauth2"
-	"gorm.io/gorm"
 )
 
 var (
@@ -116,65 +114,6 @@ func ConvertTracksToID(tracks interface{}) ([]spotifyAPI.ID, error) {
 	return trackIDs, nil
 }
 
-// SaveTracksBackup saves a list of tracks
-// in a SQLite database as a backup
-//
-// The tracks are saved in the `tracks` table
-// with the following columns:
-// - `id`: the track's ID as a string
-// - `name`: the track's name
-// - `artist`: the track's artist
-// - `album`: the track's album
-// - `uri`: the track's URI as a string
-// - `url`: the track's URL on Spotify
-//
-// If a track already exists in the database,
-// it is not inserted again
-//
-// If an error occurs while saving the tracks,
-// it is returned as an error
-func SaveTracksBackup(tracksPlaylist []spotifyAPI.PlaylistTrack) error {
-	// Check if database is available
-	if postgres.Db == nil {
-		log.Default().Println("WARNING: Database not available, skipping track backup")
-		return nil // Return nil to allow operation to continue without backup
-	}
-
-	log.Default().Println("Saving tracks backup started")
-
-	for _, trackPlaylist := range tracksPlaylist {
-		track := models.TrackDB{
-			Id:     trackPlaylist.Track.ID.String(),
-			Name:   trackPlaylist.Track.Name,
-			Artist: trackPlaylist.Track.Artists[0].Name,
-			Album:  trackPlaylist.Track.Album.Name,
-			URI:    string(trackPlaylist.Track.URI),
-			URL:    trackPlaylist.Track.ExternalURLs["spotify"],
-		}
-
-		log.Default().Printf("Created TrackDB: Name: %s, Artist: %s\n", track.Name, track.Artist)
-
-		var existingTrack models.TrackDB
-		alreadyExistTrack := postgres.Db.First(&existingTrack, "id = ?", track.Id)
-
-		if alreadyExistTrack != nil {
-			if !errors.Is(alreadyExistTrack.Error, gorm.ErrRecordNotFound) {
-				log.Printf("Error querying alreadyExistTrack: %v\n", alreadyExistTrack)
-				return alreadyExistTrack.Error
-			}
-
-			insertTrack := postgres.Db.Create(&track)
-
-			if insertTrack.Error != nil {
-				log.Printf("Error inserting track: %v - %v\n", track, insertTrack.Error)
-				return insertTrack.Error
-			}
-		}
-	}
-
-	return nil
-}
-
 // GetSpotifyService retrieves the SpotifyService from the gin context.
 // It returns nil if the SpotifyService is not found in the context.
 func GetSpotifyService(c *gin.Context) *SpotifyService.SpotifyService {
@@ -186,8 +125,24 @@ func GetSpotifyService(c *gin.Context) *SpotifyService.SpotifyService {
 }
 
 // LoadEnvVariables loads the environment variables from the .env file in the
-// current working directory.
+// current working directory and checks the ones the server needs.
 func LoadEnvVariables() {
+	LoadEnvFile()
+
+	// Verify critical environment variables are loaded
+	redirectURL := os.Getenv("REDIRECT_URL")
+	if redirectURL == "" {
+		redirectURL = os.Getenv("REDIRECT_URI")
+	}
+	if redirectURL == "" {
+		log.Fatal("REDIRECT_URL or REDIRECT_URI not found in environment variables after loading .env file")
+	}
+	log.Printf("OAuth Redirect URL configured: %s", redirectURL)
+}
+
+// LoadEnvFile loads the environment variables from the .env file in the current
+// working directory, if there is one, without checking them.
+func LoadEnvFile() {
 	// get current working directory
 	cwd, errCwd := os.Getwd()
 
@@ -212,14 +167,4 @@ func LoadEnvVariables() {
 	}
 
 	log.Println("Loaded environment variables from .env file or system")
-
-	// Verify critical environment variables are loaded
-	redirectURL := os.Getenv("REDIRECT_URL")
-	if redirectURL == "" {
-		redirectURL = os.Getenv("REDIRECT_URI")
-	}
-	if redirectURL == "" {
-		log.Fatal("REDIRECT_URL or REDIRECT_URI not found in environment variables after loading .env file")
-	}
-	log.Printf("OAuth Redirect URL configured: %s", redirectURL)
 }
@@ -0,0 +1,99 @@
+package snapshot
+
+import "sort"
+
+// TrackChange is a track added to or removed from an ordered list
+type TrackChange struct {
+	TrackID  string `json:"track_id"`
+	Position int    `json:"position"`
+}
+
+// TrackMove is a track present in both lists whose relative order changed
+type TrackMove struct {
+	TrackID string `json:"track_id"`
+	From    int    `json:"from"`
+	To      int    `json:"to"`
+}
+
+// TrackDiff describes how an ordered track list changed
+type TrackDiff struct {
+	Added   []TrackChange `json:"added"`
+	Removed []TrackChange `json:"removed"`
+	Moved   []TrackMove   `json:"moved"`
+}
+
+// DiffTracks compares two ordered track lists. The n-th occurrence of a track in from
+// is paired with its n-th occurrence in to; unpaired occurrences are removed or
+// added. Among paired tracks, the largest set that kept its relative order is
+// considered in place and the others moved, so a single insertion does not report
+// every following track as moved.
+func DiffTracks(from, to []string) TrackDiff {
+	diff := TrackDiff{Added: []TrackChange{}, Removed: []TrackChange{}, Moved: []TrackMove{}}
+
+	// 1. Pair the occurrences of each track
+	targets := make(map[string][]int)
+	for position, id := range to {
+		targets[id] = append(targets[id], position)
+	}
+
+	type pair struct{ from, to int }
+	var pairs []pair
+	paired := make([]bool, len(to))
+	for position, id := range from {
+		if len(targets[id]) == 0 {
+			diff.Removed = append(diff.Removed, TrackChange{TrackID: id, Position: position})
+			continue
+		}
+		pairs = append(pairs, pair{from: position, to: targets[id][0]})
+		paired[targets[id][0]] = true
+		targets[id] = targets[id][1:]
+	}
+	for position, id := range to {
+		if !paired[position] {
+			diff.Added = append(diff.Added, TrackChange{TrackID: id, Position: position})
+		}
+	}
+
+	// 2. Keep the longest run of pairs whose target positions increase
+	toPositions := make([]int, len(pairs))
+	for i, p := range pairs {
+		toPositions[i] = p.to
+	}
+	inPlace := longestIncreasing(toPositions)
+
+	for i, p := range pairs {
+		if !inPlace[i] {
+			diff.Moved = append(diff.Moved, TrackMove{TrackID: from[p.from], From: p.from, To: p.to})
+		}
+	}
+
+	return diff
+}
+
+// longestIncreasing marks the elements of a longest strictly increasing subsequence
+func longestIncreasing(values []int) []bool {
+	// tails[k] is the index of the smallest tail of an increasing subsequence of length k+1
+	tails := []int{}
+	previous := make([]int, len(values))
+	for i, value := range values {
+		k := sort.Search(len(tails), func(j int) bool { return values[tails[j]] >= value })
+		previous[i] = -1
+		if k > 0 {
+			previous[i] = tails[k-1]
+		}
+		if k == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[k] = i
+		}
+	}
+
+	marked := make([]bool, len(values))
+	if len(tails) == 0 {
+		return marked
+	}
+	for i := tails[len(tails)-1]; i >= 0; i = previous[i] {
+		marked[i] = true
+	}
+	return marked
+}
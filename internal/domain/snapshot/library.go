@@ -0,0 +1,66 @@
+package snapshot
+
+import (
+	"context"
+	"time"
+)
+
+// ReasonScheduled marks a snapshot taken by the periodic library snapshot
+const ReasonScheduled = "scheduled"
+
+// LibraryTrack is a saved track and the date it was saved
+type LibraryTrack struct {
+	TrackID string    `json:"track_id"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+// LibrarySnapshot is the set of tracks saved in the user library at a point in time
+type LibrarySnapshot struct {
+	ID    string
+	Owner string // user that took the snapshot (see shared.WithOwner)
+	// Tracks are in the order returned by Spotify, most recently saved first.
+	// Listings only fill TrackCount.
+	Tracks     []LibraryTrack
+	TrackCount int
+	Reason     string
+	CreatedAt  time.Time
+}
+
+// LibraryDiff lists the tracks saved and unsaved between two library snapshots
+type LibraryDiff struct {
+	Added   []LibraryTrack `json:"added"`
+	Removed []LibraryTrack `json:"removed"`
+}
+
+// DiffLibrary compares two library snapshots by track ID, whoever made the changes
+func DiffLibrary(from, to []LibraryTrack) LibraryDiff {
+	diff := LibraryDiff{Added: []LibraryTrack{}, Removed: []LibraryTrack{}}
+
+	inFrom := make(map[string]bool, len(from))
+	for _, track := range from {
+		inFrom[track.TrackID] = true
+	}
+	inTo := make(map[string]bool, len(to))
+	for _, track := range to {
+		inTo[track.TrackID] = true
+		if !inFrom[track.TrackID] {
+			diff.Added = append(diff.Added, track)
+		}
+	}
+	for _, track := range from {
+		if !inTo[track.TrackID] {
+			diff.Removed = append(diff.Removed, track)
+		}
+	}
+
+	return diff
+}
+
+// LibraryRepository persists library snapshots
+type LibraryRepository interface {
+	Create(ctx context.Context, snapshot *LibrarySnapshot) error
+	GetByID(ctx context.Context, id string) (*LibrarySnapshot, error)
+	// ListByOwner returns the most recent snapshots taken by an owner, newest first,
+	// without their tracks
+	ListByOwner(ctx context.Context, owner string, limit int) ([]LibrarySnapshot, error)
+}
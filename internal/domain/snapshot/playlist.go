@@ -0,0 +1,42 @@
+package snapshot
+
+import (
+	"context"
+	"time"
+)
+
+// Automatic snapshots use the kind of the mutation they precede as their reason
+// (e.g. "dedupe_playlist")
+const (
+	// ReasonManual marks a snapshot taken on request
+	ReasonManual = "manual"
+	// ReasonRestore marks the snapshot taken before restoring another one
+	ReasonRestore = "restore"
+)
+
+// PlaylistSnapshot is the state of a playlist at a point in time
+type PlaylistSnapshot struct {
+	ID    string
+	Owner string // user that took the snapshot (see shared.WithOwner)
+	// PlaylistID and SpotifySnapshotID identify the playlist version on Spotify
+	PlaylistID        string
+	SpotifySnapshotID string
+	Name              string
+	Description       string
+	ImageURL          string
+	// TrackIDs are the playlist items in order. Local files have no ID and are
+	// stored by URI. Listings only fill TrackCount.
+	TrackIDs   []string
+	TrackCount int
+	Reason     string
+	CreatedAt  time.Time
+}
+
+// PlaylistRepository persists playlist snapshots
+type PlaylistRepository interface {
+	Create(ctx context.Context, snapshot *PlaylistSnapshot) error
+	GetByID(ctx context.Context, id string) (*PlaylistSnapshot, error)
+	// ListByPlaylist returns the most recent snapshots of a playlist taken by an
+	// owner, newest first, without their tracks
+	ListByPlaylist(ctx context.Context, owner, playlistID string, limit int) ([]PlaylistSnapshot, error)
+}
@@ -0,0 +1,145 @@
+package track
+
+import (
+	"fmt"
+	"strings"
+
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+// ArtistMatch selects which artists of a track are considered when matching by artist
+type ArtistMatch string
+
+const (
+	// ArtistMatchPrimary only considers the first (primary) artist of a track
+	ArtistMatchPrimary ArtistMatch = "primary"
+	// ArtistMatchAny matches tracks where any of the artists appears, featured or not
+	ArtistMatchAny ArtistMatch = "any"
+	// ArtistMatchAll matches tracks where every one of the artists appears
+	ArtistMatchAll ArtistMatch = "all"
+)
+
+// ParseArtistMatch parses a match mode; an empty value means primary
+func ParseArtistMatch(value string) (ArtistMatch, error) {
+	switch ArtistMatch(value) {
+	case "", ArtistMatchPrimary:
+		return ArtistMatchPrimary, nil
+	case ArtistMatchAny, ArtistMatchAll:
+		return ArtistMatch(value), nil
+	default:
+		return "", fmt.Errorf("unknown artist match %q", value)
+	}
+}
+
+// CountsFeatured reports whether featured appearances count toward an artist
+func (m ArtistMatch) CountsFeatured() bool {
+	return m == ArtistMatchAny || m == ArtistMatchAll
+}
+
+// CountedArtists returns the artists of a track that count toward the match mode: the
+// primary artist alone, or every credited artist when featured appearances count
+func (m ArtistMatch) CountedArtists(artists []spotifyAPI.SimpleArtist) []spotifyAPI.SimpleArtist {
+	if len(artists) > 0 && !m.CountsFeatured() {
+		return artists[:1]
+	}
+	return artists
+}
+
+// CountArtistTracks returns the number of tracks counted for each artist under the
+// match mode. An artist credited twice on a track is counted once.
+func CountArtistTracks(tracks []spotifyAPI.SavedTrack, match ArtistMatch) map[spotifyAPI.ID]int {
+	counts := make(map[spotifyAPI.ID]int)
+	for _, track := range tracks {
+		seen := make(map[spotifyAPI.ID]bool, len(track.Artists))
+		for _, artist := range match.CountedArtists(track.Artists) {
+			if !seen[artist.ID] {
+				seen[artist.ID] = true
+				counts[artist.ID]++
+			}
+		}
+	}
+	return counts
+}
+
+// ArtistFilter selects tracks by artist ID
+type ArtistFilter struct {
+	IDs   []spotifyAPI.ID
+	Match ArtistMatch
+}
+
+// PrimaryArtist returns the filter matching the tracks whose primary artist is id
+func PrimaryArtist(id spotifyAPI.ID) ArtistFilter {
+	return ArtistFilter{IDs: []spotifyAPI.ID{id}, Match: ArtistMatchPrimary}
+}
+
+// ParseArtistFilter parses a comma-separated list of artist IDs and a match mode
+func ParseArtistFilter(ids, match string) (ArtistFilter, error) {
+	mode, err := ParseArtistMatch(match)
+	if err != nil {
+		return ArtistFilter{}, err
+	}
+
+	filter := ArtistFilter{Match: mode}
+	for _, id := range strings.Split(ids, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			filter.IDs = append(filter.IDs, spotifyAPI.ID(id))
+		}
+	}
+	if len(filter.IDs) == 0 {
+		return ArtistFilter{}, fmt.Errorf("at least one artist ID is required")
+	}
+
+	return filter, nil
+}
+
+// Matches reports whether a track with the given artists satisfies the filter.
+// With primary, the primary artist must be one of the IDs.
+func (f ArtistFilter) Matches(artists []spotifyAPI.SimpleArtist) bool {
+	if len(artists) == 0 {
+		return false
+	}
+
+	switch f.Match {
+	case ArtistMatchAny:
+		for _, artist := range artists {
+			if f.contains(artist.ID) {
+				return true
+			}
+		}
+		return false
+	case ArtistMatchAll:
+		for _, id := range f.IDs {
+			found := false
+			for _, artist := range artists {
+				if artist.ID == id {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	default:
+		return f.contains(artists[0].ID)
+	}
+}
+
+// String returns the comma-separated artist IDs
+func (f ArtistFilter) String() string {
+	ids := make([]string, len(f.IDs))
+	for i, id := range f.IDs {
+		ids[i] = id.String()
+	}
+	return strings.Join(ids, ",")
+}
+
+func (f ArtistFilter) contains(id spotifyAPI.ID) bool {
+	for _, candidate := range f.IDs {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
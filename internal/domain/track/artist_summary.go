@@ -2,8 +2,13 @@ package track
 
 // ArtistSummary represents a summary of tracks by artist
 type ArtistSummary struct {
-	ID       string
-	Name     string
-	Count    int
-	ImageURL string
+	ID    string
+	Name  string
+	Count int // tracks counted for the artist under the match mode of the summary
+	// PrimaryCount and FeaturedCount count the tracks where the artist is first or secondary
+	PrimaryCount  int
+	FeaturedCount int
+	ImageURL      string
+	// LastAddedAt is when the most recently saved track counted for the artist was saved
+	LastAddedAt string
 }
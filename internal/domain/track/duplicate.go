@@ -0,0 +1,288 @@
+package track
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+// Duplicate group match reasons
+const (
+	// MatchedByISRC groups versions sharing the same recording code
+	MatchedByISRC = "isrc"
+	// MatchedByMetadata groups versions with the same normalized name and artists
+	// and durations within the tolerance
+	MatchedByMetadata = "metadata"
+)
+
+// Preference is a rule used to choose the version to keep among duplicates
+type Preference string
+
+const (
+	// PreferOriginalAlbum prefers studio albums, then singles, then compilations
+	PreferOriginalAlbum Preference = "original_album"
+	// PreferExplicit prefers explicit versions
+	PreferExplicit Preference = "explicit"
+	// PreferClean prefers non-explicit versions
+	PreferClean Preference = "clean"
+	// PreferPopular prefers the most popular version
+	PreferPopular Preference = "popularity"
+	// PreferOldest prefers the version saved first
+	PreferOldest Preference = "oldest"
+	// PreferNewest prefers the version saved last
+	PreferNewest Preference = "newest"
+)
+
+// DefaultPreferences are applied when no preference is given
+var DefaultPreferences = []Preference{PreferOriginalAlbum, PreferPopular, PreferOldest}
+
+// ParsePreferences parses a comma-separated list of preferences, in priority order.
+// An empty value returns DefaultPreferences.
+func ParsePreferences(value string) ([]Preference, error) {
+	var preferences []Preference
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		switch preference := Preference(name); preference {
+		case PreferOriginalAlbum, PreferExplicit, PreferClean, PreferPopular, PreferOldest, PreferNewest:
+			preferences = append(preferences, preference)
+		default:
+			return nil, fmt.Errorf("unknown preference %q", name)
+		}
+	}
+
+	if len(preferences) == 0 {
+		return DefaultPreferences, nil
+	}
+	return preferences, nil
+}
+
+// DuplicateOptions configures duplicate detection
+type DuplicateOptions struct {
+	// DurationToleranceMs is the largest duration difference between versions matched by metadata
+	DurationToleranceMs int
+	Preferences         []Preference
+}
+
+// DuplicateGroup is a set of saved tracks that are versions of the same song
+type DuplicateGroup struct {
+	Key       string
+	MatchedBy string
+	Keep      spotifyAPI.SavedTrack
+	Remove    []spotifyAPI.SavedTrack
+}
+
+// FindDuplicates groups the saved tracks that share an ISRC, or whose normalized name
+// and artists match with durations within the tolerance. Each group keeps the version
+// ranked first by the preferences. Groups are sorted by key.
+func FindDuplicates(tracks []spotifyAPI.SavedTrack, options DuplicateOptions) []DuplicateGroup {
+	preferences := options.Preferences
+	if len(preferences) == 0 {
+		preferences = DefaultPreferences
+	}
+
+	// Ignore tracks without an ID (local files) and tracks saved twice
+	var candidates []spotifyAPI.SavedTrack
+	seen := make(map[spotifyAPI.ID]bool)
+	for _, track := range tracks {
+		if track.ID == "" || seen[track.ID] {
+			continue
+		}
+		seen[track.ID] = true
+		candidates = append(candidates, track)
+	}
+
+	groups := newUnionFind(len(candidates))
+
+	// 1. Same ISRC
+	byISRC := make(map[string]int)
+	for i, track := range candidates {
+		isrc := strings.ToUpper(track.ExternalIDs["isrc"])
+		if isrc == "" {
+			continue
+		}
+		if first, exists := byISRC[isrc]; exists {
+			groups.union(first, i)
+		} else {
+			byISRC[isrc] = i
+		}
+	}
+
+	// 2. Same normalized name and artists, with durations chained within the tolerance
+	byMetadata := make(map[string][]int)
+	for i, track := range candidates {
+		key := metadataKey(track.FullTrack)
+		byMetadata[key] = append(byMetadata[key], i)
+	}
+	for _, indexes := range byMetadata {
+		sort.Slice(indexes, func(a, b int) bool {
+			return candidates[indexes[a]].Duration < candidates[indexes[b]].Duration
+		})
+		for j := 1; j < len(indexes); j++ {
+			if candidates[indexes[j]].Duration-candidates[indexes[j-1]].Duration <= options.DurationToleranceMs {
+				groups.union(indexes[j-1], indexes[j])
+			}
+		}
+	}
+
+	// 3. Build the groups and choose the version to keep
+	members := make(map[int][]spotifyAPI.SavedTrack)
+	for i, track := range candidates {
+		root := groups.find(i)
+		members[root] = append(members[root], track)
+	}
+
+	var result []DuplicateGroup
+	for _, versions := range members {
+		if len(versions) < 2 {
+			continue
+		}
+
+		sort.SliceStable(versions, func(a, b int) bool {
+			return prefer(versions[a], versions[b], preferences)
+		})
+
+		group := DuplicateGroup{
+			Key:       metadataKey(versions[0].FullTrack),
+			MatchedBy: MatchedByMetadata,
+			Keep:      versions[0],
+			Remove:    versions[1:],
+		}
+		if isrc := sharedISRC(versions); isrc != "" {
+			group.Key = strings.ToLower(isrc)
+			group.MatchedBy = MatchedByISRC
+		}
+		result = append(result, group)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Key < result[j].Key
+	})
+
+	return result
+}
+
+// prefer reports whether a should be kept over b
+func prefer(a, b spotifyAPI.SavedTrack, preferences []Preference) bool {
+	for _, preference := range preferences {
+		var rankA, rankB int
+		switch preference {
+		case PreferOriginalAlbum:
+			rankA, rankB = albumRank(a.Album.AlbumType), albumRank(b.Album.AlbumType)
+		case PreferExplicit:
+			rankA, rankB = boolRank(!a.Explicit), boolRank(!b.Explicit)
+		case PreferClean:
+			rankA, rankB = boolRank(a.Explicit), boolRank(b.Explicit)
+		case PreferPopular:
+			rankA, rankB = -a.Popularity, -b.Popularity
+		case PreferOldest, PreferNewest:
+			// AddedAt is an RFC 3339 timestamp, so it sorts as a string
+			if a.AddedAt != b.AddedAt {
+				return (a.AddedAt < b.AddedAt) == (preference == PreferOldest)
+			}
+		}
+		if rankA != rankB {
+			return rankA < rankB
+		}
+	}
+
+	// Keep the result deterministic
+	return a.ID < b.ID
+}
+
+func albumRank(albumType string) int {
+	switch albumType {
+	case "album":
+		return 0
+	case "single":
+		return 1
+	case "compilation":
+		return 3
+	default:
+		return 2
+	}
+}
+
+func boolRank(value bool) int {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// sharedISRC returns the ISRC every version has in common, if any
+func sharedISRC(versions []spotifyAPI.SavedTrack) string {
+	isrc := strings.ToUpper(versions[0].ExternalIDs["isrc"])
+	for _, version := range versions[1:] {
+		if strings.ToUpper(version.ExternalIDs["isrc"]) != isrc {
+			return ""
+		}
+	}
+	return isrc
+}
+
+var (
+	// versionSuffix matches "(...)", "[...]" and " - ..." parts of a track name
+	versionSuffix = regexp.MustCompile(`\s*(\([^)]*\)|\[[^\]]*\]|\s-\s.*$)`)
+	// versionMarker identifies the parts that only describe a release of the same recording
+	versionMarker = regexp.MustCompile(`(?i)remaster|deluxe|mono|stereo|single version|album version|radio edit|explicit|clean`)
+	nonAlnum      = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+)
+
+// NormalizeName lowercases a track name and drops the parts describing a release,
+// such as "(Remastered 2011)" or " - Single Version"
+func NormalizeName(name string) string {
+	name = versionSuffix.ReplaceAllStringFunc(name, func(part string) string {
+		if versionMarker.MatchString(part) {
+			return ""
+		}
+		return part
+	})
+	return strings.TrimSpace(nonAlnum.ReplaceAllString(strings.ToLower(name), " "))
+}
+
+// metadataKey identifies a song by normalized name and artists
+func metadataKey(track spotifyAPI.FullTrack) string {
+	artists := make([]string, 0, len(track.Artists))
+	for _, artist := range track.Artists {
+		if artist.ID != "" {
+			artists = append(artists, artist.ID.String())
+		} else {
+			artists = append(artists, strings.ToLower(artist.Name))
+		}
+	}
+	sort.Strings(artists)
+
+	return NormalizeName(track.Name) + "|" + strings.Join(artists, ",")
+}
+
+// unionFind tracks the groups formed by pairwise matches
+type unionFind []int
+
+func newUnionFind(size int) unionFind {
+	parent := make(unionFind, size)
+	for i := range parent {
+		parent[i] = i
+	}
+	return parent
+}
+
+func (u unionFind) find(i int) int {
+	for u[i] != i {
+		u[i] = u[u[i]]
+		i = u[i]
+	}
+	return i
+}
+
+func (u unionFind) union(a, b int) {
+	if rootA, rootB := u.find(a), u.find(b); rootA != rootB {
+		u[rootB] = rootA
+	}
+}
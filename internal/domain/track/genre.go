@@ -0,0 +1,61 @@
+package track
+
+import (
+	"strings"
+
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+// GenreSummary represents a summary of saved tracks by artist genre
+type GenreSummary struct {
+	Genre string
+	Count int // tracks with the genre under the match mode of the summary
+	// ArtistCount is the number of distinct artists the tracks of the genre are counted for
+	ArtistCount int
+	// TopArtists are the artists with the most tracks in the genre, Count being those tracks
+	TopArtists []ArtistSummary
+}
+
+// NormalizeGenre returns the form genres are compared in
+func NormalizeGenre(genre string) string {
+	return strings.ToLower(strings.TrimSpace(genre))
+}
+
+// GenreArtists returns, for each genre of a track, the artists of the track counted for it.
+// With primary only the genres of the first artist are considered, with any those of every
+// artist and with all only the genres every artist shares. genres holds the genres of each
+// artist; artists without genres are unknown to the catalog.
+func GenreArtists(artists []spotifyAPI.SimpleArtist, genres map[spotifyAPI.ID][]string, match ArtistMatch) map[string][]spotifyAPI.SimpleArtist {
+	result := make(map[string][]spotifyAPI.SimpleArtist)
+	if len(artists) == 0 {
+		return result
+	}
+
+	counted := match.CountedArtists(artists)
+	seen := make(map[spotifyAPI.ID]bool, len(counted))
+	for _, artist := range counted {
+		// An artist credited twice on a track is counted once
+		if seen[artist.ID] {
+			continue
+		}
+		seen[artist.ID] = true
+
+		artistGenres := make(map[string]bool)
+		for _, genre := range genres[artist.ID] {
+			if genre = NormalizeGenre(genre); genre != "" && !artistGenres[genre] {
+				artistGenres[genre] = true
+				result[genre] = append(result[genre], artist)
+			}
+		}
+	}
+
+	if match == ArtistMatchAll {
+		for genre, genreArtists := range result {
+			if len(genreArtists) < len(seen) {
+				delete(result, genre)
+			}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,96 @@
+package track
+
+import (
+	"sort"
+	"strings"
+)
+
+// SummarySort selects the field the artists of a summary are ordered by
+type SummarySort string
+
+const (
+	// SummarySortCount orders artists by counted tracks, most first by default
+	SummarySortCount SummarySort = "count"
+	// SummarySortName orders artists alphabetically, case-insensitively
+	SummarySortName SummarySort = "name"
+	// SummarySortLastAdded orders artists by their most recently saved track, newest first by default
+	SummarySortLastAdded SummarySort = "last_added"
+)
+
+// SummaryQuery selects a page of the artist summary
+type SummaryQuery struct {
+	Min, Max int
+	Match    ArtistMatch
+	// Search keeps the artists whose name contains it, case-insensitively
+	Search string
+	Sort   SummarySort
+	// Descending reverses the order of the sort field
+	Descending bool
+	// Limit of 0 or less selects every artist from Offset on
+	Limit  int
+	Offset int
+}
+
+// SummaryPage is a page of the artist summary
+type SummaryPage struct {
+	Artists []ArtistSummary
+	// Total is the number of artists matching the query across all pages
+	Total  int
+	Limit  int
+	Offset int
+}
+
+// DefaultDescending reports the natural direction of a sort field: counts and dates
+// list the largest first, names list A first
+func (s SummarySort) DefaultDescending() bool {
+	return s != SummarySortName
+}
+
+// Filter keeps the artists within the count range of the query whose name matches its search
+func (q SummaryQuery) Filter(summary []ArtistSummary) []ArtistSummary {
+	search := strings.ToLower(strings.TrimSpace(q.Search))
+
+	filtered := make([]ArtistSummary, 0, len(summary))
+	for _, artist := range summary {
+		if q.Min > 0 && artist.Count < q.Min {
+			continue
+		}
+		if q.Max > 0 && artist.Count > q.Max {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(artist.Name), search) {
+			continue
+		}
+		filtered = append(filtered, artist)
+	}
+	return filtered
+}
+
+// SortSummary orders the artists in place by the sort field of the query. Ties are
+// broken by name and ID so that pages are stable.
+func (q SummaryQuery) SortSummary(summary []ArtistSummary) {
+	sort.SliceStable(summary, func(i, j int) bool {
+		a, b := summary[i], summary[j]
+
+		var cmp int
+		switch q.Sort {
+		case SummarySortName:
+			cmp = strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+		case SummarySortLastAdded:
+			cmp = strings.Compare(a.LastAddedAt, b.LastAddedAt)
+		default:
+			cmp = a.Count - b.Count
+		}
+		if cmp != 0 {
+			if q.Descending {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+
+		if a.Name != b.Name {
+			return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+		}
+		return a.ID < b.ID
+	})
+}
@@ -4,27 +4,41 @@ import (
 	"errors"
 	"log"
 	"os"
+	"strconv"
+	"time"
 
+	"github.com/RubenPari/clear-songs/internal/application/album"
+	"github.com/RubenPari/clear-songs/internal/application/artistinfo"
 	"github.com/RubenPari/clear-songs/internal/application/auth"
+	"github.com/RubenPari/clear-songs/internal/application/backup"
+	appJob "github.com/RubenPari/clear-songs/internal/application/job"
+	"github.com/RubenPari/clear-songs/internal/application/library"
+	appOperation "github.com/RubenPari/clear-songs/internal/application/operation"
 	"github.com/RubenPari/clear-songs/internal/application/playlist"
+	appRule "github.com/RubenPari/clear-songs/internal/application/rule"
+	appSnapshot "github.com/RubenPari/clear-songs/internal/application/snapshot"
 	"github.com/RubenPari/clear-songs/internal/application/track"
+	"github.com/RubenPari/clear-songs/internal/application/usertracks"
 	domainAuth "github.com/RubenPari/clear-songs/internal/domain/auth"
+	domainRule "github.com/RubenPari/clear-songs/internal/domain/rule"
 	"github.com/RubenPari/clear-songs/internal/domain/shared"
 	"github.com/RubenPari/clear-songs/internal/domain/shared/constants"
 	"github.com/RubenPari/clear-songs/internal/infrastructure/external/email"
+	"github.com/RubenPari/clear-songs/internal/infrastructure/external/notifier"
 	"github.com/RubenPari/clear-songs/internal/infrastructure/external/spotify"
 	"github.com/RubenPari/clear-songs/internal/infrastructure/persistence/postgres"
 	"github.com/RubenPari/clear-songs/internal/infrastructure/persistence/redis"
-	spotifyAPI "github.com/zmb3/spotify"
+	"github.com/RubenPari/clear-songs/internal/infrastructure/scheduler"
 	"golang.org/x/oauth2"
 )
 
 // Container holds all application dependencies
 type Container struct {
 	// Repositories (as interfaces)
-	SpotifyRepo  shared.SpotifyRepository
-	CacheRepo    shared.CacheRepository
-	DatabaseRepo shared.DatabaseRepository
+	SpotifyRepo    shared.SpotifyRepository
+	SpotifyFactory shared.SpotifyRepositoryFactory
+	CacheRepo      shared.CacheRepository
+	DatabaseRepo   shared.DatabaseRepository
 
 	// OAuth Config
 	OAuthConfig *oauth2.Config
@@ -33,7 +47,9 @@ type Container struct {
 	AuthService auth.AuthService
 	UserRepo    domainAuth.UserRepository
 	TokenRepo   domainAuth.TokenRepository
-	EmailSvc    domainAuth.EmailService
+	// CredentialRepo keeps the Spotify refresh token of each user for scheduled runs
+	CredentialRepo domainAuth.CredentialRepository
+	EmailSvc       domainAuth.EmailService
 
 	LoginUC    *auth.LoginUseCase
 	CallbackUC *auth.CallbackUseCase
@@ -45,12 +61,59 @@ type Container struct {
 	DeleteTracksByArtistUC *track.DeleteTracksByArtistUseCase
 	DeleteTracksByRangeUC  *track.DeleteTracksByRangeUseCase
 	DeleteTrackUC          *track.DeleteTrackUseCase
+	DeleteTracksUC         *track.DeleteTracksUseCase
 	GetTracksByArtistUC    *track.GetTracksByArtistUseCase
+	GetGenreSummaryUC      *track.GetGenreSummaryUseCase
+	DeleteTracksByGenreUC  *track.DeleteTracksByGenreUseCase
 
 	// Playlist Use Cases
 	GetUserPlaylistsUC         *playlist.GetUserPlaylistsUseCase
 	DeletePlaylistTracksUC     *playlist.DeletePlaylistTracksUseCase
 	DeletePlaylistAndLibraryUC *playlist.DeletePlaylistAndLibraryTracksUseCase
+	DedupePlaylistUC           *playlist.DedupePlaylistUseCase
+
+	// Playlist Snapshot Use Cases
+	CapturePlaylistUC         *appSnapshot.CapturePlaylistUseCase
+	ListPlaylistSnapshotsUC   *appSnapshot.ListPlaylistSnapshotsUseCase
+	DiffPlaylistSnapshotsUC   *appSnapshot.DiffPlaylistSnapshotsUseCase
+	RestorePlaylistSnapshotUC *appSnapshot.RestorePlaylistSnapshotUseCase
+
+	// Library Snapshot Use Cases
+	CaptureLibraryUC       *appSnapshot.CaptureLibraryUseCase
+	ListLibrarySnapshotsUC *appSnapshot.ListLibrarySnapshotsUseCase
+	DiffLibrarySnapshotsUC *appSnapshot.DiffLibrarySnapshotsUseCase
+	RestoreLibraryDiffUC   *appSnapshot.RestoreLibraryDiffUseCase
+
+	// Album Use Cases
+	GetUserAlbumsUC *album.GetUserAlbumsUseCase
+	ConvertAlbumUC  *album.ConvertAlbumUseCase
+
+	// Library Use Cases
+	ExportLibraryUC   *library.ExportLibraryUseCase
+	ImportLibraryUC   *library.ImportLibraryUseCase
+	FindDuplicatesUC  *library.FindDuplicatesUseCase
+	MergeDuplicatesUC *library.MergeDuplicatesUseCase
+
+	// Backup Use Cases
+	GetBackupTracksUC    *backup.GetBackupTracksUseCase
+	RestoreTracksUC      *backup.RestoreTracksUseCase
+	ClaimLegacyBackupsUC *backup.ClaimLegacyBackupsUseCase
+
+	// Operation Use Cases
+	ListOperationsUC *appOperation.ListOperationsUseCase
+	UndoOperationUC  *appOperation.UndoOperationUseCase
+
+	// Cleanup Rule Use Cases
+	ListRulesUC  *appRule.ListRulesUseCase
+	CreateRuleUC *appRule.CreateRuleUseCase
+	UpdateRuleUC *appRule.UpdateRuleUseCase
+	DeleteRuleUC *appRule.DeleteRuleUseCase
+	RunRuleUC    *appRule.RunRuleUseCase
+
+	// Background Jobs
+	JobManager               *appJob.Manager
+	RuleScheduler            *scheduler.RuleScheduler
+	LibrarySnapshotScheduler *scheduler.LibrarySnapshotScheduler
 }
 
 // NewContainer creates and initializes a new dependency injection container
@@ -67,7 +130,12 @@ func NewContainer() (*Container, error) {
 		log.Fatal("Missing required environment variables: CLIENT_ID, CLIENT_SECRET, REDIRECT_URL")
 	}
 
-	spotifyRepo := spotify.NewSpotifyRepository(clientID, clientSecret, redirectURI, constants.Scopes)
+	spotify.WarnBaseURLOverride()
+
+	// Each session gets its own client built by the factory; use cases go through the
+	// session repository, which resolves that client from the request context
+	spotifyFactory := spotify.NewRepositoryFactory(clientID, clientSecret, redirectURI, constants.Scopes)
+	var spotifyRepo shared.SpotifyRepository = spotify.NewSessionSpotifyRepository()
 
 	// Initialize OAuth config
 	oauthConfig, err := GetOAuth2Config()
@@ -75,10 +143,17 @@ func NewContainer() (*Container, error) {
 		return nil, err
 	}
 
-	// Initialize cache repository (may fail if Redis is not available)
+	// Initialize cache repository (may fail if Redis is not available).
+	// CACHE_DRIVER=memory keeps it in process, for a single instance without Redis.
 	var cacheRepo shared.CacheRepository
-	redisCache, err := redis.NewRedisCacheRepository()
-	if err != nil {
+	cacheDriver := os.Getenv("CACHE_DRIVER")
+	if cacheDriver != "" && cacheDriver != "redis" && cacheDriver != "memory" {
+		log.Printf("WARNING: Unknown CACHE_DRIVER %q, using redis", cacheDriver)
+	}
+	if cacheDriver == "memory" {
+		log.Println("WARNING: CACHE_DRIVER=memory: Spotify sessions and cached data live in this process only, are lost on restart and are not shared with other instances")
+		cacheRepo = redis.NewMemoryCacheRepository()
+	} else if redisCache, err := redis.NewRedisCacheRepository(); err != nil {
 		log.Printf("WARNING: Cache repository initialization failed: %v", err)
 		log.Println("WARNING: Application will continue without Redis caching")
 		// Use no-op cache implementation
@@ -90,41 +165,169 @@ func NewContainer() (*Container, error) {
 	// Initialize database repository (may be nil if database not available)
 	databaseRepo := postgres.NewPostgresRepository(postgres.Db)
 
-	userRepo := postgres.NewUserRepository()
-	tokenRepo := postgres.NewTokenRepository()
+	// Destructive use cases back up tracks before removing them, following BACKUP_POLICY
+	backupPolicy, err := backup.ParsePolicy(os.Getenv("BACKUP_POLICY"))
+	if err != nil {
+		return nil, err
+	}
+	if backupPolicy == backup.PolicyRequired && postgres.Db == nil {
+		log.Println("WARNING: BACKUP_POLICY=required without a database: destructive operations will fail")
+	}
+	backupWriter := backup.NewWriter(databaseRepo, backupPolicy)
+
+	// Saved tracks are served from a local mirror of the library, synced incrementally.
+	// Tracks unsaved through the wrapped repository are removed from the mirror.
+	fullSyncInterval, _ := time.ParseDuration(os.Getenv("LIBRARY_FULL_SYNC_INTERVAL"))
+	userTracks := usertracks.NewProvider(
+		spotifyRepo,
+		cacheRepo,
+		postgres.NewLibraryMirrorRepository(postgres.Db),
+		fullSyncInterval,
+	)
+	spotifyRepo = userTracks.Wrap(spotifyRepo)
+
+	// Artist metadata is looked up in batches and cached for all sessions
+	artists := artistinfo.NewProvider(spotifyRepo, cacheRepo)
+
+	// Destructive operations are recorded so they can be undone
+	operationRepo := postgres.NewOperationRepository(postgres.Db)
+	operationRecorder := appOperation.NewRecorder(operationRepo)
+
+	// Playlists are snapshotted before every mutation so they can be restored
+	playlistSnapshotRepo := postgres.NewPlaylistSnapshotRepository(postgres.Db)
+	capturePlaylistUC := appSnapshot.NewCapturePlaylistUseCase(spotifyRepo, playlistSnapshotRepo)
+
+	userRepo := postgres.NewUserRepository(postgres.Db)
+	tokenRepo := postgres.NewTokenRepository(postgres.Db)
+	credentialRepo := postgres.NewCredentialRepository(postgres.Db)
 	emailSvc := email.NewMailtrapEmailService()
 	authService := auth.NewAuthService(userRepo, tokenRepo, emailSvc)
 
 	// Initialize auth use cases
 	loginUC := auth.NewLoginUseCase(oauthConfig)
-	callbackUC := auth.NewCallbackUseCase(oauthConfig, spotifyRepo, cacheRepo, userRepo)
-	logoutUC := auth.NewLogoutUseCase(spotifyRepo, cacheRepo)
+	callbackUC := auth.NewCallbackUseCase(oauthConfig, spotifyFactory, cacheRepo, userRepo, credentialRepo)
+	logoutUC := auth.NewLogoutUseCase(cacheRepo)
 	isAuthUC := auth.NewIsAuthUseCase(spotifyRepo)
 
 	// Initialize track use cases
-	getTrackSummaryUseCase := track.NewGetTrackSummaryUseCase(spotifyRepo, cacheRepo)
-	deleteTracksByArtistUC := track.NewDeleteTracksByArtistUseCase(spotifyRepo, cacheRepo)
-	getTracksByArtistUC := track.NewGetTracksByArtistUseCase(spotifyRepo, cacheRepo)
-	deleteTrackUC := track.NewDeleteTrackUseCase(spotifyRepo, cacheRepo, databaseRepo)
+	getTrackSummaryUseCase := track.NewGetTrackSummaryUseCase(spotifyRepo, cacheRepo, userTracks, artists)
+	deleteTracksByArtistUC := track.NewDeleteTracksByArtistUseCase(spotifyRepo, cacheRepo, userTracks, backupWriter, operationRecorder)
+	getTracksByArtistUC := track.NewGetTracksByArtistUseCase(spotifyRepo, cacheRepo, userTracks)
+	deleteTrackUC := track.NewDeleteTrackUseCase(spotifyRepo, cacheRepo, backupWriter, operationRecorder)
+	deleteTracksUC := track.NewDeleteTracksUseCase(spotifyRepo, cacheRepo, backupWriter, operationRecorder)
 	deleteTracksByRangeUC := track.NewDeleteTracksByRangeUseCase(
 		spotifyRepo,
 		cacheRepo,
 		getTrackSummaryUseCase,
 		deleteTracksByArtistUC,
+		operationRecorder,
 	)
+	getGenreSummaryUC := track.NewGetGenreSummaryUseCase(cacheRepo, userTracks, artists)
+	deleteTracksByGenreUC := track.NewDeleteTracksByGenreUseCase(getGenreSummaryUC, deleteTracksUC)
 
 	// Initialize playlist use cases
 	getUserPlaylistsUC := playlist.NewGetUserPlaylistsUseCase(spotifyRepo, cacheRepo)
-	deletePlaylistTracksUC := playlist.NewDeletePlaylistTracksUseCase(spotifyRepo, cacheRepo)
+	deletePlaylistTracksUC := playlist.NewDeletePlaylistTracksUseCase(spotifyRepo, cacheRepo, backupWriter, operationRecorder, capturePlaylistUC)
 	deletePlaylistAndLibraryUC := playlist.NewDeletePlaylistAndLibraryTracksUseCase(
 		spotifyRepo,
 		cacheRepo,
-		databaseRepo,
 		deletePlaylistTracksUC,
+		operationRecorder,
+	)
+	dedupePlaylistUC := playlist.NewDedupePlaylistUseCase(spotifyRepo, cacheRepo, backupWriter, operationRecorder, capturePlaylistUC)
+
+	// Initialize playlist snapshot use cases
+	listPlaylistSnapshotsUC := appSnapshot.NewListPlaylistSnapshotsUseCase(playlistSnapshotRepo)
+	diffPlaylistSnapshotsUC := appSnapshot.NewDiffPlaylistSnapshotsUseCase(playlistSnapshotRepo, capturePlaylistUC)
+	restorePlaylistSnapshotUC := appSnapshot.NewRestorePlaylistSnapshotUseCase(
+		spotifyRepo,
+		cacheRepo,
+		playlistSnapshotRepo,
+		capturePlaylistUC,
+	)
+
+	// Initialize library snapshot use cases
+	librarySnapshotRepo := postgres.NewLibrarySnapshotRepository(postgres.Db)
+	captureLibraryUC := appSnapshot.NewCaptureLibraryUseCase(spotifyRepo, librarySnapshotRepo)
+	listLibrarySnapshotsUC := appSnapshot.NewListLibrarySnapshotsUseCase(librarySnapshotRepo)
+	diffLibrarySnapshotsUC := appSnapshot.NewDiffLibrarySnapshotsUseCase(librarySnapshotRepo, captureLibraryUC)
+	restoreLibraryDiffUC := appSnapshot.NewRestoreLibraryDiffUseCase(spotifyRepo, cacheRepo, diffLibrarySnapshotsUC)
+
+	// Initialize album use cases
+	getUserAlbumsUC := album.NewGetUserAlbumsUseCase(spotifyRepo)
+	convertAlbumUC := album.NewConvertAlbumUseCase(spotifyRepo, cacheRepo, operationRecorder)
+
+	// Initialize library use cases
+	exportLibraryUC := library.NewExportLibraryUseCase(spotifyRepo)
+	importLibraryUC := library.NewImportLibraryUseCase(spotifyRepo, cacheRepo)
+	findDuplicatesUC := library.NewFindDuplicatesUseCase(spotifyRepo, cacheRepo, userTracks)
+	mergeDuplicatesUC := library.NewMergeDuplicatesUseCase(
+		spotifyRepo,
+		cacheRepo,
+		backupWriter,
+		findDuplicatesUC,
+		operationRecorder,
+	)
+
+	// Initialize operation use cases
+	listOperationsUC := appOperation.NewListOperationsUseCase(operationRepo)
+	undoOperationUC := appOperation.NewUndoOperationUseCase(spotifyRepo, cacheRepo, operationRepo)
+
+	// Initialize backup use cases
+	getBackupTracksUC := backup.NewGetBackupTracksUseCase(databaseRepo)
+	restoreTracksUC := backup.NewRestoreTracksUseCase(spotifyRepo, cacheRepo, databaseRepo)
+	claimLegacyBackupsUC := backup.NewClaimLegacyBackupsUseCase(
+		databaseRepo,
+		credentialRepo,
+		backup.ParseAdmins(os.Getenv("BACKUP_ADMINS")),
+	)
+
+	// Initialize background jobs (kept in memory when the database is not available)
+	jobWorkers := 2
+	if value, err := strconv.Atoi(os.Getenv("JOB_WORKERS")); err == nil && value > 0 {
+		jobWorkers = value
+	}
+	jobManager := appJob.NewManager(postgres.NewJobRepository(postgres.Db), jobWorkers)
+
+	// Initialize cleanup rules and their scheduler
+	ruleRepo := postgres.NewRuleRepository(postgres.Db)
+	listRulesUC := appRule.NewListRulesUseCase(ruleRepo)
+	// Webhooks only reach public addresses, and the networks of WEBHOOK_ALLOWED_NETWORKS
+	webhookNetworks, err := notifier.ParseAllowedNetworks(os.Getenv("WEBHOOK_ALLOWED_NETWORKS"))
+	if err != nil {
+		return nil, err
+	}
+	createRuleUC := appRule.NewCreateRuleUseCase(ruleRepo)
+	updateRuleUC := appRule.NewUpdateRuleUseCase(ruleRepo)
+	deleteRuleUC := appRule.NewDeleteRuleUseCase(ruleRepo)
+	runRuleUC := appRule.NewRunRuleUseCase(
+		spotifyRepo,
+		cacheRepo,
+		userTracks,
+		ruleRepo,
+		deleteTracksUC,
+		[]domainRule.Notifier{notifier.NewLogNotifier(), notifier.NewWebhookNotifier(webhookNetworks)},
+	)
+	ruleScheduler := scheduler.NewRuleScheduler(ruleRepo, runRuleUC, jobManager, spotifyFactory, credentialRepo)
+
+	// Sessions with library history get a snapshot on LIBRARY_SNAPSHOT_SCHEDULE ("off" disables)
+	librarySnapshotSchedule := os.Getenv("LIBRARY_SNAPSHOT_SCHEDULE")
+	if librarySnapshotSchedule == "" {
+		librarySnapshotSchedule = "@daily"
+	} else if librarySnapshotSchedule == "off" {
+		librarySnapshotSchedule = ""
+	}
+	librarySnapshotScheduler := scheduler.NewLibrarySnapshotScheduler(
+		librarySnapshotSchedule,
+		captureLibraryUC,
+		jobManager,
+		spotifyFactory,
+		credentialRepo,
 	)
 
 	container := &Container{
 		SpotifyRepo:                spotifyRepo,
+		SpotifyFactory:             spotifyFactory,
 		CacheRepo:                  cacheRepo,
 		DatabaseRepo:               databaseRepo,
 		OAuthConfig:                oauthConfig,
@@ -135,15 +338,47 @@ func NewContainer() (*Container, error) {
 		AuthService:                authService,
 		UserRepo:                   userRepo,
 		TokenRepo:                  tokenRepo,
+		CredentialRepo:             credentialRepo,
 		EmailSvc:                   emailSvc,
 		GetTrackSummaryUseCase:     getTrackSummaryUseCase,
 		DeleteTracksByArtistUC:     deleteTracksByArtistUC,
 		DeleteTracksByRangeUC:      deleteTracksByRangeUC,
 		DeleteTrackUC:              deleteTrackUC,
+		DeleteTracksUC:             deleteTracksUC,
 		GetTracksByArtistUC:        getTracksByArtistUC,
+		GetGenreSummaryUC:          getGenreSummaryUC,
+		DeleteTracksByGenreUC:      deleteTracksByGenreUC,
 		GetUserPlaylistsUC:         getUserPlaylistsUC,
 		DeletePlaylistTracksUC:     deletePlaylistTracksUC,
 		DeletePlaylistAndLibraryUC: deletePlaylistAndLibraryUC,
+		DedupePlaylistUC:           dedupePlaylistUC,
+		CapturePlaylistUC:          capturePlaylistUC,
+		ListPlaylistSnapshotsUC:    listPlaylistSnapshotsUC,
+		DiffPlaylistSnapshotsUC:    diffPlaylistSnapshotsUC,
+		RestorePlaylistSnapshotUC:  restorePlaylistSnapshotUC,
+		CaptureLibraryUC:           captureLibraryUC,
+		ListLibrarySnapshotsUC:     listLibrarySnapshotsUC,
+		DiffLibrarySnapshotsUC:     diffLibrarySnapshotsUC,
+		RestoreLibraryDiffUC:       restoreLibraryDiffUC,
+		GetUserAlbumsUC:            getUserAlbumsUC,
+		ConvertAlbumUC:             convertAlbumUC,
+		ExportLibraryUC:            exportLibraryUC,
+		ImportLibraryUC:            importLibraryUC,
+		FindDuplicatesUC:           findDuplicatesUC,
+		MergeDuplicatesUC:          mergeDuplicatesUC,
+		GetBackupTracksUC:          getBackupTracksUC,
+		RestoreTracksUC:            restoreTracksUC,
+		ClaimLegacyBackupsUC:       claimLegacyBackupsUC,
+		ListOperationsUC:           listOperationsUC,
+		UndoOperationUC:            undoOperationUC,
+		ListRulesUC:                listRulesUC,
+		CreateRuleUC:               createRuleUC,
+		UpdateRuleUC:               updateRuleUC,
+		DeleteRuleUC:               deleteRuleUC,
+		RunRuleUC:                  runRuleUC,
+		JobManager:                 jobManager,
+		RuleScheduler:              ruleScheduler,
+		LibrarySnapshotScheduler:   librarySnapshotScheduler,
 	}
 
 	return container, nil
@@ -167,9 +402,6 @@ func GetOAuth2Config() (*oauth2.Config, error) {
 		ClientSecret: clientSecret,
 		RedirectURL:  redirectURI,
 		Scopes:       constants.Scopes,
-		Endpoint: oauth2.Endpoint{
-			AuthURL:  spotifyAPI.AuthURL,
-			TokenURL: spotifyAPI.TokenURL,
-		},
+		Endpoint:     spotify.OAuthEndpoint(),
 	}, nil
 }
@@ -0,0 +1,166 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/RubenPari/clear-songs/internal/domain/rule"
+)
+
+type logNotifier struct{}
+
+// NewLogNotifier creates a notifier that writes every rule run to the server log
+func NewLogNotifier() rule.Notifier {
+	return &logNotifier{}
+}
+
+func (n *logNotifier) Notify(ctx context.Context, r *rule.Rule, report *rule.RunReport) error {
+	if report.Error != "" {
+		log.Printf("Cleanup rule %q (%s) failed: %s", r.Name, r.ID, report.Error)
+		return nil
+	}
+
+	log.Printf("Cleanup rule %q (%s): %d tracks matched, %d deleted (dry run: %t)",
+		r.Name, r.ID, report.MatchedCount, report.DeletedCount, report.DryRun)
+	return nil
+}
+
+type webhookNotifier struct {
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a notifier that POSTs the JSON run report to the
+// webhook URL of the rule, if it has one.
+//
+// Webhook URLs are chosen by users, so the notifier only connects to public addresses:
+// loopback, private, link-local and other special-purpose addresses are refused after
+// DNS resolution, unless they belong to one of the allowed networks. Redirects are not
+// followed, as they could lead to a refused address.
+func NewWebhookNotifier(allowed []netip.Prefix) rule.Notifier {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, conn syscall.RawConn) error {
+			return checkWebhookAddress(address, allowed)
+		},
+	}
+
+	return &webhookNotifier{client: &http.Client{
+		Timeout: 10 * time.Second,
+		// No proxy: the dialer must see the address of the webhook itself
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 5 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}}
+}
+
+// ParseAllowedNetworks parses a WEBHOOK_ALLOWED_NETWORKS value: comma-separated CIDR
+// prefixes or single IP addresses
+func ParseAllowedNetworks(value string) ([]netip.Prefix, error) {
+	var networks []netip.Prefix
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if addr, err := netip.ParseAddr(entry); err == nil {
+			networks = append(networks, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook network %q: %w", entry, err)
+		}
+		networks = append(networks, prefix.Masked())
+	}
+	return networks, nil
+}
+
+// checkWebhookAddress refuses to connect to a non-public address outside allowed
+func checkWebhookAddress(address string, allowed []netip.Prefix) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("webhook address %q: %w", address, err)
+	}
+
+	ip := addrPort.Addr().Unmap()
+	for _, prefix := range allowed {
+		if prefix.Contains(ip) {
+			return nil
+		}
+	}
+	if !isPublic(ip) {
+		return fmt.Errorf("webhook address %s is not public", ip)
+	}
+	return nil
+}
+
+// isPublic reports whether ip is a global unicast address outside the private,
+// shared and documentation ranges
+func isPublic(ip netip.Addr) bool {
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+	for _, prefix := range nonPublicNetworks {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// nonPublicNetworks are the special-purpose ranges IsGlobalUnicast and IsPrivate let through
+var nonPublicNetworks = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("2001:db8::/32"),
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, r *rule.Rule, report *rule.RunReport) error {
+	if r.WebhookURL == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
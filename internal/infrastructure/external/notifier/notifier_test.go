@@ -0,0 +1,102 @@
+package notifier
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"sync/atomic"
+	"testing"
+
+	"github.com/RubenPari/clear-songs/internal/domain/rule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	ctx := context.Background()
+	report := &rule.RunReport{RuleID: "rule_1"}
+	loopback := []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}
+
+	t.Run("Success - should post the report to an allowed network", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		}))
+		defer server.Close()
+
+		err := NewWebhookNotifier(loopback).Notify(ctx, &rule.Rule{WebhookURL: server.URL}, report)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("Error - should refuse a loopback address", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+		}))
+		defer server.Close()
+
+		err := NewWebhookNotifier(nil).Notify(ctx, &rule.Rule{WebhookURL: server.URL}, report)
+
+		assert.ErrorContains(t, err, "not public")
+		assert.Zero(t, atomic.LoadInt32(&calls))
+	})
+
+	t.Run("Error - should not follow redirects", func(t *testing.T) {
+		var redirected int32
+		target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&redirected, 1)
+		}))
+		defer target.Close()
+		server := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusTemporaryRedirect))
+		defer server.Close()
+
+		err := NewWebhookNotifier(loopback).Notify(ctx, &rule.Rule{WebhookURL: server.URL}, report)
+
+		assert.ErrorContains(t, err, "status 307")
+		assert.Zero(t, atomic.LoadInt32(&redirected))
+	})
+}
+
+func TestCheckWebhookAddress(t *testing.T) {
+	for address, public := range map[string]bool{
+		"93.184.216.34:443":       true,
+		"[2606:4700::1111]:443":   true,
+		"127.0.0.1:80":            false,
+		"10.0.0.5:80":             false,
+		"172.16.0.1:80":           false,
+		"192.168.1.10:80":         false,
+		"169.254.169.254:80":      false,
+		"100.64.0.1:80":           false,
+		"0.0.0.0:80":              false,
+		"[::1]:80":                false,
+		"[fe80::1]:80":            false,
+		"[fd00::1]:80":            false,
+		"[::ffff:127.0.0.1]:80":   false,
+		"[::ffff:169.254.1.1]:80": false,
+	} {
+		err := checkWebhookAddress(address, nil)
+		assert.Equal(t, public, err == nil, address)
+	}
+}
+
+func TestParseAllowedNetworks(t *testing.T) {
+	t.Run("Success - should parse prefixes and single addresses", func(t *testing.T) {
+		networks, err := ParseAllowedNetworks(" 192.168.1.10, 10.1.2.3/16 ,")
+
+		require.NoError(t, err)
+		assert.Equal(t, []netip.Prefix{
+			netip.MustParsePrefix("192.168.1.10/32"),
+			netip.MustParsePrefix("10.1.0.0/16"),
+		}, networks)
+		assert.NoError(t, checkWebhookAddress("10.1.200.1:80", networks))
+	})
+
+	t.Run("Error - should reject an invalid network", func(t *testing.T) {
+		_, err := ParseAllowedNetworks("lan")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,72 @@
+package spotify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/zmb3/spotify"
+)
+
+// apiBaseURL is the Spotify Web API root used for endpoints the spotify library lacks
+const apiBaseURL = "https://api.spotify.com/v1/"
+
+// apiRequest sends an authenticated request to the Web API. body and result are
+// JSON-encoded/decoded when not nil. Errors are returned as spotify.Error, like the library does.
+func (r *SpotifyRepositoryImpl) apiRequest(ctx context.Context, method, path string, body, result any) error {
+	if r.apiClient == nil {
+		return errors.New("spotify client not initialized")
+	}
+
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, apiBaseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := r.apiClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			E spotify.Error `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil || apiErr.E.Message == "" {
+			return fmt.Errorf("spotify: HTTP %d: %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+		}
+		return apiErr.E
+	}
+
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// joinIDs formats IDs for a comma-separated query parameter
+func joinIDs(ids []spotify.ID) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = id.String()
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,88 @@
+package spotify
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/zmb3/spotify"
+	"golang.org/x/oauth2"
+)
+
+// spotifyHosts are the hosts RewriteTransport redirects: the Web API and the accounts service
+var spotifyHosts = map[string]bool{
+	"api.spotify.com":      true,
+	"accounts.spotify.com": true,
+}
+
+// baseURLFromEnv returns SPOTIFY_BASE_URL, an origin serving both the Web API (under /v1/)
+// and the accounts service (/authorize, /api/token) in place of Spotify, such as the fake
+// server of the end-to-end tests. It returns nil when unset or invalid.
+func baseURLFromEnv() *url.URL {
+	value := os.Getenv("SPOTIFY_BASE_URL")
+	if value == "" {
+		return nil
+	}
+
+	target, err := url.Parse(value)
+	if err != nil || target.Scheme == "" || target.Host == "" {
+		log.Printf("WARNING: Ignoring invalid SPOTIFY_BASE_URL %q", value)
+		return nil
+	}
+	return target
+}
+
+// WarnBaseURLOverride logs, on every start, that SPOTIFY_BASE_URL sends the Spotify
+// traffic elsewhere. The client secret and user tokens go with it, so a value left over
+// from testing must not go unnoticed.
+func WarnBaseURLOverride() {
+	if target := baseURLFromEnv(); target != nil {
+		log.Printf("WARNING: SPOTIFY_BASE_URL is set: Spotify Web API and OAuth requests, with the client secret and user tokens, go to %s instead of Spotify", target.Redacted())
+	}
+}
+
+// OAuthEndpoint returns the Spotify OAuth endpoint, on SPOTIFY_BASE_URL when set
+func OAuthEndpoint() oauth2.Endpoint {
+	target := baseURLFromEnv()
+	if target == nil {
+		return oauth2.Endpoint{AuthURL: spotify.AuthURL, TokenURL: spotify.TokenURL}
+	}
+
+	base := strings.TrimSuffix(target.String(), "/")
+	return oauth2.Endpoint{AuthURL: base + "/authorize", TokenURL: base + "/api/token"}
+}
+
+// RewriteTransport is an http.RoundTripper sending requests for the Spotify hosts to
+// another origin, keeping their paths. The spotify library has its API root built in,
+// so this is how its requests reach SPOTIFY_BASE_URL.
+type RewriteTransport struct {
+	base   http.RoundTripper
+	target *url.URL
+}
+
+// NewRewriteTransport wraps base (http.DefaultTransport if nil)
+func NewRewriteTransport(base http.RoundTripper, target *url.URL) *RewriteTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &RewriteTransport{base: base, target: target}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *RewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !spotifyHosts[req.URL.Host] {
+		return t.base.RoundTrip(req)
+	}
+
+	// Requests must not be modified by a RoundTripper, so the clone is rewritten
+	rewritten := req.Clone(req.Context())
+	rewritten.URL.Scheme = t.target.Scheme
+	rewritten.URL.Host = t.target.Host
+	rewritten.URL.Path = strings.TrimSuffix(t.target.Path, "/") + req.URL.Path
+	rewritten.URL.RawPath = ""
+	rewritten.Host = ""
+
+	return t.base.RoundTrip(rewritten)
+}
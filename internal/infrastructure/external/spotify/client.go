@@ -4,44 +4,68 @@ import (
 	"context"
 	"errors"
 	"log"
+	"net/http"
+	"sort"
 
+	"github.com/RubenPari/clear-songs/internal/domain/job"
 	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	domainTrack "github.com/RubenPari/clear-songs/internal/domain/track"
 	"github.com/zmb3/spotify"
 	"golang.org/x/oauth2"
 )
 
 // SpotifyRepositoryImpl implements the SpotifyRepository interface
 type SpotifyRepositoryImpl struct {
-	clientID      string
-	clientSecret  string
-	redirectURI   string
-	authenticator spotify.Authenticator
-	token         *oauth2.Token
-	client        *spotify.Client
+	clientID     string
+	clientSecret string
+	redirectURI  string
+	token        *oauth2.Token
+	client       *spotify.Client
+	oauthConfig  *oauth2.Config
+	httpClient   *http.Client // base client for API and token refresh calls, optional
+	apiClient    *http.Client // authenticated client, for endpoints the spotify library lacks
 }
 
 // NewSpotifyRepository creates a new Spotify repository implementation
 func NewSpotifyRepository(clientID, clientSecret, redirectURI string, scopes []string) *SpotifyRepositoryImpl {
-	auth := spotify.NewAuthenticator(redirectURI, scopes...)
-	auth.SetAuthInfo(clientID, clientSecret)
-
 	return &SpotifyRepositoryImpl{
-		clientID:      clientID,
-		clientSecret:  clientSecret,
-		redirectURI:   redirectURI,
-		authenticator: auth,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		redirectURI:  redirectURI,
+		oauthConfig: &oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			RedirectURL:  redirectURI,
+			Scopes:       scopes,
+			Endpoint:     OAuthEndpoint(),
+		},
 	}
 }
 
+// WithHTTPClient sets the client used underneath the OAuth transport, e.g. one with a
+// rate-limited transport. It must be called before SetAccessToken.
+func (r *SpotifyRepositoryImpl) WithHTTPClient(httpClient *http.Client) *SpotifyRepositoryImpl {
+	r.httpClient = httpClient
+	return r
+}
+
 // SetAccessToken sets the OAuth token and creates a new client
 func (r *SpotifyRepositoryImpl) SetAccessToken(token interface{}) error {
 	oauthToken, ok := token.(*oauth2.Token)
-	if !ok {
+	if !ok || oauthToken == nil {
 		return errors.New("invalid token type")
 	}
 
 	r.token = oauthToken
-	client := r.authenticator.NewClient(oauthToken)
+
+	baseClient := r.httpClient
+	if baseClient == nil {
+		baseClient = &http.Client{Transport: newBaseTransport()}
+	}
+
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, baseClient)
+	r.apiClient = r.oauthConfig.Client(ctx, oauthToken)
+	client := spotify.NewClient(r.apiClient)
 	r.client = &client
 	return nil
 }
@@ -51,6 +75,14 @@ func (r *SpotifyRepositoryImpl) GetClient() *spotify.Client {
 	return r.client
 }
 
+// Token returns the current OAuth token, which may have been refreshed by the client
+func (r *SpotifyRepositoryImpl) Token() (*oauth2.Token, error) {
+	if r.client == nil {
+		return nil, errors.New("spotify client not initialized")
+	}
+	return r.client.Token()
+}
+
 // GetCurrentUser retrieves the current authenticated user
 func (r *SpotifyRepositoryImpl) GetCurrentUser(ctx context.Context) (*spotify.PrivateUser, error) {
 	if r.client == nil {
@@ -100,12 +132,12 @@ func (r *SpotifyRepositoryImpl) GetAllUserTracks(ctx context.Context) ([]spotify
 	return allTracks, nil
 }
 
-// GetTracksByArtist filters tracks by artist ID
-func (r *SpotifyRepositoryImpl) GetTracksByArtist(ctx context.Context, artistID spotify.ID, tracks []spotify.SavedTrack) ([]spotify.SavedTrack, error) {
+// GetTracksByArtist filters tracks by artist IDs
+func (r *SpotifyRepositoryImpl) GetTracksByArtist(ctx context.Context, filter domainTrack.ArtistFilter, tracks []spotify.SavedTrack) ([]spotify.SavedTrack, error) {
 	var filteredTracks []spotify.SavedTrack
 
 	for _, track := range tracks {
-		if len(track.Artists) > 0 && track.Artists[0].ID == artistID {
+		if filter.Matches(track.Artists) {
 			filteredTracks = append(filteredTracks, track)
 		}
 	}
@@ -113,12 +145,12 @@ func (r *SpotifyRepositoryImpl) GetTracksByArtist(ctx context.Context, artistID
 	return filteredTracks, nil
 }
 
-// GetTrackIDsByArtist returns only track IDs for an artist
-func (r *SpotifyRepositoryImpl) GetTrackIDsByArtist(ctx context.Context, artistID spotify.ID, tracks []spotify.SavedTrack) ([]spotify.ID, error) {
+// GetTrackIDsByArtist returns only the IDs of the tracks matching the artist filter
+func (r *SpotifyRepositoryImpl) GetTrackIDsByArtist(ctx context.Context, filter domainTrack.ArtistFilter, tracks []spotify.SavedTrack) ([]spotify.ID, error) {
 	var trackIDs []spotify.ID
 
 	for _, track := range tracks {
-		if len(track.Artists) > 0 && track.Artists[0].ID == artistID {
+		if filter.Matches(track.Artists) {
 			trackIDs = append(trackIDs, track.ID)
 		}
 	}
@@ -141,10 +173,16 @@ func (r *SpotifyRepositoryImpl) DeleteTracksFromLibrary(ctx context.Context, tra
 			end = len(trackIDs)
 		}
 
+		// Stop between batches when the caller (e.g. a cancelled job) gives up
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		batch := trackIDs[offset:end]
 		if err := r.client.RemoveTracksFromLibrary(batch...); err != nil {
 			return err
 		}
+		job.ReportBatch(ctx, len(batch))
 
 		log.Printf("Deleted tracks from offset: %d", offset)
 		offset += limit
@@ -153,6 +191,39 @@ func (r *SpotifyRepositoryImpl) DeleteTracksFromLibrary(ctx context.Context, tra
 	return nil
 }
 
+// AddTracksToLibrary saves tracks to user's library
+func (r *SpotifyRepositoryImpl) AddTracksToLibrary(ctx context.Context, trackIDs []spotify.ID) error {
+	if r.client == nil {
+		return errors.New("spotify client not initialized")
+	}
+
+	limit := 50
+	offset := 0
+
+	for offset < len(trackIDs) {
+		end := offset + limit
+		if end > len(trackIDs) {
+			end = len(trackIDs)
+		}
+
+		// Stop between batches when the caller (e.g. a cancelled job) gives up
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		batch := trackIDs[offset:end]
+		if err := r.client.AddTracksToLibrary(batch...); err != nil {
+			return err
+		}
+		job.ReportBatch(ctx, len(batch))
+
+		log.Printf("Added tracks to library from offset: %d", offset)
+		offset += limit
+	}
+
+	return nil
+}
+
 // GetPlaylist retrieves a playlist by ID
 func (r *SpotifyRepositoryImpl) GetPlaylist(ctx context.Context, playlistID spotify.ID) (*spotify.FullPlaylist, error) {
 	if r.client == nil {
@@ -217,10 +288,103 @@ func (r *SpotifyRepositoryImpl) DeletePlaylistTracks(ctx context.Context, playli
 			end = len(trackIDs)
 		}
 
+		// Stop between batches when the caller (e.g. a cancelled job) gives up
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		batch := trackIDs[offset:end]
 		if _, err := r.client.RemoveTracksFromPlaylist(playlistID, batch...); err != nil {
 			return err
 		}
+		job.ReportBatch(ctx, len(batch))
+
+		offset += limit
+	}
+
+	return nil
+}
+
+// DeletePlaylistTracksAt removes tracks at specific positions of a playlist snapshot.
+// Positions are removed from the end of the playlist backwards, so that each batch
+// leaves the positions of the next one unchanged, and each batch is applied to the
+// snapshot returned by the previous one.
+func (r *SpotifyRepositoryImpl) DeletePlaylistTracksAt(ctx context.Context, playlistID spotify.ID, snapshotID string, tracks []spotify.TrackToRemove) (string, error) {
+	if r.client == nil {
+		return "", errors.New("spotify client not initialized")
+	}
+
+	// One entry per position, highest position first
+	var positions []spotify.TrackToRemove
+	for _, track := range tracks {
+		for _, position := range track.Positions {
+			positions = append(positions, spotify.TrackToRemove{URI: track.URI, Positions: []int{position}})
+		}
+	}
+	sort.Slice(positions, func(i, j int) bool {
+		return positions[i].Positions[0] > positions[j].Positions[0]
+	})
+
+	limit := 100
+	offset := 0
+
+	for offset < len(positions) {
+		end := offset + limit
+		if end > len(positions) {
+			end = len(positions)
+		}
+
+		if err := ctx.Err(); err != nil {
+			return snapshotID, err
+		}
+
+		batch := positions[offset:end]
+		newSnapshotID, err := r.client.RemoveTracksFromPlaylistOpt(playlistID, batch, snapshotID)
+		if err != nil {
+			return snapshotID, err
+		}
+		snapshotID = newSnapshotID
+		job.ReportBatch(ctx, len(batch))
+
+		offset += limit
+	}
+
+	return snapshotID, nil
+}
+
+// AddTracksToPlaylist inserts tracks into a playlist starting at position, keeping their order
+func (r *SpotifyRepositoryImpl) AddTracksToPlaylist(ctx context.Context, playlistID spotify.ID, trackIDs []spotify.ID, position int) error {
+	if r.client == nil {
+		return errors.New("spotify client not initialized")
+	}
+
+	limit := 100
+	offset := 0
+
+	for offset < len(trackIDs) {
+		end := offset + limit
+		if end > len(trackIDs) {
+			end = len(trackIDs)
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		batch := trackIDs[offset:end]
+		uris := make([]string, len(batch))
+		for i, id := range batch {
+			uris[i] = "spotify:track:" + id.String()
+		}
+
+		body := map[string]any{
+			"uris":     uris,
+			"position": position + offset,
+		}
+		if err := r.apiRequest(ctx, http.MethodPost, "playlists/"+playlistID.String()+"/tracks", body, nil); err != nil {
+			return err
+		}
+		job.ReportBatch(ctx, len(batch))
 
 		offset += limit
 	}
@@ -228,6 +392,48 @@ func (r *SpotifyRepositoryImpl) DeletePlaylistTracks(ctx context.Context, playli
 	return nil
 }
 
+// ReplacePlaylistTracks replaces the playlist items with the first 100 tracks and
+// appends the rest in batches of 100
+func (r *SpotifyRepositoryImpl) ReplacePlaylistTracks(ctx context.Context, playlistID spotify.ID, trackIDs []spotify.ID) error {
+	if r.client == nil {
+		return errors.New("spotify client not initialized")
+	}
+
+	end := len(trackIDs)
+	if end > 100 {
+		end = 100
+	}
+
+	uris := make([]string, end)
+	for i, id := range trackIDs[:end] {
+		uris[i] = "spotify:track:" + id.String()
+	}
+
+	body := map[string]any{"uris": uris}
+	if err := r.apiRequest(ctx, http.MethodPut, "playlists/"+playlistID.String()+"/tracks", body, nil); err != nil {
+		return err
+	}
+	job.ReportBatch(ctx, end)
+
+	if end == len(trackIDs) {
+		return nil
+	}
+	return r.AddTracksToPlaylist(ctx, playlistID, trackIDs[end:], end)
+}
+
+// UpdatePlaylistDetails changes the name and description of a playlist
+func (r *SpotifyRepositoryImpl) UpdatePlaylistDetails(ctx context.Context, playlistID spotify.ID, name, description string) error {
+	if r.client == nil {
+		return errors.New("spotify client not initialized")
+	}
+
+	body := map[string]any{
+		"name":        name,
+		"description": description,
+	}
+	return r.apiRequest(ctx, http.MethodPut, "playlists/"+playlistID.String(), body, nil)
+}
+
 // GetUserPlaylists retrieves playlists owned or followed by the user with pagination
 func (r *SpotifyRepositoryImpl) GetUserPlaylists(ctx context.Context, limit, offset int) ([]spotify.SimplePlaylist, error) {
 	if r.client == nil {
@@ -268,6 +474,145 @@ func (r *SpotifyRepositoryImpl) GetAllUserPlaylists(ctx context.Context) ([]spot
 	return allPlaylists, nil
 }
 
+// GetUserAlbums retrieves albums saved by the user with pagination
+func (r *SpotifyRepositoryImpl) GetUserAlbums(ctx context.Context, limit, offset int) ([]spotify.SavedAlbum, error) {
+	if r.client == nil {
+		return nil, errors.New("spotify client not initialized")
+	}
+
+	page, err := r.client.CurrentUsersAlbumsOpt(&spotify.Options{
+		Limit:  &limit,
+		Offset: &offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return page.Albums, nil
+}
+
+// GetAllUserAlbums retrieves all user albums with automatic pagination
+func (r *SpotifyRepositoryImpl) GetAllUserAlbums(ctx context.Context) ([]spotify.SavedAlbum, error) {
+	var allAlbums []spotify.SavedAlbum
+	limit := 50
+	offset := 0
+
+	for {
+		albums, err := r.GetUserAlbums(ctx, limit, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(albums) == 0 {
+			break
+		}
+
+		allAlbums = append(allAlbums, albums...)
+		offset += limit
+	}
+
+	return allAlbums, nil
+}
+
+// GetAlbum retrieves album information
+func (r *SpotifyRepositoryImpl) GetAlbum(ctx context.Context, albumID spotify.ID) (*spotify.FullAlbum, error) {
+	if r.client == nil {
+		return nil, errors.New("spotify client not initialized")
+	}
+	return r.client.GetAlbum(albumID)
+}
+
+// GetAlbumTracks retrieves all tracks of an album with automatic pagination
+func (r *SpotifyRepositoryImpl) GetAlbumTracks(ctx context.Context, albumID spotify.ID) ([]spotify.SimpleTrack, error) {
+	if r.client == nil {
+		return nil, errors.New("spotify client not initialized")
+	}
+
+	var allTracks []spotify.SimpleTrack
+	limit := 50
+	offset := 0
+
+	for {
+		page, err := r.client.GetAlbumTracksOpt(albumID, &spotify.Options{
+			Limit:  &limit,
+			Offset: &offset,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		allTracks = append(allTracks, page.Tracks...)
+		if len(page.Tracks) < limit {
+			break
+		}
+		offset += limit
+	}
+
+	return allTracks, nil
+}
+
+// RemoveAlbumsFromLibrary removes albums from user's saved albums
+func (r *SpotifyRepositoryImpl) RemoveAlbumsFromLibrary(ctx context.Context, albumIDs []spotify.ID) error {
+	if r.client == nil {
+		return errors.New("spotify client not initialized")
+	}
+
+	limit := 50
+	offset := 0
+
+	for offset < len(albumIDs) {
+		end := offset + limit
+		if end > len(albumIDs) {
+			end = len(albumIDs)
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		batch := albumIDs[offset:end]
+		if err := r.apiRequest(ctx, http.MethodDelete, "me/albums?ids="+joinIDs(batch), nil, nil); err != nil {
+			return err
+		}
+		job.ReportBatch(ctx, len(batch))
+
+		offset += limit
+	}
+
+	return nil
+}
+
+// SaveAlbumsToLibrary saves albums to user's saved albums
+func (r *SpotifyRepositoryImpl) SaveAlbumsToLibrary(ctx context.Context, albumIDs []spotify.ID) error {
+	if r.client == nil {
+		return errors.New("spotify client not initialized")
+	}
+
+	limit := 50
+	offset := 0
+
+	for offset < len(albumIDs) {
+		end := offset + limit
+		if end > len(albumIDs) {
+			end = len(albumIDs)
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		batch := albumIDs[offset:end]
+		if err := r.apiRequest(ctx, http.MethodPut, "me/albums?ids="+joinIDs(batch), nil, nil); err != nil {
+			return err
+		}
+		job.ReportBatch(ctx, len(batch))
+
+		offset += limit
+	}
+
+	return nil
+}
+
 // GetArtist retrieves artist information
 func (r *SpotifyRepositoryImpl) GetArtist(ctx context.Context, artistID spotify.ID) (*spotify.FullArtist, error) {
 	if r.client == nil {
@@ -276,6 +621,43 @@ func (r *SpotifyRepositoryImpl) GetArtist(ctx context.Context, artistID spotify.
 	return r.client.GetArtist(artistID)
 }
 
+// GetArtists retrieves information of several artists in batches of 50
+func (r *SpotifyRepositoryImpl) GetArtists(ctx context.Context, artistIDs []spotify.ID) ([]spotify.FullArtist, error) {
+	if r.client == nil {
+		return nil, errors.New("spotify client not initialized")
+	}
+
+	var artists []spotify.FullArtist
+	limit := 50
+	offset := 0
+
+	for offset < len(artistIDs) {
+		end := offset + limit
+		if end > len(artistIDs) {
+			end = len(artistIDs)
+		}
+
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		batch, err := r.client.GetArtists(artistIDs[offset:end]...)
+		if err != nil {
+			return nil, err
+		}
+		for _, artist := range batch {
+			// Spotify answers null for IDs it does not know
+			if artist != nil {
+				artists = append(artists, *artist)
+			}
+		}
+
+		offset += limit
+	}
+
+	return artists, nil
+}
+
 // GetTrack retrieves track information
 func (r *SpotifyRepositoryImpl) GetTrack(ctx context.Context, trackID spotify.ID) (*spotify.FullTrack, error) {
 	if r.client == nil {
@@ -284,5 +666,22 @@ func (r *SpotifyRepositoryImpl) GetTrack(ctx context.Context, trackID spotify.ID
 	return r.client.GetTrack(trackID)
 }
 
+// SearchTracks searches the catalog for tracks matching a query
+func (r *SpotifyRepositoryImpl) SearchTracks(ctx context.Context, query string, limit int) ([]spotify.FullTrack, error) {
+	if r.client == nil {
+		return nil, errors.New("spotify client not initialized")
+	}
+
+	result, err := r.client.SearchOpt(query, spotify.SearchTypeTrack, &spotify.Options{Limit: &limit})
+	if err != nil {
+		return nil, err
+	}
+	if result.Tracks == nil {
+		return nil, nil
+	}
+
+	return result.Tracks.Tracks, nil
+}
+
 // Ensure SpotifyRepositoryImpl implements SpotifyRepository interface
 var _ shared.SpotifyRepository = (*SpotifyRepositoryImpl)(nil)
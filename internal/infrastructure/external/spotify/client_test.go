@@ -0,0 +1,243 @@
+package spotify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/RubenPari/clear-songs/test/fakespotify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zmb3/spotify"
+	"golang.org/x/oauth2"
+)
+
+// newFakeRepository returns a repository talking to a fake Spotify server through the
+// production transports, without waiting between retries
+func newFakeRepository(t *testing.T) (*SpotifyRepositoryImpl, *fakespotify.Server) {
+	fake := fakespotify.New(t)
+	target, err := url.Parse(fake.URL)
+	require.NoError(t, err)
+
+	transport := NewRateLimitTransport(NewRewriteTransport(http.DefaultTransport, target), TransportConfig{
+		MaxRetries:  3,
+		BaseBackoff: time.Millisecond,
+		MaxBackoff:  time.Second,
+	})
+	transport.sleep = func(ctx context.Context, d time.Duration) error { return ctx.Err() }
+
+	repo := NewSpotifyRepository("client", "secret", "http://localhost/callback", nil).
+		WithHTTPClient(&http.Client{Transport: transport})
+	require.NoError(t, repo.SetAccessToken(&oauth2.Token{
+		AccessToken: fake.IssueToken(time.Hour),
+		TokenType:   "Bearer",
+		Expiry:      time.Now().Add(time.Hour),
+	}))
+	return repo, fake
+}
+
+// seedLibrary adds count tracks by one artist to the catalog and saves them, the
+// first one most recently
+func seedLibrary(fake *fakespotify.Server, count int) []spotify.ID {
+	artist := fakespotify.Artist("artist-1", "Artist One", "indie rock")
+	fake.AddArtist(artist)
+
+	ids := make([]spotify.ID, count)
+	now := time.Now()
+	for i := range ids {
+		track := fakespotify.Track(fmt.Sprintf("track-%03d", i), fmt.Sprintf("Track %d", i), artist)
+		fake.AddTrack(track)
+		fake.Save(now.Add(-time.Duration(i)*time.Minute), track.ID)
+		ids[i] = track.ID
+	}
+	return ids
+}
+
+func TestSpotifyRepository_Library(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success - should read the whole library across pages", func(t *testing.T) {
+		repo, fake := newFakeRepository(t)
+		ids := seedLibrary(fake, 120)
+
+		tracks, err := repo.GetAllUserTracks(ctx)
+
+		require.NoError(t, err)
+		require.Len(t, tracks, 120)
+		assert.Equal(t, ids[0], tracks[0].ID)
+		assert.Equal(t, ids[119], tracks[119].ID)
+		// Three full or partial pages, then an empty one
+		assert.Equal(t, 4, fake.Requests(http.MethodGet, "/me/tracks"))
+	})
+
+	t.Run("Success - should read a library that fills its last page exactly", func(t *testing.T) {
+		repo, fake := newFakeRepository(t)
+		seedLibrary(fake, 100)
+
+		tracks, err := repo.GetAllUserTracks(ctx)
+
+		require.NoError(t, err)
+		assert.Len(t, tracks, 100)
+		assert.Equal(t, 3, fake.Requests(http.MethodGet, "/me/tracks"))
+	})
+
+	t.Run("Success - should delete tracks in batches of 50", func(t *testing.T) {
+		repo, fake := newFakeRepository(t)
+		ids := seedLibrary(fake, 120)
+
+		err := repo.DeleteTracksFromLibrary(ctx, ids[:110])
+
+		require.NoError(t, err)
+		assert.Equal(t, ids[110:], fake.SavedTrackIDs())
+		assert.Equal(t, 3, fake.Requests(http.MethodDelete, "/me/tracks"))
+	})
+
+	t.Run("Success - should save tracks back to the library", func(t *testing.T) {
+		repo, fake := newFakeRepository(t)
+		ids := seedLibrary(fake, 3)
+		require.NoError(t, repo.DeleteTracksFromLibrary(ctx, ids))
+
+		err := repo.AddTracksToLibrary(ctx, ids[:2])
+
+		require.NoError(t, err)
+		assert.ElementsMatch(t, ids[:2], fake.SavedTrackIDs())
+	})
+
+	t.Run("Error - should reject an expired token", func(t *testing.T) {
+		repo, fake := newFakeRepository(t)
+		require.NoError(t, repo.SetAccessToken(&oauth2.Token{
+			AccessToken: fake.IssueToken(-time.Minute),
+			Expiry:      time.Now().Add(time.Hour),
+		}))
+
+		_, err := repo.GetCurrentUser(ctx)
+
+		var apiErr spotify.Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusUnauthorized, apiErr.Status)
+	})
+}
+
+func TestSpotifyRepository_Playlists(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success - should read a playlist across pages of 100", func(t *testing.T) {
+		repo, fake := newFakeRepository(t)
+		ids := seedLibrary(fake, 200)
+		fake.AddPlaylist("playlist-1", "Mix", ids...)
+
+		tracks, err := repo.GetAllPlaylistTracks(ctx, "playlist-1")
+
+		require.NoError(t, err)
+		assert.Len(t, tracks, 200)
+		assert.Equal(t, 3, fake.Requests(http.MethodGet, "/playlists/playlist-1/tracks"))
+	})
+
+	t.Run("Success - should remove positions from the end and chain snapshots", func(t *testing.T) {
+		repo, fake := newFakeRepository(t)
+		ids := seedLibrary(fake, 3)
+		fake.AddPlaylist("playlist-1", "Mix", ids[0], ids[1], ids[0], ids[2])
+		playlist, err := repo.GetPlaylist(ctx, "playlist-1")
+		require.NoError(t, err)
+
+		snapshotID, err := repo.DeletePlaylistTracksAt(ctx, "playlist-1", playlist.SnapshotID, []spotify.TrackToRemove{
+			spotify.NewTrackToRemove(ids[0].String(), []int{0, 2}),
+		})
+
+		require.NoError(t, err)
+		assert.NotEqual(t, playlist.SnapshotID, snapshotID)
+		assert.Equal(t, []spotify.ID{ids[1], ids[2]}, fake.PlaylistTrackIDs("playlist-1"))
+	})
+
+	t.Run("Error - should fail when a position does not hold the track", func(t *testing.T) {
+		repo, fake := newFakeRepository(t)
+		ids := seedLibrary(fake, 2)
+		fake.AddPlaylist("playlist-1", "Mix", ids...)
+
+		_, err := repo.DeletePlaylistTracksAt(ctx, "playlist-1", "", []spotify.TrackToRemove{
+			spotify.NewTrackToRemove(ids[0].String(), []int{1}),
+		})
+
+		assert.Error(t, err)
+		assert.Equal(t, ids, fake.PlaylistTrackIDs("playlist-1"))
+	})
+
+	t.Run("Success - should replace and insert tracks keeping their order", func(t *testing.T) {
+		repo, fake := newFakeRepository(t)
+		ids := seedLibrary(fake, 150)
+		fake.AddPlaylist("playlist-1", "Mix", ids[0])
+
+		require.NoError(t, repo.ReplacePlaylistTracks(ctx, "playlist-1", ids[1:]))
+		require.NoError(t, repo.AddTracksToPlaylist(ctx, "playlist-1", ids[:1], 0))
+
+		assert.Equal(t, ids, fake.PlaylistTrackIDs("playlist-1"))
+	})
+}
+
+func TestSpotifyRepository_Catalog(t *testing.T) {
+	t.Run("Success - should skip artists Spotify does not know", func(t *testing.T) {
+		repo, fake := newFakeRepository(t)
+		fake.AddArtist(fakespotify.Artist("artist-1", "Artist One", "jazz"))
+
+		artists, err := repo.GetArtists(context.Background(), []spotify.ID{"artist-1", "missing"})
+
+		require.NoError(t, err)
+		require.Len(t, artists, 1)
+		assert.Equal(t, []string{"jazz"}, artists[0].Genres)
+	})
+}
+
+func TestSpotifyRepository_Failures(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success - should retry reads rejected with 429", func(t *testing.T) {
+		repo, fake := newFakeRepository(t)
+		seedLibrary(fake, 10)
+		fake.FailNext(http.MethodGet, "/me/tracks", http.StatusTooManyRequests, 2)
+
+		tracks, err := repo.GetUserTracks(ctx, 50, 0)
+
+		require.NoError(t, err)
+		assert.Len(t, tracks, 10)
+		assert.Equal(t, 3, fake.Requests(http.MethodGet, "/me/tracks"))
+	})
+
+	t.Run("Success - should retry deletes failing with 5xx", func(t *testing.T) {
+		repo, fake := newFakeRepository(t)
+		ids := seedLibrary(fake, 10)
+		fake.FailNext(http.MethodDelete, "/me/tracks", http.StatusServiceUnavailable, 1)
+
+		err := repo.DeleteTracksFromLibrary(ctx, ids)
+
+		require.NoError(t, err)
+		assert.Empty(t, fake.SavedTrackIDs())
+	})
+
+	t.Run("Error - should not retry inserts failing with 5xx", func(t *testing.T) {
+		repo, fake := newFakeRepository(t)
+		ids := seedLibrary(fake, 2)
+		fake.AddPlaylist("playlist-1", "Mix")
+		fake.FailNext(http.MethodPost, "/playlists/playlist-1/tracks", http.StatusInternalServerError, 1)
+
+		err := repo.AddTracksToPlaylist(ctx, "playlist-1", ids, 0)
+
+		assert.Error(t, err)
+		assert.Equal(t, 1, fake.Requests(http.MethodPost, "/playlists/playlist-1/tracks"))
+		assert.Empty(t, fake.PlaylistTrackIDs("playlist-1"))
+	})
+
+	t.Run("Error - should give up after the retry budget", func(t *testing.T) {
+		repo, fake := newFakeRepository(t)
+		fake.FailNext(http.MethodGet, "/me", http.StatusBadGateway, 10)
+
+		_, err := repo.GetCurrentUser(ctx)
+
+		var apiErr spotify.Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadGateway, apiErr.Status)
+		assert.Equal(t, 4, fake.Requests(http.MethodGet, "/me"))
+	})
+}
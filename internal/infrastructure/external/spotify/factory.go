@@ -0,0 +1,111 @@
+package spotify
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	domainAuth "github.com/RubenPari/clear-songs/internal/domain/auth"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	"golang.org/x/oauth2"
+)
+
+// RepositoryFactory creates a SpotifyRepositoryImpl per user token
+type RepositoryFactory struct {
+	clientID     string
+	clientSecret string
+	redirectURI  string
+	scopes       []string
+	httpClient   *http.Client
+}
+
+// NewRepositoryFactory creates a new factory for per-session Spotify repositories.
+// All repositories share one rate-limited transport configured from the environment.
+func NewRepositoryFactory(clientID, clientSecret, redirectURI string, scopes []string) *RepositoryFactory {
+	return &RepositoryFactory{
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		redirectURI:  redirectURI,
+		scopes:       scopes,
+		httpClient: &http.Client{
+			Transport: NewRateLimitTransport(newBaseTransport(), TransportConfigFromEnv()),
+		},
+	}
+}
+
+// NewRepository returns a new repository authenticated with the given token.
+// Every call builds its own client, so repositories are never shared between users.
+func (f *RepositoryFactory) NewRepository(token *oauth2.Token) shared.SpotifyRepository {
+	repo := NewSpotifyRepository(f.clientID, f.clientSecret, f.redirectURI, f.scopes).
+		WithHTTPClient(f.httpClient)
+	_ = repo.SetAccessToken(token)
+	return repo
+}
+
+// PersistRefreshedToken caches the token of spotifyRepo again under the session key when
+// the OAuth client refreshed it since original was loaded, so the next request reuses
+// it. A refresh token rotated by Spotify is also stored for the owner in ctx.
+func PersistRefreshedToken(
+	ctx context.Context,
+	cacheRepo shared.CacheRepository,
+	credentialRepo domainAuth.CredentialRepository,
+	sessionID string,
+	original *oauth2.Token,
+	spotifyRepo shared.SpotifyRepository,
+) {
+	current := refreshedToken(original, spotifyRepo)
+	if current == nil {
+		return
+	}
+
+	if err := cacheRepo.SetToken(ctx, sessionID, current); err != nil {
+		log.Printf("ERROR: Failed to persist refreshed token: %v", err)
+	}
+	saveRotatedRefreshToken(ctx, credentialRepo, original, current)
+}
+
+// PersistRotatedCredential stores the refresh token of spotifyRepo for the owner in ctx
+// when Spotify rotated it since original was loaded, so the next scheduled run can
+// still mint access tokens
+func PersistRotatedCredential(
+	ctx context.Context,
+	credentialRepo domainAuth.CredentialRepository,
+	original *oauth2.Token,
+	spotifyRepo shared.SpotifyRepository,
+) {
+	if current := refreshedToken(original, spotifyRepo); current != nil {
+		saveRotatedRefreshToken(ctx, credentialRepo, original, current)
+	}
+}
+
+// refreshedToken returns the token of spotifyRepo when the OAuth client refreshed it
+// since original was loaded, or nil
+func refreshedToken(original *oauth2.Token, spotifyRepo shared.SpotifyRepository) *oauth2.Token {
+	source, ok := spotifyRepo.(interface{ Token() (*oauth2.Token, error) })
+	if !ok {
+		return nil
+	}
+
+	current, err := source.Token()
+	if err != nil || current == nil || current.AccessToken == original.AccessToken {
+		return nil
+	}
+	return current
+}
+
+// saveRotatedRefreshToken stores the refresh token of current for the owner in ctx when
+// it differs from the one of original
+func saveRotatedRefreshToken(ctx context.Context, credentialRepo domainAuth.CredentialRepository, original, current *oauth2.Token) {
+	owner := shared.OwnerFromContext(ctx)
+	if credentialRepo == nil || owner == "" || current.RefreshToken == "" || current.RefreshToken == original.RefreshToken {
+		return
+	}
+
+	credential := &domainAuth.SpotifyCredential{Owner: owner, RefreshToken: current.RefreshToken}
+	if err := credentialRepo.Save(ctx, credential); err != nil {
+		log.Printf("ERROR: Failed to store rotated Spotify credential: %v", err)
+	}
+}
+
+// Ensure RepositoryFactory implements SpotifyRepositoryFactory interface
+var _ shared.SpotifyRepositoryFactory = (*RepositoryFactory)(nil)
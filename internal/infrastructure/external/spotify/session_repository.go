@@ -0,0 +1,135 @@
+package spotify
+
+import (
+	"context"
+
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	domainTrack "github.com/RubenPari/clear-songs/internal/domain/track"
+	"github.com/zmb3/spotify"
+)
+
+// SessionSpotifyRepository implements SpotifyRepository by delegating every call to the
+// repository bound to the session carried by the request context (see SessionMiddleware).
+// It holds no token itself, so it can be safely shared by all use cases.
+type SessionSpotifyRepository struct{}
+
+// NewSessionSpotifyRepository creates a new session-aware Spotify repository
+func NewSessionSpotifyRepository() *SessionSpotifyRepository {
+	return &SessionSpotifyRepository{}
+}
+
+// current returns the repository of the current session. Without a session an
+// unauthenticated repository is returned, whose API calls fail with an error.
+func (r *SessionSpotifyRepository) current(ctx context.Context) shared.SpotifyRepository {
+	if repo := shared.SpotifyRepositoryFromContext(ctx); repo != nil {
+		return repo
+	}
+	return &SpotifyRepositoryImpl{}
+}
+
+func (r *SessionSpotifyRepository) GetCurrentUser(ctx context.Context) (*spotify.PrivateUser, error) {
+	return r.current(ctx).GetCurrentUser(ctx)
+}
+
+func (r *SessionSpotifyRepository) GetUserTracks(ctx context.Context, limit, offset int) ([]spotify.SavedTrack, error) {
+	return r.current(ctx).GetUserTracks(ctx, limit, offset)
+}
+
+func (r *SessionSpotifyRepository) GetAllUserTracks(ctx context.Context) ([]spotify.SavedTrack, error) {
+	return r.current(ctx).GetAllUserTracks(ctx)
+}
+
+func (r *SessionSpotifyRepository) GetTracksByArtist(ctx context.Context, filter domainTrack.ArtistFilter, tracks []spotify.SavedTrack) ([]spotify.SavedTrack, error) {
+	return r.current(ctx).GetTracksByArtist(ctx, filter, tracks)
+}
+
+func (r *SessionSpotifyRepository) GetTrackIDsByArtist(ctx context.Context, filter domainTrack.ArtistFilter, tracks []spotify.SavedTrack) ([]spotify.ID, error) {
+	return r.current(ctx).GetTrackIDsByArtist(ctx, filter, tracks)
+}
+
+func (r *SessionSpotifyRepository) DeleteTracksFromLibrary(ctx context.Context, trackIDs []spotify.ID) error {
+	return r.current(ctx).DeleteTracksFromLibrary(ctx, trackIDs)
+}
+
+func (r *SessionSpotifyRepository) AddTracksToLibrary(ctx context.Context, trackIDs []spotify.ID) error {
+	return r.current(ctx).AddTracksToLibrary(ctx, trackIDs)
+}
+
+func (r *SessionSpotifyRepository) GetPlaylist(ctx context.Context, playlistID spotify.ID) (*spotify.FullPlaylist, error) {
+	return r.current(ctx).GetPlaylist(ctx, playlistID)
+}
+
+func (r *SessionSpotifyRepository) GetPlaylistTracks(ctx context.Context, playlistID spotify.ID, limit, offset int) ([]spotify.PlaylistTrack, error) {
+	return r.current(ctx).GetPlaylistTracks(ctx, playlistID, limit, offset)
+}
+
+func (r *SessionSpotifyRepository) GetAllPlaylistTracks(ctx context.Context, playlistID spotify.ID) ([]spotify.PlaylistTrack, error) {
+	return r.current(ctx).GetAllPlaylistTracks(ctx, playlistID)
+}
+
+func (r *SessionSpotifyRepository) DeletePlaylistTracks(ctx context.Context, playlistID spotify.ID, trackIDs []spotify.ID) error {
+	return r.current(ctx).DeletePlaylistTracks(ctx, playlistID, trackIDs)
+}
+
+func (r *SessionSpotifyRepository) DeletePlaylistTracksAt(ctx context.Context, playlistID spotify.ID, snapshotID string, tracks []spotify.TrackToRemove) (string, error) {
+	return r.current(ctx).DeletePlaylistTracksAt(ctx, playlistID, snapshotID, tracks)
+}
+
+func (r *SessionSpotifyRepository) AddTracksToPlaylist(ctx context.Context, playlistID spotify.ID, trackIDs []spotify.ID, position int) error {
+	return r.current(ctx).AddTracksToPlaylist(ctx, playlistID, trackIDs, position)
+}
+
+func (r *SessionSpotifyRepository) ReplacePlaylistTracks(ctx context.Context, playlistID spotify.ID, trackIDs []spotify.ID) error {
+	return r.current(ctx).ReplacePlaylistTracks(ctx, playlistID, trackIDs)
+}
+
+func (r *SessionSpotifyRepository) UpdatePlaylistDetails(ctx context.Context, playlistID spotify.ID, name, description string) error {
+	return r.current(ctx).UpdatePlaylistDetails(ctx, playlistID, name, description)
+}
+
+func (r *SessionSpotifyRepository) GetUserPlaylists(ctx context.Context, limit, offset int) ([]spotify.SimplePlaylist, error) {
+	return r.current(ctx).GetUserPlaylists(ctx, limit, offset)
+}
+
+func (r *SessionSpotifyRepository) GetAllUserPlaylists(ctx context.Context) ([]spotify.SimplePlaylist, error) {
+	return r.current(ctx).GetAllUserPlaylists(ctx)
+}
+
+func (r *SessionSpotifyRepository) GetAllUserAlbums(ctx context.Context) ([]spotify.SavedAlbum, error) {
+	return r.current(ctx).GetAllUserAlbums(ctx)
+}
+
+func (r *SessionSpotifyRepository) GetAlbum(ctx context.Context, albumID spotify.ID) (*spotify.FullAlbum, error) {
+	return r.current(ctx).GetAlbum(ctx, albumID)
+}
+
+func (r *SessionSpotifyRepository) GetAlbumTracks(ctx context.Context, albumID spotify.ID) ([]spotify.SimpleTrack, error) {
+	return r.current(ctx).GetAlbumTracks(ctx, albumID)
+}
+
+func (r *SessionSpotifyRepository) RemoveAlbumsFromLibrary(ctx context.Context, albumIDs []spotify.ID) error {
+	return r.current(ctx).RemoveAlbumsFromLibrary(ctx, albumIDs)
+}
+
+func (r *SessionSpotifyRepository) SaveAlbumsToLibrary(ctx context.Context, albumIDs []spotify.ID) error {
+	return r.current(ctx).SaveAlbumsToLibrary(ctx, albumIDs)
+}
+
+func (r *SessionSpotifyRepository) GetArtist(ctx context.Context, artistID spotify.ID) (*spotify.FullArtist, error) {
+	return r.current(ctx).GetArtist(ctx, artistID)
+}
+
+func (r *SessionSpotifyRepository) GetArtists(ctx context.Context, artistIDs []spotify.ID) ([]spotify.FullArtist, error) {
+	return r.current(ctx).GetArtists(ctx, artistIDs)
+}
+
+func (r *SessionSpotifyRepository) GetTrack(ctx context.Context, trackID spotify.ID) (*spotify.FullTrack, error) {
+	return r.current(ctx).GetTrack(ctx, trackID)
+}
+
+func (r *SessionSpotifyRepository) SearchTracks(ctx context.Context, query string, limit int) ([]spotify.FullTrack, error) {
+	return r.current(ctx).SearchTracks(ctx, query, limit)
+}
+
+// Ensure SessionSpotifyRepository implements SpotifyRepository interface
+var _ shared.SpotifyRepository = (*SessionSpotifyRepository)(nil)
@@ -0,0 +1,260 @@
+package spotify
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// TransportConfig controls retries and throttling of Spotify API requests
+type TransportConfig struct {
+	// MaxRetries is the number of retries after the first attempt
+	MaxRetries int
+	// BaseBackoff is the delay before the first retry, doubled on each attempt
+	BaseBackoff time.Duration
+	// MaxBackoff caps the delay between attempts, including Retry-After
+	MaxBackoff time.Duration
+	// RequestsPerSecond is the global request budget shared by every session; 0 disables it
+	RequestsPerSecond float64
+}
+
+// TransportConfigFromEnv reads SPOTIFY_MAX_RETRIES and SPOTIFY_MAX_RPS, falling back to defaults
+func TransportConfigFromEnv() TransportConfig {
+	cfg := TransportConfig{
+		MaxRetries:        5,
+		BaseBackoff:       500 * time.Millisecond,
+		MaxBackoff:        30 * time.Second,
+		RequestsPerSecond: 10,
+	}
+
+	if value, err := strconv.Atoi(os.Getenv("SPOTIFY_MAX_RETRIES")); err == nil && value >= 0 {
+		cfg.MaxRetries = value
+	}
+	if value, err := strconv.ParseFloat(os.Getenv("SPOTIFY_MAX_RPS"), 64); err == nil && value >= 0 {
+		cfg.RequestsPerSecond = value
+	}
+
+	return cfg
+}
+
+// RateLimitTransport is an http.RoundTripper that honours Retry-After on 429,
+// retries idempotent requests with jittered exponential backoff on 5xx and network
+// errors, and spaces out requests to stay within a global budget
+type RateLimitTransport struct {
+	base   http.RoundTripper
+	cfg    TransportConfig
+	budget *requestBudget
+
+	// sleep waits for d or until ctx is done; replaced in tests
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewRateLimitTransport wraps base (http.DefaultTransport if nil)
+func NewRateLimitTransport(base http.RoundTripper, cfg TransportConfig) *RateLimitTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &RateLimitTransport{
+		base:   base,
+		cfg:    cfg,
+		budget: newRequestBudget(cfg.RequestsPerSecond),
+		sleep:  sleepContext,
+	}
+}
+
+// newBaseTransport returns the transport used for Spotify requests, redirected to
+// SPOTIFY_BASE_URL when set. HTTP/2 is disabled, see https://github.com/zmb3/spotify/issues/20
+func newBaseTransport() http.RoundTripper {
+	var transport http.RoundTripper = &http.Transport{
+		Proxy:        http.ProxyFromEnvironment,
+		TLSNextProto: map[string]func(authority string, c *tls.Conn) http.RoundTripper{},
+	}
+
+	if target := baseURLFromEnv(); target != nil {
+		transport = NewRewriteTransport(transport, target)
+	}
+	return transport
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	for attempt := 0; ; attempt++ {
+		if err := t.sleep(ctx, t.budget.reserve()); err != nil {
+			return nil, err
+		}
+
+		attemptReq, err := rewindRequest(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := t.base.RoundTrip(attemptReq)
+
+		wait, retry := t.retryDelay(req, resp, err, attempt)
+		if !retry {
+			return resp, err
+		}
+
+		if resp != nil {
+			log.Printf("Spotify API returned %d for %s %s, retrying in %s", resp.StatusCode, req.Method, req.URL.Path, wait)
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		} else {
+			log.Printf("Spotify API request %s %s failed: %v, retrying in %s", req.Method, req.URL.Path, err, wait)
+		}
+
+		if err := t.sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// retryDelay decides whether an attempt should be retried and how long to wait first
+func (t *RateLimitTransport) retryDelay(req *http.Request, resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	if attempt >= t.cfg.MaxRetries || req.Context().Err() != nil {
+		return 0, false
+	}
+	if attempt > 0 && req.Body != nil && req.GetBody == nil {
+		return 0, false // Body cannot be replayed
+	}
+
+	if err != nil {
+		return t.backoff(attempt), isIdempotent(req.Method)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		// The request was rejected before being processed, so any method is safe to retry.
+		// Every session shares the app's rate limit, so the whole budget pauses.
+		wait, ok := retryAfter(resp)
+		if !ok {
+			wait = t.backoff(attempt)
+		}
+		wait = min(wait, t.cfg.MaxBackoff)
+		t.budget.pause(wait)
+		return wait, true
+	case resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented:
+		if !isIdempotent(req.Method) {
+			return 0, false
+		}
+		if wait, ok := retryAfter(resp); ok {
+			return min(wait, t.cfg.MaxBackoff), true
+		}
+		return t.backoff(attempt), true
+	}
+
+	return 0, false
+}
+
+// backoff returns a full-jitter exponential delay for the given attempt
+func (t *RateLimitTransport) backoff(attempt int) time.Duration {
+	if t.cfg.BaseBackoff <= 0 {
+		return 0
+	}
+
+	ceiling := t.cfg.BaseBackoff << attempt
+	if ceiling <= 0 || ceiling > t.cfg.MaxBackoff {
+		ceiling = t.cfg.MaxBackoff
+	}
+
+	return ceiling/2 + time.Duration(rand.Int63n(int64(ceiling/2)+1))
+}
+
+// rewindRequest returns the request to send for an attempt, with a fresh body on retries
+func rewindRequest(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 0 || req.Body == nil || req.GetBody == nil {
+		return req, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+
+	clone := req.Clone(req.Context())
+	clone.Body = body
+	return clone, nil
+}
+
+// retryAfter parses the Retry-After header, which Spotify sends in seconds
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// requestBudget spaces requests evenly so that at most rps are sent per second
+type requestBudget struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRequestBudget(rps float64) *requestBudget {
+	budget := &requestBudget{}
+	if rps > 0 {
+		budget.interval = time.Duration(float64(time.Second) / rps)
+	}
+	return budget
+}
+
+// reserve books the next request slot and returns how long to wait for it
+func (b *requestBudget) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	if b.next.Before(now) {
+		b.next = now
+	}
+
+	wait := b.next.Sub(now)
+	b.next = b.next.Add(b.interval)
+	return wait
+}
+
+// pause holds every request for at least d
+func (b *requestBudget) pause(d time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if until := time.Now().Add(d); until.After(b.next) {
+		b.next = until
+	}
+}
@@ -0,0 +1,145 @@
+package spotify
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestTransport returns a transport that records waits instead of sleeping
+func newTestTransport(cfg TransportConfig) (*RateLimitTransport, *[]time.Duration) {
+	var mu sync.Mutex
+	waits := []time.Duration{}
+
+	transport := NewRateLimitTransport(http.DefaultTransport, cfg)
+	transport.sleep = func(ctx context.Context, d time.Duration) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if d > 0 {
+			waits = append(waits, d)
+		}
+		return ctx.Err()
+	}
+	return transport, &waits
+}
+
+// newFlakyServer fails the first failures requests with status, then succeeds
+func newFlakyServer(failures int32, status int, headers map[string]string) (*httptest.Server, *int32) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= failures {
+			for key, value := range headers {
+				w.Header().Set(key, value)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	return server, &calls
+}
+
+func TestRateLimitTransport(t *testing.T) {
+	cfg := TransportConfig{
+		MaxRetries:  3,
+		BaseBackoff: 100 * time.Millisecond,
+		MaxBackoff:  5 * time.Second,
+	}
+
+	t.Run("Success - 429 should wait for Retry-After and retry any method", func(t *testing.T) {
+		server, calls := newFlakyServer(1, http.StatusTooManyRequests, map[string]string{"Retry-After": "2"})
+		defer server.Close()
+
+		transport, waits := newTestTransport(cfg)
+		client := &http.Client{Transport: transport}
+
+		resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{"uris":[]}`))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+		require.NotEmpty(t, *waits)
+		assert.Equal(t, 2*time.Second, (*waits)[0])
+	})
+
+	t.Run("Success - 5xx should be retried with backoff for idempotent requests", func(t *testing.T) {
+		server, calls := newFlakyServer(2, http.StatusBadGateway, nil)
+		defer server.Close()
+
+		transport, waits := newTestTransport(cfg)
+		client := &http.Client{Transport: transport}
+
+		req, _ := http.NewRequest(http.MethodDelete, server.URL, nil)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, int32(3), atomic.LoadInt32(calls))
+		require.Len(t, *waits, 2)
+		assert.GreaterOrEqual(t, (*waits)[0], 50*time.Millisecond)
+		assert.LessOrEqual(t, (*waits)[0], 100*time.Millisecond)
+		assert.GreaterOrEqual(t, (*waits)[1], 100*time.Millisecond)
+		assert.LessOrEqual(t, (*waits)[1], 200*time.Millisecond)
+	})
+
+	t.Run("Error - 5xx should not be retried for non-idempotent requests", func(t *testing.T) {
+		server, calls := newFlakyServer(1, http.StatusInternalServerError, nil)
+		defer server.Close()
+
+		transport, _ := newTestTransport(cfg)
+		client := &http.Client{Transport: transport}
+
+		resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{}`))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+	})
+
+	t.Run("Error - should give up after MaxRetries", func(t *testing.T) {
+		server, calls := newFlakyServer(10, http.StatusServiceUnavailable, nil)
+		defer server.Close()
+
+		transport, _ := newTestTransport(cfg)
+		client := &http.Client{Transport: transport}
+
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, int32(4), atomic.LoadInt32(calls))
+	})
+
+	t.Run("Success - requests should be spaced to respect the budget", func(t *testing.T) {
+		server, _ := newFlakyServer(0, http.StatusOK, nil)
+		defer server.Close()
+
+		budgetCfg := cfg
+		budgetCfg.RequestsPerSecond = 10
+		transport, waits := newTestTransport(budgetCfg)
+		client := &http.Client{Transport: transport}
+
+		for i := 0; i < 3; i++ {
+			resp, err := client.Get(server.URL)
+			require.NoError(t, err)
+			resp.Body.Close()
+		}
+
+		// The first request goes out immediately, the next ones wait for their slot
+		require.Len(t, *waits, 2)
+		assert.InDelta(t, float64(100*time.Millisecond), float64((*waits)[0]), float64(20*time.Millisecond))
+		assert.InDelta(t, float64(200*time.Millisecond), float64((*waits)[1]), float64(20*time.Millisecond))
+	})
+}
@@ -0,0 +1,94 @@
+// Package persistence opens the database selected by DB_DRIVER, either Postgres (see the
+// postgres package) or an embedded SQLite file (see the sqlite package), and migrates it.
+// The GORM repositories of the postgres package work on top of both.
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/RubenPari/clear-songs/internal/infrastructure/persistence/migrations"
+	"github.com/RubenPari/clear-songs/internal/infrastructure/persistence/postgres"
+	"github.com/RubenPari/clear-songs/internal/infrastructure/persistence/sqlite"
+	"gorm.io/gorm"
+)
+
+// Init connects to the configured database, applies its pending migrations and sets
+// postgres.Db to it.
+//
+// A missing or unreachable database is logged and the application continues without
+// it. A failing migration is returned as an error instead: running against a schema
+// the code does not expect is never safe.
+func Init() error {
+	db, err := Connect()
+	if err != nil {
+		log.Printf("WARNING: %v", err)
+		log.Println("WARNING: Application will continue without database. Backup functionality will be disabled.")
+		return nil // Return nil to allow application to continue without database
+	}
+	if db == nil {
+		return nil
+	}
+
+	if err := migrate(db); err != nil {
+		if sqlDB, errDB := db.DB(); errDB == nil {
+			_ = sqlDB.Close()
+		}
+		return fmt.Errorf("database migration failed: %w", err)
+	}
+
+	postgres.Db = db
+
+	log.Println("Successfully connected to database with pooling configured!")
+
+	return nil
+}
+
+// Connect opens the database selected by DB_DRIVER without migrating it. It returns a
+// nil database, after logging why, when the Postgres credentials are not set.
+func Connect() (*gorm.DB, error) {
+	switch driver := os.Getenv("DB_DRIVER"); driver {
+	case "", "postgres":
+		return postgres.Connect()
+	case "sqlite":
+		// DB_DRIVER=sqlite keeps the data in a local file instead
+		path := sqlite.PathFromEnv()
+		db, err := sqlite.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("SQLite database initialization failed: %w", err)
+		}
+		log.Printf("Opened SQLite database %s", path)
+		return db, nil
+	default:
+		return nil, fmt.Errorf("unknown DB_DRIVER %q, expected postgres or sqlite", driver)
+	}
+}
+
+// migrate applies the pending migrations, or only reports them when
+// DB_AUTO_MIGRATE=false leaves migrating to the migrate subcommand
+func migrate(db *gorm.DB) error {
+	migrator, err := migrations.New(db)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if os.Getenv("DB_AUTO_MIGRATE") == "false" {
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			log.Printf("WARNING: %d database migrations are pending, run the migrate up subcommand", len(pending))
+		}
+		return nil
+	}
+
+	applied, err := migrator.Up(ctx)
+	for _, migration := range applied {
+		log.Printf("Applied database migration %04d_%s", migration.Version, migration.Name)
+	}
+	return err
+}
@@ -0,0 +1,230 @@
+package migrations_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/RubenPari/clear-songs/internal/domain/backup"
+	"github.com/RubenPari/clear-songs/internal/infrastructure/persistence/migrations"
+	"github.com/RubenPari/clear-songs/internal/infrastructure/persistence/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func openTestDB(t *testing.T) *gorm.DB {
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "clear-songs.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB, _ := db.DB()
+		_ = sqlDB.Close()
+	})
+	return db
+}
+
+func versions(migrations []migrations.Migration) []int {
+	result := make([]int, len(migrations))
+	for i, migration := range migrations {
+		result[i] = migration.Version
+	}
+	return result
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("Success - should load the same versions for every driver", func(t *testing.T) {
+		onPostgres, err := migrations.Load("postgres")
+		require.NoError(t, err)
+		onSQLite, err := migrations.Load("sqlite")
+		require.NoError(t, err)
+
+		require.NotEmpty(t, onPostgres)
+		assert.Equal(t, versions(onPostgres), versions(onSQLite))
+		for i := range onPostgres {
+			assert.Equal(t, onPostgres[i].Name, onSQLite[i].Name)
+			assert.Equal(t, i+1, onPostgres[i].Version)
+		}
+	})
+
+	t.Run("Error - should reject an unknown driver", func(t *testing.T) {
+		_, err := migrations.Load("mysql")
+		assert.Error(t, err)
+	})
+}
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success - should apply every migration once", func(t *testing.T) {
+		db := openTestDB(t)
+		migrator, err := migrations.New(db)
+		require.NoError(t, err)
+
+		applied, err := migrator.Up(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9}, versions(applied))
+		assert.True(t, db.Migrator().HasTable("track_backups"))
+		assert.False(t, db.Migrator().HasTable("track_dbs"))
+
+		applied, err = migrator.Up(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, applied)
+
+		statuses, err := migrator.Status(ctx)
+		require.NoError(t, err)
+		for _, status := range statuses {
+			assert.NotNil(t, status.AppliedAt, status.Name)
+		}
+	})
+
+	t.Run("Success - should revert and reapply the latest migrations", func(t *testing.T) {
+		db := openTestDB(t)
+		migrator, err := migrations.New(db)
+		require.NoError(t, err)
+		_, err = migrator.Up(ctx)
+		require.NoError(t, err)
+
+		reverted, err := migrator.Down(ctx, 8)
+		require.NoError(t, err)
+		assert.Equal(t, []int{9, 8, 7, 6, 5, 4, 3, 2}, versions(reverted))
+		assert.False(t, db.Migrator().HasTable("track_backups"))
+		assert.False(t, db.Migrator().HasIndex("track_dbs", "idx_track_dbs_id"))
+
+		pending, err := migrator.Pending(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []int{2, 3, 4, 5, 6, 7, 8, 9}, versions(pending))
+
+		applied, err := migrator.Up(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []int{2, 3, 4, 5, 6, 7, 8, 9}, versions(applied))
+	})
+
+	t.Run("Success - should revert everything down to an empty database", func(t *testing.T) {
+		db := openTestDB(t)
+		migrator, err := migrations.New(db)
+		require.NoError(t, err)
+		_, err = migrator.Up(ctx)
+		require.NoError(t, err)
+
+		reverted, err := migrator.Down(ctx, 10)
+		require.NoError(t, err)
+		assert.Len(t, reverted, 9)
+		assert.False(t, db.Migrator().HasTable("users"))
+		assert.False(t, db.Migrator().HasTable("track_dbs"))
+	})
+
+	t.Run("Success - should adopt a database created before versioned migrations", func(t *testing.T) {
+		db := openTestDB(t)
+		all, err := migrations.Load("sqlite")
+		require.NoError(t, err)
+		require.NoError(t, db.Exec(all[0].Up).Error)
+		require.NoError(t, db.Exec(
+			`INSERT INTO track_dbs (id, name, artist, album, uri, url) VALUES ('track-1', 'Song', 'Artist', 'Album', 'uri', 'url')`,
+		).Error)
+
+		migrator, err := migrations.New(db)
+		require.NoError(t, err)
+		applied, err := migrator.Up(ctx)
+
+		require.NoError(t, err)
+		assert.Len(t, applied, 9)
+		var backups []struct {
+			TrackID string
+			Owner   string
+			Artists string
+		}
+		require.NoError(t, db.Table("track_backups").Find(&backups).Error)
+		require.Len(t, backups, 1)
+		assert.Equal(t, "track-1", backups[0].TrackID)
+		assert.Equal(t, backup.LegacyOwner, backups[0].Owner)
+		assert.JSONEq(t, `[{"name":"Artist"}]`, backups[0].Artists)
+	})
+
+	t.Run("Success - should keep every copy of a track stored twice before versioned migrations", func(t *testing.T) {
+		db := openTestDB(t)
+		// Like the Postgres table of earlier releases, without a primary key
+		require.NoError(t, db.Exec(`CREATE TABLE track_dbs (
+			id varchar(100) NOT NULL, created_at datetime, updated_at datetime, deleted_at datetime,
+			name varchar(100) NOT NULL, artist varchar(100) NOT NULL, album varchar(100) NOT NULL,
+			uri varchar(200) NOT NULL, url varchar(200) NOT NULL, restored_at datetime
+		)`).Error)
+		for _, name := range []string{"First", "Second"} {
+			require.NoError(t, db.Exec(
+				`INSERT INTO track_dbs (id, name, artist, album, uri, url) VALUES ('track-1', ?, 'Artist', 'Album', 'uri', 'url')`, name,
+			).Error)
+		}
+
+		migrator, err := migrations.New(db)
+		require.NoError(t, err)
+		_, err = migrator.Up(ctx)
+
+		require.NoError(t, err)
+		var names []string
+		require.NoError(t, db.Table("track_backups").Where("track_id = ?", "track-1").Order("name").Pluck("name", &names).Error)
+		assert.Equal(t, []string{"First", "Second"}, names)
+		assert.False(t, db.Migrator().HasTable("track_dbs_duplicates"))
+	})
+
+	t.Run("Success - should carry legacy backups over when reverting track_backups", func(t *testing.T) {
+		db := openTestDB(t)
+		migrator, err := migrations.New(db)
+		require.NoError(t, err)
+		_, err = migrator.Up(ctx)
+		require.NoError(t, err)
+		require.NoError(t, db.Exec(
+			`INSERT INTO track_backups (id, owner, track_id, name, artist, created_at) VALUES ('backup-1', ?, 'track-1', 'Song', 'Artist', ?)`,
+			backup.LegacyOwner, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		).Error)
+
+		_, err = migrator.Down(ctx, 6)
+
+		require.NoError(t, err)
+		var names []string
+		require.NoError(t, db.Table("track_dbs").Pluck("name", &names).Error)
+		assert.Equal(t, []string{"Song"}, names)
+	})
+
+	t.Run("Error - should refuse to revert track_backups when backups would be lost", func(t *testing.T) {
+		for name, insert := range map[string]string{
+			"owned backup": `INSERT INTO track_backups (id, owner, track_id, name, artist, created_at) VALUES ('backup-1', 'spotify:user_1', 'track-1', 'Song', 'Artist', ?)`,
+			"second backup of a track": `INSERT INTO track_backups (id, track_id, name, artist, created_at)
+				SELECT 'backup-' || n, 'track-1', 'Song', 'Artist', ? FROM (SELECT 1 AS n UNION SELECT 2) AS numbers`,
+		} {
+			t.Run(name, func(t *testing.T) {
+				db := openTestDB(t)
+				migrator, err := migrations.New(db)
+				require.NoError(t, err)
+				_, err = migrator.Up(ctx)
+				require.NoError(t, err)
+				require.NoError(t, db.Exec(insert, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)).Error)
+
+				_, err = migrator.Down(ctx, 6)
+
+				assert.ErrorContains(t, err, "rollback_would_delete_track_backups")
+				assert.True(t, db.Migrator().HasTable("track_backups"))
+				var count int64
+				require.NoError(t, db.Table("track_backups").Count(&count).Error)
+				assert.NotZero(t, count)
+				pending, err := migrator.Pending(ctx)
+				require.NoError(t, err)
+				assert.NotContains(t, versions(pending), 4)
+			})
+		}
+	})
+
+	t.Run("Error - should refuse a database migrated by a newer version", func(t *testing.T) {
+		db := openTestDB(t)
+		migrator, err := migrations.New(db)
+		require.NoError(t, err)
+		_, err = migrator.Up(ctx)
+		require.NoError(t, err)
+		require.NoError(t, db.Exec(
+			`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`, 99, "future", time.Now(),
+		).Error)
+
+		_, err = migrator.Up(ctx)
+
+		assert.ErrorContains(t, err, "newer")
+	})
+}
@@ -0,0 +1,164 @@
+-- The schema previously created by GORM's AutoMigrate. Every statement is guarded so
+-- databases created before versioned migrations adopt this version unchanged.
+
+CREATE TABLE IF NOT EXISTS users (
+    id uuid DEFAULT gen_random_uuid(),
+    email text NOT NULL,
+    password_hash text NOT NULL,
+    is_verified boolean DEFAULT false,
+    spotify_id text,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_spotify_id ON users (spotify_id);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS verification_tokens (
+    id uuid DEFAULT gen_random_uuid(),
+    user_id uuid NOT NULL,
+    token text NOT NULL,
+    expires_at timestamptz NOT NULL,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_verification_tokens_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_verification_tokens_user_id ON verification_tokens (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_verification_tokens_token ON verification_tokens (token);
+
+CREATE TABLE IF NOT EXISTS reset_tokens (
+    id uuid DEFAULT gen_random_uuid(),
+    user_id uuid NOT NULL,
+    token text NOT NULL,
+    expires_at timestamptz NOT NULL,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_reset_tokens_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_reset_tokens_user_id ON reset_tokens (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reset_tokens_token ON reset_tokens (token);
+
+CREATE TABLE IF NOT EXISTS track_dbs (
+    id varchar(100) NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    name varchar(100) NOT NULL,
+    artist varchar(100) NOT NULL,
+    album varchar(100) NOT NULL,
+    uri varchar(200) NOT NULL,
+    url varchar(200) NOT NULL,
+    restored_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_track_dbs_deleted_at ON track_dbs (deleted_at);
+
+CREATE TABLE IF NOT EXISTS jobs (
+    id varchar(36),
+    type varchar(100) NOT NULL,
+    owner varchar(200) NOT NULL,
+    status varchar(20) NOT NULL,
+    tracks_total bigint NOT NULL DEFAULT 0,
+    tracks_processed bigint NOT NULL DEFAULT 0,
+    batches_done bigint NOT NULL DEFAULT 0,
+    errors text,
+    created_at timestamptz,
+    started_at timestamptz,
+    finished_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_jobs_status ON jobs (status);
+CREATE INDEX IF NOT EXISTS idx_jobs_owner ON jobs (owner);
+
+CREATE TABLE IF NOT EXISTS operations (
+    id varchar(36),
+    kind varchar(100) NOT NULL,
+    owner varchar(200) NOT NULL,
+    params text,
+    library_track_ids text,
+    playlist_id varchar(100),
+    playlist_tracks text,
+    created_at timestamptz,
+    undone_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_operations_created_at ON operations (created_at);
+CREATE INDEX IF NOT EXISTS idx_operations_owner ON operations (owner);
+
+CREATE TABLE IF NOT EXISTS rules (
+    id varchar(36),
+    owner varchar(200) NOT NULL,
+    name varchar(200) NOT NULL,
+    criteria text,
+    schedule varchar(100) NOT NULL,
+    enabled boolean,
+    dry_run boolean,
+    webhook_url varchar(500),
+    last_run_at timestamptz,
+    last_match_count bigint,
+    last_error text,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_rules_enabled ON rules (enabled);
+CREATE INDEX IF NOT EXISTS idx_rules_owner ON rules (owner);
+
+CREATE TABLE IF NOT EXISTS playlist_snapshots (
+    id varchar(36),
+    owner varchar(200) NOT NULL,
+    playlist_id varchar(100) NOT NULL,
+    spotify_snapshot_id varchar(200),
+    name varchar(500),
+    description text,
+    image_url varchar(500),
+    reason varchar(100),
+    track_count bigint NOT NULL DEFAULT 0,
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_playlist_snapshots_created_at ON playlist_snapshots (created_at);
+CREATE INDEX IF NOT EXISTS idx_playlist_snapshots_owner_playlist ON playlist_snapshots (owner, playlist_id);
+
+CREATE TABLE IF NOT EXISTS playlist_snapshot_tracks (
+    snapshot_id varchar(36),
+    position bigint,
+    track_id varchar(200) NOT NULL,
+    PRIMARY KEY (snapshot_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS library_snapshots (
+    id varchar(36),
+    owner varchar(200) NOT NULL,
+    reason varchar(100),
+    track_count bigint NOT NULL DEFAULT 0,
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_library_snapshots_created_at ON library_snapshots (created_at);
+CREATE INDEX IF NOT EXISTS idx_library_snapshots_owner ON library_snapshots (owner);
+
+CREATE TABLE IF NOT EXISTS library_snapshot_tracks (
+    snapshot_id varchar(36),
+    position bigint,
+    track_id varchar(100) NOT NULL,
+    added_at timestamptz,
+    PRIMARY KEY (snapshot_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS library_tracks (
+    owner varchar(200),
+    track_id varchar(100),
+    added_at timestamptz,
+    track text,
+    PRIMARY KEY (owner, track_id)
+);
+CREATE INDEX IF NOT EXISTS idx_library_tracks_added_at ON library_tracks (added_at);
+
+CREATE TABLE IF NOT EXISTS library_sync_states (
+    owner varchar(200),
+    last_synced_at timestamptz,
+    last_full_sync_at timestamptz,
+    PRIMARY KEY (owner)
+);
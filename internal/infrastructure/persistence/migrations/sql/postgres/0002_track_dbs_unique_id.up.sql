@@ -0,0 +1,9 @@
+-- Backups are looked up and restored by Spotify track ID, so each track must be
+-- stored once. The first stored copy of a duplicated track stays in track_dbs; the
+-- other copies are moved to track_dbs_duplicates instead of being deleted, and are
+-- carried over to track_backups by 0004.
+CREATE TABLE IF NOT EXISTS track_dbs_duplicates AS SELECT * FROM track_dbs WHERE 1 = 0;
+INSERT INTO track_dbs_duplicates
+SELECT a.* FROM track_dbs a WHERE EXISTS (SELECT 1 FROM track_dbs b WHERE b.id = a.id AND b.ctid < a.ctid);
+DELETE FROM track_dbs a USING track_dbs b WHERE a.id = b.id AND a.ctid > b.ctid;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_track_dbs_id ON track_dbs (id);
@@ -0,0 +1,5 @@
+-- Names longer than the old limit are cut to fit
+ALTER TABLE track_dbs
+    ALTER COLUMN name TYPE varchar(100) USING left(name, 100),
+    ALTER COLUMN artist TYPE varchar(100) USING left(artist, 100),
+    ALTER COLUMN album TYPE varchar(100) USING left(album, 100);
@@ -0,0 +1,5 @@
+-- varchar(100) rejected long track, artist and album names
+ALTER TABLE track_dbs
+    ALTER COLUMN name TYPE varchar(500),
+    ALTER COLUMN artist TYPE varchar(500),
+    ALTER COLUMN album TYPE varchar(500);
@@ -0,0 +1,8 @@
+-- The Spotify refresh token of each owner, from which scheduled rule runs and library
+-- snapshots mint access tokens once the owner's session and cached token are gone.
+CREATE TABLE IF NOT EXISTS spotify_credentials (
+    owner varchar(200),
+    refresh_token text NOT NULL,
+    updated_at timestamptz,
+    PRIMARY KEY (owner)
+);
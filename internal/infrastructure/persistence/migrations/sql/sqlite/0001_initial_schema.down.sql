@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS library_sync_states;
+DROP TABLE IF EXISTS library_tracks;
+DROP TABLE IF EXISTS library_snapshot_tracks;
+DROP TABLE IF EXISTS library_snapshots;
+DROP TABLE IF EXISTS playlist_snapshot_tracks;
+DROP TABLE IF EXISTS playlist_snapshots;
+DROP TABLE IF EXISTS rules;
+DROP TABLE IF EXISTS operations;
+DROP TABLE IF EXISTS jobs;
+DROP TABLE IF EXISTS track_dbs;
+DROP TABLE IF EXISTS reset_tokens;
+DROP TABLE IF EXISTS verification_tokens;
+DROP TABLE IF EXISTS users;
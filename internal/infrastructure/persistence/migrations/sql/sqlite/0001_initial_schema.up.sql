@@ -0,0 +1,160 @@
+-- The schema previously created by sqlite.Migrate. Every statement is guarded so
+-- databases created before versioned migrations adopt this version unchanged.
+
+CREATE TABLE IF NOT EXISTS users (
+    id TEXT PRIMARY KEY,
+    email TEXT NOT NULL,
+    password_hash TEXT NOT NULL,
+    is_verified NUMERIC DEFAULT false,
+    spotify_id TEXT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_spotify_id ON users (spotify_id);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS verification_tokens (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users (id),
+    token TEXT NOT NULL,
+    expires_at DATETIME NOT NULL,
+    created_at DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_verification_tokens_user_id ON verification_tokens (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_verification_tokens_token ON verification_tokens (token);
+
+CREATE TABLE IF NOT EXISTS reset_tokens (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users (id),
+    token TEXT NOT NULL,
+    expires_at DATETIME NOT NULL,
+    created_at DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_reset_tokens_user_id ON reset_tokens (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reset_tokens_token ON reset_tokens (token);
+
+CREATE TABLE IF NOT EXISTS track_dbs (
+    id varchar(100) NOT NULL,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    name varchar(100) NOT NULL,
+    artist varchar(100) NOT NULL,
+    album varchar(100) NOT NULL,
+    uri varchar(200) NOT NULL,
+    url varchar(200) NOT NULL,
+    restored_at datetime,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_track_dbs_deleted_at ON track_dbs (deleted_at);
+
+CREATE TABLE IF NOT EXISTS jobs (
+    id varchar(36),
+    type varchar(100) NOT NULL,
+    owner varchar(200) NOT NULL,
+    status varchar(20) NOT NULL,
+    tracks_total integer NOT NULL DEFAULT 0,
+    tracks_processed integer NOT NULL DEFAULT 0,
+    batches_done integer NOT NULL DEFAULT 0,
+    errors text,
+    created_at datetime,
+    started_at datetime,
+    finished_at datetime,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_jobs_status ON jobs (status);
+CREATE INDEX IF NOT EXISTS idx_jobs_owner ON jobs (owner);
+
+CREATE TABLE IF NOT EXISTS operations (
+    id varchar(36),
+    kind varchar(100) NOT NULL,
+    owner varchar(200) NOT NULL,
+    params text,
+    library_track_ids text,
+    playlist_id varchar(100),
+    playlist_tracks text,
+    created_at datetime,
+    undone_at datetime,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_operations_created_at ON operations (created_at);
+CREATE INDEX IF NOT EXISTS idx_operations_owner ON operations (owner);
+
+CREATE TABLE IF NOT EXISTS rules (
+    id varchar(36),
+    owner varchar(200) NOT NULL,
+    name varchar(200) NOT NULL,
+    criteria text,
+    schedule varchar(100) NOT NULL,
+    enabled numeric,
+    dry_run numeric,
+    webhook_url varchar(500),
+    last_run_at datetime,
+    last_match_count integer,
+    last_error text,
+    created_at datetime,
+    updated_at datetime,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_rules_enabled ON rules (enabled);
+CREATE INDEX IF NOT EXISTS idx_rules_owner ON rules (owner);
+
+CREATE TABLE IF NOT EXISTS playlist_snapshots (
+    id varchar(36),
+    owner varchar(200) NOT NULL,
+    playlist_id varchar(100) NOT NULL,
+    spotify_snapshot_id varchar(200),
+    name varchar(500),
+    description text,
+    image_url varchar(500),
+    reason varchar(100),
+    track_count integer NOT NULL DEFAULT 0,
+    created_at datetime,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_playlist_snapshots_created_at ON playlist_snapshots (created_at);
+CREATE INDEX IF NOT EXISTS idx_playlist_snapshots_owner_playlist ON playlist_snapshots (owner, playlist_id);
+
+CREATE TABLE IF NOT EXISTS playlist_snapshot_tracks (
+    snapshot_id varchar(36),
+    position integer,
+    track_id varchar(200) NOT NULL,
+    PRIMARY KEY (snapshot_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS library_snapshots (
+    id varchar(36),
+    owner varchar(200) NOT NULL,
+    reason varchar(100),
+    track_count integer NOT NULL DEFAULT 0,
+    created_at datetime,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_library_snapshots_created_at ON library_snapshots (created_at);
+CREATE INDEX IF NOT EXISTS idx_library_snapshots_owner ON library_snapshots (owner);
+
+CREATE TABLE IF NOT EXISTS library_snapshot_tracks (
+    snapshot_id varchar(36),
+    position integer,
+    track_id varchar(100) NOT NULL,
+    added_at datetime,
+    PRIMARY KEY (snapshot_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS library_tracks (
+    owner varchar(200),
+    track_id varchar(100),
+    added_at datetime,
+    track text,
+    PRIMARY KEY (owner, track_id)
+);
+CREATE INDEX IF NOT EXISTS idx_library_tracks_added_at ON library_tracks (added_at);
+
+CREATE TABLE IF NOT EXISTS library_sync_states (
+    owner varchar(200),
+    last_synced_at datetime,
+    last_full_sync_at datetime,
+    PRIMARY KEY (owner)
+);
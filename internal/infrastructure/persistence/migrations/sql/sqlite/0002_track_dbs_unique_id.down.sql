@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_track_dbs_id;
+INSERT INTO track_dbs SELECT * FROM track_dbs_duplicates;
+DROP TABLE track_dbs_duplicates;
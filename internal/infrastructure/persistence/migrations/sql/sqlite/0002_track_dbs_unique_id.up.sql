@@ -0,0 +1,9 @@
+-- Backups are looked up and restored by Spotify track ID, so each track must be
+-- stored once. The first stored copy of a duplicated track stays in track_dbs; the
+-- other copies are moved to track_dbs_duplicates instead of being deleted, and are
+-- carried over to track_backups by 0004.
+CREATE TABLE IF NOT EXISTS track_dbs_duplicates AS SELECT * FROM track_dbs WHERE 1 = 0;
+INSERT INTO track_dbs_duplicates
+SELECT * FROM track_dbs WHERE rowid NOT IN (SELECT MIN(rowid) FROM track_dbs GROUP BY id);
+DELETE FROM track_dbs WHERE rowid NOT IN (SELECT MIN(rowid) FROM track_dbs GROUP BY id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_track_dbs_id ON track_dbs (id);
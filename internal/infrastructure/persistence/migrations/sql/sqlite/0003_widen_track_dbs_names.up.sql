@@ -0,0 +1,2 @@
+-- SQLite does not enforce varchar lengths, so long names already fit. The version
+-- exists to keep both drivers on the same schema version.
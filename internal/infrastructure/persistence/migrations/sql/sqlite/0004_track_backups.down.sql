@@ -0,0 +1,40 @@
+-- track_dbs keeps one row per track and knows nothing of owners, operations or
+-- playlists. The rollback is refused, by violating the check constraint below, while
+-- any backup could not be carried over as is: restore or export them first, then
+-- delete them explicitly to roll back.
+CREATE TEMPORARY TABLE track_backups_rollback_guard (
+    lossy_backups integer NOT NULL,
+    CONSTRAINT rollback_would_delete_track_backups CHECK (lossy_backups = 0)
+);
+INSERT INTO track_backups_rollback_guard (lossy_backups)
+SELECT COUNT(*) FROM track_backups b
+WHERE b.owner <> '' OR b.operation_id <> '' OR b.reason <> '' OR b.playlist_id <> ''
+   OR b.position IS NOT NULL
+   OR EXISTS (SELECT 1 FROM track_backups n WHERE n.track_id = b.track_id AND n.id <> b.id);
+DROP TABLE track_backups_rollback_guard;
+
+CREATE TABLE track_dbs (
+    id varchar(100) NOT NULL,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    name varchar(100) NOT NULL,
+    artist varchar(100) NOT NULL,
+    album varchar(100) NOT NULL,
+    uri varchar(200) NOT NULL,
+    url varchar(200) NOT NULL,
+    restored_at datetime,
+    PRIMARY KEY (id)
+);
+CREATE INDEX idx_track_dbs_deleted_at ON track_dbs (deleted_at);
+CREATE UNIQUE INDEX idx_track_dbs_id ON track_dbs (id);
+-- Reverting 0002 moves the duplicates kept here back into track_dbs; the guard above
+-- leaves none to keep
+CREATE TABLE track_dbs_duplicates AS SELECT * FROM track_dbs WHERE 1 = 0;
+
+INSERT INTO track_dbs (id, created_at, updated_at, name, artist, album, uri, url, restored_at)
+SELECT b.track_id, b.created_at, b.created_at, b.name, b.artist, COALESCE(b.album, ''),
+       COALESCE(b.uri, ''), COALESCE(b.url, ''), b.restored_at
+FROM track_backups b;
+
+DROP TABLE track_backups;
@@ -0,0 +1,45 @@
+-- One row per removed track and operation, replacing track_dbs, which kept a single
+-- row per track without telling who removed it, how, or from where.
+CREATE TABLE IF NOT EXISTS track_backups (
+    id varchar(36),
+    owner varchar(200) NOT NULL DEFAULT '',
+    operation_id varchar(36) NOT NULL DEFAULT '',
+    reason varchar(100) NOT NULL DEFAULT '',
+    playlist_id varchar(100) NOT NULL DEFAULT '',
+    position integer,
+    track_id varchar(100) NOT NULL,
+    name varchar(500) NOT NULL,
+    artist varchar(1000) NOT NULL,
+    artists text,
+    album varchar(500),
+    album_id varchar(100),
+    isrc varchar(20),
+    duration_ms integer,
+    uri varchar(200),
+    url varchar(200),
+    created_at datetime NOT NULL,
+    restored_at datetime,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_track_backups_owner ON track_backups (owner);
+CREATE INDEX IF NOT EXISTS idx_track_backups_operation_id ON track_backups (operation_id);
+CREATE INDEX IF NOT EXISTS idx_track_backups_track_id ON track_backups (track_id);
+CREATE INDEX IF NOT EXISTS idx_track_backups_created_at ON track_backups (created_at);
+
+-- Earlier backups belong to no user: they have no owner, which 0005 turns into "legacy"
+INSERT INTO track_backups (id, track_id, name, artist, artists, album, uri, url, created_at, restored_at)
+SELECT lower(hex(randomblob(16))), id, name, artist, json_array(json_object('name', artist)),
+       album, uri, url, COALESCE(created_at, CURRENT_TIMESTAMP), restored_at
+FROM track_dbs
+WHERE deleted_at IS NULL;
+
+-- Databases migrated by an earlier 0002 have no duplicates table
+CREATE TABLE IF NOT EXISTS track_dbs_duplicates AS SELECT * FROM track_dbs WHERE 1 = 0;
+INSERT INTO track_backups (id, track_id, name, artist, artists, album, uri, url, created_at, restored_at)
+SELECT lower(hex(randomblob(16))), id, name, artist, json_array(json_object('name', artist)),
+       album, uri, url, COALESCE(created_at, CURRENT_TIMESTAMP), restored_at
+FROM track_dbs_duplicates
+WHERE deleted_at IS NULL;
+
+DROP TABLE track_dbs_duplicates;
+DROP TABLE track_dbs;
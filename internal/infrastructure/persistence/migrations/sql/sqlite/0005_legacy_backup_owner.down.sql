@@ -0,0 +1 @@
+UPDATE track_backups SET owner = '' WHERE owner = 'legacy';
@@ -0,0 +1,5 @@
+-- Backups taken before owners were recorded cannot be attributed to a user. They are
+-- given the "legacy" owner, which no user has, instead of being shared with every
+-- user. A user claims them with POST /backup/legacy/claim: anyone on a single-user
+-- deployment, otherwise only the owners listed in BACKUP_ADMINS.
+UPDATE track_backups SET owner = 'legacy' WHERE owner = '';
@@ -0,0 +1,3 @@
+-- How many removed playlist tracks an interrupted undo already re-inserted, so a retry
+-- resumes after them instead of inserting them twice
+ALTER TABLE operations ADD COLUMN playlist_tracks_restored integer NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+-- When an undo of the operation started, so two concurrent undos cannot both re-insert
+-- the removed tracks. Cleared again when the undo fails.
+ALTER TABLE operations ADD COLUMN undo_started_at datetime;
@@ -0,0 +1 @@
+ALTER TABLE operations DROP COLUMN playlist_tracks_added;
@@ -0,0 +1,3 @@
+-- Tracks an operation inserted in a playlist in place of removed ones (the version kept
+-- by a merge of duplicates), so an undo can remove them again
+ALTER TABLE operations ADD COLUMN playlist_tracks_added text;
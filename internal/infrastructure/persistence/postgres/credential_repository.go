@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/RubenPari/clear-songs/internal/domain/auth"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	"github.com/RubenPari/clear-songs/internal/infrastructure/persistence/postgres/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type credentialRepository struct {
+	db *gorm.DB
+}
+
+// NewCredentialRepository creates a Spotify credential repository backed by Postgres.
+// If db is nil, credentials are kept in memory and lost on restart.
+func NewCredentialRepository(db *gorm.DB) auth.CredentialRepository {
+	if db == nil {
+		return &memoryCredentialRepository{credentials: make(map[string]auth.SpotifyCredential)}
+	}
+	return &credentialRepository{db: db}
+}
+
+func (r *credentialRepository) Save(ctx context.Context, credential *auth.SpotifyCredential) error {
+	row := &models.SpotifyCredentialDB{
+		Owner:        credential.Owner,
+		RefreshToken: credential.RefreshToken,
+	}
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "owner"}},
+			DoUpdates: clause.AssignmentColumns([]string{"refresh_token", "updated_at"}),
+		}).
+		Create(row).Error
+	if err != nil {
+		return err
+	}
+
+	credential.UpdatedAt = row.UpdatedAt
+	return nil
+}
+
+func (r *credentialRepository) Get(ctx context.Context, owner string) (*auth.SpotifyCredential, error) {
+	var row models.SpotifyCredentialDB
+	if err := r.db.WithContext(ctx).First(&row, "owner = ?", owner).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, shared.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &auth.SpotifyCredential{
+		Owner:        row.Owner,
+		RefreshToken: row.RefreshToken,
+		UpdatedAt:    row.UpdatedAt,
+	}, nil
+}
+
+func (r *credentialRepository) ListOwners(ctx context.Context) ([]string, error) {
+	var owners []string
+	if err := r.db.WithContext(ctx).Model(&models.SpotifyCredentialDB{}).Order("owner").Pluck("owner", &owners).Error; err != nil {
+		return nil, err
+	}
+	return owners, nil
+}
+
+// memoryCredentialRepository keeps credentials in memory when the database is not available
+type memoryCredentialRepository struct {
+	mu          sync.RWMutex
+	credentials map[string]auth.SpotifyCredential
+}
+
+func (r *memoryCredentialRepository) Save(ctx context.Context, credential *auth.SpotifyCredential) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	credential.UpdatedAt = time.Now()
+	r.credentials[credential.Owner] = *credential
+	return nil
+}
+
+func (r *memoryCredentialRepository) Get(ctx context.Context, owner string) (*auth.SpotifyCredential, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	credential, exists := r.credentials[owner]
+	if !exists {
+		return nil, shared.ErrNotFound
+	}
+	return &credential, nil
+}
+
+func (r *memoryCredentialRepository) ListOwners(ctx context.Context) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	owners := make([]string, 0, len(r.credentials))
+	for owner := range r.credentials {
+		owners = append(owners, owner)
+	}
+	sort.Strings(owners)
+	return owners, nil
+}
@@ -6,13 +6,23 @@
  *
  * The package provides:
  * - Database connection management
- * - Automatic schema migration
+ * - Versioned schema migration on startup
  * - Global database instance for use throughout the application
  *
  * Database Schema:
- * The package uses GORM's AutoMigrate feature to automatically create and
- * update database tables based on model definitions. Currently manages:
- * - TrackDB model: Stores track metadata and user library information
+ * The tables are created and evolved by the ordered SQL scripts of the migrations
+ * package, recorded in the schema_migrations table. Currently manages:
+ * - TrackBackupDB model: Stores the backups of removed tracks, per user and operation
+ * - SpotifyCredentialDB model: Stores the Spotify refresh token of each user for scheduled runs
+ * - JobDB model: Stores the state and progress of background deletion jobs
+ * - OperationDB model: Stores destructive operations so they can be undone
+ * - RuleDB model: Stores scheduled cleanup rules
+ * - PlaylistSnapshotDB and PlaylistSnapshotTrackDB models: Store playlist history
+ * - LibrarySnapshotDB and LibrarySnapshotTrackDB models: Store library history
+ * - LibraryTrackDB and LibrarySyncStateDB models: Store the local mirror of saved libraries
+ *
+ * Pending migrations are applied by persistence.Init unless DB_AUTO_MIGRATE=false,
+ * in which case they are applied with the migrate subcommand of the server binary.
  *
  * Connection Configuration:
  * Database credentials are loaded from environment variables:
@@ -22,6 +32,9 @@
  * - DB_PASSWORD: Database password
  * - DB_NAME: Database name
  *
+ * DB_DRIVER=sqlite stores the same tables in the local file DB_PATH
+ * (clear-songs.db by default) instead, see the sqlite and persistence packages.
+ *
  * @package postgres
  * @author Clear Songs Development Team
  */
@@ -33,7 +46,6 @@ import (
 	"os"
 	"time"
 
-	"github.com/RubenPari/clear-songs/internal/infrastructure/persistence/postgres/models"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -42,22 +54,19 @@ import (
  * Global Database Instance
  *
  * This variable holds the GORM database connection instance.
- * It is initialized by the Init() function and can be accessed
+ * It is initialized by persistence.Init() and can be accessed
  * throughout the application for database operations.
  *
  * The variable is set to nil initially and will be assigned
  * a valid database connection after successful initialization.
+ * It holds the SQLite database when DB_DRIVER=sqlite.
  */
 var Db *gorm.DB = nil
 
-// Init connects to the Postgres database and performs auto-migration to create the
-// tables as necessary. It sets the Db global variable to the connected database.
-//
-// This function returns an error if it fails to connect to the database, test the
-// connection, or perform the auto-migration. If the function is successful, it
-// will log a message to the console indicating that the database connection was
-// successful.
-func Init() error {
+// Connect opens the Postgres database configured by the DB_* variables without
+// migrating it. It returns a nil database, after logging why, when the credentials are
+// not set. Choosing between Postgres and SQLite is left to the persistence package.
+func Connect() (*gorm.DB, error) {
 	// postgres credentials
 	host := os.Getenv("DB_HOST")
 	port := os.Getenv("DB_PORT")
@@ -70,20 +79,16 @@ func Init() error {
 		log.Println("WARNING: Database environment variables not set (DB_HOST, DB_PORT, DB_USER, DB_PASSWORD, DB_NAME)")
 		log.Println("WARNING: Application will continue without database. Backup functionality will be disabled.")
 		log.Println("WARNING: To enable database, set the required environment variables and restart the application.")
-		return nil // Return nil to allow application to continue without database
+		return nil, nil
 	}
 
 	// create the connection string
 	postgresInfo := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s", host, port, user, password, dbname)
 
 	// Open the connection
-	var errConnectDb error
 	db, errConnectDb := gorm.Open(postgres.Open(postgresInfo), &gorm.Config{})
-
 	if errConnectDb != nil {
-		log.Printf("WARNING: Database connection failed: %v", errConnectDb)
-		log.Println("WARNING: Application will continue without database. Backup functionality will be disabled.")
-		return nil // Return nil to allow application to continue without database
+		return nil, fmt.Errorf("database connection failed: %w", errConnectDb)
 	}
 
 	// Extract the underlying sql.DB to configure connection pooling
@@ -100,31 +105,12 @@ func Init() error {
 	}
 
 	// test connection
-	errTestDb := db.Exec("SELECT 1").Error
-
-	if errTestDb != nil {
-		log.Printf("WARNING: Database connection test failed: %v", errTestDb)
-		log.Println("WARNING: Application will continue without database. Backup functionality will be disabled.")
-		return nil // Return nil to allow application to continue without database
+	if errTestDb := db.Exec("SELECT 1").Error; errTestDb != nil {
+		if sqlDB != nil {
+			_ = sqlDB.Close()
+		}
+		return nil, fmt.Errorf("database connection test failed: %w", errTestDb)
 	}
 
-	// auto-migration
-	errMigration := db.AutoMigrate(
-		&models.UserDB{},
-		&models.VerificationTokenDB{},
-		&models.ResetTokenDB{},
-		&models.TrackDB{},
-	)
-
-	if errMigration != nil {
-		log.Printf("WARNING: Database migration failed: %v", errMigration)
-		log.Println("WARNING: Application will continue without database. Backup functionality will be disabled.")
-		return nil // Return nil to allow application to continue without database
-	}
-
-	Db = db
-
-	log.Println("Successfully connected to database with pooling configured!")
-
-	return nil
+	return db, nil
 }
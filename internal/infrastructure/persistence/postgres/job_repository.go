@@ -0,0 +1,169 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/RubenPari/clear-songs/internal/domain/job"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	"github.com/RubenPari/clear-songs/internal/infrastructure/persistence/postgres/models"
+	"gorm.io/gorm"
+)
+
+type jobRepository struct {
+	db *gorm.DB
+}
+
+// NewJobRepository creates a job repository backed by Postgres.
+// If db is nil, jobs are kept in memory and lost on restart.
+func NewJobRepository(db *gorm.DB) job.Repository {
+	if db == nil {
+		return &memoryJobRepository{jobs: make(map[string]job.Job)}
+	}
+	return &jobRepository{db: db}
+}
+
+func mapToJob(row *models.JobDB) *job.Job {
+	var jobErrors []string
+	if row.Errors != "" {
+		_ = json.Unmarshal([]byte(row.Errors), &jobErrors)
+	}
+
+	return &job.Job{
+		ID:              row.ID,
+		Type:            row.Type,
+		Owner:           row.Owner,
+		Status:          job.Status(row.Status),
+		TracksTotal:     row.TracksTotal,
+		TracksProcessed: row.TracksProcessed,
+		BatchesDone:     row.BatchesDone,
+		Errors:          jobErrors,
+		CreatedAt:       row.CreatedAt,
+		StartedAt:       row.StartedAt,
+		FinishedAt:      row.FinishedAt,
+	}
+}
+
+func mapToJobDB(j *job.Job) (*models.JobDB, error) {
+	jobErrors := ""
+	if len(j.Errors) > 0 {
+		encoded, err := json.Marshal(j.Errors)
+		if err != nil {
+			return nil, err
+		}
+		jobErrors = string(encoded)
+	}
+
+	return &models.JobDB{
+		ID:              j.ID,
+		Type:            j.Type,
+		Owner:           j.Owner,
+		Status:          string(j.Status),
+		TracksTotal:     j.TracksTotal,
+		TracksProcessed: j.TracksProcessed,
+		BatchesDone:     j.BatchesDone,
+		Errors:          jobErrors,
+		CreatedAt:       j.CreatedAt,
+		StartedAt:       j.StartedAt,
+		FinishedAt:      j.FinishedAt,
+	}, nil
+}
+
+func (r *jobRepository) Create(ctx context.Context, j *job.Job) error {
+	row, err := mapToJobDB(j)
+	if err != nil {
+		return err
+	}
+
+	if err := r.db.WithContext(ctx).Create(row).Error; err != nil {
+		return err
+	}
+
+	j.CreatedAt = row.CreatedAt
+	return nil
+}
+
+func (r *jobRepository) Update(ctx context.Context, j *job.Job) error {
+	row, err := mapToJobDB(j)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithContext(ctx).Save(row).Error
+}
+
+func (r *jobRepository) GetByID(ctx context.Context, id string) (*job.Job, error) {
+	var row models.JobDB
+	if err := r.db.WithContext(ctx).First(&row, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, shared.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return mapToJob(&row), nil
+}
+
+func (r *jobRepository) MarkUnfinishedInterrupted(ctx context.Context) error {
+	return r.db.WithContext(ctx).Model(&models.JobDB{}).
+		Where("status IN ?", []string{string(job.StatusPending), string(job.StatusRunning)}).
+		Updates(map[string]any{
+			"status":      string(job.StatusInterrupted),
+			"finished_at": time.Now(),
+		}).Error
+}
+
+// memoryJobRepository keeps jobs in memory when the database is not available
+type memoryJobRepository struct {
+	mu   sync.RWMutex
+	jobs map[string]job.Job
+}
+
+func (r *memoryJobRepository) Create(ctx context.Context, j *job.Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if j.CreatedAt.IsZero() {
+		j.CreatedAt = time.Now()
+	}
+	r.jobs[j.ID] = copyJob(j)
+	return nil
+}
+
+func (r *memoryJobRepository) Update(ctx context.Context, j *job.Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.jobs[j.ID] = copyJob(j)
+	return nil
+}
+
+func (r *memoryJobRepository) GetByID(ctx context.Context, id string) (*job.Job, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored, exists := r.jobs[id]
+	if !exists {
+		return nil, shared.ErrNotFound
+	}
+
+	found := copyJob(&stored)
+	return &found, nil
+}
+
+func (r *memoryJobRepository) MarkUnfinishedInterrupted(ctx context.Context) error {
+	return nil // Nothing survives a restart
+}
+
+// copyJob detaches the stored job from the caller's slices
+func copyJob(j *job.Job) job.Job {
+	copied := *j
+	copied.Errors = append([]string(nil), j.Errors...)
+	return copied
+}
+
+var _ job.Repository = (*jobRepository)(nil)
+var _ job.Repository = (*memoryJobRepository)(nil)
@@ -0,0 +1,224 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/RubenPari/clear-songs/internal/domain/library"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	"github.com/RubenPari/clear-songs/internal/infrastructure/persistence/postgres/models"
+	spotifyAPI "github.com/zmb3/spotify"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type libraryMirrorRepository struct {
+	db *gorm.DB
+}
+
+// NewLibraryMirrorRepository creates a library mirror backed by Postgres.
+// If db is nil, the mirror is kept in memory and rebuilt after a restart.
+func NewLibraryMirrorRepository(db *gorm.DB) library.MirrorRepository {
+	if db == nil {
+		return &memoryLibraryMirrorRepository{
+			tracks: make(map[string]map[string]spotifyAPI.SavedTrack),
+			states: make(map[string]library.SyncState),
+		}
+	}
+	return &libraryMirrorRepository{db: db}
+}
+
+// savedAt parses the date a track was saved, the zero time if it is malformed
+func savedAt(track spotifyAPI.SavedTrack) time.Time {
+	addedAt, _ := time.Parse(spotifyAPI.TimestampLayout, track.AddedAt)
+	return addedAt
+}
+
+func mapToLibraryTrackDBs(owner string, tracks []spotifyAPI.SavedTrack) ([]models.LibraryTrackDB, error) {
+	rows := make([]models.LibraryTrackDB, 0, len(tracks))
+	for _, track := range tracks {
+		encoded, err := json.Marshal(track)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, models.LibraryTrackDB{
+			Owner:   owner,
+			TrackID: track.ID.String(),
+			AddedAt: savedAt(track),
+			Track:   string(encoded),
+		})
+	}
+	return rows, nil
+}
+
+func (r *libraryMirrorRepository) GetTracks(ctx context.Context, owner string) ([]spotifyAPI.SavedTrack, error) {
+	var rows []models.LibraryTrackDB
+	if err := r.db.WithContext(ctx).
+		Where("owner = ?", owner).
+		Order("added_at DESC, track_id").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	tracks := make([]spotifyAPI.SavedTrack, len(rows))
+	for i := range rows {
+		if err := decodeJSON(rows[i].Track, &tracks[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return tracks, nil
+}
+
+func (r *libraryMirrorRepository) AddTracks(ctx context.Context, owner string, tracks []spotifyAPI.SavedTrack) error {
+	if len(tracks) == 0 {
+		return nil
+	}
+	rows, err := mapToLibraryTrackDBs(owner, tracks)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "owner"}, {Name: "track_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"added_at", "track"}),
+		}).
+		CreateInBatches(rows, 500).Error
+}
+
+func (r *libraryMirrorRepository) ReplaceTracks(ctx context.Context, owner string, tracks []spotifyAPI.SavedTrack) error {
+	rows, err := mapToLibraryTrackDBs(owner, tracks)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("owner = ?", owner).Delete(&models.LibraryTrackDB{}).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(rows, 500).Error
+	})
+}
+
+func (r *libraryMirrorRepository) RemoveTracks(ctx context.Context, owner string, trackIDs []string) error {
+	if len(trackIDs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Where("owner = ? AND track_id IN ?", owner, trackIDs).
+		Delete(&models.LibraryTrackDB{}).Error
+}
+
+func (r *libraryMirrorRepository) GetState(ctx context.Context, owner string) (*library.SyncState, error) {
+	var row models.LibrarySyncStateDB
+	if err := r.db.WithContext(ctx).First(&row, "owner = ?", owner).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, shared.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &library.SyncState{
+		Owner:          row.Owner,
+		LastSyncedAt:   row.LastSyncedAt,
+		LastFullSyncAt: row.LastFullSyncAt,
+	}, nil
+}
+
+func (r *libraryMirrorRepository) SaveState(ctx context.Context, state *library.SyncState) error {
+	return r.db.WithContext(ctx).Save(&models.LibrarySyncStateDB{
+		Owner:          state.Owner,
+		LastSyncedAt:   state.LastSyncedAt,
+		LastFullSyncAt: state.LastFullSyncAt,
+	}).Error
+}
+
+// memoryLibraryMirrorRepository keeps the mirror in memory when the database is not available
+type memoryLibraryMirrorRepository struct {
+	mu     sync.RWMutex
+	tracks map[string]map[string]spotifyAPI.SavedTrack // by owner, then track ID
+	states map[string]library.SyncState
+}
+
+func (r *memoryLibraryMirrorRepository) GetTracks(ctx context.Context, owner string) ([]spotifyAPI.SavedTrack, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tracks := make([]spotifyAPI.SavedTrack, 0, len(r.tracks[owner]))
+	for _, track := range r.tracks[owner] {
+		tracks = append(tracks, track)
+	}
+
+	sort.Slice(tracks, func(i, j int) bool {
+		if a, b := savedAt(tracks[i]), savedAt(tracks[j]); !a.Equal(b) {
+			return a.After(b)
+		}
+		return tracks[i].ID < tracks[j].ID
+	})
+
+	return tracks, nil
+}
+
+func (r *memoryLibraryMirrorRepository) AddTracks(ctx context.Context, owner string, tracks []spotifyAPI.SavedTrack) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.tracks[owner] == nil {
+		r.tracks[owner] = make(map[string]spotifyAPI.SavedTrack)
+	}
+	for _, track := range tracks {
+		r.tracks[owner][track.ID.String()] = track
+	}
+	return nil
+}
+
+func (r *memoryLibraryMirrorRepository) ReplaceTracks(ctx context.Context, owner string, tracks []spotifyAPI.SavedTrack) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tracks[owner] = make(map[string]spotifyAPI.SavedTrack, len(tracks))
+	for _, track := range tracks {
+		r.tracks[owner][track.ID.String()] = track
+	}
+	return nil
+}
+
+func (r *memoryLibraryMirrorRepository) RemoveTracks(ctx context.Context, owner string, trackIDs []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, id := range trackIDs {
+		delete(r.tracks[owner], id)
+	}
+	return nil
+}
+
+func (r *memoryLibraryMirrorRepository) GetState(ctx context.Context, owner string) (*library.SyncState, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	state, exists := r.states[owner]
+	if !exists {
+		return nil, shared.ErrNotFound
+	}
+	return &state, nil
+}
+
+func (r *memoryLibraryMirrorRepository) SaveState(ctx context.Context, state *library.SyncState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.states[state.Owner] = *state
+	return nil
+}
+
+var _ library.MirrorRepository = (*libraryMirrorRepository)(nil)
+var _ library.MirrorRepository = (*memoryLibraryMirrorRepository)(nil)
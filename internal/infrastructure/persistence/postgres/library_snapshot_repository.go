@@ -0,0 +1,173 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	"github.com/RubenPari/clear-songs/internal/domain/snapshot"
+	"github.com/RubenPari/clear-songs/internal/infrastructure/persistence/postgres/models"
+	"gorm.io/gorm"
+)
+
+type librarySnapshotRepository struct {
+	db *gorm.DB
+}
+
+// NewLibrarySnapshotRepository creates a library snapshot repository backed by Postgres.
+// If db is nil, snapshots are kept in memory and lost on restart.
+func NewLibrarySnapshotRepository(db *gorm.DB) snapshot.LibraryRepository {
+	if db == nil {
+		return &memoryLibrarySnapshotRepository{snapshots: make(map[string]snapshot.LibrarySnapshot)}
+	}
+	return &librarySnapshotRepository{db: db}
+}
+
+func mapToLibrarySnapshot(row *models.LibrarySnapshotDB) *snapshot.LibrarySnapshot {
+	return &snapshot.LibrarySnapshot{
+		ID:         row.ID,
+		Owner:      row.Owner,
+		TrackCount: row.TrackCount,
+		Reason:     row.Reason,
+		CreatedAt:  row.CreatedAt,
+	}
+}
+
+func (r *librarySnapshotRepository) Create(ctx context.Context, s *snapshot.LibrarySnapshot) error {
+	row := &models.LibrarySnapshotDB{
+		ID:         s.ID,
+		Owner:      s.Owner,
+		Reason:     s.Reason,
+		TrackCount: len(s.Tracks),
+	}
+	s.TrackCount = row.TrackCount
+
+	tracks := make([]models.LibrarySnapshotTrackDB, len(s.Tracks))
+	for position, track := range s.Tracks {
+		tracks[position] = models.LibrarySnapshotTrackDB{
+			SnapshotID: s.ID,
+			Position:   position,
+			TrackID:    track.TrackID,
+			AddedAt:    track.AddedAt,
+		}
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(row).Error; err != nil {
+			return err
+		}
+		if len(tracks) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(tracks, 500).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	s.CreatedAt = row.CreatedAt
+	return nil
+}
+
+func (r *librarySnapshotRepository) GetByID(ctx context.Context, id string) (*snapshot.LibrarySnapshot, error) {
+	var row models.LibrarySnapshotDB
+	if err := r.db.WithContext(ctx).First(&row, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, shared.ErrNotFound
+		}
+		return nil, err
+	}
+
+	var tracks []models.LibrarySnapshotTrackDB
+	if err := r.db.WithContext(ctx).
+		Where("snapshot_id = ?", id).
+		Order("position").
+		Find(&tracks).Error; err != nil {
+		return nil, err
+	}
+
+	s := mapToLibrarySnapshot(&row)
+	s.Tracks = make([]snapshot.LibraryTrack, len(tracks))
+	for i, track := range tracks {
+		s.Tracks[i] = snapshot.LibraryTrack{TrackID: track.TrackID, AddedAt: track.AddedAt}
+	}
+
+	return s, nil
+}
+
+func (r *librarySnapshotRepository) ListByOwner(ctx context.Context, owner string, limit int) ([]snapshot.LibrarySnapshot, error) {
+	var rows []models.LibrarySnapshotDB
+	if err := r.db.WithContext(ctx).
+		Where("owner = ?", owner).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]snapshot.LibrarySnapshot, 0, len(rows))
+	for i := range rows {
+		snapshots = append(snapshots, *mapToLibrarySnapshot(&rows[i]))
+	}
+
+	return snapshots, nil
+}
+
+// memoryLibrarySnapshotRepository keeps snapshots in memory when the database is not available
+type memoryLibrarySnapshotRepository struct {
+	mu        sync.RWMutex
+	snapshots map[string]snapshot.LibrarySnapshot
+}
+
+func (r *memoryLibrarySnapshotRepository) Create(ctx context.Context, s *snapshot.LibrarySnapshot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if s.CreatedAt.IsZero() {
+		s.CreatedAt = time.Now()
+	}
+	s.TrackCount = len(s.Tracks)
+	stored := *s
+	stored.Tracks = append([]snapshot.LibraryTrack(nil), s.Tracks...)
+	r.snapshots[s.ID] = stored
+	return nil
+}
+
+func (r *memoryLibrarySnapshotRepository) GetByID(ctx context.Context, id string) (*snapshot.LibrarySnapshot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, exists := r.snapshots[id]
+	if !exists {
+		return nil, shared.ErrNotFound
+	}
+	return &s, nil
+}
+
+func (r *memoryLibrarySnapshotRepository) ListByOwner(ctx context.Context, owner string, limit int) ([]snapshot.LibrarySnapshot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	snapshots := []snapshot.LibrarySnapshot{}
+	for _, s := range r.snapshots {
+		if s.Owner == owner {
+			s.Tracks = nil
+			snapshots = append(snapshots, s)
+		}
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].CreatedAt.After(snapshots[j].CreatedAt)
+	})
+	if len(snapshots) > limit {
+		snapshots = snapshots[:limit]
+	}
+
+	return snapshots, nil
+}
+
+var _ snapshot.LibraryRepository = (*librarySnapshotRepository)(nil)
+var _ snapshot.LibraryRepository = (*memoryLibrarySnapshotRepository)(nil)
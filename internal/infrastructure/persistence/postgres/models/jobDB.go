@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+type JobDB struct {
+	ID              string    `gorm:"primaryKey;type:varchar(36)"`
+	Type            string    `gorm:"type:varchar(100);not null"`
+	Owner           string    `gorm:"type:varchar(200);index;not null"`
+	Status          string    `gorm:"type:varchar(20);index;not null"`
+	TracksTotal     int       `gorm:"not null;default:0"`
+	TracksProcessed int       `gorm:"not null;default:0"`
+	BatchesDone     int       `gorm:"not null;default:0"`
+	Errors          string    `gorm:"type:text"` // JSON-encoded list of error messages
+	CreatedAt       time.Time `gorm:"autoCreateTime"`
+	StartedAt       *time.Time
+	FinishedAt      *time.Time
+}
+
+func (JobDB) TableName() string {
+	return "jobs"
+}
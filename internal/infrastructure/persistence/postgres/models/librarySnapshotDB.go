@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+type LibrarySnapshotDB struct {
+	ID         string    `gorm:"primaryKey;type:varchar(36)"`
+	Owner      string    `gorm:"type:varchar(200);index;not null"`
+	Reason     string    `gorm:"type:varchar(100)"`
+	TrackCount int       `gorm:"not null;default:0"`
+	CreatedAt  time.Time `gorm:"autoCreateTime;index"`
+}
+
+func (LibrarySnapshotDB) TableName() string {
+	return "library_snapshots"
+}
+
+// LibrarySnapshotTrackDB is a track saved in the library when the snapshot was taken
+type LibrarySnapshotTrackDB struct {
+	SnapshotID string `gorm:"primaryKey;type:varchar(36)"`
+	Position   int    `gorm:"primaryKey"`
+	TrackID    string `gorm:"type:varchar(100);not null"`
+	AddedAt    time.Time
+}
+
+func (LibrarySnapshotTrackDB) TableName() string {
+	return "library_snapshot_tracks"
+}
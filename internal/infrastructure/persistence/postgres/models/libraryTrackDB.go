@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// LibraryTrackDB is a track of the local mirror of a session's saved library
+type LibraryTrackDB struct {
+	Owner   string    `gorm:"primaryKey;type:varchar(200)"`
+	TrackID string    `gorm:"primaryKey;type:varchar(100)"`
+	AddedAt time.Time `gorm:"index"`
+	Track   string    `gorm:"type:text"` // JSON-encoded saved track
+}
+
+func (LibraryTrackDB) TableName() string {
+	return "library_tracks"
+}
+
+// LibrarySyncStateDB records when the library mirror of a session was last synced
+type LibrarySyncStateDB struct {
+	Owner          string `gorm:"primaryKey;type:varchar(200)"`
+	LastSyncedAt   time.Time
+	LastFullSyncAt time.Time
+}
+
+func (LibrarySyncStateDB) TableName() string {
+	return "library_sync_states"
+}
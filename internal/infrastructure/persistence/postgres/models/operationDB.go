@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+type OperationDB struct {
+	ID              string `gorm:"primaryKey;type:varchar(36)"`
+	Kind            string `gorm:"type:varchar(100);not null"`
+	Owner           string `gorm:"type:varchar(200);index;not null"`
+	Params          string `gorm:"type:text"` // JSON-encoded map
+	LibraryTrackIDs string `gorm:"type:text"` // JSON-encoded list
+	PlaylistID      string `gorm:"type:varchar(100)"`
+	PlaylistTracks  string `gorm:"type:text"` // JSON-encoded list of track IDs and positions
+	// PlaylistTracksAdded is the JSON-encoded list of tracks inserted in place of removed ones
+	PlaylistTracksAdded string `gorm:"type:text"`
+	// PlaylistTracksRestored counts the playlist tracks an interrupted undo re-inserted
+	PlaylistTracksRestored int       `gorm:"not null;default:0"`
+	CreatedAt              time.Time `gorm:"autoCreateTime;index"`
+	UndoStartedAt          *time.Time
+	UndoneAt               *time.Time
+}
+
+func (OperationDB) TableName() string {
+	return "operations"
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+type PlaylistSnapshotDB struct {
+	ID                string    `gorm:"primaryKey;type:varchar(36)"`
+	Owner             string    `gorm:"type:varchar(200);index:idx_playlist_snapshots_owner_playlist;not null"`
+	PlaylistID        string    `gorm:"type:varchar(100);index:idx_playlist_snapshots_owner_playlist;not null"`
+	SpotifySnapshotID string    `gorm:"type:varchar(200)"`
+	Name              string    `gorm:"type:varchar(500)"`
+	Description       string    `gorm:"type:text"`
+	ImageURL          string    `gorm:"type:varchar(500)"`
+	Reason            string    `gorm:"type:varchar(100)"`
+	TrackCount        int       `gorm:"not null;default:0"`
+	CreatedAt         time.Time `gorm:"autoCreateTime;index"`
+}
+
+func (PlaylistSnapshotDB) TableName() string {
+	return "playlist_snapshots"
+}
+
+// PlaylistSnapshotTrackDB is one item of a playlist snapshot, in playlist order
+type PlaylistSnapshotTrackDB struct {
+	SnapshotID string `gorm:"primaryKey;type:varchar(36)"`
+	Position   int    `gorm:"primaryKey"`
+	TrackID    string `gorm:"type:varchar(200);not null"`
+}
+
+func (PlaylistSnapshotTrackDB) TableName() string {
+	return "playlist_snapshot_tracks"
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+type RuleDB struct {
+	ID             string `gorm:"primaryKey;type:varchar(36)"`
+	Owner          string `gorm:"type:varchar(200);index;not null"`
+	Name           string `gorm:"type:varchar(200);not null"`
+	Criteria       string `gorm:"type:text"` // JSON-encoded criteria
+	Schedule       string `gorm:"type:varchar(100);not null"`
+	Enabled        bool   `gorm:"index"`
+	DryRun         bool
+	WebhookURL     string `gorm:"type:varchar(500)"`
+	LastRunAt      *time.Time
+	LastMatchCount int
+	LastError      string    `gorm:"type:text"`
+	CreatedAt      time.Time `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime"`
+}
+
+func (RuleDB) TableName() string {
+	return "rules"
+}
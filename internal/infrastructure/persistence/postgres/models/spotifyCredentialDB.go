@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+type SpotifyCredentialDB struct {
+	Owner        string    `gorm:"primaryKey;type:varchar(200)"`
+	RefreshToken string    `gorm:"type:text;not null"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime"`
+}
+
+func (SpotifyCredentialDB) TableName() string {
+	return "spotify_credentials"
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// TrackBackupDB is a track saved before an operation removed it
+type TrackBackupDB struct {
+	ID          string `gorm:"primaryKey;type:varchar(36)"`
+	Owner       string `gorm:"type:varchar(200);index;not null;default:''"`
+	OperationID string `gorm:"type:varchar(36);index;not null;default:''"`
+	Reason      string `gorm:"type:varchar(100);not null;default:''"`
+	PlaylistID  string `gorm:"type:varchar(100);not null;default:''"`
+	Position    *int
+	TrackID     string `gorm:"type:varchar(100);index;not null"`
+	Name        string `gorm:"type:varchar(500);not null"`
+	Artist      string `gorm:"type:varchar(1000);not null"` // artist names, comma-separated, for searching
+	Artists     string `gorm:"type:text"`                   // JSON-encoded list of artists
+	Album       string `gorm:"type:varchar(500)"`
+	AlbumID     string `gorm:"type:varchar(100)"`
+	ISRC        string `gorm:"type:varchar(20)"`
+	DurationMs  int
+	URI         string    `gorm:"type:varchar(200)"`
+	URL         string    `gorm:"type:varchar(200)"`
+	CreatedAt   time.Time `gorm:"autoCreateTime;index;not null"`
+	RestoredAt  *time.Time
+}
+
+func (TrackBackupDB) TableName() string {
+	return "track_backups"
+}
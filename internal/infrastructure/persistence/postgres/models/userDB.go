@@ -3,6 +3,7 @@ package models
 import (
 	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -21,6 +22,14 @@ func (UserDB) TableName() string {
 	return "users"
 }
 
+// BeforeCreate generates the ID in Go, for databases without gen_random_uuid()
+func (u *UserDB) BeforeCreate(tx *gorm.DB) error {
+	if u.ID == "" {
+		u.ID = uuid.NewString()
+	}
+	return nil
+}
+
 type VerificationTokenDB struct {
 	ID        string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
 	UserID    string    `gorm:"index;not null"`
@@ -35,6 +44,14 @@ func (VerificationTokenDB) TableName() string {
 	return "verification_tokens"
 }
 
+// BeforeCreate generates the ID in Go, for databases without gen_random_uuid()
+func (t *VerificationTokenDB) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.NewString()
+	}
+	return nil
+}
+
 type ResetTokenDB struct {
 	ID        string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
 	UserID    string    `gorm:"index;not null"`
@@ -48,3 +65,11 @@ type ResetTokenDB struct {
 func (ResetTokenDB) TableName() string {
 	return "reset_tokens"
 }
+
+// BeforeCreate generates the ID in Go, for databases without gen_random_uuid()
+func (t *ResetTokenDB) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.NewString()
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/RubenPari/clear-songs/internal/domain/operation"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	"github.com/RubenPari/clear-songs/internal/infrastructure/persistence/postgres/models"
+	"gorm.io/gorm"
+)
+
+type operationRepository struct {
+	db *gorm.DB
+}
+
+// NewOperationRepository creates an operation repository backed by Postgres.
+// If db is nil, operations are kept in memory and lost on restart.
+func NewOperationRepository(db *gorm.DB) operation.Repository {
+	if db == nil {
+		return &memoryOperationRepository{operations: make(map[string]operation.Operation)}
+	}
+	return &operationRepository{db: db}
+}
+
+func mapToOperation(row *models.OperationDB) (*operation.Operation, error) {
+	op := &operation.Operation{
+		ID:         row.ID,
+		Kind:       operation.Kind(row.Kind),
+		Owner:      row.Owner,
+		PlaylistID: row.PlaylistID,
+		CreatedAt:  row.CreatedAt,
+		UndoneAt:   row.UndoneAt,
+
+		UndoStartedAt:          row.UndoStartedAt,
+		PlaylistTracksRestored: row.PlaylistTracksRestored,
+	}
+
+	if err := decodeJSON(row.Params, &op.Params); err != nil {
+		return nil, err
+	}
+	if err := decodeJSON(row.LibraryTrackIDs, &op.LibraryTrackIDs); err != nil {
+		return nil, err
+	}
+	if err := decodeJSON(row.PlaylistTracks, &op.PlaylistTracks); err != nil {
+		return nil, err
+	}
+	if err := decodeJSON(row.PlaylistTracksAdded, &op.PlaylistTracksAdded); err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+func mapToOperationDB(op *operation.Operation) (*models.OperationDB, error) {
+	params, err := json.Marshal(op.Params)
+	if err != nil {
+		return nil, err
+	}
+	libraryTrackIDs, err := json.Marshal(op.LibraryTrackIDs)
+	if err != nil {
+		return nil, err
+	}
+	playlistTracks, err := json.Marshal(op.PlaylistTracks)
+	if err != nil {
+		return nil, err
+	}
+	playlistTracksAdded, err := json.Marshal(op.PlaylistTracksAdded)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.OperationDB{
+		ID:              op.ID,
+		Kind:            string(op.Kind),
+		Owner:           op.Owner,
+		Params:          string(params),
+		LibraryTrackIDs: string(libraryTrackIDs),
+		PlaylistID:      op.PlaylistID,
+		PlaylistTracks:  string(playlistTracks),
+		CreatedAt:       op.CreatedAt,
+		UndoneAt:        op.UndoneAt,
+
+		UndoStartedAt:          op.UndoStartedAt,
+		PlaylistTracksAdded:    string(playlistTracksAdded),
+		PlaylistTracksRestored: op.PlaylistTracksRestored,
+	}, nil
+}
+
+func decodeJSON(value string, target any) error {
+	if value == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(value), target)
+}
+
+func (r *operationRepository) Create(ctx context.Context, op *operation.Operation) error {
+	row, err := mapToOperationDB(op)
+	if err != nil {
+		return err
+	}
+
+	if err := r.db.WithContext(ctx).Create(row).Error; err != nil {
+		return err
+	}
+
+	op.CreatedAt = row.CreatedAt
+	return nil
+}
+
+func (r *operationRepository) GetByID(ctx context.Context, id string) (*operation.Operation, error) {
+	var row models.OperationDB
+	if err := r.db.WithContext(ctx).First(&row, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, shared.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return mapToOperation(&row)
+}
+
+func (r *operationRepository) ListByOwner(ctx context.Context, owner string, limit int) ([]operation.Operation, error) {
+	var rows []models.OperationDB
+	if err := r.db.WithContext(ctx).
+		Where("owner = ?", owner).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	operations := make([]operation.Operation, 0, len(rows))
+	for i := range rows {
+		op, err := mapToOperation(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		operations = append(operations, *op)
+	}
+
+	return operations, nil
+}
+
+func (r *operationRepository) ClaimUndo(ctx context.Context, id string, at time.Time) error {
+	result := r.db.WithContext(ctx).Model(&models.OperationDB{}).
+		Where("id = ? AND undone_at IS NULL", id).
+		Where("undo_started_at IS NULL OR undo_started_at < ?", at.Add(-operation.UndoClaimTimeout)).
+		Update("undo_started_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: operation is already being undone", shared.ErrConflict)
+	}
+	return nil
+}
+
+func (r *operationRepository) ReleaseUndo(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Model(&models.OperationDB{}).
+		Where("id = ? AND undone_at IS NULL", id).
+		Update("undo_started_at", nil).Error
+}
+
+func (r *operationRepository) MarkUndone(ctx context.Context, id string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.OperationDB{}).
+		Where("id = ?", id).
+		Update("undone_at", at).Error
+}
+
+func (r *operationRepository) MarkPlaylistTracksRestored(ctx context.Context, id string, count int) error {
+	return r.db.WithContext(ctx).Model(&models.OperationDB{}).
+		Where("id = ?", id).
+		Update("playlist_tracks_restored", count).Error
+}
+
+// memoryOperationRepository keeps operations in memory when the database is not available
+type memoryOperationRepository struct {
+	mu         sync.RWMutex
+	operations map[string]operation.Operation
+}
+
+func (r *memoryOperationRepository) Create(ctx context.Context, op *operation.Operation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if op.CreatedAt.IsZero() {
+		op.CreatedAt = time.Now()
+	}
+	r.operations[op.ID] = *op
+	return nil
+}
+
+func (r *memoryOperationRepository) GetByID(ctx context.Context, id string) (*operation.Operation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	op, exists := r.operations[id]
+	if !exists {
+		return nil, shared.ErrNotFound
+	}
+	return &op, nil
+}
+
+func (r *memoryOperationRepository) ListByOwner(ctx context.Context, owner string, limit int) ([]operation.Operation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	operations := []operation.Operation{}
+	for _, op := range r.operations {
+		if op.Owner == owner {
+			operations = append(operations, op)
+		}
+	}
+
+	sort.Slice(operations, func(i, j int) bool {
+		return operations[i].CreatedAt.After(operations[j].CreatedAt)
+	})
+	if len(operations) > limit {
+		operations = operations[:limit]
+	}
+
+	return operations, nil
+}
+
+func (r *memoryOperationRepository) ClaimUndo(ctx context.Context, id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	op, exists := r.operations[id]
+	if !exists {
+		return shared.ErrNotFound
+	}
+	if op.IsUndone() || (op.UndoStartedAt != nil && !op.UndoStartedAt.Before(at.Add(-operation.UndoClaimTimeout))) {
+		return fmt.Errorf("%w: operation is already being undone", shared.ErrConflict)
+	}
+	op.UndoStartedAt = &at
+	r.operations[id] = op
+	return nil
+}
+
+func (r *memoryOperationRepository) ReleaseUndo(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	op, exists := r.operations[id]
+	if !exists {
+		return shared.ErrNotFound
+	}
+	if !op.IsUndone() {
+		op.UndoStartedAt = nil
+		r.operations[id] = op
+	}
+	return nil
+}
+
+func (r *memoryOperationRepository) MarkUndone(ctx context.Context, id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	op, exists := r.operations[id]
+	if !exists {
+		return shared.ErrNotFound
+	}
+	op.UndoneAt = &at
+	r.operations[id] = op
+	return nil
+}
+
+var _ operation.Repository = (*operationRepository)(nil)
+var _ operation.Repository = (*memoryOperationRepository)(nil)
+
+func (r *memoryOperationRepository) MarkPlaylistTracksRestored(ctx context.Context, id string, count int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	op, exists := r.operations[id]
+	if !exists {
+		return shared.ErrNotFound
+	}
+	op.PlaylistTracksRestored = count
+	r.operations[id] = op
+	return nil
+}
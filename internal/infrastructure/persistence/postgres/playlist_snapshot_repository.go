@@ -0,0 +1,178 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	"github.com/RubenPari/clear-songs/internal/domain/snapshot"
+	"github.com/RubenPari/clear-songs/internal/infrastructure/persistence/postgres/models"
+	"gorm.io/gorm"
+)
+
+type playlistSnapshotRepository struct {
+	db *gorm.DB
+}
+
+// NewPlaylistSnapshotRepository creates a playlist snapshot repository backed by Postgres.
+// If db is nil, snapshots are kept in memory and lost on restart.
+func NewPlaylistSnapshotRepository(db *gorm.DB) snapshot.PlaylistRepository {
+	if db == nil {
+		return &memoryPlaylistSnapshotRepository{snapshots: make(map[string]snapshot.PlaylistSnapshot)}
+	}
+	return &playlistSnapshotRepository{db: db}
+}
+
+func mapToPlaylistSnapshot(row *models.PlaylistSnapshotDB) *snapshot.PlaylistSnapshot {
+	return &snapshot.PlaylistSnapshot{
+		ID:                row.ID,
+		Owner:             row.Owner,
+		PlaylistID:        row.PlaylistID,
+		SpotifySnapshotID: row.SpotifySnapshotID,
+		Name:              row.Name,
+		Description:       row.Description,
+		ImageURL:          row.ImageURL,
+		TrackCount:        row.TrackCount,
+		Reason:            row.Reason,
+		CreatedAt:         row.CreatedAt,
+	}
+}
+
+func (r *playlistSnapshotRepository) Create(ctx context.Context, s *snapshot.PlaylistSnapshot) error {
+	row := &models.PlaylistSnapshotDB{
+		ID:                s.ID,
+		Owner:             s.Owner,
+		PlaylistID:        s.PlaylistID,
+		SpotifySnapshotID: s.SpotifySnapshotID,
+		Name:              s.Name,
+		Description:       s.Description,
+		ImageURL:          s.ImageURL,
+		Reason:            s.Reason,
+		TrackCount:        len(s.TrackIDs),
+	}
+	s.TrackCount = row.TrackCount
+
+	tracks := make([]models.PlaylistSnapshotTrackDB, len(s.TrackIDs))
+	for position, trackID := range s.TrackIDs {
+		tracks[position] = models.PlaylistSnapshotTrackDB{SnapshotID: s.ID, Position: position, TrackID: trackID}
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(row).Error; err != nil {
+			return err
+		}
+		if len(tracks) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(tracks, 500).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	s.CreatedAt = row.CreatedAt
+	return nil
+}
+
+func (r *playlistSnapshotRepository) GetByID(ctx context.Context, id string) (*snapshot.PlaylistSnapshot, error) {
+	var row models.PlaylistSnapshotDB
+	if err := r.db.WithContext(ctx).First(&row, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, shared.ErrNotFound
+		}
+		return nil, err
+	}
+
+	var tracks []models.PlaylistSnapshotTrackDB
+	if err := r.db.WithContext(ctx).
+		Where("snapshot_id = ?", id).
+		Order("position").
+		Find(&tracks).Error; err != nil {
+		return nil, err
+	}
+
+	s := mapToPlaylistSnapshot(&row)
+	s.TrackIDs = make([]string, len(tracks))
+	for i, track := range tracks {
+		s.TrackIDs[i] = track.TrackID
+	}
+
+	return s, nil
+}
+
+func (r *playlistSnapshotRepository) ListByPlaylist(ctx context.Context, owner, playlistID string, limit int) ([]snapshot.PlaylistSnapshot, error) {
+	var rows []models.PlaylistSnapshotDB
+	if err := r.db.WithContext(ctx).
+		Where("owner = ? AND playlist_id = ?", owner, playlistID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]snapshot.PlaylistSnapshot, 0, len(rows))
+	for i := range rows {
+		snapshots = append(snapshots, *mapToPlaylistSnapshot(&rows[i]))
+	}
+
+	return snapshots, nil
+}
+
+// memoryPlaylistSnapshotRepository keeps snapshots in memory when the database is not available
+type memoryPlaylistSnapshotRepository struct {
+	mu        sync.RWMutex
+	snapshots map[string]snapshot.PlaylistSnapshot
+}
+
+func (r *memoryPlaylistSnapshotRepository) Create(ctx context.Context, s *snapshot.PlaylistSnapshot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if s.CreatedAt.IsZero() {
+		s.CreatedAt = time.Now()
+	}
+	s.TrackCount = len(s.TrackIDs)
+	stored := *s
+	stored.TrackIDs = append([]string(nil), s.TrackIDs...)
+	r.snapshots[s.ID] = stored
+	return nil
+}
+
+func (r *memoryPlaylistSnapshotRepository) GetByID(ctx context.Context, id string) (*snapshot.PlaylistSnapshot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, exists := r.snapshots[id]
+	if !exists {
+		return nil, shared.ErrNotFound
+	}
+	return &s, nil
+}
+
+func (r *memoryPlaylistSnapshotRepository) ListByPlaylist(ctx context.Context, owner, playlistID string, limit int) ([]snapshot.PlaylistSnapshot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	snapshots := []snapshot.PlaylistSnapshot{}
+	for _, s := range r.snapshots {
+		if s.Owner == owner && s.PlaylistID == playlistID {
+			s.TrackIDs = nil
+			snapshots = append(snapshots, s)
+		}
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].CreatedAt.After(snapshots[j].CreatedAt)
+	})
+	if len(snapshots) > limit {
+		snapshots = snapshots[:limit]
+	}
+
+	return snapshots, nil
+}
+
+var _ snapshot.PlaylistRepository = (*playlistSnapshotRepository)(nil)
+var _ snapshot.PlaylistRepository = (*memoryPlaylistSnapshotRepository)(nil)
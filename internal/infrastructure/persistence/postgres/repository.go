@@ -1,12 +1,17 @@
 package postgres
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"log"
+	"strings"
+	"time"
 
+	"github.com/RubenPari/clear-songs/internal/domain/backup"
 	"github.com/RubenPari/clear-songs/internal/domain/shared"
 	"github.com/RubenPari/clear-songs/internal/infrastructure/persistence/postgres/models"
-	spotifyAPI "github.com/zmb3/spotify"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -24,82 +29,190 @@ func NewPostgresRepository(db *gorm.DB) shared.DatabaseRepository {
 	return &PostgresRepository{db: db}
 }
 
-// SaveTracksBackup saves tracks to database as backup
-func (r *PostgresRepository) SaveTracksBackup(tracks []spotifyAPI.PlaylistTrack) error {
-	log.Println("Saving tracks backup started")
-
-	for _, trackPlaylist := range tracks {
-		track := models.TrackDB{
-			Id:     trackPlaylist.Track.ID.String(),
-			Name:   trackPlaylist.Track.Name,
-			Artist: trackPlaylist.Track.Artists[0].Name,
-			Album:  trackPlaylist.Track.Album.Name,
-			URI:    string(trackPlaylist.Track.URI),
-			URL:    trackPlaylist.Track.ExternalURLs["spotify"],
-		}
+// SaveTracksBackup saves one row per backed-up track. Each deletion is kept, even of
+// a track that was backed up before.
+func (r *PostgresRepository) SaveTracksBackup(ctx context.Context, tracks []backup.TrackBackup) error {
+	if len(tracks) == 0 {
+		return nil
+	}
 
-		if err := r.saveToDB(track); err != nil {
+	rows := make([]models.TrackBackupDB, 0, len(tracks))
+	for i := range tracks {
+		if tracks[i].BackupID == "" {
+			tracks[i].BackupID = uuid.NewString()
+		}
+		row, err := mapToTrackBackupDB(&tracks[i])
+		if err != nil {
 			return err
 		}
+		rows = append(rows, *row)
+	}
+
+	if err := r.db.WithContext(ctx).CreateInBatches(rows, 100).Error; err != nil {
+		log.Printf("Error inserting tracks backup: %v\n", err)
+		return err
 	}
 
 	return nil
 }
 
-// SaveFullTracksBackup saves full tracks to database as backup
-func (r *PostgresRepository) SaveFullTracksBackup(tracks []spotifyAPI.FullTrack) error {
-	log.Println("Saving full tracks backup started")
-
-	for _, t := range tracks {
-		track := models.TrackDB{
-			Id:     t.ID.String(),
-			Name:   t.Name,
-			Artist: t.Artists[0].Name,
-			Album:  t.Album.Name,
-			URI:    string(t.URI),
-			URL:    t.ExternalURLs["spotify"],
-		}
+// GetTracksBackup retrieves the backed-up tracks matching the filter, newest first
+func (r *PostgresRepository) GetTracksBackup(ctx context.Context, filter backup.Filter) ([]backup.TrackBackup, error) {
+	query := r.db.WithContext(ctx).Model(&models.TrackBackupDB{}).
+		Where("owner = ?", filter.Owner)
 
-		if err := r.saveToDB(track); err != nil {
-			return err
-		}
+	if len(filter.BackupIDs) > 0 {
+		query = query.Where("id IN ?", filter.BackupIDs)
+	}
+	if len(filter.TrackIDs) > 0 {
+		query = query.Where("track_id IN ?", filter.TrackIDs)
+	}
+	if filter.OperationID != "" {
+		query = query.Where("operation_id = ?", filter.OperationID)
+	}
+	if filter.PlaylistID != "" {
+		query = query.Where("playlist_id = ?", filter.PlaylistID)
+	}
+	// LOWER ... LIKE rather than ILIKE, which SQLite lacks
+	if filter.Artist != "" {
+		query = query.Where("LOWER(artist) LIKE ?", "%"+strings.ToLower(filter.Artist)+"%")
+	}
+	if filter.Album != "" {
+		query = query.Where("LOWER(album) LIKE ?", "%"+strings.ToLower(filter.Album)+"%")
+	}
+	if filter.DeletedFrom != nil {
+		query = query.Where("created_at >= ?", *filter.DeletedFrom)
+	}
+	if filter.DeletedTo != nil {
+		query = query.Where("created_at <= ?", *filter.DeletedTo)
+	}
+	if !filter.IncludeRestored {
+		query = query.Where("restored_at IS NULL")
 	}
 
-	return nil
+	var rows []models.TrackBackupDB
+	if err := query.Order("created_at DESC").Order("position").Find(&rows).Error; err != nil {
+		log.Printf("Error querying tracks backup: %v\n", err)
+		return nil, err
+	}
+
+	tracks := make([]backup.TrackBackup, 0, len(rows))
+	for i := range rows {
+		tracks = append(tracks, mapToTrackBackup(&rows[i]))
+	}
+
+	return tracks, nil
 }
 
-func (r *PostgresRepository) saveToDB(track models.TrackDB) error {
-	var existingTrack models.TrackDB
-	result := r.db.First(&existingTrack, "id = ?", track.Id)
+// MarkTracksRestored flags the given backups as restored. Backups of other owners are
+// left untouched.
+func (r *PostgresRepository) MarkTracksRestored(ctx context.Context, owner string, backupIDs []string) error {
+	if len(backupIDs) == 0 {
+		return nil
+	}
 
+	now := time.Now()
+	result := r.db.WithContext(ctx).Model(&models.TrackBackupDB{}).
+		Where("id IN ? AND owner = ?", backupIDs, owner).
+		Update("restored_at", &now)
 	if result.Error != nil {
-		if !errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			log.Printf("Error querying existing track: %v\n", result.Error)
-			return result.Error
-		}
-
-		// Track doesn't exist, insert it
-		if err := r.db.Create(&track).Error; err != nil {
-			log.Printf("Error inserting track: %v - %v\n", track, err)
-			return err
-		}
+		log.Printf("Error marking tracks as restored: %v\n", result.Error)
+		return result.Error
 	}
+
 	return nil
 }
 
+// ReassignTracksBackup hands every backup of owner from to owner to
+func (r *PostgresRepository) ReassignTracksBackup(ctx context.Context, from, to string) (int, error) {
+	result := r.db.WithContext(ctx).Model(&models.TrackBackupDB{}).
+		Where("owner = ?", from).
+		Update("owner", to)
+	if result.Error != nil {
+		log.Printf("Error reassigning track backups: %v\n", result.Error)
+		return 0, result.Error
+	}
+
+	return int(result.RowsAffected), nil
+}
+
+func mapToTrackBackupDB(t *backup.TrackBackup) (*models.TrackBackupDB, error) {
+	artists, err := json.Marshal(t.Artists)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TrackBackupDB{
+		ID:          t.BackupID,
+		Owner:       t.Owner,
+		OperationID: t.OperationID,
+		Reason:      t.Reason,
+		PlaylistID:  t.PlaylistID,
+		Position:    t.Position,
+		TrackID:     t.ID,
+		Name:        t.Name,
+		Artist:      t.ArtistNames(),
+		Artists:     string(artists),
+		Album:       t.Album,
+		AlbumID:     t.AlbumID,
+		ISRC:        t.ISRC,
+		DurationMs:  t.DurationMs,
+		URI:         t.URI,
+		URL:         t.URL,
+		CreatedAt:   t.BackedUpAt,
+		RestoredAt:  t.RestoredAt,
+	}, nil
+}
+
+func mapToTrackBackup(row *models.TrackBackupDB) backup.TrackBackup {
+	var artists []backup.Artist
+	if err := decodeJSON(row.Artists, &artists); err != nil || len(artists) == 0 {
+		artists = []backup.Artist{{Name: row.Artist}}
+	}
+
+	return backup.TrackBackup{
+		BackupID:   row.ID,
+		ID:         row.TrackID,
+		Name:       row.Name,
+		Artists:    artists,
+		Album:      row.Album,
+		AlbumID:    row.AlbumID,
+		ISRC:       row.ISRC,
+		DurationMs: row.DurationMs,
+		URI:        row.URI,
+		URL:        row.URL,
+		Origin: backup.Origin{
+			Owner:       row.Owner,
+			OperationID: row.OperationID,
+			Reason:      row.Reason,
+			PlaylistID:  row.PlaylistID,
+		},
+		Position:   row.Position,
+		BackedUpAt: row.CreatedAt,
+		RestoredAt: row.RestoredAt,
+	}
+}
+
 // NoOpDatabaseRepository is a no-op implementation when database is not available
 type NoOpDatabaseRepository struct{}
 
-func (n *NoOpDatabaseRepository) SaveTracksBackup(tracks []spotifyAPI.PlaylistTrack) error {
-	log.Println("WARNING: Database not available, skipping track backup")
-	return nil // No-op
+func (n *NoOpDatabaseRepository) SaveTracksBackup(ctx context.Context, tracks []backup.TrackBackup) error {
+	return errors.New("database not available")
+}
+
+func (n *NoOpDatabaseRepository) GetTracksBackup(ctx context.Context, filter backup.Filter) ([]backup.TrackBackup, error) {
+	log.Println("WARNING: Database not available, no track backup to read")
+	return []backup.TrackBackup{}, nil
 }
 
-func (n *NoOpDatabaseRepository) SaveFullTracksBackup(tracks []spotifyAPI.FullTrack) error {
-	log.Println("WARNING: Database not available, skipping track backup")
+func (n *NoOpDatabaseRepository) MarkTracksRestored(ctx context.Context, owner string, backupIDs []string) error {
 	return nil // No-op
 }
 
+func (n *NoOpDatabaseRepository) ReassignTracksBackup(ctx context.Context, from, to string) (int, error) {
+	return 0, nil // No-op
+}
+
 // Ensure implementations
 var _ shared.DatabaseRepository = (*PostgresRepository)(nil)
 var _ shared.DatabaseRepository = (*NoOpDatabaseRepository)(nil)
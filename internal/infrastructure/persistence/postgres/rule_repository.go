@@ -0,0 +1,243 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/RubenPari/clear-songs/internal/domain/rule"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	"github.com/RubenPari/clear-songs/internal/infrastructure/persistence/postgres/models"
+	"gorm.io/gorm"
+)
+
+type ruleRepository struct {
+	db *gorm.DB
+}
+
+// NewRuleRepository creates a cleanup rule repository backed by Postgres.
+// If db is nil, rules are kept in memory and lost on restart.
+func NewRuleRepository(db *gorm.DB) rule.Repository {
+	if db == nil {
+		return &memoryRuleRepository{rules: make(map[string]rule.Rule)}
+	}
+	return &ruleRepository{db: db}
+}
+
+func mapToRule(row *models.RuleDB) (*rule.Rule, error) {
+	r := &rule.Rule{
+		ID:             row.ID,
+		Owner:          row.Owner,
+		Name:           row.Name,
+		Schedule:       row.Schedule,
+		Enabled:        row.Enabled,
+		DryRun:         row.DryRun,
+		WebhookURL:     row.WebhookURL,
+		LastRunAt:      row.LastRunAt,
+		LastMatchCount: row.LastMatchCount,
+		LastError:      row.LastError,
+		CreatedAt:      row.CreatedAt,
+		UpdatedAt:      row.UpdatedAt,
+	}
+
+	if err := decodeJSON(row.Criteria, &r.Criteria); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func mapToRuleDB(r *rule.Rule) (*models.RuleDB, error) {
+	criteria, err := json.Marshal(r.Criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.RuleDB{
+		ID:             r.ID,
+		Owner:          r.Owner,
+		Name:           r.Name,
+		Criteria:       string(criteria),
+		Schedule:       r.Schedule,
+		Enabled:        r.Enabled,
+		DryRun:         r.DryRun,
+		WebhookURL:     r.WebhookURL,
+		LastRunAt:      r.LastRunAt,
+		LastMatchCount: r.LastMatchCount,
+		LastError:      r.LastError,
+		CreatedAt:      r.CreatedAt,
+		UpdatedAt:      r.UpdatedAt,
+	}, nil
+}
+
+func mapToRules(rows []models.RuleDB) ([]rule.Rule, error) {
+	rules := make([]rule.Rule, 0, len(rows))
+	for i := range rows {
+		r, err := mapToRule(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, *r)
+	}
+	return rules, nil
+}
+
+func (r *ruleRepository) Create(ctx context.Context, value *rule.Rule) error {
+	row, err := mapToRuleDB(value)
+	if err != nil {
+		return err
+	}
+
+	if err := r.db.WithContext(ctx).Create(row).Error; err != nil {
+		return err
+	}
+
+	value.CreatedAt = row.CreatedAt
+	value.UpdatedAt = row.UpdatedAt
+	return nil
+}
+
+func (r *ruleRepository) Update(ctx context.Context, value *rule.Rule) error {
+	row, err := mapToRuleDB(value)
+	if err != nil {
+		return err
+	}
+
+	result := r.db.WithContext(ctx).Save(row)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	value.UpdatedAt = row.UpdatedAt
+	return nil
+}
+
+func (r *ruleRepository) Delete(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Delete(&models.RuleDB{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return shared.ErrNotFound
+	}
+	return nil
+}
+
+func (r *ruleRepository) GetByID(ctx context.Context, id string) (*rule.Rule, error) {
+	var row models.RuleDB
+	if err := r.db.WithContext(ctx).First(&row, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, shared.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return mapToRule(&row)
+}
+
+func (r *ruleRepository) ListByOwner(ctx context.Context, owner string) ([]rule.Rule, error) {
+	var rows []models.RuleDB
+	if err := r.db.WithContext(ctx).
+		Where("owner = ?", owner).
+		Order("created_at ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	return mapToRules(rows)
+}
+
+func (r *ruleRepository) ListEnabled(ctx context.Context) ([]rule.Rule, error) {
+	var rows []models.RuleDB
+	if err := r.db.WithContext(ctx).
+		Where("enabled = ?", true).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	return mapToRules(rows)
+}
+
+// memoryRuleRepository keeps rules in memory when the database is not available
+type memoryRuleRepository struct {
+	mu    sync.RWMutex
+	rules map[string]rule.Rule
+}
+
+func (r *memoryRuleRepository) Create(ctx context.Context, value *rule.Rule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if value.CreatedAt.IsZero() {
+		value.CreatedAt = now
+	}
+	value.UpdatedAt = now
+	r.rules[value.ID] = *value
+	return nil
+}
+
+func (r *memoryRuleRepository) Update(ctx context.Context, value *rule.Rule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.rules[value.ID]; !exists {
+		return shared.ErrNotFound
+	}
+	value.UpdatedAt = time.Now()
+	r.rules[value.ID] = *value
+	return nil
+}
+
+func (r *memoryRuleRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.rules[id]; !exists {
+		return shared.ErrNotFound
+	}
+	delete(r.rules, id)
+	return nil
+}
+
+func (r *memoryRuleRepository) GetByID(ctx context.Context, id string) (*rule.Rule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	value, exists := r.rules[id]
+	if !exists {
+		return nil, shared.ErrNotFound
+	}
+	return &value, nil
+}
+
+func (r *memoryRuleRepository) ListByOwner(ctx context.Context, owner string) ([]rule.Rule, error) {
+	return r.list(func(value rule.Rule) bool { return value.Owner == owner }), nil
+}
+
+func (r *memoryRuleRepository) ListEnabled(ctx context.Context) ([]rule.Rule, error) {
+	return r.list(func(value rule.Rule) bool { return value.Enabled }), nil
+}
+
+func (r *memoryRuleRepository) list(keep func(rule.Rule) bool) []rule.Rule {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rules := []rule.Rule{}
+	for _, value := range r.rules {
+		if keep(value) {
+			rules = append(rules, value)
+		}
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].CreatedAt.Before(rules[j].CreatedAt)
+	})
+	return rules
+}
+
+var _ rule.Repository = (*ruleRepository)(nil)
+var _ rule.Repository = (*memoryRuleRepository)(nil)
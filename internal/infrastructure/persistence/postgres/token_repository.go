@@ -13,9 +13,14 @@ type tokenRepository struct {
 	db *gorm.DB
 }
 
-func NewTokenRepository() auth.TokenRepository {
+// NewTokenRepository creates a verification and reset token repository backed by
+// Postgres. If db is nil, every call fails with shared.ErrUnavailable.
+func NewTokenRepository(db *gorm.DB) auth.TokenRepository {
+	if db == nil {
+		return unavailableTokenRepository{}
+	}
 	return &tokenRepository{
-		db: Db,
+		db: db,
 	}
 }
 
@@ -100,3 +105,30 @@ func (r *tokenRepository) DeleteResetToken(ctx context.Context, tokenStr string)
 	result := r.db.WithContext(ctx).Where("token = ?", tokenStr).Delete(&models.ResetTokenDB{})
 	return result.Error
 }
+
+// unavailableTokenRepository answers for the token repository when the database is not available
+type unavailableTokenRepository struct{}
+
+func (unavailableTokenRepository) CreateVerificationToken(ctx context.Context, token *auth.VerificationToken) error {
+	return errNoDatabase
+}
+
+func (unavailableTokenRepository) GetVerificationToken(ctx context.Context, token string) (*auth.VerificationToken, error) {
+	return nil, errNoDatabase
+}
+
+func (unavailableTokenRepository) DeleteVerificationToken(ctx context.Context, token string) error {
+	return errNoDatabase
+}
+
+func (unavailableTokenRepository) CreateResetToken(ctx context.Context, token *auth.ResetToken) error {
+	return errNoDatabase
+}
+
+func (unavailableTokenRepository) GetResetToken(ctx context.Context, token string) (*auth.ResetToken, error) {
+	return nil, errNoDatabase
+}
+
+func (unavailableTokenRepository) DeleteResetToken(ctx context.Context, token string) error {
+	return errNoDatabase
+}
@@ -3,8 +3,10 @@ package postgres
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/RubenPari/clear-songs/internal/domain/auth"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
 	"github.com/RubenPari/clear-songs/internal/infrastructure/persistence/postgres/models"
 	"gorm.io/gorm"
 )
@@ -13,9 +15,14 @@ type userRepository struct {
 	db *gorm.DB
 }
 
-func NewUserRepository() auth.UserRepository {
+// NewUserRepository creates a user repository backed by Postgres. If db is nil, local
+// accounts cannot be stored and every call fails with shared.ErrUnavailable.
+func NewUserRepository(db *gorm.DB) auth.UserRepository {
+	if db == nil {
+		return unavailableUserRepository{}
+	}
 	return &userRepository{
-		db: Db, // using global Db from postgres package
+		db: db,
 	}
 }
 
@@ -102,3 +109,29 @@ func (r *userRepository) Update(ctx context.Context, user *auth.User) error {
 	result := r.db.WithContext(ctx).Save(dbUser)
 	return result.Error
 }
+
+// errNoDatabase reports that local accounts need a database
+var errNoDatabase = fmt.Errorf("%w: local accounts need a database, see DB_DRIVER", shared.ErrUnavailable)
+
+// unavailableUserRepository answers for the user repository when the database is not available
+type unavailableUserRepository struct{}
+
+func (unavailableUserRepository) Create(ctx context.Context, user *auth.User) error {
+	return errNoDatabase
+}
+
+func (unavailableUserRepository) GetByID(ctx context.Context, id string) (*auth.User, error) {
+	return nil, errNoDatabase
+}
+
+func (unavailableUserRepository) GetByEmail(ctx context.Context, email string) (*auth.User, error) {
+	return nil, errNoDatabase
+}
+
+func (unavailableUserRepository) GetBySpotifyID(ctx context.Context, spotifyID string) (*auth.User, error) {
+	return nil, errNoDatabase
+}
+
+func (unavailableUserRepository) Update(ctx context.Context, user *auth.User) error {
+	return errNoDatabase
+}
@@ -0,0 +1,189 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	spotifyAPI "github.com/zmb3/spotify"
+	"golang.org/x/oauth2"
+)
+
+// memoryEntry is a JSON encoded value with its expiry, zero when it never expires
+type memoryEntry struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+// MemoryCacheRepository is an in-process cache with the key layout and expiry
+// semantics of RedisCacheRepository. It backs CACHE_DRIVER=memory, used by
+// single-instance deployments and the end-to-end tests.
+type MemoryCacheRepository struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	now     func() time.Time
+}
+
+// NewMemoryCacheRepository creates an empty in-memory cache
+func NewMemoryCacheRepository() *MemoryCacheRepository {
+	return &MemoryCacheRepository{
+		entries: make(map[string]memoryEntry),
+		now:     time.Now,
+	}
+}
+
+// SetToken stores the OAuth token of a session in cache
+func (r *MemoryCacheRepository) SetToken(ctx context.Context, sessionID string, token *oauth2.Token) error {
+	if token == nil {
+		return r.ClearToken(ctx, sessionID)
+	}
+	return r.setRaw(tokenKey(sessionID), token, tokenTTL)
+}
+
+// GetToken retrieves the OAuth token of a session from cache
+func (r *MemoryCacheRepository) GetToken(ctx context.Context, sessionID string) (*oauth2.Token, error) {
+	if sessionID == "" {
+		return nil, nil
+	}
+	var token oauth2.Token
+	found, err := r.getRaw(tokenKey(sessionID), &token)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// ClearToken removes the token of a session from cache
+func (r *MemoryCacheRepository) ClearToken(ctx context.Context, sessionID string) error {
+	r.deleteRaw(tokenKey(sessionID))
+	return nil
+}
+
+// GetUserTracks retrieves cached user tracks
+func (r *MemoryCacheRepository) GetUserTracks(ctx context.Context) ([]spotifyAPI.SavedTrack, error) {
+	var tracks []spotifyAPI.SavedTrack
+	found, err := r.Get(ctx, "userTracks", &tracks)
+	if err != nil || !found {
+		return nil, err
+	}
+	return tracks, nil
+}
+
+// SetUserTracks stores user tracks in cache
+func (r *MemoryCacheRepository) SetUserTracks(ctx context.Context, tracks []spotifyAPI.SavedTrack, ttl time.Duration) error {
+	return r.Set(ctx, "userTracks", tracks, ttl)
+}
+
+// InvalidateUserTracks removes user tracks from cache
+func (r *MemoryCacheRepository) InvalidateUserTracks(ctx context.Context) error {
+	return r.Delete(ctx, "userTracks")
+}
+
+// GetPlaylistTracks retrieves cached playlist tracks
+func (r *MemoryCacheRepository) GetPlaylistTracks(ctx context.Context, playlistID spotifyAPI.ID) ([]spotifyAPI.PlaylistTrack, error) {
+	var tracks []spotifyAPI.PlaylistTrack
+	found, err := r.Get(ctx, "tracksPlaylist"+playlistID.String(), &tracks)
+	if err != nil || !found {
+		return nil, err
+	}
+	return tracks, nil
+}
+
+// SetPlaylistTracks stores playlist tracks in cache
+func (r *MemoryCacheRepository) SetPlaylistTracks(ctx context.Context, playlistID spotifyAPI.ID, tracks []spotifyAPI.PlaylistTrack, ttl time.Duration) error {
+	return r.Set(ctx, "tracksPlaylist"+playlistID.String(), tracks, ttl)
+}
+
+// InvalidatePlaylistTracks removes playlist tracks from cache
+func (r *MemoryCacheRepository) InvalidatePlaylistTracks(ctx context.Context, playlistID spotifyAPI.ID) error {
+	return r.Delete(ctx, "tracksPlaylist"+playlistID.String())
+}
+
+// GetArtists retrieves the cached artists among ids
+func (r *MemoryCacheRepository) GetArtists(ctx context.Context, ids []spotifyAPI.ID) (map[spotifyAPI.ID]spotifyAPI.FullArtist, error) {
+	artists := make(map[spotifyAPI.ID]spotifyAPI.FullArtist)
+	for _, id := range ids {
+		var artist spotifyAPI.FullArtist
+		found, err := r.getRaw(artistKey(id), &artist)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			artists[id] = artist
+		}
+	}
+	return artists, nil
+}
+
+// SetArtists stores artist metadata in cache
+func (r *MemoryCacheRepository) SetArtists(ctx context.Context, artists []spotifyAPI.FullArtist, ttl time.Duration) error {
+	for _, artist := range artists {
+		if err := r.setRaw(artistKey(artist.ID), artist, ttl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get retrieves a value from the cache of the current session
+func (r *MemoryCacheRepository) Get(ctx context.Context, key string, target interface{}) (bool, error) {
+	return r.getRaw(scopedKey(ctx, key), target)
+}
+
+// Set stores a value in the cache of the current session
+func (r *MemoryCacheRepository) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return r.setRaw(scopedKey(ctx, key), value, ttl)
+}
+
+// Delete removes a key from the cache of the current session
+func (r *MemoryCacheRepository) Delete(ctx context.Context, key string) error {
+	r.deleteRaw(scopedKey(ctx, key))
+	return nil
+}
+
+func (r *MemoryCacheRepository) getRaw(key string, target interface{}) (bool, error) {
+	r.mu.Lock()
+	entry, ok := r.entries[key]
+	if ok && !entry.expiresAt.IsZero() && !r.now().Before(entry.expiresAt) {
+		delete(r.entries, key)
+		ok = false
+	}
+	r.mu.Unlock()
+
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal(entry.data, target); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *MemoryCacheRepository) setRaw(key string, value interface{}, ttl time.Duration) error {
+	// Values are stored encoded, like in Redis, so callers never share memory with the cache
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	entry := memoryEntry{data: data}
+	if ttl > 0 {
+		entry.expiresAt = r.now().Add(ttl)
+	}
+
+	r.mu.Lock()
+	r.entries[key] = entry
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *MemoryCacheRepository) deleteRaw(key string) {
+	r.mu.Lock()
+	delete(r.entries, key)
+	r.mu.Unlock()
+}
+
+// Ensure MemoryCacheRepository implements CacheRepository interface
+var _ shared.CacheRepository = (*MemoryCacheRepository)(nil)
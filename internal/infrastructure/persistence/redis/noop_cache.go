@@ -17,15 +17,15 @@ func NewNoOpCacheRepository() shared.CacheRepository {
 	return &NoOpCacheRepository{}
 }
 
-func (n *NoOpCacheRepository) SetToken(ctx context.Context, token *oauth2.Token) error {
+func (n *NoOpCacheRepository) SetToken(ctx context.Context, sessionID string, token *oauth2.Token) error {
 	return nil // No-op
 }
 
-func (n *NoOpCacheRepository) GetToken(ctx context.Context) (*oauth2.Token, error) {
+func (n *NoOpCacheRepository) GetToken(ctx context.Context, sessionID string) (*oauth2.Token, error) {
 	return nil, nil // No token found
 }
 
-func (n *NoOpCacheRepository) ClearToken(ctx context.Context) error {
+func (n *NoOpCacheRepository) ClearToken(ctx context.Context, sessionID string) error {
 	return nil // No-op
 }
 
@@ -53,6 +53,14 @@ func (n *NoOpCacheRepository) InvalidatePlaylistTracks(ctx context.Context, play
 	return nil // No-op
 }
 
+func (n *NoOpCacheRepository) GetArtists(ctx context.Context, ids []spotifyAPI.ID) (map[spotifyAPI.ID]spotifyAPI.FullArtist, error) {
+	return map[spotifyAPI.ID]spotifyAPI.FullArtist{}, nil // No cache
+}
+
+func (n *NoOpCacheRepository) SetArtists(ctx context.Context, artists []spotifyAPI.FullArtist, ttl time.Duration) error {
+	return nil // No-op
+}
+
 func (n *NoOpCacheRepository) Get(ctx context.Context, key string, target interface{}) (bool, error) {
 	return false, nil // Not found
 }
@@ -67,18 +67,21 @@ func NewRedisCacheRepository() (*RedisCacheRepository, error) {
 	}, nil
 }
 
-// SetToken stores the OAuth token in cache
-func (r *RedisCacheRepository) SetToken(ctx context.Context, token *oauth2.Token) error {
+// SetToken stores the OAuth token of a session in cache
+func (r *RedisCacheRepository) SetToken(ctx context.Context, sessionID string, token *oauth2.Token) error {
 	if token == nil {
-		return r.ClearToken(ctx)
+		return r.ClearToken(ctx, sessionID)
 	}
-	return r.Set(ctx, "spotify_token", token, tokenTTL)
+	return r.setRaw(ctx, tokenKey(sessionID), token, tokenTTL)
 }
 
-// GetToken retrieves the OAuth token from cache
-func (r *RedisCacheRepository) GetToken(ctx context.Context) (*oauth2.Token, error) {
+// GetToken retrieves the OAuth token of a session from cache
+func (r *RedisCacheRepository) GetToken(ctx context.Context, sessionID string) (*oauth2.Token, error) {
+	if sessionID == "" {
+		return nil, nil
+	}
 	var token oauth2.Token
-	found, err := r.Get(ctx, "spotify_token", &token)
+	found, err := r.getRaw(ctx, tokenKey(sessionID), &token)
 	if err != nil {
 		return nil, err
 	}
@@ -88,9 +91,9 @@ func (r *RedisCacheRepository) GetToken(ctx context.Context) (*oauth2.Token, err
 	return &token, nil
 }
 
-// ClearToken removes the token from cache
-func (r *RedisCacheRepository) ClearToken(ctx context.Context) error {
-	return r.Delete(ctx, "spotify_token")
+// ClearToken removes the token of a session from cache
+func (r *RedisCacheRepository) ClearToken(ctx context.Context, sessionID string) error {
+	return r.deleteRaw(ctx, tokenKey(sessionID))
 }
 
 // GetUserTracks retrieves cached user tracks
@@ -142,8 +145,70 @@ func (r *RedisCacheRepository) InvalidatePlaylistTracks(ctx context.Context, pla
 	return r.Delete(ctx, key)
 }
 
-// Get retrieves a value from cache
+// GetArtists retrieves the cached artists among ids with a single round trip
+func (r *RedisCacheRepository) GetArtists(ctx context.Context, ids []spotifyAPI.ID) (map[spotifyAPI.ID]spotifyAPI.FullArtist, error) {
+	artists := make(map[spotifyAPI.ID]spotifyAPI.FullArtist)
+	if r.client == nil || len(ids) == 0 {
+		return artists, nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = artistKey(id)
+	}
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			continue // Not cached
+		}
+		var artist spotifyAPI.FullArtist
+		if err := json.Unmarshal([]byte(raw), &artist); err != nil {
+			continue
+		}
+		artists[ids[i]] = artist
+	}
+	return artists, nil
+}
+
+// SetArtists stores artists in cache, each under its own key
+func (r *RedisCacheRepository) SetArtists(ctx context.Context, artists []spotifyAPI.FullArtist, ttl time.Duration) error {
+	if r.client == nil || len(artists) == 0 {
+		return nil
+	}
+
+	pipe := r.client.Pipeline()
+	for _, artist := range artists {
+		data, err := json.Marshal(artist)
+		if err != nil {
+			return err
+		}
+		pipe.Set(ctx, artistKey(artist.ID), data, ttl)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Get retrieves a value from the cache of the current session
 func (r *RedisCacheRepository) Get(ctx context.Context, key string, target interface{}) (bool, error) {
+	return r.getRaw(ctx, scopedKey(ctx, key), target)
+}
+
+// Set stores a value in the cache of the current session
+func (r *RedisCacheRepository) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return r.setRaw(ctx, scopedKey(ctx, key), value, ttl)
+}
+
+// Delete removes a key from the cache of the current session
+func (r *RedisCacheRepository) Delete(ctx context.Context, key string) error {
+	return r.deleteRaw(ctx, scopedKey(ctx, key))
+}
+
+func (r *RedisCacheRepository) getRaw(ctx context.Context, key string, target interface{}) (bool, error) {
 	if r.client == nil {
 		return false, nil
 	}
@@ -163,8 +228,7 @@ func (r *RedisCacheRepository) Get(ctx context.Context, key string, target inter
 	return true, nil
 }
 
-// Set stores a value in cache
-func (r *RedisCacheRepository) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+func (r *RedisCacheRepository) setRaw(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
 	if r.client == nil {
 		return nil // Silently fail if Redis is not available
 	}
@@ -177,13 +241,35 @@ func (r *RedisCacheRepository) Set(ctx context.Context, key string, value interf
 	return r.client.Set(ctx, key, data, ttl).Err()
 }
 
-// Delete removes a key from cache
-func (r *RedisCacheRepository) Delete(ctx context.Context, key string) error {
+func (r *RedisCacheRepository) deleteRaw(ctx context.Context, key string) error {
 	if r.client == nil {
 		return nil
 	}
 	return r.client.Del(ctx, key).Err()
 }
 
+// tokenKey returns the key holding the OAuth token of a session
+func tokenKey(sessionID string) string {
+	return "spotify_token:" + sessionID
+}
+
+// artistKey returns the key holding the metadata of an artist
+func artistKey(id spotifyAPI.ID) string {
+	return "artist:" + id.String()
+}
+
+// scopedKey prefixes key with the owner carried by ctx, or else its session, so that
+// users never read each other's cached data. Keying by owner lets every session of a
+// user, and the scheduled runs acting for them, share and invalidate one cache.
+func scopedKey(ctx context.Context, key string) string {
+	if owner := shared.OwnerFromContext(ctx); owner != "" {
+		return "owner:" + owner + ":" + key
+	}
+	if sessionID := shared.SessionFromContext(ctx); sessionID != "" {
+		return "session:" + sessionID + ":" + key
+	}
+	return key
+}
+
 // Ensure RedisCacheRepository implements CacheRepository interface
 var _ shared.CacheRepository = (*RedisCacheRepository)(nil)
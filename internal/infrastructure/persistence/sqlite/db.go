@@ -0,0 +1,50 @@
+// Package sqlite opens an embedded SQLite database, for single-user and self-hosted
+// deployments without a database server. Its schema is kept in line with the Postgres
+// backend by the migrations package, and the GORM repositories of the postgres
+// package work unchanged on top of it.
+package sqlite
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// DefaultPath is the database file used when DB_PATH is not set
+const DefaultPath = "clear-songs.db"
+
+// PathFromEnv returns DB_PATH, falling back to DefaultPath
+func PathFromEnv() string {
+	if path := os.Getenv("DB_PATH"); path != "" {
+		return path
+	}
+	return DefaultPath
+}
+
+// Open opens (creating it if needed) the database file at path. It does not migrate
+// the schema, see migrations.New. Foreign keys are enforced and writers wait for each other instead of failing.
+func Open(path string) (*gorm.DB, error) {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("create database directory: %w", err)
+		}
+	}
+
+	dsn := path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite allows a single writer, one connection avoids "database is locked" errors
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	return db, nil
+}
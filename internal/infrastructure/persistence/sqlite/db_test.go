@@ -0,0 +1,212 @@
+package sqlite_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/RubenPari/clear-songs/internal/domain/auth"
+	"github.com/RubenPari/clear-songs/internal/domain/backup"
+	"github.com/RubenPari/clear-songs/internal/infrastructure/persistence/migrations"
+	"github.com/RubenPari/clear-songs/internal/infrastructure/persistence/postgres"
+	"github.com/RubenPari/clear-songs/internal/infrastructure/persistence/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	spotifyAPI "github.com/zmb3/spotify"
+	"gorm.io/gorm"
+)
+
+func openTestDB(t *testing.T, path string) *gorm.DB {
+	db, err := sqlite.Open(path)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB, _ := db.DB()
+		_ = sqlDB.Close()
+	})
+
+	migrator, err := migrations.New(db)
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+	return db
+}
+
+func fullTrack(id, name, artist, album string) spotifyAPI.FullTrack {
+	return spotifyAPI.FullTrack{
+		SimpleTrack: spotifyAPI.SimpleTrack{
+			ID:           spotifyAPI.ID(id),
+			Name:         name,
+			Artists:      []spotifyAPI.SimpleArtist{{ID: spotifyAPI.ID("artist-" + id), Name: artist}},
+			Duration:     180000,
+			URI:          spotifyAPI.URI("spotify:track:" + id),
+			ExternalURLs: map[string]string{"spotify": "https://open.spotify.com/track/" + id},
+		},
+		Album:       spotifyAPI.SimpleAlbum{Name: album},
+		ExternalIDs: map[string]string{"isrc": "USRC1"},
+	}
+}
+
+func TestOpen(t *testing.T) {
+	t.Run("Success - should create the file and migrate it again idempotently", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "data", "clear-songs.db")
+
+		db := openTestDB(t, path)
+		migrator, err := migrations.New(db)
+		require.NoError(t, err)
+		applied, err := migrator.Up(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, applied)
+
+		for _, table := range []string{"users", "verification_tokens", "reset_tokens", "track_backups", "jobs", "library_tracks"} {
+			assert.True(t, db.Migrator().HasTable(table), table)
+		}
+	})
+}
+
+func TestUserAndTokenRepositories(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t, filepath.Join(t.TempDir(), "clear-songs.db"))
+	users := postgres.NewUserRepository(db)
+	tokens := postgres.NewTokenRepository(db)
+
+	user := &auth.User{Email: "user@example.com", PasswordHash: "hash"}
+	require.NoError(t, users.Create(ctx, user))
+
+	t.Run("Success - should generate the user ID", func(t *testing.T) {
+		assert.Len(t, user.ID, 36)
+		assert.False(t, user.CreatedAt.IsZero())
+	})
+
+	t.Run("Success - should find and update the user", func(t *testing.T) {
+		spotifyID := "spotify-user"
+		user.SpotifyID = &spotifyID
+		user.IsVerified = true
+		require.NoError(t, users.Update(ctx, user))
+
+		found, err := users.GetBySpotifyID(ctx, spotifyID)
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, user.ID, found.ID)
+		assert.True(t, found.IsVerified)
+
+		missing, err := users.GetByEmail(ctx, "nobody@example.com")
+		assert.NoError(t, err)
+		assert.Nil(t, missing)
+	})
+
+	t.Run("Error - should reject a duplicate email", func(t *testing.T) {
+		err := users.Create(ctx, &auth.User{Email: "user@example.com", PasswordHash: "hash"})
+		assert.Error(t, err)
+	})
+
+	t.Run("Success - should store and delete tokens", func(t *testing.T) {
+		token := &auth.ResetToken{UserID: user.ID, Token: "reset-token", ExpiresAt: time.Now().Add(time.Hour)}
+		require.NoError(t, tokens.CreateResetToken(ctx, token))
+		assert.NotEmpty(t, token.ID)
+
+		found, err := tokens.GetResetToken(ctx, "reset-token")
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, user.ID, found.UserID)
+
+		require.NoError(t, tokens.DeleteResetToken(ctx, "reset-token"))
+		found, err = tokens.GetResetToken(ctx, "reset-token")
+		assert.NoError(t, err)
+		assert.Nil(t, found)
+	})
+
+	t.Run("Error - should enforce the user foreign key", func(t *testing.T) {
+		err := tokens.CreateVerificationToken(ctx, &auth.VerificationToken{
+			UserID:    "00000000-0000-0000-0000-000000000000",
+			Token:     "orphan",
+			ExpiresAt: time.Now().Add(time.Hour),
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestDatabaseRepository_Backups(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "clear-songs.db")
+	repo := postgres.NewPostgresRepository(openTestDB(t, path))
+
+	origin := backup.Origin{Owner: "session:1", OperationID: "operation-1", Reason: "delete_tracks"}
+	require.NoError(t, repo.SaveTracksBackup(ctx, backup.FromFullTracks(origin, []spotifyAPI.FullTrack{
+		fullTrack("track-1", "Song 1", "Rock Band", "First Album"),
+		fullTrack("track-2", "Song 2", "Jazz Trio", "Blue Notes"),
+	})))
+
+	t.Run("Success - should filter backups by artist case-insensitively", func(t *testing.T) {
+		tracks, err := repo.GetTracksBackup(ctx, backup.Filter{Owner: "session:1", Artist: "rock"})
+
+		require.NoError(t, err)
+		require.Len(t, tracks, 1)
+		assert.Equal(t, "track-1", tracks[0].ID)
+		assert.Equal(t, "operation-1", tracks[0].OperationID)
+		assert.Equal(t, []backup.Artist{{ID: "artist-track-1", Name: "Rock Band"}}, tracks[0].Artists)
+		assert.Equal(t, "USRC1", tracks[0].ISRC)
+		assert.Equal(t, 180000, tracks[0].DurationMs)
+	})
+
+	t.Run("Success - should only return the backups of the session", func(t *testing.T) {
+		tracks, err := repo.GetTracksBackup(ctx, backup.Filter{Owner: "session:2"})
+
+		require.NoError(t, err)
+		assert.Empty(t, tracks)
+	})
+
+	t.Run("Success - should keep every deletion of a track with its playlist position", func(t *testing.T) {
+		item := spotifyAPI.PlaylistTrack{Track: fullTrack("track-1", "Song 1", "Rock Band", "First Album")}
+		playlistOrigin := backup.Origin{Owner: "session:1", OperationID: "operation-2", PlaylistID: "playlist-1"}
+		require.NoError(t, repo.SaveTracksBackup(ctx, backup.FromPlaylistTracks(playlistOrigin, []spotifyAPI.PlaylistTrack{item}, []int{7})))
+
+		tracks, err := repo.GetTracksBackup(ctx, backup.Filter{Owner: "session:1", TrackIDs: []string{"track-1"}})
+		require.NoError(t, err)
+		require.Len(t, tracks, 2)
+
+		fromPlaylist, err := repo.GetTracksBackup(ctx, backup.Filter{Owner: "session:1", PlaylistID: "playlist-1"})
+		require.NoError(t, err)
+		require.Len(t, fromPlaylist, 1)
+		require.NotNil(t, fromPlaylist[0].Position)
+		assert.Equal(t, 7, *fromPlaylist[0].Position)
+	})
+
+	t.Run("Success - should hide restored tracks and keep them across reopening", func(t *testing.T) {
+		tracks, err := repo.GetTracksBackup(ctx, backup.Filter{Owner: "session:1", TrackIDs: []string{"track-2"}})
+		require.NoError(t, err)
+		require.Len(t, tracks, 1)
+		require.NoError(t, repo.MarkTracksRestored(ctx, "session:2", []string{tracks[0].BackupID}))
+		tracks, err = repo.GetTracksBackup(ctx, backup.Filter{Owner: "session:1", TrackIDs: []string{"track-2"}})
+		require.NoError(t, err)
+		require.Len(t, tracks, 1, "another session must not restore the backup")
+		require.NoError(t, repo.MarkTracksRestored(ctx, "session:1", []string{tracks[0].BackupID}))
+
+		reopened := postgres.NewPostgresRepository(openTestDB(t, path))
+		tracks, err = reopened.GetTracksBackup(ctx, backup.Filter{Owner: "session:1", OperationID: "operation-1"})
+		require.NoError(t, err)
+		require.Len(t, tracks, 1)
+		assert.Equal(t, "track-1", tracks[0].ID)
+
+		all, err := reopened.GetTracksBackup(ctx, backup.Filter{Owner: "session:1", OperationID: "operation-1", IncludeRestored: true})
+		require.NoError(t, err)
+		assert.Len(t, all, 2)
+	})
+}
+
+func TestLibraryMirrorRepository(t *testing.T) {
+	ctx := context.Background()
+	mirror := postgres.NewLibraryMirrorRepository(openTestDB(t, filepath.Join(t.TempDir(), "clear-songs.db")))
+
+	saved := spotifyAPI.SavedTrack{AddedAt: "2026-01-01T00:00:00Z", FullTrack: fullTrack("track-1", "Song 1", "Rock Band", "First Album")}
+	require.NoError(t, mirror.AddTracks(ctx, "session:1", []spotifyAPI.SavedTrack{saved}))
+
+	// Saving the same track again updates it in place
+	saved.AddedAt = "2026-02-01T00:00:00Z"
+	require.NoError(t, mirror.AddTracks(ctx, "session:1", []spotifyAPI.SavedTrack{saved}))
+
+	tracks, err := mirror.GetTracks(ctx, "session:1")
+	require.NoError(t, err)
+	require.Len(t, tracks, 1)
+	assert.Equal(t, "2026-02-01T00:00:00Z", tracks[0].AddedAt)
+}
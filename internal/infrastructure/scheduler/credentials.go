@@ -0,0 +1,28 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/RubenPari/clear-songs/internal/domain/auth"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	"golang.org/x/oauth2"
+)
+
+// errNoCredential is recorded when the owner never logged in with Spotify since
+// credentials are stored, or revoked the app
+var errNoCredential = errors.New("no Spotify credential stored for the owner, log in again to resume scheduled runs")
+
+// ownerToken returns a token holding the refresh token stored for the owner in ctx.
+// It has no access token yet: the OAuth client mints one on the first call.
+func ownerToken(ctx context.Context, credentialRepo auth.CredentialRepository) (*oauth2.Token, error) {
+	credential, err := credentialRepo.Get(ctx, shared.OwnerFromContext(ctx))
+	if errors.Is(err, shared.ErrNotFound) {
+		return nil, errNoCredential
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load Spotify credential: %w", err)
+	}
+	return &oauth2.Token{RefreshToken: credential.RefreshToken}, nil
+}
@@ -0,0 +1,104 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+
+	appJob "github.com/RubenPari/clear-songs/internal/application/job"
+	appSnapshot "github.com/RubenPari/clear-songs/internal/application/snapshot"
+	"github.com/RubenPari/clear-songs/internal/domain/auth"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	domainSnapshot "github.com/RubenPari/clear-songs/internal/domain/snapshot"
+	"github.com/RubenPari/clear-songs/internal/infrastructure/external/spotify"
+	"github.com/robfig/cron/v3"
+)
+
+// LibrarySnapshotScheduler periodically snapshots the library of every user with a stored
+// Spotify credential, i.e. every user that logged in to Spotify. Each snapshot is a
+// background job acting with that credential, so snapshots keep being taken after their
+// session expired.
+type LibrarySnapshotScheduler struct {
+	cron           *cron.Cron
+	schedule       string
+	captureUC      *appSnapshot.CaptureLibraryUseCase
+	jobs           *appJob.Manager
+	spotifyFactory shared.SpotifyRepositoryFactory
+	credentialRepo auth.CredentialRepository
+}
+
+// NewLibrarySnapshotScheduler creates a new library snapshot scheduler. An empty schedule
+// disables periodic snapshots.
+func NewLibrarySnapshotScheduler(
+	schedule string,
+	captureUC *appSnapshot.CaptureLibraryUseCase,
+	jobs *appJob.Manager,
+	spotifyFactory shared.SpotifyRepositoryFactory,
+	credentialRepo auth.CredentialRepository,
+) *LibrarySnapshotScheduler {
+	return &LibrarySnapshotScheduler{
+		cron:           cron.New(),
+		schedule:       schedule,
+		captureUC:      captureUC,
+		jobs:           jobs,
+		spotifyFactory: spotifyFactory,
+		credentialRepo: credentialRepo,
+	}
+}
+
+// Start starts the scheduler
+func (s *LibrarySnapshotScheduler) Start(ctx context.Context) {
+	if s.schedule == "" {
+		return
+	}
+	if _, err := s.cron.AddFunc(s.schedule, func() { s.snapshotAll(ctx) }); err != nil {
+		log.Printf("ERROR: Failed to schedule library snapshots: %v", err)
+		return
+	}
+	s.cron.Start()
+}
+
+// Stop stops the scheduler and waits for a running trigger to return
+func (s *LibrarySnapshotScheduler) Stop(ctx context.Context) error {
+	select {
+	case <-s.cron.Stop().Done():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// snapshotAll enqueues a library snapshot for each owner with a stored Spotify credential
+func (s *LibrarySnapshotScheduler) snapshotAll(ctx context.Context) {
+	owners, err := s.credentialRepo.ListOwners(ctx)
+	if err != nil {
+		log.Printf("ERROR: Failed to load the owners to snapshot: %v", err)
+		return
+	}
+
+	for _, owner := range owners {
+		s.trigger(ctx, owner)
+	}
+}
+
+// trigger enqueues a library snapshot on behalf of an owner
+func (s *LibrarySnapshotScheduler) trigger(ctx context.Context, owner string) {
+	ctx = shared.WithOwner(ctx, owner)
+
+	token, err := ownerToken(ctx, s.credentialRepo)
+	if err != nil {
+		log.Printf("WARNING: Skipping scheduled library snapshot of %s: %v", owner, err)
+		return
+	}
+
+	spotifyRepo := s.spotifyFactory.NewRepository(token)
+	ctx = shared.WithSpotifyRepository(ctx, spotifyRepo)
+
+	_, err = s.jobs.Enqueue(ctx, "library_snapshot", func(ctx context.Context) error {
+		_, err := s.captureUC.Execute(ctx, domainSnapshot.ReasonScheduled)
+		spotify.PersistRotatedCredential(ctx, s.credentialRepo, token, spotifyRepo)
+		return err
+	})
+	if err != nil {
+		log.Printf("WARNING: Skipping scheduled library snapshot of %s: %v", owner, err)
+	}
+}
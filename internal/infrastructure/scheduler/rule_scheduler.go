@@ -0,0 +1,156 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	appJob "github.com/RubenPari/clear-songs/internal/application/job"
+	appRule "github.com/RubenPari/clear-songs/internal/application/rule"
+	"github.com/RubenPari/clear-songs/internal/domain/auth"
+	"github.com/RubenPari/clear-songs/internal/domain/rule"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	"github.com/RubenPari/clear-songs/internal/infrastructure/external/spotify"
+	"github.com/robfig/cron/v3"
+)
+
+// reloadSchedule is how often the scheduler picks up created, updated and deleted rules
+const reloadSchedule = "@every 1m"
+
+// scheduledRule is a rule registered with cron
+type scheduledRule struct {
+	entryID  cron.EntryID
+	schedule string
+}
+
+// RuleScheduler runs the enabled cleanup rules on their cron schedule. Each run is a
+// background job acting with the Spotify credential stored for the rule owner, so runs
+// keep working after the owner's session expired.
+type RuleScheduler struct {
+	cron           *cron.Cron
+	ruleRepo       rule.Repository
+	runRuleUC      *appRule.RunRuleUseCase
+	jobs           *appJob.Manager
+	spotifyFactory shared.SpotifyRepositoryFactory
+	credentialRepo auth.CredentialRepository
+
+	mu      sync.Mutex
+	entries map[string]scheduledRule // by rule ID
+}
+
+// NewRuleScheduler creates a new rule scheduler
+func NewRuleScheduler(
+	ruleRepo rule.Repository,
+	runRuleUC *appRule.RunRuleUseCase,
+	jobs *appJob.Manager,
+	spotifyFactory shared.SpotifyRepositoryFactory,
+	credentialRepo auth.CredentialRepository,
+) *RuleScheduler {
+	return &RuleScheduler{
+		cron:           cron.New(),
+		ruleRepo:       ruleRepo,
+		runRuleUC:      runRuleUC,
+		jobs:           jobs,
+		spotifyFactory: spotifyFactory,
+		credentialRepo: credentialRepo,
+		entries:        make(map[string]scheduledRule),
+	}
+}
+
+// Start registers the enabled rules and starts the scheduler
+func (s *RuleScheduler) Start(ctx context.Context) {
+	s.reload(ctx)
+	if _, err := s.cron.AddFunc(reloadSchedule, func() { s.reload(ctx) }); err != nil {
+		log.Printf("ERROR: Failed to schedule cleanup rule reload: %v", err)
+	}
+	s.cron.Start()
+}
+
+// Stop stops the scheduler and waits for running reloads and triggers to return.
+// Rule runs already handed to the job manager are stopped by its Shutdown.
+func (s *RuleScheduler) Stop(ctx context.Context) error {
+	select {
+	case <-s.cron.Stop().Done():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// reload brings the cron entries in line with the enabled rules
+func (s *RuleScheduler) reload(ctx context.Context) {
+	rules, err := s.ruleRepo.ListEnabled(ctx)
+	if err != nil {
+		log.Printf("ERROR: Failed to load cleanup rules: %v", err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	enabled := make(map[string]bool, len(rules))
+	for _, r := range rules {
+		enabled[r.ID] = true
+
+		current, exists := s.entries[r.ID]
+		if exists && current.schedule == r.Schedule {
+			continue
+		}
+		if exists {
+			s.cron.Remove(current.entryID)
+			delete(s.entries, r.ID)
+		}
+
+		ruleID, owner := r.ID, r.Owner
+		entryID, err := s.cron.AddFunc(r.Schedule, func() { s.trigger(ctx, ruleID, owner) })
+		if err != nil {
+			log.Printf("ERROR: Failed to schedule cleanup rule %s: %v", r.ID, err)
+			continue
+		}
+		s.entries[r.ID] = scheduledRule{entryID: entryID, schedule: r.Schedule}
+	}
+
+	for ruleID, current := range s.entries {
+		if !enabled[ruleID] {
+			s.cron.Remove(current.entryID)
+			delete(s.entries, ruleID)
+		}
+	}
+}
+
+// trigger enqueues a run of the rule on behalf of its owner
+func (s *RuleScheduler) trigger(ctx context.Context, ruleID, owner string) {
+	ctx = shared.WithOwner(ctx, owner)
+
+	token, err := ownerToken(ctx, s.credentialRepo)
+	if err != nil {
+		s.recordError(ctx, ruleID, err)
+		return
+	}
+
+	spotifyRepo := s.spotifyFactory.NewRepository(token)
+	ctx = shared.WithSpotifyRepository(ctx, spotifyRepo)
+
+	_, err = s.jobs.Enqueue(ctx, "cleanup_rule", func(ctx context.Context) error {
+		_, err := s.runRuleUC.Execute(ctx, ruleID, appRule.RunOptions{Scheduled: true})
+		spotify.PersistRotatedCredential(ctx, s.credentialRepo, token, spotifyRepo)
+		return err
+	})
+	if err != nil {
+		s.recordError(ctx, ruleID, err)
+	}
+}
+
+// recordError stores why a scheduled run could not start
+func (s *RuleScheduler) recordError(ctx context.Context, ruleID string, cause error) {
+	log.Printf("WARNING: Skipping scheduled run of cleanup rule %s: %v", ruleID, cause)
+
+	r, err := s.ruleRepo.GetByID(ctx, ruleID)
+	if err != nil {
+		return
+	}
+	r.LastError = cause.Error()
+	if err := s.ruleRepo.Update(ctx, r); err != nil {
+		log.Printf("WARNING: Failed to store error of cleanup rule %s: %v", ruleID, err)
+	}
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"github.com/RubenPari/clear-songs/internal/application/album"
+	"github.com/RubenPari/clear-songs/internal/domain/shared/utils"
+	"github.com/gin-gonic/gin"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+// AlbumController handles saved-album endpoints
+type AlbumController struct {
+	BaseController
+	getUserAlbumsUC *album.GetUserAlbumsUseCase
+	convertAlbumUC  *album.ConvertAlbumUseCase
+}
+
+// NewAlbumController creates a new album controller
+func NewAlbumController(
+	getUserAlbumsUC *album.GetUserAlbumsUseCase,
+	convertAlbumUC *album.ConvertAlbumUseCase,
+) *AlbumController {
+	return &AlbumController{
+		getUserAlbumsUC: getUserAlbumsUC,
+		convertAlbumUC:  convertAlbumUC,
+	}
+}
+
+// GetUserAlbums handles GET /album/list
+func (ac *AlbumController) GetUserAlbums(c *gin.Context) {
+	albums, err := ac.getUserAlbumsUC.Execute(c.Request.Context())
+	if err != nil {
+		ac.HandleDomainError(c, err)
+		return
+	}
+
+	// Convert to response format
+	response := make([]album.AlbumResponse, 0, len(albums))
+	for _, a := range albums {
+		artists := make([]string, len(a.Artists))
+		for i, artist := range a.Artists {
+			artists[i] = artist.Name
+		}
+
+		response = append(response, album.AlbumResponse{
+			ID:          a.ID.String(),
+			Name:        a.Name,
+			Artists:     artists,
+			TotalTracks: a.Tracks.Total,
+			ImageURL:    utils.GetMediumImage(a.Images),
+			AddedAt:     a.AddedAt,
+		})
+	}
+
+	ac.JSONSuccess(c, response)
+}
+
+// ConvertAlbum handles POST /album/convert
+func (ac *AlbumController) ConvertAlbum(c *gin.Context) {
+	var req album.ConvertRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		ac.JSONValidationError(c, "Album id is required")
+		return
+	}
+
+	result, err := ac.convertAlbumUC.Execute(c.Request.Context(), spotifyAPI.ID(req.ID), req.RemoveAlbum)
+	if err != nil {
+		ac.HandleDomainError(c, err)
+		return
+	}
+
+	ac.JSONSuccess(c, result)
+}
@@ -1,13 +1,9 @@
 package handlers
 
 import (
-	"context"
-	"fmt"
-	"os"
-
 	"github.com/RubenPari/clear-songs/internal/application/auth"
+	"github.com/RubenPari/clear-songs/internal/infrastructure/transport/http/middleware"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 )
 
 // AuthControllerRefactored is the refactored auth controller using dependency injection
@@ -48,33 +44,12 @@ func (ac *AuthControllerRefactored) Callback(c *gin.Context) {
 		return
 	}
 
-	// Try to get local User ID from JWT if present
-	localUserID := ""
-	tokenString, errCookie := c.Cookie("auth_token")
-	if errCookie == nil && tokenString != "" {
-		jwtSecret := os.Getenv("JWT_SECRET")
-		if jwtSecret == "" {
-			jwtSecret = "fallback-secret-for-dev"
-		}
-
-		token, errJWT := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method")
-			}
-			return []byte(jwtSecret), nil
-		})
+	// Bind the Spotify token to the current session, starting one if needed.
+	// A local user logged in with email/password is linked to the Spotify profile.
+	sessionID := middleware.EnsureSession(c)
+	localUserID := c.GetString(middleware.LocalUserIDKey)
 
-		if errJWT == nil && token.Valid {
-			if claims, ok := token.Claims.(jwt.MapClaims); ok {
-				if sub, ok := claims["sub"].(string); ok {
-					localUserID = sub
-				}
-			}
-		}
-	}
-
-	ctx := context.Background()
-	redirectURL, err := ac.callbackUC.Execute(ctx, code, localUserID)
+	redirectURL, err := ac.callbackUC.Execute(c.Request.Context(), code, sessionID, localUserID)
 	if err != nil {
 		ac.JSONInternalError(c, "Error authenticating user")
 		return
@@ -85,19 +60,23 @@ func (ac *AuthControllerRefactored) Callback(c *gin.Context) {
 
 // Logout handles GET /auth/logout
 func (ac *AuthControllerRefactored) Logout(c *gin.Context) {
-	ctx := context.Background()
-	if err := ac.logoutUC.Execute(ctx); err != nil {
+	sessionID := c.GetString(middleware.SessionIDKey)
+	if err := ac.logoutUC.Execute(c.Request.Context(), sessionID); err != nil {
 		ac.JSONInternalError(c, "Error logging out")
 		return
 	}
 
+	// Anonymous sessions end with the logout, local users keep their JWT session
+	if c.GetString(middleware.LocalUserIDKey) == "" {
+		c.SetCookie(middleware.SessionCookieName, "", -1, "/", "", false, true)
+	}
+
 	ac.JSONSuccess(c, gin.H{"message": "User logged out successfully"})
 }
 
 // IsAuth handles GET /auth/is-auth
 func (ac *AuthControllerRefactored) IsAuth(c *gin.Context) {
-	ctx := context.Background()
-	userInfo, err := ac.isAuthUC.Execute(ctx)
+	userInfo, err := ac.isAuthUC.Execute(c.Request.Context())
 	if err != nil {
 		c.JSON(200, gin.H{
 			"success": false,
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/RubenPari/clear-songs/internal/application/backup"
+	domainBackup "github.com/RubenPari/clear-songs/internal/domain/backup"
+	"github.com/gin-gonic/gin"
+)
+
+// BackupController exposes the tracks saved in the backup table
+type BackupController struct {
+	BaseController
+	getBackupTracksUC *backup.GetBackupTracksUseCase
+	restoreTracksUC   *backup.RestoreTracksUseCase
+	claimLegacyUC     *backup.ClaimLegacyBackupsUseCase
+}
+
+// NewBackupController creates a new backup controller
+func NewBackupController(
+	getBackupTracksUC *backup.GetBackupTracksUseCase,
+	restoreTracksUC *backup.RestoreTracksUseCase,
+	claimLegacyUC *backup.ClaimLegacyBackupsUseCase,
+) *BackupController {
+	return &BackupController{
+		getBackupTracksUC: getBackupTracksUC,
+		restoreTracksUC:   restoreTracksUC,
+		claimLegacyUC:     claimLegacyUC,
+	}
+}
+
+// GetBackupTracks handles GET /backup/tracks
+func (bc *BackupController) GetBackupTracks(c *gin.Context) {
+	var req backup.ListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		bc.JSONValidationError(c, "Invalid filter parameters (dates must be YYYY-MM-DD)")
+		return
+	}
+
+	filter := domainBackup.Filter{
+		OperationID:     req.OperationID,
+		PlaylistID:      req.PlaylistID,
+		Artist:          req.Artist,
+		Album:           req.Album,
+		DeletedFrom:     req.DeletedFrom,
+		DeletedTo:       endOfDay(req.DeletedTo),
+		IncludeRestored: req.IncludeRestored,
+	}
+
+	ctx := c.Request.Context()
+	tracks, err := bc.getBackupTracksUC.Execute(ctx, filter)
+	if err != nil {
+		bc.HandleDomainError(c, err)
+		return
+	}
+
+	response := make([]backup.TrackBackupResponse, 0, len(tracks))
+	for _, t := range tracks {
+		response = append(response, backup.TrackBackupResponse{
+			BackupID:    t.BackupID,
+			ID:          t.ID,
+			Name:        t.Name,
+			Artist:      t.ArtistNames(),
+			Artists:     t.Artists,
+			Album:       t.Album,
+			AlbumID:     t.AlbumID,
+			ISRC:        t.ISRC,
+			DurationMs:  t.DurationMs,
+			URI:         t.URI,
+			SpotifyURL:  t.URL,
+			OperationID: t.OperationID,
+			Reason:      t.Reason,
+			PlaylistID:  t.PlaylistID,
+			Position:    t.Position,
+			DeletedAt:   t.BackedUpAt,
+			RestoredAt:  t.RestoredAt,
+		})
+	}
+
+	bc.JSONSuccess(c, response)
+}
+
+// RestoreTracks handles POST /backup/restore
+func (bc *BackupController) RestoreTracks(c *gin.Context) {
+	var req backup.RestoreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bc.JSONValidationError(c, "Invalid request payload")
+		return
+	}
+
+	// A restore must be explicit: backups, tracks, an operation, or all of them
+	if len(req.BackupIDs) == 0 && len(req.TrackIDs) == 0 && req.OperationID == "" && !req.All {
+		bc.JSONValidationError(c, "One of backup_ids, track_ids, operation_id or all=true must be provided")
+		return
+	}
+
+	filter := domainBackup.Filter{
+		BackupIDs:   req.BackupIDs,
+		TrackIDs:    req.TrackIDs,
+		OperationID: req.OperationID,
+		PlaylistID:  req.PlaylistID,
+		Artist:      req.Artist,
+		Album:       req.Album,
+		DeletedFrom: req.DeletedFrom,
+		DeletedTo:   req.DeletedTo,
+	}
+
+	ctx := c.Request.Context()
+	result, err := bc.restoreTracksUC.Execute(ctx, filter)
+	if err != nil {
+		bc.HandleDomainError(c, err)
+		return
+	}
+
+	bc.JSONSuccess(c, result)
+}
+
+// ClaimLegacyBackups handles POST /backup/legacy/claim
+func (bc *BackupController) ClaimLegacyBackups(c *gin.Context) {
+	result, err := bc.claimLegacyUC.Execute(c.Request.Context())
+	if err != nil {
+		bc.HandleDomainError(c, err)
+		return
+	}
+
+	bc.JSONSuccess(c, result)
+}
+
+// endOfDay moves a date-only bound to the last instant of that day so the
+// whole day is included in the range
+func endOfDay(date *time.Time) *time.Time {
+	if date == nil {
+		return nil
+	}
+	end := date.Add(24*time.Hour - time.Nanosecond)
+	return &end
+}
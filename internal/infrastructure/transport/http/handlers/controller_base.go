@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/RubenPari/clear-songs/internal/application/shared/dto"
 	"github.com/RubenPari/clear-songs/internal/domain/shared"
@@ -17,6 +21,33 @@ func (bc *BaseController) JSONSuccess(c *gin.Context, data any) {
 	c.JSON(http.StatusOK, dto.NewSuccess(data))
 }
 
+// JSONPage sends a page of a list with its pagination metadata. The response carries
+// an ETag of its content, and clients sending it back in If-None-Match get 304 Not
+// Modified while the page is unchanged.
+func (bc *BaseController) JSONPage(c *gin.Context, data any, pagination *dto.Pagination) {
+	content, err := json.Marshal(struct {
+		Data       any             `json:"data"`
+		Pagination *dto.Pagination `json:"pagination"`
+	}{data, pagination})
+	if err != nil {
+		bc.JSONInternalError(c, "An unexpected error occurred")
+		return
+	}
+
+	// Weak, since the response timestamp changes on every request
+	sum := sha256.Sum256(content)
+	etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "private, no-cache")
+
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewPage(data, pagination))
+}
+
 // JSONError sends an error JSON response
 func (bc *BaseController) JSONError(c *gin.Context, status int, code, message string) {
 	c.JSON(status, dto.NewError(code, message))
@@ -47,13 +78,32 @@ func (bc *BaseController) HandleDomainError(c *gin.Context, err error) {
 	switch {
 	case errors.Is(err, shared.ErrValidation):
 		bc.JSONValidationError(c, err.Error())
+	case errors.Is(err, shared.ErrConflict):
+		bc.JSONError(c, http.StatusConflict, "CONFLICT", err.Error())
 	case errors.Is(err, shared.ErrNotFound):
 		bc.JSONNotFound(c, "Resource")
 	case errors.Is(err, shared.ErrUnauthorized):
 		bc.JSONUnauthorized(c)
+	case errors.Is(err, shared.ErrForbidden):
+		bc.JSONError(c, http.StatusForbidden, "FORBIDDEN", err.Error())
 	case errors.Is(err, shared.ErrExternalAPI):
 		c.JSON(http.StatusBadGateway, dto.NewError("EXTERNAL_API_ERROR", err.Error()))
+	case errors.Is(err, shared.ErrBackupFailed):
+		bc.JSONError(c, http.StatusServiceUnavailable, "BACKUP_FAILED", err.Error())
+	case errors.Is(err, shared.ErrUnavailable):
+		bc.JSONError(c, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE", err.Error())
 	default:
 		bc.JSONInternalError(c, "An unexpected error occurred")
 	}
 }
+
+// etagMatches reports whether an If-None-Match header lists etag, comparing weakly
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	appJob "github.com/RubenPari/clear-songs/internal/application/job"
+	"github.com/RubenPari/clear-songs/internal/application/shared/dto"
+	"github.com/RubenPari/clear-songs/internal/infrastructure/transport/http/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// JobController exposes the state of background jobs
+type JobController struct {
+	BaseController
+	jobs *appJob.Manager
+}
+
+// NewJobController creates a new job controller
+func NewJobController(jobs *appJob.Manager) *JobController {
+	return &JobController{jobs: jobs}
+}
+
+// GetJob handles GET /jobs/:id
+func (jc *JobController) GetJob(c *gin.Context) {
+	j, err := jc.jobs.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		jc.HandleDomainError(c, err)
+		return
+	}
+
+	jc.JSONSuccess(c, appJob.NewJobResponse(j))
+}
+
+// CancelJob handles POST /jobs/:id/cancel
+func (jc *JobController) CancelJob(c *gin.Context) {
+	j, err := jc.jobs.Cancel(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		jc.HandleDomainError(c, err)
+		return
+	}
+
+	jc.JSONSuccess(c, appJob.NewJobResponse(j))
+}
+
+// enqueueJob runs task in the background and replies 202 Accepted with the new job.
+// The task keeps using the Spotify client of the request after the response is sent, so
+// a token the client refreshes meanwhile is persisted to the session once it finishes.
+func enqueueJob(c *gin.Context, bc *BaseController, jobs *appJob.Manager, jobType string, task appJob.Task) {
+	if persistToken, ok := c.Value(middleware.PersistTokenKey).(func(context.Context)); ok {
+		run := task
+		task = func(ctx context.Context) error {
+			err := run(ctx)
+			persistToken(context.WithoutCancel(ctx))
+			return err
+		}
+	}
+
+	j, err := jobs.Enqueue(c.Request.Context(), jobType, task)
+	if err != nil {
+		bc.HandleDomainError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.NewSuccess(appJob.NewJobResponse(j)))
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	appJob "github.com/RubenPari/clear-songs/internal/application/job"
+	"github.com/RubenPari/clear-songs/internal/application/library"
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportFileSize bounds the size of uploaded import files
+const maxImportFileSize = 10 << 20
+
+// LibraryController handles whole-library endpoints
+type LibraryController struct {
+	BaseController
+	exportLibraryUC   *library.ExportLibraryUseCase
+	importLibraryUC   *library.ImportLibraryUseCase
+	findDuplicatesUC  *library.FindDuplicatesUseCase
+	mergeDuplicatesUC *library.MergeDuplicatesUseCase
+	jobs              *appJob.Manager
+}
+
+// NewLibraryController creates a new library controller
+func NewLibraryController(
+	exportLibraryUC *library.ExportLibraryUseCase,
+	importLibraryUC *library.ImportLibraryUseCase,
+	findDuplicatesUC *library.FindDuplicatesUseCase,
+	mergeDuplicatesUC *library.MergeDuplicatesUseCase,
+	jobs *appJob.Manager,
+) *LibraryController {
+	return &LibraryController{
+		exportLibraryUC:   exportLibraryUC,
+		importLibraryUC:   importLibraryUC,
+		findDuplicatesUC:  findDuplicatesUC,
+		mergeDuplicatesUC: mergeDuplicatesUC,
+		jobs:              jobs,
+	}
+}
+
+// ExportLibrary handles GET /library/export
+func (lc *LibraryController) ExportLibrary(c *gin.Context) {
+	var req library.ExportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		lc.JSONValidationError(c, "format must be one of csv, json, m3u8, xspf")
+		return
+	}
+
+	filename := "library"
+	if req.PlaylistID != "" {
+		filename = "playlist-" + req.PlaylistID
+	}
+	c.Header("Content-Type", library.ContentType(req.Format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, filename, req.Format))
+
+	if err := lc.exportLibraryUC.Execute(c.Request.Context(), req, c.Writer); err != nil {
+		// Once rows are streamed the status is sent; the client sees a truncated file
+		if c.Writer.Written() {
+			log.Printf("ERROR: Library export interrupted: %v", err)
+			c.Abort()
+			return
+		}
+
+		// The error is JSON, not the requested file
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		lc.HandleDomainError(c, err)
+	}
+}
+
+// ImportLibrary handles POST /library/import
+func (lc *LibraryController) ImportLibrary(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportFileSize)
+
+	var req library.ImportRequest
+	if err := c.ShouldBind(&req); err != nil {
+		lc.JSONValidationError(c, "format must be csv or json")
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		lc.JSONValidationError(c, "A CSV or JSON file of at most 10 MB is required in the file field")
+		return
+	}
+
+	// Detect the format from the file name when not given
+	if req.Format == "" {
+		req.Format = library.FormatCSV
+		if strings.EqualFold(filepath.Ext(header.Filename), ".json") {
+			req.Format = library.FormatJSON
+		}
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		lc.JSONInternalError(c, "Failed to read the uploaded file")
+		return
+	}
+	defer file.Close()
+
+	result, err := lc.importLibraryUC.Execute(c.Request.Context(), req, file)
+	if err != nil {
+		lc.HandleDomainError(c, err)
+		return
+	}
+
+	lc.JSONSuccess(c, result)
+}
+
+// FindDuplicates handles GET /library/duplicates
+func (lc *LibraryController) FindDuplicates(c *gin.Context) {
+	var req library.DuplicatesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		lc.JSONValidationError(c, "tolerance_ms must be between 0 and 60000")
+		return
+	}
+
+	result, err := lc.findDuplicatesUC.Execute(c.Request.Context(), req)
+	if err != nil {
+		lc.HandleDomainError(c, err)
+		return
+	}
+
+	lc.JSONSuccess(c, result)
+}
+
+// MergeDuplicates handles POST /library/duplicates/merge
+func (lc *LibraryController) MergeDuplicates(c *gin.Context) {
+	var req library.MergeDuplicatesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		lc.JSONValidationError(c, "Invalid tolerance_ms, dry_run or async parameter")
+		return
+	}
+
+	// Run in the background, the client polls GET /jobs/:id
+	if req.Async && !req.DryRun {
+		enqueueJob(c, &lc.BaseController, lc.jobs, "merge_duplicates", func(ctx context.Context) error {
+			_, err := lc.mergeDuplicatesUC.Execute(ctx, req)
+			return err
+		})
+		return
+	}
+
+	result, err := lc.mergeDuplicatesUC.Execute(c.Request.Context(), req)
+	if err != nil {
+		lc.HandleDomainError(c, err)
+		return
+	}
+
+	lc.JSONSuccess(c, result)
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+
+	appJob "github.com/RubenPari/clear-songs/internal/application/job"
+	"github.com/RubenPari/clear-songs/internal/application/snapshot"
+	domainSnapshot "github.com/RubenPari/clear-songs/internal/domain/snapshot"
+	"github.com/gin-gonic/gin"
+)
+
+// LibrarySnapshotListRequest validates the query parameters of the snapshot listing
+type LibrarySnapshotListRequest struct {
+	Limit int `form:"limit,default=20" binding:"min=1,max=100"`
+}
+
+// LibrarySnapshotController handles the library history endpoints
+type LibrarySnapshotController struct {
+	BaseController
+	captureUC *snapshot.CaptureLibraryUseCase
+	listUC    *snapshot.ListLibrarySnapshotsUseCase
+	diffUC    *snapshot.DiffLibrarySnapshotsUseCase
+	restoreUC *snapshot.RestoreLibraryDiffUseCase
+	jobs      *appJob.Manager
+}
+
+// NewLibrarySnapshotController creates a new library snapshot controller
+func NewLibrarySnapshotController(
+	captureUC *snapshot.CaptureLibraryUseCase,
+	listUC *snapshot.ListLibrarySnapshotsUseCase,
+	diffUC *snapshot.DiffLibrarySnapshotsUseCase,
+	restoreUC *snapshot.RestoreLibraryDiffUseCase,
+	jobs *appJob.Manager,
+) *LibrarySnapshotController {
+	return &LibrarySnapshotController{
+		captureUC: captureUC,
+		listUC:    listUC,
+		diffUC:    diffUC,
+		restoreUC: restoreUC,
+		jobs:      jobs,
+	}
+}
+
+// TakeSnapshot handles POST /library/snapshots
+func (sc *LibrarySnapshotController) TakeSnapshot(c *gin.Context) {
+	taken, err := sc.captureUC.Execute(c.Request.Context(), domainSnapshot.ReasonManual)
+	if err != nil {
+		sc.HandleDomainError(c, err)
+		return
+	}
+
+	sc.JSONSuccess(c, snapshot.NewLibrarySnapshotResponse(taken))
+}
+
+// GetSnapshots handles GET /library/snapshots
+func (sc *LibrarySnapshotController) GetSnapshots(c *gin.Context) {
+	var req LibrarySnapshotListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		sc.JSONValidationError(c, "limit must be between 1 and 100")
+		return
+	}
+
+	snapshots, err := sc.listUC.Execute(c.Request.Context(), req.Limit)
+	if err != nil {
+		sc.HandleDomainError(c, err)
+		return
+	}
+
+	response := make([]snapshot.LibrarySnapshotResponse, 0, len(snapshots))
+	for i := range snapshots {
+		response = append(response, snapshot.NewLibrarySnapshotResponse(&snapshots[i]))
+	}
+
+	sc.JSONSuccess(c, response)
+}
+
+// DiffSnapshots handles GET /library/diff
+func (sc *LibrarySnapshotController) DiffSnapshots(c *gin.Context) {
+	var req snapshot.LibraryDiffRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		sc.JSONValidationError(c, "from is required")
+		return
+	}
+
+	result, err := sc.diffUC.Execute(c.Request.Context(), req)
+	if err != nil {
+		sc.HandleDomainError(c, err)
+		return
+	}
+
+	sc.JSONSuccess(c, result)
+}
+
+// RestoreDiff handles POST /library/diff/restore
+func (sc *LibrarySnapshotController) RestoreDiff(c *gin.Context) {
+	var req snapshot.LibraryDiffRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		sc.JSONValidationError(c, "from is required")
+		return
+	}
+
+	// Run in the background, the client polls GET /jobs/:id
+	if req.Async {
+		enqueueJob(c, &sc.BaseController, sc.jobs, "restore_library_diff", func(ctx context.Context) error {
+			_, err := sc.restoreUC.Execute(ctx, req)
+			return err
+		})
+		return
+	}
+
+	result, err := sc.restoreUC.Execute(c.Request.Context(), req)
+	if err != nil {
+		sc.HandleDomainError(c, err)
+		return
+	}
+
+	sc.JSONSuccess(c, result)
+}
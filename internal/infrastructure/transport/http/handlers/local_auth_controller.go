@@ -2,14 +2,14 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net/http"
-	"os"
-	"time"
 
 	"github.com/RubenPari/clear-songs/internal/application/auth"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	"github.com/RubenPari/clear-songs/internal/infrastructure/transport/http/middleware"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 )
 
 type LocalAuthController struct {
@@ -33,6 +33,9 @@ func (ac *LocalAuthController) Register(c *gin.Context) {
 	ctx := context.Background()
 	err := ac.authService.Register(ctx, req)
 	if err != nil {
+		if ac.unavailable(c, err) {
+			return
+		}
 		if err == auth.ErrUserExists {
 			ac.JSONValidationError(c, "User already exists")
 			return
@@ -55,6 +58,9 @@ func (ac *LocalAuthController) ConfirmEmail(c *gin.Context) {
 	ctx := context.Background()
 	err := ac.authService.ConfirmEmail(ctx, token)
 	if err != nil {
+		if ac.unavailable(c, err) {
+			return
+		}
 		ac.JSONError(c, http.StatusBadRequest, "INVALID_TOKEN", "Invalid or expired token")
 		return
 	}
@@ -72,6 +78,9 @@ func (ac *LocalAuthController) Login(c *gin.Context) {
 	ctx := context.Background()
 	user, err := ac.authService.Login(ctx, req)
 	if err != nil {
+		if ac.unavailable(c, err) {
+			return
+		}
 		if err == auth.ErrInvalidCredentials {
 			ac.JSONError(c, http.StatusUnauthorized, "UNAUTHORIZED", "Invalid credentials")
 			return
@@ -85,25 +94,14 @@ func (ac *LocalAuthController) Login(c *gin.Context) {
 	}
 
 	// Generate JWT
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		jwtSecret = "fallback-secret-for-dev"
-	}
-
-	claims := jwt.MapClaims{
-		"sub":   user.ID,
-		"email": user.Email,
-		"exp":   time.Now().Add(7 * 24 * time.Hour).Unix(), // 7 days
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(jwtSecret))
+	tokenString, err := auth.IssueToken(user)
 	if err != nil {
 		ac.JSONInternalError(c, "Failed to generate token")
 		return
 	}
 
 	// Set as HTTP-only cookie
-	c.SetCookie("auth_token", tokenString, 7*24*3600, "/", "", false, true)
+	c.SetCookie(middleware.AuthTokenCookieName, tokenString, int(auth.TokenTTL.Seconds()), "/", "", false, true)
 
 	ac.JSONSuccess(c, gin.H{
 		"user": gin.H{
@@ -126,6 +124,9 @@ func (ac *LocalAuthController) ForgotPassword(c *gin.Context) {
 	ctx := context.Background()
 	// Ignore errors to prevent email enumeration, but log them for debugging
 	if err := ac.authService.ForgotPassword(ctx, req.Email); err != nil {
+		if ac.unavailable(c, err) {
+			return
+		}
 		// Log the error but don't expose it to the client
 		log.Printf("ERROR: ForgotPassword failed for email %s: %v", req.Email, err)
 	}
@@ -143,6 +144,9 @@ func (ac *LocalAuthController) ResetPassword(c *gin.Context) {
 	ctx := context.Background()
 	err := ac.authService.ResetPassword(ctx, req)
 	if err != nil {
+		if ac.unavailable(c, err) {
+			return
+		}
 		ac.JSONError(c, http.StatusBadRequest, "BAD_REQUEST", "Failed to reset password. Token may be invalid.")
 		return
 	}
@@ -166,6 +170,9 @@ func (ac *LocalAuthController) ChangePassword(c *gin.Context) {
 	ctx := context.Background()
 	err := ac.authService.ChangePassword(ctx, userID.(string), req)
 	if err != nil {
+		if ac.unavailable(c, err) {
+			return
+		}
 		if err == auth.ErrInvalidCredentials {
 			ac.JSONError(c, http.StatusBadRequest, "BAD_REQUEST", "Invalid old password")
 			return
@@ -177,7 +184,17 @@ func (ac *LocalAuthController) ChangePassword(c *gin.Context) {
 	ac.JSONSuccess(c, gin.H{"message": "Password changed successfully."})
 }
 
+// unavailable answers 503 when err reports that local accounts cannot be stored, e.g.
+// because no database is configured
+func (ac *LocalAuthController) unavailable(c *gin.Context, err error) bool {
+	if !errors.Is(err, shared.ErrUnavailable) {
+		return false
+	}
+	ac.HandleDomainError(c, err)
+	return true
+}
+
 func (ac *LocalAuthController) Logout(c *gin.Context) {
-	c.SetCookie("auth_token", "", -1, "/", "", false, true)
+	c.SetCookie(middleware.AuthTokenCookieName, "", -1, "/", "", false, true)
 	ac.JSONSuccess(c, gin.H{"message": "Logged out successfully"})
 }
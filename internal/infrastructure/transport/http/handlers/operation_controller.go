@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"github.com/RubenPari/clear-songs/internal/application/operation"
+	"github.com/gin-gonic/gin"
+)
+
+// OperationListRequest validates the query parameters of the operation listing
+type OperationListRequest struct {
+	Limit int `form:"limit,default=20" binding:"min=1,max=100"`
+}
+
+// OperationController handles the undo log endpoints
+type OperationController struct {
+	BaseController
+	listOperationsUC *operation.ListOperationsUseCase
+	undoOperationUC  *operation.UndoOperationUseCase
+}
+
+// NewOperationController creates a new operation controller
+func NewOperationController(
+	listOperationsUC *operation.ListOperationsUseCase,
+	undoOperationUC *operation.UndoOperationUseCase,
+) *OperationController {
+	return &OperationController{
+		listOperationsUC: listOperationsUC,
+		undoOperationUC:  undoOperationUC,
+	}
+}
+
+// GetOperations handles GET /operations
+func (oc *OperationController) GetOperations(c *gin.Context) {
+	var req OperationListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		oc.JSONValidationError(c, "limit must be between 1 and 100")
+		return
+	}
+
+	operations, err := oc.listOperationsUC.Execute(c.Request.Context(), req.Limit)
+	if err != nil {
+		oc.HandleDomainError(c, err)
+		return
+	}
+
+	response := make([]operation.OperationResponse, 0, len(operations))
+	for i := range operations {
+		response = append(response, operation.NewOperationResponse(&operations[i]))
+	}
+
+	oc.JSONSuccess(c, response)
+}
+
+// UndoOperation handles POST /operations/:id/undo
+func (oc *OperationController) UndoOperation(c *gin.Context) {
+	result, err := oc.undoOperationUC.Execute(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		oc.HandleDomainError(c, err)
+		return
+	}
+
+	oc.JSONSuccess(c, result)
+}
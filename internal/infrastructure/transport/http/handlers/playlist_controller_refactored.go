@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 
+	appJob "github.com/RubenPari/clear-songs/internal/application/job"
 	"github.com/RubenPari/clear-songs/internal/application/playlist"
 	"github.com/RubenPari/clear-songs/internal/domain/shared/utils"
 	"github.com/gin-gonic/gin"
@@ -11,7 +12,10 @@ import (
 
 // PlaylistRequest validates the incoming query parameters
 type PlaylistRequest struct {
-	ID string `form:"id" binding:"required"`
+	playlist.FilterRequest
+	ID     string `form:"id" binding:"required"`
+	DryRun bool   `form:"dry_run"`
+	Async  bool   `form:"async"`
 }
 
 // PlaylistControllerRefactored is the refactored playlist controller using dependency injection
@@ -20,6 +24,8 @@ type PlaylistControllerRefactored struct {
 	getUserPlaylistsUC         *playlist.GetUserPlaylistsUseCase
 	deletePlaylistTracksUC     *playlist.DeletePlaylistTracksUseCase
 	deletePlaylistAndLibraryUC *playlist.DeletePlaylistAndLibraryTracksUseCase
+	dedupePlaylistUC           *playlist.DedupePlaylistUseCase
+	jobs                       *appJob.Manager
 }
 
 // NewPlaylistControllerRefactored creates a new playlist controller
@@ -27,17 +33,21 @@ func NewPlaylistControllerRefactored(
 	getUserPlaylistsUC *playlist.GetUserPlaylistsUseCase,
 	deletePlaylistTracksUC *playlist.DeletePlaylistTracksUseCase,
 	deletePlaylistAndLibraryUC *playlist.DeletePlaylistAndLibraryTracksUseCase,
+	dedupePlaylistUC *playlist.DedupePlaylistUseCase,
+	jobs *appJob.Manager,
 ) *PlaylistControllerRefactored {
 	return &PlaylistControllerRefactored{
 		getUserPlaylistsUC:         getUserPlaylistsUC,
 		deletePlaylistTracksUC:     deletePlaylistTracksUC,
 		deletePlaylistAndLibraryUC: deletePlaylistAndLibraryUC,
+		dedupePlaylistUC:           dedupePlaylistUC,
+		jobs:                       jobs,
 	}
 }
 
 // GetUserPlaylists handles GET /playlist/list
 func (pc *PlaylistControllerRefactored) GetUserPlaylists(c *gin.Context) {
-	ctx := context.Background() // Better approach: use c.Request.Context()
+	ctx := c.Request.Context()
 	playlists, err := pc.getUserPlaylistsUC.Execute(ctx)
 	if err != nil {
 		pc.HandleDomainError(c, err)
@@ -63,14 +73,39 @@ func (pc *PlaylistControllerRefactored) GetUserPlaylists(c *gin.Context) {
 func (pc *PlaylistControllerRefactored) DeleteAllPlaylistTracks(c *gin.Context) {
 	var req PlaylistRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		pc.JSONValidationError(c, "Playlist id is required")
+		pc.JSONValidationError(c, "Playlist id is required and filters must be valid")
+		return
+	}
+
+	filter, err := req.ToFilter()
+	if err != nil {
+		pc.HandleDomainError(c, err)
 		return
 	}
 
 	playlistID := spotifyAPI.ID(req.ID)
-	ctx := context.Background()
+	ctx := c.Request.Context()
+
+	// Preview only, nothing is removed
+	if req.DryRun {
+		preview, err := pc.deletePlaylistTracksUC.Preview(ctx, playlistID, filter)
+		if err != nil {
+			pc.HandleDomainError(c, err)
+			return
+		}
+		pc.JSONSuccess(c, preview)
+		return
+	}
 
-	if err := pc.deletePlaylistTracksUC.Execute(ctx, playlistID); err != nil {
+	// Run in the background, the client polls GET /jobs/:id
+	if req.Async {
+		enqueueJob(c, &pc.BaseController, pc.jobs, "delete_playlist_tracks", func(ctx context.Context) error {
+			return pc.deletePlaylistTracksUC.Execute(ctx, playlistID, filter)
+		})
+		return
+	}
+
+	if err := pc.deletePlaylistTracksUC.Execute(ctx, playlistID, filter); err != nil {
 		pc.HandleDomainError(c, err)
 		return
 	}
@@ -82,17 +117,70 @@ func (pc *PlaylistControllerRefactored) DeleteAllPlaylistTracks(c *gin.Context)
 func (pc *PlaylistControllerRefactored) DeleteAllPlaylistAndUserTracks(c *gin.Context) {
 	var req PlaylistRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		pc.JSONValidationError(c, "Playlist id is required")
+		pc.JSONValidationError(c, "Playlist id is required and filters must be valid")
+		return
+	}
+
+	filter, err := req.ToFilter()
+	if err != nil {
+		pc.HandleDomainError(c, err)
 		return
 	}
 
 	playlistID := spotifyAPI.ID(req.ID)
-	ctx := context.Background()
+	ctx := c.Request.Context()
+
+	// Preview only, nothing is removed
+	if req.DryRun {
+		preview, err := pc.deletePlaylistAndLibraryUC.Preview(ctx, playlistID, filter)
+		if err != nil {
+			pc.HandleDomainError(c, err)
+			return
+		}
+		pc.JSONSuccess(c, preview)
+		return
+	}
+
+	// Run in the background, the client polls GET /jobs/:id
+	if req.Async {
+		enqueueJob(c, &pc.BaseController, pc.jobs, "delete_playlist_and_library_tracks", func(ctx context.Context) error {
+			return pc.deletePlaylistAndLibraryUC.Execute(ctx, playlistID, filter)
+		})
+		return
+	}
 
-	if err := pc.deletePlaylistAndLibraryUC.Execute(ctx, playlistID); err != nil {
+	if err := pc.deletePlaylistAndLibraryUC.Execute(ctx, playlistID, filter); err != nil {
 		pc.HandleDomainError(c, err)
 		return
 	}
 
 	pc.JSONSuccess(c, gin.H{"message": "Tracks deleted successfully"})
 }
+
+// DedupePlaylist handles POST /playlist/:id/dedupe
+func (pc *PlaylistControllerRefactored) DedupePlaylist(c *gin.Context) {
+	playlistID := spotifyAPI.ID(c.Param("id"))
+
+	var req playlist.DedupeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		pc.JSONValidationError(c, "Invalid dry_run or async parameter")
+		return
+	}
+
+	// Run in the background, the client polls GET /jobs/:id
+	if req.Async && !req.DryRun {
+		enqueueJob(c, &pc.BaseController, pc.jobs, "dedupe_playlist", func(ctx context.Context) error {
+			_, err := pc.dedupePlaylistUC.Execute(ctx, playlistID, req)
+			return err
+		})
+		return
+	}
+
+	result, err := pc.dedupePlaylistUC.Execute(c.Request.Context(), playlistID, req)
+	if err != nil {
+		pc.HandleDomainError(c, err)
+		return
+	}
+
+	pc.JSONSuccess(c, result)
+}
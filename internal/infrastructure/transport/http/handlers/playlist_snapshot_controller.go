@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"github.com/RubenPari/clear-songs/internal/application/snapshot"
+	domainSnapshot "github.com/RubenPari/clear-songs/internal/domain/snapshot"
+	"github.com/gin-gonic/gin"
+	spotifyAPI "github.com/zmb3/spotify"
+)
+
+// PlaylistSnapshotListRequest validates the query parameters of the snapshot listing
+type PlaylistSnapshotListRequest struct {
+	Limit int `form:"limit,default=20" binding:"min=1,max=100"`
+}
+
+// PlaylistSnapshotController handles the playlist history endpoints
+type PlaylistSnapshotController struct {
+	BaseController
+	captureUC *snapshot.CapturePlaylistUseCase
+	listUC    *snapshot.ListPlaylistSnapshotsUseCase
+	diffUC    *snapshot.DiffPlaylistSnapshotsUseCase
+	restoreUC *snapshot.RestorePlaylistSnapshotUseCase
+}
+
+// NewPlaylistSnapshotController creates a new playlist snapshot controller
+func NewPlaylistSnapshotController(
+	captureUC *snapshot.CapturePlaylistUseCase,
+	listUC *snapshot.ListPlaylistSnapshotsUseCase,
+	diffUC *snapshot.DiffPlaylistSnapshotsUseCase,
+	restoreUC *snapshot.RestorePlaylistSnapshotUseCase,
+) *PlaylistSnapshotController {
+	return &PlaylistSnapshotController{
+		captureUC: captureUC,
+		listUC:    listUC,
+		diffUC:    diffUC,
+		restoreUC: restoreUC,
+	}
+}
+
+// TakeSnapshot handles POST /playlist/:id/snapshots
+func (sc *PlaylistSnapshotController) TakeSnapshot(c *gin.Context) {
+	taken, err := sc.captureUC.Execute(c.Request.Context(), spotifyAPI.ID(c.Param("id")), domainSnapshot.ReasonManual)
+	if err != nil {
+		sc.HandleDomainError(c, err)
+		return
+	}
+
+	sc.JSONSuccess(c, snapshot.NewPlaylistSnapshotResponse(taken))
+}
+
+// GetSnapshots handles GET /playlist/:id/snapshots
+func (sc *PlaylistSnapshotController) GetSnapshots(c *gin.Context) {
+	var req PlaylistSnapshotListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		sc.JSONValidationError(c, "limit must be between 1 and 100")
+		return
+	}
+
+	snapshots, err := sc.listUC.Execute(c.Request.Context(), spotifyAPI.ID(c.Param("id")), req.Limit)
+	if err != nil {
+		sc.HandleDomainError(c, err)
+		return
+	}
+
+	response := make([]snapshot.PlaylistSnapshotResponse, 0, len(snapshots))
+	for i := range snapshots {
+		response = append(response, snapshot.NewPlaylistSnapshotResponse(&snapshots[i]))
+	}
+
+	sc.JSONSuccess(c, response)
+}
+
+// DiffSnapshots handles GET /playlist/:id/snapshots/diff
+func (sc *PlaylistSnapshotController) DiffSnapshots(c *gin.Context) {
+	var req snapshot.PlaylistDiffRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		sc.JSONValidationError(c, "from is required")
+		return
+	}
+
+	result, err := sc.diffUC.Execute(c.Request.Context(), spotifyAPI.ID(c.Param("id")), req)
+	if err != nil {
+		sc.HandleDomainError(c, err)
+		return
+	}
+
+	sc.JSONSuccess(c, result)
+}
+
+// RestoreSnapshot handles POST /playlist/:id/snapshots/:snapshot_id/restore
+func (sc *PlaylistSnapshotController) RestoreSnapshot(c *gin.Context) {
+	result, err := sc.restoreUC.Execute(c.Request.Context(), spotifyAPI.ID(c.Param("id")), c.Param("snapshot_id"))
+	if err != nil {
+		sc.HandleDomainError(c, err)
+		return
+	}
+
+	sc.JSONSuccess(c, result)
+}
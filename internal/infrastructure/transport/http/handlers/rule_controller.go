@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+
+	appJob "github.com/RubenPari/clear-songs/internal/application/job"
+	"github.com/RubenPari/clear-songs/internal/application/rule"
+	"github.com/gin-gonic/gin"
+)
+
+// RuleRunRequest holds the query parameters of the manual run endpoint
+type RuleRunRequest struct {
+	rule.RunRequest
+	Async bool `form:"async"`
+}
+
+// RuleController handles the cleanup rule endpoints
+type RuleController struct {
+	BaseController
+	listRulesUC  *rule.ListRulesUseCase
+	createRuleUC *rule.CreateRuleUseCase
+	updateRuleUC *rule.UpdateRuleUseCase
+	deleteRuleUC *rule.DeleteRuleUseCase
+	runRuleUC    *rule.RunRuleUseCase
+	jobs         *appJob.Manager
+}
+
+// NewRuleController creates a new rule controller
+func NewRuleController(
+	listRulesUC *rule.ListRulesUseCase,
+	createRuleUC *rule.CreateRuleUseCase,
+	updateRuleUC *rule.UpdateRuleUseCase,
+	deleteRuleUC *rule.DeleteRuleUseCase,
+	runRuleUC *rule.RunRuleUseCase,
+	jobs *appJob.Manager,
+) *RuleController {
+	return &RuleController{
+		listRulesUC:  listRulesUC,
+		createRuleUC: createRuleUC,
+		updateRuleUC: updateRuleUC,
+		deleteRuleUC: deleteRuleUC,
+		runRuleUC:    runRuleUC,
+		jobs:         jobs,
+	}
+}
+
+// GetRules handles GET /rules
+func (rc *RuleController) GetRules(c *gin.Context) {
+	rules, err := rc.listRulesUC.Execute(c.Request.Context())
+	if err != nil {
+		rc.HandleDomainError(c, err)
+		return
+	}
+
+	response := make([]rule.RuleResponse, 0, len(rules))
+	for i := range rules {
+		response = append(response, rule.NewRuleResponse(&rules[i]))
+	}
+
+	rc.JSONSuccess(c, response)
+}
+
+// CreateRule handles POST /rules
+func (rc *RuleController) CreateRule(c *gin.Context) {
+	var req rule.RuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rc.JSONValidationError(c, "Invalid request payload")
+		return
+	}
+
+	created, err := rc.createRuleUC.Execute(c.Request.Context(), req)
+	if err != nil {
+		rc.HandleDomainError(c, err)
+		return
+	}
+
+	rc.JSONSuccess(c, rule.NewRuleResponse(created))
+}
+
+// UpdateRule handles PUT /rules/:id
+func (rc *RuleController) UpdateRule(c *gin.Context) {
+	var req rule.RuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rc.JSONValidationError(c, "Invalid request payload")
+		return
+	}
+
+	updated, err := rc.updateRuleUC.Execute(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		rc.HandleDomainError(c, err)
+		return
+	}
+
+	rc.JSONSuccess(c, rule.NewRuleResponse(updated))
+}
+
+// DeleteRule handles DELETE /rules/:id
+func (rc *RuleController) DeleteRule(c *gin.Context) {
+	if err := rc.deleteRuleUC.Execute(c.Request.Context(), c.Param("id")); err != nil {
+		rc.HandleDomainError(c, err)
+		return
+	}
+
+	rc.JSONSuccess(c, gin.H{"message": "Rule deleted successfully"})
+}
+
+// RunRule handles POST /rules/:id/run
+func (rc *RuleController) RunRule(c *gin.Context) {
+	var req RuleRunRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		rc.JSONValidationError(c, "dry_run and async must be booleans")
+		return
+	}
+
+	ruleID := c.Param("id")
+	opts := rule.RunOptions{DryRun: req.DryRun}
+
+	// Run in the background, the client polls GET /jobs/:id
+	if req.Async {
+		enqueueJob(c, &rc.BaseController, rc.jobs, "cleanup_rule", func(ctx context.Context) error {
+			_, err := rc.runRuleUC.Execute(ctx, ruleID, opts)
+			return err
+		})
+		return
+	}
+
+	result, err := rc.runRuleUC.Execute(c.Request.Context(), ruleID, opts)
+	if err != nil {
+		rc.HandleDomainError(c, err)
+		return
+	}
+
+	rc.JSONSuccess(c, result)
+}
@@ -3,8 +3,11 @@ package handlers
 import (
 	"context"
 
+	appJob "github.com/RubenPari/clear-songs/internal/application/job"
+	"github.com/RubenPari/clear-songs/internal/application/shared/dto"
 	"github.com/RubenPari/clear-songs/internal/application/track"
 	"github.com/RubenPari/clear-songs/internal/domain/shared/utils"
+	domainTrack "github.com/RubenPari/clear-songs/internal/domain/track"
 	"github.com/gin-gonic/gin"
 	spotifyAPI "github.com/zmb3/spotify"
 )
@@ -17,6 +20,9 @@ type TrackControllerComplete struct {
 	deleteTracksByRangeUC  *track.DeleteTracksByRangeUseCase
 	deleteTrackUC          *track.DeleteTrackUseCase
 	getTracksByArtistUC    *track.GetTracksByArtistUseCase
+	getGenreSummaryUC      *track.GetGenreSummaryUseCase
+	deleteTracksByGenreUC  *track.DeleteTracksByGenreUseCase
+	jobs                   *appJob.Manager
 }
 
 // NewTrackControllerComplete creates a new complete track controller
@@ -26,6 +32,9 @@ func NewTrackControllerComplete(
 	deleteByRangeUC *track.DeleteTracksByRangeUseCase,
 	getTracksByArtistUC *track.GetTracksByArtistUseCase,
 	deleteTrackUC *track.DeleteTrackUseCase,
+	getGenreSummaryUC *track.GetGenreSummaryUseCase,
+	deleteByGenreUC *track.DeleteTracksByGenreUseCase,
+	jobs *appJob.Manager,
 ) *TrackControllerComplete {
 	return &TrackControllerComplete{
 		getTrackSummaryUseCase: getTrackSummaryUC,
@@ -33,35 +42,96 @@ func NewTrackControllerComplete(
 		deleteTracksByRangeUC:  deleteByRangeUC,
 		deleteTrackUC:          deleteTrackUC,
 		getTracksByArtistUC:    getTracksByArtistUC,
+		getGenreSummaryUC:      getGenreSummaryUC,
+		deleteTracksByGenreUC:  deleteByGenreUC,
+		jobs:                   jobs,
 	}
 }
 
 // GetTrackSummary handles GET /track/summary
 func (tc *TrackControllerComplete) GetTrackSummary(c *gin.Context) {
-	var req track.RangeRequest
+	var req track.SummaryRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		tc.JSONValidationError(c, "Invalid min or max parameters")
+		tc.JSONValidationError(c, "Invalid min, max, match, sort, order, limit or offset parameters")
 		return
 	}
 
+	// Each sort field has a natural direction unless an order is given
+	sort := domainTrack.SummarySort(req.Sort)
+	descending := sort.DefaultDescending()
+	if req.Order != "" {
+		descending = req.Order == "desc"
+	}
+
 	// Execute use case
 	// Note: the original manual validation fell back to 0 if min/max strings were empty,
 	// which matches how Gin parses missing query integers.
-	ctx := context.Background() // In production, c.Request.Context() is preferred.
-	result, err := tc.getTrackSummaryUseCase.Execute(ctx, req.Min, req.Max)
+	ctx := c.Request.Context()
+	page, err := tc.getTrackSummaryUseCase.Query(ctx, domainTrack.SummaryQuery{
+		Min:        req.Min,
+		Max:        req.Max,
+		Match:      domainTrack.ArtistMatch(req.Match),
+		Search:     req.Search,
+		Sort:       sort,
+		Descending: descending,
+		Limit:      req.Limit,
+		Offset:     req.Offset,
+	})
 	if err != nil {
 		tc.HandleDomainError(c, err)
 		return
 	}
 
 	// Convert to API response format (entities to models)
-	var response []track.ArtistSummary
-	for _, artist := range result {
+	response := make([]track.ArtistSummary, 0, len(page.Artists))
+	for _, artist := range page.Artists {
 		response = append(response, track.ArtistSummary{
-			Id:       artist.ID,
-			Name:     artist.Name,
-			Count:    artist.Count,
-			ImageURL: artist.ImageURL,
+			Id:            artist.ID,
+			Name:          artist.Name,
+			Count:         artist.Count,
+			PrimaryCount:  artist.PrimaryCount,
+			FeaturedCount: artist.FeaturedCount,
+			ImageURL:      artist.ImageURL,
+			LastAddedAt:   artist.LastAddedAt,
+		})
+	}
+
+	tc.JSONPage(c, response, dto.NewPagination(page.Total, page.Limit, page.Offset))
+}
+
+// GetGenreSummary handles GET /track/summary/genres
+func (tc *TrackControllerComplete) GetGenreSummary(c *gin.Context) {
+	var req track.GenreRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		tc.JSONValidationError(c, "Invalid match or top parameters")
+		return
+	}
+
+	// Execute use case
+	ctx := c.Request.Context()
+	result, err := tc.getGenreSummaryUC.Execute(ctx, domainTrack.ArtistMatch(req.Match), req.Top)
+	if err != nil {
+		tc.HandleDomainError(c, err)
+		return
+	}
+
+	// Convert to API response format
+	response := make([]track.GenreSummary, 0, len(result))
+	for _, genre := range result {
+		topArtists := make([]track.ArtistSummary, 0, len(genre.TopArtists))
+		for _, artist := range genre.TopArtists {
+			topArtists = append(topArtists, track.ArtistSummary{
+				Id:       artist.ID,
+				Name:     artist.Name,
+				Count:    artist.Count,
+				ImageURL: artist.ImageURL,
+			})
+		}
+		response = append(response, track.GenreSummary{
+			Genre:       genre.Genre,
+			Count:       genre.Count,
+			ArtistCount: genre.ArtistCount,
+			TopArtists:  topArtists,
 		})
 	}
 
@@ -70,18 +140,22 @@ func (tc *TrackControllerComplete) GetTrackSummary(c *gin.Context) {
 
 // GetTracksByArtist handles GET /track/by-artist/:id_artist
 func (tc *TrackControllerComplete) GetTracksByArtist(c *gin.Context) {
-	// Get artist ID from URL
-	idArtistString := c.Param("id_artist")
-	if idArtistString == "" {
-		tc.JSONValidationError(c, "Artist ID is required")
+	var req track.ArtistRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		tc.JSONValidationError(c, "Invalid match parameter")
 		return
 	}
 
-	artistID := spotifyAPI.ID(idArtistString)
+	// Get artist IDs from URL
+	filter, err := domainTrack.ParseArtistFilter(c.Param("id_artist"), req.Match)
+	if err != nil {
+		tc.JSONValidationError(c, "Artist ID is required")
+		return
+	}
 
 	// Execute use case
-	ctx := context.Background() // In production, use c.Request.Context()
-	tracks, err := tc.getTracksByArtistUC.Execute(ctx, artistID)
+	ctx := c.Request.Context()
+	tracks, err := tc.getTracksByArtistUC.Execute(ctx, filter)
 	if err != nil {
 		tc.HandleDomainError(c, err)
 		return
@@ -118,18 +192,87 @@ func (tc *TrackControllerComplete) GetTracksByArtist(c *gin.Context) {
 
 // DeleteTrackByArtist handles DELETE /track/by-artist/:id_artist
 func (tc *TrackControllerComplete) DeleteTrackByArtist(c *gin.Context) {
-	// Get artist ID from URL
-	idArtistString := c.Param("id_artist")
-	if idArtistString == "" {
+	var req track.DeleteRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		tc.JSONValidationError(c, "Invalid match, dry_run or async parameter")
+		return
+	}
+
+	// Get artist IDs from URL
+	filter, err := domainTrack.ParseArtistFilter(c.Param("id_artist"), req.Match)
+	if err != nil {
 		tc.JSONValidationError(c, "Artist ID is required")
 		return
 	}
 
-	artistID := spotifyAPI.ID(idArtistString)
+	ctx := c.Request.Context()
+
+	// Preview only, nothing is removed
+	if req.DryRun {
+		preview, err := tc.deleteTracksByArtistUC.Preview(ctx, filter)
+		if err != nil {
+			tc.HandleDomainError(c, err)
+			return
+		}
+		tc.JSONSuccess(c, preview)
+		return
+	}
+
+	// Run in the background, the client polls GET /jobs/:id
+	if req.Async {
+		enqueueJob(c, &tc.BaseController, tc.jobs, "delete_tracks_by_artist", func(ctx context.Context) error {
+			return tc.deleteTracksByArtistUC.Execute(ctx, filter)
+		})
+		return
+	}
 
 	// Execute use case
-	ctx := context.Background() // In production, use c.Request.Context()
-	if err := tc.deleteTracksByArtistUC.Execute(ctx, artistID); err != nil {
+	if err := tc.deleteTracksByArtistUC.Execute(ctx, filter); err != nil {
+		tc.HandleDomainError(c, err)
+		return
+	}
+
+	tc.JSONSuccess(c, gin.H{"message": "Tracks deleted successfully"})
+}
+
+// DeleteTrackByGenre handles DELETE /track/by-genre/:genre
+func (tc *TrackControllerComplete) DeleteTrackByGenre(c *gin.Context) {
+	var req track.DeleteRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		tc.JSONValidationError(c, "Invalid match, dry_run or async parameter")
+		return
+	}
+
+	genre := domainTrack.NormalizeGenre(c.Param("genre"))
+	if genre == "" {
+		tc.JSONValidationError(c, "Genre is required")
+		return
+	}
+
+	ctx := c.Request.Context()
+	match := domainTrack.ArtistMatch(req.Match)
+
+	// Preview only, nothing is removed
+	if req.DryRun {
+		preview, err := tc.deleteTracksByGenreUC.Preview(ctx, genre, match)
+		if err != nil {
+			tc.HandleDomainError(c, err)
+			return
+		}
+		tc.JSONSuccess(c, preview)
+		return
+	}
+
+	// Run in the background, the client polls GET /jobs/:id
+	if req.Async {
+		enqueueJob(c, &tc.BaseController, tc.jobs, "delete_tracks_by_genre", func(ctx context.Context) error {
+			return tc.deleteTracksByGenreUC.Execute(ctx, genre, match)
+		})
+		return
+	}
+
+	// Execute use case
+	if err := tc.deleteTracksByGenreUC.Execute(ctx, genre, match); err != nil {
 		tc.HandleDomainError(c, err)
 		return
 	}
@@ -149,7 +292,7 @@ func (tc *TrackControllerComplete) DeleteTrack(c *gin.Context) {
 	trackID := spotifyAPI.ID(idTrackString)
 
 	// Execute use case
-	ctx := context.Background() // In production, use c.Request.Context()
+	ctx := c.Request.Context()
 	if err := tc.deleteTrackUC.Execute(ctx, trackID); err != nil {
 		tc.HandleDomainError(c, err)
 		return
@@ -162,7 +305,7 @@ func (tc *TrackControllerComplete) DeleteTrack(c *gin.Context) {
 func (tc *TrackControllerComplete) DeleteTrackByRange(c *gin.Context) {
 	var req track.RangeRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		tc.JSONValidationError(c, "Invalid min or max parameters")
+		tc.JSONValidationError(c, "Invalid min, max or match parameters")
 		return
 	}
 
@@ -172,9 +315,30 @@ func (tc *TrackControllerComplete) DeleteTrackByRange(c *gin.Context) {
 		return
 	}
 
+	ctx := c.Request.Context()
+	match := domainTrack.ArtistMatch(req.Match)
+
+	// Preview only, nothing is removed
+	if req.DryRun {
+		preview, err := tc.deleteTracksByRangeUC.Preview(ctx, req.Min, req.Max, match)
+		if err != nil {
+			tc.HandleDomainError(c, err)
+			return
+		}
+		tc.JSONSuccess(c, preview)
+		return
+	}
+
+	// Run in the background, the client polls GET /jobs/:id
+	if req.Async {
+		enqueueJob(c, &tc.BaseController, tc.jobs, "delete_tracks_by_range", func(ctx context.Context) error {
+			return tc.deleteTracksByRangeUC.Execute(ctx, req.Min, req.Max, match)
+		})
+		return
+	}
+
 	// Execute use case
-	ctx := context.Background() // In production, use c.Request.Context()
-	if err := tc.deleteTracksByRangeUC.Execute(ctx, req.Min, req.Max); err != nil {
+	if err := tc.deleteTracksByRangeUC.Execute(ctx, req.Min, req.Max, match); err != nil {
 		tc.HandleDomainError(c, err)
 		return
 	}
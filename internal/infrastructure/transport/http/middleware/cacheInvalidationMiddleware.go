@@ -1,55 +1,65 @@
 package middleware
 
 import (
+	"strconv"
 	"strings"
 
-	cacheManager "github.com/RubenPari/clear-songs/internal/infrastructure/persistence/redis"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
 	"github.com/gin-gonic/gin"
 	spotifyAPI "github.com/zmb3/spotify"
 )
 
-// CacheInvalidationMiddleware automatically invalidates cache based on the endpoint called
-func CacheInvalidationMiddleware() gin.HandlerFunc {
+// CacheInvalidationMiddleware automatically invalidates cache based on the endpoint called.
+// Invalidation is scoped to the session of the request, so one user's changes never
+// evict another user's cache. Dry runs change nothing, so they keep the cache.
+func CacheInvalidationMiddleware(cacheRepo shared.CacheRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Execute the request first
 		c.Next()
 
-		// Only invalidate cache if the request was successful
-		if c.Writer.Status() >= 200 && c.Writer.Status() < 300 {
+		// Only invalidate cache if the request was successful and changed something
+		dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+		if !dryRun && c.Writer.Status() >= 200 && c.Writer.Status() < 300 {
 			path := c.Request.URL.Path
 			method := c.Request.Method
 
 			// Only invalidate on modification operations (DELETE, POST, PUT, PATCH)
 			if method == "DELETE" || method == "POST" || method == "PUT" || method == "PATCH" {
-				invalidateBasedOnEndpoint(c, path)
+				invalidateBasedOnEndpoint(c, cacheRepo, path)
 			}
 		}
 	}
 }
 
-func invalidateBasedOnEndpoint(c *gin.Context, path string) {
+func invalidateBasedOnEndpoint(c *gin.Context, cacheRepo shared.CacheRepository, path string) {
+	ctx := c.Request.Context()
+
 	switch {
 	case strings.HasPrefix(path, "/track/"):
 		// Any track operation affects user data
-		cacheManager.InvalidateUserData()
+		_ = cacheRepo.InvalidateUserTracks(ctx)
 
 	case strings.HasPrefix(path, "/playlist/"):
 		// Playlist operations
 		if playlistID := c.Query("id"); playlistID != "" {
-			cacheManager.InvalidatePlaylist(spotifyAPI.ID(playlistID))
+			_ = cacheRepo.InvalidatePlaylistTracks(ctx, spotifyAPI.ID(playlistID))
 		}
 
 		// If it's a playlist operation that also affects user library
 		if strings.Contains(path, "all") || strings.Contains(path, "library") {
-			cacheManager.InvalidateUserData()
+			_ = cacheRepo.InvalidateUserTracks(ctx)
 		}
 
+	case strings.HasPrefix(path, "/backup/"):
+		// Restoring backed-up tracks re-saves them to the user library
+		_ = cacheRepo.InvalidateUserTracks(ctx)
+
 	case strings.HasPrefix(path, "/album/"):
 		// Album operations usually affect user library
-		cacheManager.InvalidateUserData()
+		_ = cacheRepo.InvalidateUserTracks(ctx)
 
 	default:
-		// For any other modification operation, do a full reset as a safety measure
-		cacheManager.Reset()
+		// For any other modification operation, drop the user library as a safety measure
+		_ = cacheRepo.InvalidateUserTracks(ctx)
 	}
 }
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/RubenPari/clear-songs/test/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCacheInvalidationMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	setup := func() (*gin.Engine, *mocks.MockCacheRepository) {
+		cache := new(mocks.MockCacheRepository)
+		router := gin.New()
+		router.Use(CacheInvalidationMiddleware(cache))
+		router.DELETE("/track/by-artist/:id", func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+		return router, cache
+	}
+	request := func(router *gin.Engine, path string) {
+		req, _ := http.NewRequest(http.MethodDelete, path, nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	t.Run("Success - should invalidate the library after a delete", func(t *testing.T) {
+		router, cache := setup()
+		cache.On("InvalidateUserTracks", mock.Anything).Return(nil).Once()
+
+		request(router, "/track/by-artist/artist_1")
+
+		cache.AssertExpectations(t)
+	})
+
+	t.Run("Success - should keep the cache on a dry run", func(t *testing.T) {
+		router, cache := setup()
+
+		request(router, "/track/by-artist/artist_1?dry_run=true")
+
+		cache.AssertNotCalled(t, "InvalidateUserTracks", mock.Anything)
+	})
+}
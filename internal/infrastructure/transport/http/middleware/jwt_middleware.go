@@ -1,74 +1,59 @@
 package middleware
 
 import (
-	"fmt"
 	"net/http"
-	"os"
 
+	"github.com/RubenPari/clear-songs/internal/application/auth"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 )
 
+// AuthTokenCookieName is the cookie holding the JWT of local users
+const AuthTokenCookieName = "auth_token"
+
 func JWTMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		tokenString, err := c.Cookie("auth_token")
-		if err != nil {
-			// Check Authorization header as fallback
-			authHeader := c.GetHeader("Authorization")
-			if len(authHeader) > 7 && authHeader[:7] == "Bearer " {
-				tokenString = authHeader[7:]
-			} else {
-				c.JSON(http.StatusUnauthorized, gin.H{
-					"success": false,
-					"error": gin.H{
-						"code":    "UNAUTHORIZED",
-						"message": "Authentication required",
-					},
-				})
-				c.Abort()
-				return
-			}
-		}
-
-		jwtSecret := os.Getenv("JWT_SECRET")
-		if jwtSecret == "" {
-			jwtSecret = "fallback-secret-for-dev"
-		}
-
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return []byte(jwtSecret), nil
-		})
-
-		if err != nil || !token.Valid {
+		tokenString := tokenFromRequest(c)
+		if tokenString == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"success": false,
 				"error": gin.H{
 					"code":    "UNAUTHORIZED",
-					"message": "Invalid or expired token",
+					"message": "Authentication required",
 				},
 			})
 			c.Abort()
 			return
 		}
 
-		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-			c.Set("userID", claims["sub"])
-			c.Set("userEmail", claims["email"])
-		} else {
+		claims, err := auth.ParseToken(tokenString)
+		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"success": false,
 				"error": gin.H{
 					"code":    "UNAUTHORIZED",
-					"message": "Invalid token claims",
+					"message": "Invalid or expired token",
 				},
 			})
 			c.Abort()
 			return
 		}
 
+		c.Set("userID", claims["sub"])
+		c.Set("userEmail", claims["email"])
+
 		c.Next()
 	}
 }
+
+// tokenFromRequest returns the JWT sent as cookie or, as fallback, as bearer token
+func tokenFromRequest(c *gin.Context) string {
+	if tokenString, err := c.Cookie(AuthTokenCookieName); err == nil && tokenString != "" {
+		return tokenString
+	}
+
+	authHeader := c.GetHeader("Authorization")
+	if len(authHeader) > 7 && authHeader[:7] == "Bearer " {
+		return authHeader[7:]
+	}
+	return ""
+}
@@ -3,43 +3,182 @@ package middleware
 import (
 	"context"
 	"log"
+	"time"
 
+	"github.com/RubenPari/clear-songs/internal/application/auth"
+	domainAuth "github.com/RubenPari/clear-songs/internal/domain/auth"
 	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	"github.com/RubenPari/clear-songs/internal/infrastructure/external/spotify"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
-// SessionMiddlewareRefactored creates a session middleware that uses dependency injection
+const (
+	// SessionCookieName is the cookie holding the opaque ID of Spotify-only sessions
+	SessionCookieName = "session_id"
+	// SessionIDKey is the gin context key holding the session ID of the request
+	SessionIDKey = "sessionID"
+	// LocalUserIDKey is the gin context key holding the local user ID from the JWT, if any
+	LocalUserIDKey = "localUserID"
+	// PersistTokenKey is the gin context key holding a func(context.Context) that persists
+	// the token refreshed by the Spotify repository of the request, if any. Work that keeps
+	// using the repository after the response, such as background jobs, calls it when done.
+	PersistTokenKey = "persistToken"
+
+	sessionCookieMaxAge = 30 * 24 * 3600
+
+	// ownerCacheKey is the session cache entry remembering the Spotify user of a session
+	ownerCacheKey = "owner"
+	ownerCacheTTL = 24 * time.Hour
+)
+
+// SessionMiddlewareRefactored creates a session middleware that uses dependency injection.
+//
+// Every request is bound to a session: the local user ID from the JWT `sub` claim when
+// the user is logged in with email/password, or the opaque `session_id` cookie otherwise.
+// The session's token is loaded from cache and a dedicated Spotify repository is built
+// for the request, so concurrent users never act on each other's libraries.
+//
+// The request is also bound to its owner, the identity its data is stored under: the
+// local user, or else the Spotify user of the session. Logging out or losing the cookie
+// ends the session but not the owner, so the user finds their data on the next login.
 func SessionMiddlewareRefactored(
-	spotifyRepo shared.SpotifyRepository,
+	spotifyFactory shared.SpotifyRepositoryFactory,
 	cacheRepo shared.CacheRepository,
+	credentialRepo domainAuth.CredentialRepository,
 ) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx := context.Background()
+		localUserID := localUserIDFromRequest(c)
+		if localUserID != "" {
+			c.Set(LocalUserIDKey, localUserID)
+		}
 
-		// Retrieve OAuth token from cache
-		token, err := cacheRepo.GetToken(ctx)
+		sessionID := sessionIDFromRequest(c, localUserID)
+		if sessionID == "" {
+			c.Next()
+			return
+		}
+
+		c.Set(SessionIDKey, sessionID)
+		ctx := shared.WithSession(c.Request.Context(), sessionID)
+
+		// Retrieve OAuth token of this session from cache
+		token, err := cacheRepo.GetToken(ctx, sessionID)
 		if err != nil {
 			log.Printf("ERROR: Failed to retrieve token from cache: %v", err)
 		}
 
-		// If token exists, user is authenticated
+		if localUserID != "" {
+			ctx = shared.WithOwner(ctx, shared.LocalUserOwner(localUserID))
+		}
+
+		var spotifyRepo shared.SpotifyRepository
+		var persistToken func(ctx context.Context)
 		if token != nil {
-			// Configure the Spotify repository with the user's token
-			if err := spotifyRepo.SetAccessToken(token); err != nil {
-				log.Printf("ERROR: Failed to set access token: %v", err)
-			} else {
+			// Build a Spotify repository bound to this session only
+			spotifyRepo = spotifyFactory.NewRepository(token)
+			persistToken = func(ctx context.Context) {
+				spotify.PersistRefreshedToken(ctx, cacheRepo, credentialRepo, sessionID, token, spotifyRepo)
+			}
+			c.Set(PersistTokenKey, persistToken)
+
+			// Data is owned by the user, not the session: without an owner the
+			// request is treated as unauthenticated
+			if owner := sessionOwner(ctx, cacheRepo, spotifyRepo, localUserID); owner != "" {
+				ctx = shared.WithOwner(ctx, owner)
+				ctx = shared.WithSpotifyRepository(ctx, spotifyRepo)
+
 				// Store Spotify repository in context for use by handlers
 				c.Set("spotifyRepository", spotifyRepo)
 			}
-		} else {
+		} else if c.Request.URL.Path != "/auth/is-auth" {
 			// Log when token is not found (for debugging)
 			// Only log for non-auth endpoints to avoid spam
-			if c.Request.URL.Path != "/auth/is-auth" {
-				log.Printf("DEBUG: No token found in cache for path: %s", c.Request.URL.Path)
-			}
+			log.Printf("DEBUG: No token found in cache for path: %s", c.Request.URL.Path)
 		}
 
+		c.Request = c.Request.WithContext(ctx)
+
 		// Continue to next middleware or handler
 		c.Next()
+
+		// Persist the token if the client refreshed it during the request
+		if persistToken != nil {
+			persistToken(c.Request.Context())
+		}
+	}
+}
+
+// EnsureSession returns the session ID of the request, starting a new anonymous
+// session (and setting its cookie) when the request has none
+func EnsureSession(c *gin.Context) string {
+	if sessionID := c.GetString(SessionIDKey); sessionID != "" {
+		return sessionID
+	}
+
+	value := uuid.NewString()
+	c.SetCookie(SessionCookieName, value, sessionCookieMaxAge, "/", "", false, true)
+
+	sessionID := "session:" + value
+	c.Set(SessionIDKey, sessionID)
+	c.Request = c.Request.WithContext(shared.WithSession(c.Request.Context(), sessionID))
+	return sessionID
+}
+
+// sessionOwner returns the owner of a session authenticated with spotifyRepo: the local
+// user when logged in with email/password, or else the Spotify user, looked up once and
+// remembered in the session cache. It returns an empty string when the lookup fails.
+func sessionOwner(
+	ctx context.Context,
+	cacheRepo shared.CacheRepository,
+	spotifyRepo shared.SpotifyRepository,
+	localUserID string,
+) string {
+	if localUserID != "" {
+		return shared.LocalUserOwner(localUserID)
+	}
+
+	var owner string
+	if found, err := cacheRepo.Get(ctx, ownerCacheKey, &owner); err == nil && found && owner != "" {
+		return owner
+	}
+
+	user, err := spotifyRepo.GetCurrentUser(ctx)
+	if err != nil {
+		log.Printf("ERROR: Failed to identify the Spotify user of the session: %v", err)
+		return ""
+	}
+
+	owner = shared.SpotifyUserOwner(user.ID)
+	if err := cacheRepo.Set(ctx, ownerCacheKey, owner, ownerCacheTTL); err != nil {
+		log.Printf("WARNING: Failed to cache the owner of the session: %v", err)
+	}
+	return owner
+}
+
+// sessionIDFromRequest derives the session ID from the local user or the session cookie
+func sessionIDFromRequest(c *gin.Context, localUserID string) string {
+	if localUserID != "" {
+		return "user:" + localUserID
+	}
+	if value, err := c.Cookie(SessionCookieName); err == nil && value != "" {
+		return "session:" + value
+	}
+	return ""
+}
+
+// localUserIDFromRequest returns the `sub` claim of a valid JWT sent as cookie or
+// bearer token, or an empty string
+func localUserIDFromRequest(c *gin.Context) string {
+	tokenString := tokenFromRequest(c)
+	if tokenString == "" {
+		return ""
+	}
+
+	claims, err := auth.ParseToken(tokenString)
+	if err != nil {
+		return ""
 	}
+	sub, _ := claims["sub"].(string)
+	return sub
 }
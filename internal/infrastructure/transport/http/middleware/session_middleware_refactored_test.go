@@ -0,0 +1,213 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/RubenPari/clear-songs/internal/application/auth"
+	domainAuth "github.com/RubenPari/clear-songs/internal/domain/auth"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	"github.com/RubenPari/clear-songs/test/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	spotifyAPI "github.com/zmb3/spotify"
+	"golang.org/x/oauth2"
+)
+
+// sessionTokenCache serves one token per session ID and keeps values per session
+type sessionTokenCache struct {
+	mocks.MockCacheRepository
+	tokens map[string]*oauth2.Token
+	values map[string][]byte
+}
+
+func (c *sessionTokenCache) GetToken(ctx context.Context, sessionID string) (*oauth2.Token, error) {
+	return c.tokens[sessionID], nil
+}
+
+func (c *sessionTokenCache) SetToken(ctx context.Context, sessionID string, token *oauth2.Token) error {
+	c.tokens[sessionID] = token
+	return nil
+}
+
+func (c *sessionTokenCache) Get(ctx context.Context, key string, target interface{}) (bool, error) {
+	data, ok := c.values[shared.SessionFromContext(ctx)+":"+key]
+	if !ok {
+		return false, nil
+	}
+	return true, json.Unmarshal(data, target)
+}
+
+func (c *sessionTokenCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	c.values[shared.SessionFromContext(ctx)+":"+key] = data
+	return err
+}
+
+// tokenRecordingFactory builds mock repositories remembering the token they were built
+// with. The Spotify user of a token is the part of its access token before "_token".
+type tokenRecordingFactory struct {
+	built   map[shared.SpotifyRepository]string
+	lookups int
+}
+
+func (f *tokenRecordingFactory) NewRepository(token *oauth2.Token) shared.SpotifyRepository {
+	repo := new(mocks.MockSpotifyRepository)
+	f.built[repo] = token.AccessToken
+
+	userID, _, _ := strings.Cut(token.AccessToken, "_token")
+	call := repo.On("GetCurrentUser", mock.Anything).Run(func(mock.Arguments) { f.lookups++ })
+	if userID == "broken" {
+		call.Return(nil, errors.New("spotify unavailable"))
+	} else {
+		call.Return(&spotifyAPI.PrivateUser{User: spotifyAPI.User{ID: userID}}, nil)
+	}
+	return repo
+}
+
+func TestSessionMiddlewareRefactored(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cache := &sessionTokenCache{
+		tokens: map[string]*oauth2.Token{
+			"session:alice":       {AccessToken: "alice_token"},
+			"session:alice-again": {AccessToken: "alice_token2"},
+			"session:bob":         {AccessToken: "bob_token"},
+			"session:broken":      {AccessToken: "broken_token"},
+			"user:carol":          {AccessToken: "carol_token"},
+		},
+		values: map[string][]byte{},
+	}
+	factory := &tokenRecordingFactory{built: map[shared.SpotifyRepository]string{}}
+
+	router := gin.New()
+	router.Use(SessionMiddlewareRefactored(factory, cache, nil))
+	router.GET("/whoami", func(c *gin.Context) {
+		ctx := c.Request.Context()
+		repo := shared.SpotifyRepositoryFromContext(ctx)
+		if repo == nil {
+			c.String(http.StatusUnauthorized, "")
+			return
+		}
+		c.String(http.StatusOK, factory.built[repo]+"|"+shared.SessionFromContext(ctx)+"|"+shared.OwnerFromContext(ctx))
+	})
+
+	request := func(sessionCookie string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/whoami", nil)
+		if sessionCookie != "" {
+			req.AddCookie(&http.Cookie{Name: SessionCookieName, Value: sessionCookie})
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	bearer := func(token string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/whoami", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Each session gets a client bound to its own token", func(t *testing.T) {
+		alice := request("alice")
+		bob := request("bob")
+
+		assert.Equal(t, "alice_token|session:alice|spotify:alice", alice.Body.String())
+		assert.Equal(t, "bob_token|session:bob|spotify:bob", bob.Body.String())
+	})
+
+	t.Run("A new session of the same Spotify user has the same owner", func(t *testing.T) {
+		assert.Equal(t, "alice_token2|session:alice-again|spotify:alice", request("alice-again").Body.String())
+	})
+
+	t.Run("The Spotify user is looked up once per session", func(t *testing.T) {
+		before := factory.lookups
+		request("alice")
+		request("alice")
+
+		assert.Equal(t, before, factory.lookups)
+	})
+
+	t.Run("Local users are identified by the JWT issued at login", func(t *testing.T) {
+		token, err := auth.IssueToken(&domainAuth.User{ID: "carol", Email: "carol@example.com"})
+		require.NoError(t, err)
+
+		assert.Equal(t, "carol_token|user:carol|user:carol", bearer(token).Body.String())
+		assert.Equal(t, http.StatusUnauthorized, bearer(token+"x").Code)
+	})
+
+	t.Run("Sessions whose user cannot be identified are not authenticated", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, request("broken").Code)
+	})
+
+	t.Run("Requests without a known session are not authenticated", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, request("").Code)
+		assert.Equal(t, http.StatusUnauthorized, request("mallory").Code)
+	})
+}
+
+// refreshingRepository is a Spotify repository whose OAuth client refreshes its token
+type refreshingRepository struct {
+	*mocks.MockSpotifyRepository
+	token *oauth2.Token
+}
+
+func (r *refreshingRepository) Token() (*oauth2.Token, error) {
+	return r.token, nil
+}
+
+type refreshingFactory struct {
+	repo *refreshingRepository
+}
+
+func (f *refreshingFactory) NewRepository(token *oauth2.Token) shared.SpotifyRepository {
+	f.repo.token = token
+	return f.repo
+}
+
+func TestSessionMiddlewareRefactored_PersistToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cache := &sessionTokenCache{
+		tokens: map[string]*oauth2.Token{"session:alice": {AccessToken: "alice_token"}},
+		values: map[string][]byte{},
+	}
+	repo := &refreshingRepository{MockSpotifyRepository: new(mocks.MockSpotifyRepository)}
+	repo.On("GetCurrentUser", mock.Anything).Return(&spotifyAPI.PrivateUser{User: spotifyAPI.User{ID: "alice"}}, nil)
+
+	// The handler hands the persister to work that outlives the request, like a job
+	var persistToken func(context.Context)
+	router := gin.New()
+	router.Use(SessionMiddlewareRefactored(&refreshingFactory{repo: repo}, cache, nil))
+	router.POST("/job", func(c *gin.Context) {
+		persistToken, _ = c.Value(PersistTokenKey).(func(context.Context))
+		c.Status(http.StatusAccepted)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/job", nil)
+	req.AddCookie(&http.Cookie{Name: SessionCookieName, Value: "alice"})
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusAccepted, w.Code)
+	require.NotNil(t, persistToken)
+
+	t.Run("A token refreshed after the response is persisted to the session", func(t *testing.T) {
+		refreshed := &oauth2.Token{AccessToken: "alice_token_refreshed"}
+		repo.token = refreshed
+
+		persistToken(context.Background())
+
+		assert.Same(t, refreshed, cache.tokens["session:alice"])
+	})
+}
@@ -21,14 +21,15 @@ func SetUpRoutesRefactored(server *gin.Engine, container *di.Container) {
 	 * Global Middleware
 	 *
 	 * These middleware functions are applied to all routes:
-	 * - SessionMiddlewareRefactored: Manages user sessions using DI
+	 * - SessionMiddlewareRefactored: Binds each request to its session and Spotify client
 	 * - CacheInvalidationMiddleware: Invalidates cache when data is modified
 	 */
 	server.Use(middleware.SessionMiddlewareRefactored(
-		container.SpotifyRepo,
+		container.SpotifyFactory,
 		container.CacheRepo,
+		container.CredentialRepo,
 	))
-	server.Use(middleware.CacheInvalidationMiddleware())
+	server.Use(middleware.CacheInvalidationMiddleware(container.CacheRepo))
 
 	/**
 	 * 404 Not Found Handler
@@ -49,6 +50,9 @@ func SetUpRoutesRefactored(server *gin.Engine, container *di.Container) {
 		container.DeleteTracksByRangeUC,
 		container.GetTracksByArtistUC,
 		container.DeleteTrackUC,
+		container.GetGenreSummaryUC,
+		container.DeleteTracksByGenreUC,
+		container.JobManager,
 	)
 
 	track := server.Group("/track")
@@ -56,12 +60,18 @@ func SetUpRoutesRefactored(server *gin.Engine, container *di.Container) {
 		track.GET("/summary",
 			middleware.SpotifyAuthMiddlewareRefactored(),
 			trackController.GetTrackSummary)
+		track.GET("/summary/genres",
+			middleware.SpotifyAuthMiddlewareRefactored(),
+			trackController.GetGenreSummary)
 		track.GET("/by-artist/:id_artist",
 			middleware.SpotifyAuthMiddlewareRefactored(),
 			trackController.GetTracksByArtist)
 		track.DELETE("/by-artist/:id_artist",
 			middleware.SpotifyAuthMiddlewareRefactored(),
 			trackController.DeleteTrackByArtist)
+		track.DELETE("/by-genre/:genre",
+			middleware.SpotifyAuthMiddlewareRefactored(),
+			trackController.DeleteTrackByGenre)
 		track.DELETE("/:id_track",
 			middleware.SpotifyAuthMiddlewareRefactored(),
 			trackController.DeleteTrack)
@@ -115,6 +125,14 @@ func SetUpRoutesRefactored(server *gin.Engine, container *di.Container) {
 		container.GetUserPlaylistsUC,
 		container.DeletePlaylistTracksUC,
 		container.DeletePlaylistAndLibraryUC,
+		container.DedupePlaylistUC,
+		container.JobManager,
+	)
+	playlistSnapshotController := handlers.NewPlaylistSnapshotController(
+		container.CapturePlaylistUC,
+		container.ListPlaylistSnapshotsUC,
+		container.DiffPlaylistSnapshotsUC,
+		container.RestorePlaylistSnapshotUC,
 	)
 
 	playlist := server.Group("/playlist")
@@ -128,5 +146,170 @@ func SetUpRoutesRefactored(server *gin.Engine, container *di.Container) {
 		playlist.DELETE("/delete-tracks-and-library",
 			middleware.SpotifyAuthMiddlewareRefactored(),
 			playlistController.DeleteAllPlaylistAndUserTracks)
+		playlist.POST("/:id/dedupe",
+			middleware.SpotifyAuthMiddlewareRefactored(),
+			playlistController.DedupePlaylist)
+		playlist.POST("/:id/snapshots",
+			middleware.SpotifyAuthMiddlewareRefactored(),
+			playlistSnapshotController.TakeSnapshot)
+		playlist.GET("/:id/snapshots",
+			middleware.SpotifyAuthMiddlewareRefactored(),
+			playlistSnapshotController.GetSnapshots)
+		playlist.GET("/:id/snapshots/diff",
+			middleware.SpotifyAuthMiddlewareRefactored(),
+			playlistSnapshotController.DiffSnapshots)
+		playlist.POST("/:id/snapshots/:snapshot_id/restore",
+			middleware.SpotifyAuthMiddlewareRefactored(),
+			playlistSnapshotController.RestoreSnapshot)
+	}
+
+	/**
+	 * Album Management Routes Group
+	 */
+	albumController := handlers.NewAlbumController(
+		container.GetUserAlbumsUC,
+		container.ConvertAlbumUC,
+	)
+
+	album := server.Group("/album")
+	{
+		album.GET("/list",
+			middleware.SpotifyAuthMiddlewareRefactored(),
+			albumController.GetUserAlbums)
+		album.POST("/convert",
+			middleware.SpotifyAuthMiddlewareRefactored(),
+			albumController.ConvertAlbum)
+	}
+
+	/**
+	 * Library Routes Group
+	 */
+	libraryController := handlers.NewLibraryController(
+		container.ExportLibraryUC,
+		container.ImportLibraryUC,
+		container.FindDuplicatesUC,
+		container.MergeDuplicatesUC,
+		container.JobManager,
+	)
+	librarySnapshotController := handlers.NewLibrarySnapshotController(
+		container.CaptureLibraryUC,
+		container.ListLibrarySnapshotsUC,
+		container.DiffLibrarySnapshotsUC,
+		container.RestoreLibraryDiffUC,
+		container.JobManager,
+	)
+
+	library := server.Group("/library")
+	{
+		library.GET("/export",
+			middleware.SpotifyAuthMiddlewareRefactored(),
+			libraryController.ExportLibrary)
+		library.POST("/import",
+			middleware.SpotifyAuthMiddlewareRefactored(),
+			libraryController.ImportLibrary)
+		library.GET("/duplicates",
+			middleware.SpotifyAuthMiddlewareRefactored(),
+			libraryController.FindDuplicates)
+		library.POST("/duplicates/merge",
+			middleware.SpotifyAuthMiddlewareRefactored(),
+			libraryController.MergeDuplicates)
+		library.POST("/snapshots",
+			middleware.SpotifyAuthMiddlewareRefactored(),
+			librarySnapshotController.TakeSnapshot)
+		library.GET("/snapshots",
+			middleware.SpotifyAuthMiddlewareRefactored(),
+			librarySnapshotController.GetSnapshots)
+		library.GET("/diff",
+			middleware.SpotifyAuthMiddlewareRefactored(),
+			librarySnapshotController.DiffSnapshots)
+		library.POST("/diff/restore",
+			middleware.SpotifyAuthMiddlewareRefactored(),
+			librarySnapshotController.RestoreDiff)
+	}
+
+	/**
+	 * Backup Routes Group
+	 */
+	backupController := handlers.NewBackupController(
+		container.GetBackupTracksUC,
+		container.RestoreTracksUC,
+		container.ClaimLegacyBackupsUC,
+	)
+
+	backup := server.Group("/backup")
+	{
+		backup.GET("/tracks",
+			middleware.SpotifyAuthMiddlewareRefactored(),
+			backupController.GetBackupTracks)
+		backup.POST("/restore",
+			middleware.SpotifyAuthMiddlewareRefactored(),
+			backupController.RestoreTracks)
+		backup.POST("/legacy/claim",
+			middleware.SpotifyAuthMiddlewareRefactored(),
+			backupController.ClaimLegacyBackups)
+	}
+
+	/**
+	 * Operations (Undo Log) Routes Group
+	 */
+	operationController := handlers.NewOperationController(
+		container.ListOperationsUC,
+		container.UndoOperationUC,
+	)
+
+	operations := server.Group("/operations")
+	{
+		operations.GET("",
+			middleware.SpotifyAuthMiddlewareRefactored(),
+			operationController.GetOperations)
+		operations.POST("/:id/undo",
+			middleware.SpotifyAuthMiddlewareRefactored(),
+			operationController.UndoOperation)
+	}
+
+	/**
+	 * Cleanup Rules Routes Group
+	 */
+	ruleController := handlers.NewRuleController(
+		container.ListRulesUC,
+		container.CreateRuleUC,
+		container.UpdateRuleUC,
+		container.DeleteRuleUC,
+		container.RunRuleUC,
+		container.JobManager,
+	)
+
+	rules := server.Group("/rules")
+	{
+		rules.GET("",
+			middleware.SpotifyAuthMiddlewareRefactored(),
+			ruleController.GetRules)
+		rules.POST("",
+			middleware.SpotifyAuthMiddlewareRefactored(),
+			ruleController.CreateRule)
+		rules.PUT("/:id",
+			middleware.SpotifyAuthMiddlewareRefactored(),
+			ruleController.UpdateRule)
+		rules.DELETE("/:id",
+			middleware.SpotifyAuthMiddlewareRefactored(),
+			ruleController.DeleteRule)
+		rules.POST("/:id/run",
+			middleware.SpotifyAuthMiddlewareRefactored(),
+			ruleController.RunRule)
+	}
+
+	/**
+	 * Background Jobs Routes Group
+	 */
+	jobController := handlers.NewJobController(container.JobManager)
+
+	jobs := server.Group("/jobs")
+	{
+		jobs.GET("/:id",
+			middleware.SpotifyAuthMiddlewareRefactored(),
+			jobController.GetJob)
+		jobs.POST("/:id/cancel",
+			middleware.SpotifyAuthMiddlewareRefactored(),
+			jobController.CancelJob)
 	}
 }
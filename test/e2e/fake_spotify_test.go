@@ -0,0 +1,214 @@
+package e2e
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/RubenPari/clear-songs/internal/application/playlist"
+	"github.com/RubenPari/clear-songs/internal/application/shared/dto"
+	"github.com/RubenPari/clear-songs/internal/application/track"
+	"github.com/RubenPari/clear-songs/internal/infrastructure/di"
+	httptransport "github.com/RubenPari/clear-songs/internal/infrastructure/transport/http"
+	"github.com/RubenPari/clear-songs/test/fakespotify"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zmb3/spotify"
+)
+
+// fakeSpotifyApp is the full router wired to a fake Spotify server
+type fakeSpotifyApp struct {
+	router  *gin.Engine
+	fake    *fakespotify.Server
+	session *http.Cookie
+}
+
+// newFakeSpotifyApp seeds a library of two artists and logs in through the OAuth callback
+func newFakeSpotifyApp(t *testing.T) *fakeSpotifyApp {
+	fake := fakespotify.New(t)
+
+	rock := fakespotify.Artist("artist-rock", "Rock Band", "rock")
+	jazz := fakespotify.Artist("artist-jazz", "Jazz Trio", "jazz")
+	fake.AddArtist(rock, jazz)
+	fake.AddTrack(
+		fakespotify.Track("track-rock-1", "Rock 1", rock),
+		fakespotify.Track("track-rock-2", "Rock 2", rock),
+		fakespotify.Track("track-rock-3", "Rock 3", rock),
+		fakespotify.Track("track-jazz-1", "Jazz 1", jazz),
+	)
+	now := time.Now()
+	fake.Save(now.Add(-3*time.Hour), "track-rock-1", "track-rock-2", "track-rock-3")
+	fake.Save(now.Add(-time.Hour), "track-jazz-1")
+	fake.AddPlaylist("playlist-mix", "Mix", "track-rock-1", "track-jazz-1", "track-rock-1")
+
+	setupTestEnv()
+	t.Setenv("SPOTIFY_BASE_URL", fake.URL)
+	t.Setenv("CACHE_DRIVER", "memory")
+	t.Setenv("SPOTIFY_MAX_RPS", "0")
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	container, err := di.NewContainer()
+	if err != nil {
+		t.Skipf("Skipping E2E test: DI container failed: %v", err)
+	}
+	httptransport.SetUpRoutesRefactored(router, container)
+
+	app := &fakeSpotifyApp{router: router, fake: fake}
+
+	w := app.do(http.MethodGet, "/auth/callback?code=fake-code")
+	require.Equal(t, http.StatusFound, w.Code, w.Body.String())
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == "session_id" {
+			app.session = cookie
+		}
+	}
+	require.NotNil(t, app.session, "Callback should start a session")
+
+	return app
+}
+
+func (a *fakeSpotifyApp) do(method, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if a.session != nil {
+		req.AddCookie(a.session)
+	}
+	w := httptest.NewRecorder()
+	a.router.ServeHTTP(w, req)
+	return w
+}
+
+func (a *fakeSpotifyApp) summary(t *testing.T) []track.ArtistSummary {
+	w := a.do(http.MethodGet, "/track/summary")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response dto.APIResponse[[]track.ArtistSummary]
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.True(t, response.Success)
+	return response.Data
+}
+
+func TestFakeSpotify_E2E(t *testing.T) {
+	t.Run("GET /track/summary - should count the saved tracks per artist", func(t *testing.T) {
+		app := newFakeSpotifyApp(t)
+
+		summary := app.summary(t)
+
+		require.Len(t, summary, 2)
+		assert.Equal(t, "Rock Band", summary[0].Name)
+		assert.Equal(t, 3, summary[0].Count)
+		assert.Equal(t, "Jazz Trio", summary[1].Name)
+		assert.Equal(t, 1, summary[1].Count)
+	})
+
+	t.Run("GET /track/summary - should page through the summary", func(t *testing.T) {
+		app := newFakeSpotifyApp(t)
+
+		w := app.do(http.MethodGet, "/track/summary?limit=1&offset=1")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var response dto.APIResponse[[]track.ArtistSummary]
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Data, 1)
+		assert.Equal(t, "Jazz Trio", response.Data[0].Name)
+		require.NotNil(t, response.Meta)
+		require.NotNil(t, response.Meta.Pagination)
+		assert.Equal(t, 2, response.Meta.Pagination.Total)
+	})
+
+	t.Run("DELETE /track/by-artist - should remove the artist's tracks from Spotify", func(t *testing.T) {
+		app := newFakeSpotifyApp(t)
+		app.summary(t)
+
+		w := app.do(http.MethodDelete, "/track/by-artist/artist-rock")
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, []spotify.ID{"track-jazz-1"}, app.fake.SavedTrackIDs())
+
+		// Track reads are served fresh after a delete
+		w = app.do(http.MethodGet, "/track/by-artist/artist-rock")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var response dto.APIResponse[[]track.TrackResponse]
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Empty(t, response.Data)
+	})
+
+	t.Run("DELETE /track/:id_track - should survive rate limiting and server errors", func(t *testing.T) {
+		app := newFakeSpotifyApp(t)
+		app.fake.FailNext(http.MethodDelete, "/me/tracks", http.StatusTooManyRequests, 1)
+
+		w := app.do(http.MethodDelete, "/track/track-jazz-1")
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.NotContains(t, app.fake.SavedTrackIDs(), spotify.ID("track-jazz-1"))
+		assert.Equal(t, 2, app.fake.Requests(http.MethodDelete, "/me/tracks"))
+
+		app.fake.FailNext(http.MethodGet, "/me/tracks", http.StatusServiceUnavailable, 1)
+		summary := app.summary(t)
+		require.Len(t, summary, 1)
+		assert.Equal(t, "Rock Band", summary[0].Name)
+	})
+
+	t.Run("DELETE /playlist/delete-tracks - should empty the playlist and keep the library", func(t *testing.T) {
+		app := newFakeSpotifyApp(t)
+
+		w := app.do(http.MethodGet, "/playlist/list")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), "playlist-mix")
+
+		w = app.do(http.MethodDelete, "/playlist/delete-tracks?id=playlist-mix")
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Empty(t, app.fake.PlaylistTrackIDs("playlist-mix"))
+		assert.Len(t, app.fake.SavedTrackIDs(), 4)
+	})
+
+	t.Run("DELETE /playlist/delete-tracks - should remove more than 100 filtered positions across snapshots", func(t *testing.T) {
+		app := newFakeSpotifyApp(t)
+		rock := fakespotify.Artist("artist-rock", "Rock Band", "rock")
+		var ids []spotify.ID
+		for i := 0; i < 150; i++ {
+			id := fmt.Sprintf("track-bulk-%d", i)
+			app.fake.AddTrack(fakespotify.Track(id, fmt.Sprintf("Bulk %d", i), rock))
+			// Kept items between the removed ones, so the positions are not contiguous
+			ids = append(ids, spotify.ID(id))
+			if i%2 == 0 {
+				ids = append(ids, "track-jazz-1")
+			}
+		}
+		app.fake.AddPlaylist("playlist-bulk", "Bulk", ids...)
+
+		w := app.do(http.MethodDelete, "/playlist/delete-tracks?id=playlist-bulk&artist_ids=artist-rock")
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		remaining := app.fake.PlaylistTrackIDs("playlist-bulk")
+		assert.Len(t, remaining, 75)
+		assert.NotContains(t, remaining, spotify.ID("track-bulk-0"))
+		assert.Equal(t, 2, app.fake.Requests(http.MethodDelete, "/playlists/playlist-bulk/tracks"))
+	})
+
+	t.Run("POST /playlist/:id/dedupe - should keep the first occurrence of each track", func(t *testing.T) {
+		app := newFakeSpotifyApp(t)
+
+		w := app.do(http.MethodPost, "/playlist/playlist-mix/dedupe")
+
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var response dto.APIResponse[playlist.DedupeResult]
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 1, response.Data.TracksRemoved)
+		require.Len(t, response.Data.Removed, 1)
+		assert.Equal(t, 2, response.Data.Removed[0].Position)
+		assert.Equal(t, []spotify.ID{"track-rock-1", "track-jazz-1"}, app.fake.PlaylistTrackIDs("playlist-mix"))
+
+		// A second run finds nothing against the new snapshot
+		w = app.do(http.MethodPost, "/playlist/playlist-mix/dedupe")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 0, response.Data.TracksRemoved)
+	})
+}
@@ -0,0 +1,887 @@
+// Package fakespotify is an in-process fake of the Spotify Web API and accounts service
+// for integration and end-to-end tests. It holds seeded, mutable state, so the real
+// SpotifyRepositoryImpl and the whole Gin router can run against it, and it can inject
+// 429 and 5xx failures on chosen endpoints.
+//
+// Point the application at it with SPOTIFY_BASE_URL=<server URL>: Web API requests are
+// served under /v1/ and the accounts service under /authorize and /api/token.
+package fakespotify
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zmb3/spotify"
+)
+
+// DefaultUserID is the ID of the user the server starts with
+const DefaultUserID = "fake-user"
+
+// failure is a status the server answers with instead of serving a request
+type failure struct {
+	status int
+	times  int
+}
+
+// playlist is a playlist with its items, whose snapshot changes on every edit
+type playlist struct {
+	spotify.SimplePlaylist
+	description string
+	items       []spotify.PlaylistTrack
+	snapshot    int
+}
+
+// Server is a fake Spotify server. Its state is safe for concurrent use by the server
+// and the test.
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	user      spotify.PrivateUser
+	artists   map[spotify.ID]spotify.FullArtist
+	tracks    map[spotify.ID]spotify.FullTrack
+	saved     []spotify.SavedTrack
+	playlists map[spotify.ID]*playlist
+	order     []spotify.ID
+	tokens    map[string]time.Time
+	refresh   map[string]bool
+	issued    int
+	failures  map[string]*failure
+	requests  map[string]int
+	now       func() time.Time
+}
+
+// New starts a fake server, closed when the test ends
+func New(t testing.TB) *Server {
+	s := &Server{
+		user: spotify.PrivateUser{
+			User: spotify.User{
+				DisplayName: "Fake User",
+				ID:          DefaultUserID,
+				URI:         spotify.URI("spotify:user:" + DefaultUserID),
+			},
+			Email:   DefaultUserID + "@example.com",
+			Country: "IT",
+			Product: "premium",
+		},
+		artists:   make(map[spotify.ID]spotify.FullArtist),
+		tracks:    make(map[spotify.ID]spotify.FullTrack),
+		playlists: make(map[spotify.ID]*playlist),
+		tokens:    make(map[string]time.Time),
+		refresh:   make(map[string]bool),
+		failures:  make(map[string]*failure),
+		requests:  make(map[string]int),
+		now:       time.Now,
+	}
+
+	mux := http.NewServeMux()
+
+	// Accounts service
+	mux.HandleFunc("GET /authorize", s.authorize)
+	mux.HandleFunc("POST /api/token", s.token)
+
+	// Web API
+	mux.HandleFunc("GET /v1/me", s.currentUser)
+	mux.HandleFunc("GET /v1/me/tracks", s.savedTracks)
+	mux.HandleFunc("PUT /v1/me/tracks", s.saveTracks)
+	mux.HandleFunc("DELETE /v1/me/tracks", s.removeSavedTracks)
+	mux.HandleFunc("GET /v1/me/playlists", s.userPlaylists)
+	mux.HandleFunc("GET /v1/playlists/{id}", s.getPlaylist)
+	mux.HandleFunc("PUT /v1/playlists/{id}", s.updatePlaylist)
+	mux.HandleFunc("GET /v1/playlists/{id}/tracks", s.playlistTracks)
+	mux.HandleFunc("POST /v1/playlists/{id}/tracks", s.addPlaylistTracks)
+	mux.HandleFunc("PUT /v1/playlists/{id}/tracks", s.replacePlaylistTracks)
+	mux.HandleFunc("DELETE /v1/playlists/{id}/tracks", s.removePlaylistTracks)
+	mux.HandleFunc("GET /v1/artists", s.getArtists)
+	mux.HandleFunc("GET /v1/artists/{id}", s.getArtist)
+	mux.HandleFunc("GET /v1/tracks", s.getTracks)
+	mux.HandleFunc("GET /v1/tracks/{id}", s.getTrack)
+
+	s.Server = httptest.NewServer(s.middleware(mux))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Artist returns a catalog artist with the given genres
+func Artist(id, name string, genres ...string) spotify.FullArtist {
+	return spotify.FullArtist{
+		SimpleArtist: simpleArtist(id, name),
+		Genres:       genres,
+		Popularity:   50,
+	}
+}
+
+// Track returns a catalog track by the given artists, the first one being the primary
+func Track(id, name string, artists ...spotify.FullArtist) spotify.FullTrack {
+	simple := make([]spotify.SimpleArtist, len(artists))
+	for i, artist := range artists {
+		simple[i] = artist.SimpleArtist
+	}
+
+	return spotify.FullTrack{
+		SimpleTrack: spotify.SimpleTrack{
+			Artists:      simple,
+			Duration:     180000,
+			ExternalURLs: map[string]string{"spotify": "https://open.spotify.com/track/" + id},
+			ID:           spotify.ID(id),
+			Name:         name,
+			URI:          spotify.URI("spotify:track:" + id),
+			Type:         "track",
+		},
+		Album: spotify.SimpleAlbum{
+			Name:    name + " (Single)",
+			Artists: simple,
+			ID:      spotify.ID("album-" + id),
+			URI:     spotify.URI("spotify:album:album-" + id),
+		},
+		Popularity: 50,
+	}
+}
+
+func simpleArtist(id, name string) spotify.SimpleArtist {
+	return spotify.SimpleArtist{
+		Name:         name,
+		ID:           spotify.ID(id),
+		URI:          spotify.URI("spotify:artist:" + id),
+		ExternalURLs: map[string]string{"spotify": "https://open.spotify.com/artist/" + id},
+	}
+}
+
+// SetUser replaces the profile returned by /me
+func (s *Server) SetUser(user spotify.PrivateUser) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.user = user
+}
+
+// AddArtist adds artists to the catalog
+func (s *Server) AddArtist(artists ...spotify.FullArtist) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, artist := range artists {
+		s.artists[artist.ID] = artist
+	}
+}
+
+// AddTrack adds tracks to the catalog, without saving them
+func (s *Server) AddTrack(tracks ...spotify.FullTrack) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, track := range tracks {
+		s.tracks[track.ID] = track
+	}
+}
+
+// Save adds catalog tracks to the library as saved at addedAt. Tracks already saved
+// are left unchanged, as on Spotify.
+func (s *Server) Save(addedAt time.Time, ids ...spotify.ID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.save(addedAt, ids)
+}
+
+// AddPlaylist creates a playlist owned by the user with the given catalog tracks
+func (s *Server) AddPlaylist(id spotify.ID, name string, trackIDs ...spotify.ID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := &playlist{
+		SimplePlaylist: spotify.SimplePlaylist{
+			ID:    id,
+			Name:  name,
+			Owner: s.user.User,
+			URI:   spotify.URI("spotify:playlist:" + id),
+		},
+	}
+	addedAt := s.now().UTC().Format(time.RFC3339)
+	for _, trackID := range trackIDs {
+		p.items = append(p.items, spotify.PlaylistTrack{AddedAt: addedAt, AddedBy: s.user.User, Track: s.tracks[trackID]})
+	}
+
+	if _, exists := s.playlists[id]; !exists {
+		s.order = append(s.order, id)
+	}
+	s.playlists[id] = p
+}
+
+// SavedTrackIDs returns the IDs of the saved tracks, most recently added first
+func (s *Server) SavedTrackIDs() []spotify.ID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]spotify.ID, len(s.saved))
+	for i, saved := range s.saved {
+		ids[i] = saved.ID
+	}
+	return ids
+}
+
+// PlaylistTrackIDs returns the IDs of the items of a playlist, in order
+func (s *Server) PlaylistTrackIDs(id spotify.ID) []spotify.ID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.playlists[id]
+	if !ok {
+		return nil
+	}
+	ids := make([]spotify.ID, len(p.items))
+	for i, item := range p.items {
+		ids[i] = item.Track.ID
+	}
+	return ids
+}
+
+// Requests returns how many requests reached method and path, failed ones included.
+// Web API paths are given without the /v1 prefix, e.g. "/me/tracks".
+func (s *Server) Requests(method, path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[method+" "+path]
+}
+
+// FailNext answers the next times requests to method and path with status, with
+// Retry-After: 0 so that clients retry right away. Paths are given as for Requests.
+func (s *Server) FailNext(method, path string, status, times int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[method+" "+path] = &failure{status: status, times: times}
+}
+
+// IssueToken returns a valid access token expiring in expiresIn, as if the user had
+// logged in, for tests that skip the OAuth flow
+func (s *Server) IssueToken(expiresIn time.Duration) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	access, _ := s.issue(expiresIn)
+	return access
+}
+
+// middleware records requests, injects failures and authenticates Web API calls
+func (s *Server) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/v1")
+		key := r.Method + " " + path
+
+		s.mu.Lock()
+		s.requests[key]++
+		var status int
+		if f, ok := s.failures[key]; ok && f.times > 0 {
+			f.times--
+			status = f.status
+		}
+		s.mu.Unlock()
+
+		if status != 0 {
+			w.Header().Set("Retry-After", "0")
+			writeError(w, status, http.StatusText(status))
+			return
+		}
+
+		if strings.HasPrefix(r.URL.Path, "/v1/") && !s.authenticated(r) {
+			writeError(w, http.StatusUnauthorized, "Invalid access token")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authenticated reports whether the request carries an issued, unexpired access token
+func (s *Server) authenticated(r *http.Request) bool {
+	access, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expiry, ok := s.tokens[access]
+	return ok && s.now().Before(expiry)
+}
+
+// issue creates an access and refresh token pair; s.mu must be held
+func (s *Server) issue(expiresIn time.Duration) (string, string) {
+	s.issued++
+	access := fmt.Sprintf("access-%d", s.issued)
+	refresh := fmt.Sprintf("refresh-%d", s.issued)
+	s.tokens[access] = s.now().Add(expiresIn)
+	s.refresh[refresh] = true
+	return access, refresh
+}
+
+// save adds tracks to the library; s.mu must be held
+func (s *Server) save(addedAt time.Time, ids []spotify.ID) {
+	for _, id := range ids {
+		if s.isSaved(id) {
+			continue
+		}
+		s.saved = append(s.saved, spotify.SavedTrack{
+			AddedAt:   addedAt.UTC().Format(time.RFC3339),
+			FullTrack: s.tracks[id],
+		})
+	}
+
+	// The library lists the most recently added tracks first
+	sort.SliceStable(s.saved, func(i, j int) bool {
+		return s.saved[i].AddedAt > s.saved[j].AddedAt
+	})
+}
+
+func (s *Server) isSaved(id spotify.ID) bool {
+	for _, saved := range s.saved {
+		if saved.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// authorize grants access right away, redirecting to the client with a code
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) {
+	redirect, err := url.Parse(r.URL.Query().Get("redirect_uri"))
+	if err != nil || redirect.String() == "" {
+		writeError(w, http.StatusBadRequest, "Invalid redirect URI")
+		return
+	}
+
+	query := redirect.Query()
+	query.Set("code", "fake-code")
+	if state := r.URL.Query().Get("state"); state != "" {
+		query.Set("state", state)
+	}
+	redirect.RawQuery = query.Encode()
+
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+// token exchanges any authorization code, or an issued refresh token, for a new token
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid form")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.PostForm.Get("grant_type") {
+	case "authorization_code":
+		if r.PostForm.Get("code") == "" {
+			writeOAuthError(w, "invalid_grant")
+			return
+		}
+	case "refresh_token":
+		refresh := r.PostForm.Get("refresh_token")
+		if !s.refresh[refresh] {
+			writeOAuthError(w, "invalid_grant")
+			return
+		}
+		delete(s.refresh, refresh)
+	default:
+		writeOAuthError(w, "unsupported_grant_type")
+		return
+	}
+
+	access, refresh := s.issue(time.Hour)
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token":  access,
+		"token_type":    "Bearer",
+		"expires_in":    3600,
+		"refresh_token": refresh,
+		"scope":         r.PostForm.Get("scope"),
+	})
+}
+
+func (s *Server) currentUser(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, s.user)
+}
+
+func (s *Server) savedTracks(w http.ResponseWriter, r *http.Request) {
+	limit, offset, ok := pageParams(w, r, 20, 50)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, newPage(r, s.saved, limit, offset))
+}
+
+func (s *Server) saveTracks(w http.ResponseWriter, r *http.Request) {
+	ids, ok := idsParam(w, r, 50)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range ids {
+		if _, exists := s.tracks[id]; !exists {
+			writeError(w, http.StatusBadRequest, "Invalid track ID "+id.String())
+			return
+		}
+	}
+	s.save(s.now(), ids)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) removeSavedTracks(w http.ResponseWriter, r *http.Request) {
+	ids, ok := idsParam(w, r, 50)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	remove := make(map[spotify.ID]bool, len(ids))
+	for _, id := range ids {
+		remove[id] = true
+	}
+	kept := s.saved[:0]
+	for _, saved := range s.saved {
+		if !remove[saved.ID] {
+			kept = append(kept, saved)
+		}
+	}
+	s.saved = kept
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) userPlaylists(w http.ResponseWriter, r *http.Request) {
+	limit, offset, ok := pageParams(w, r, 20, 50)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	playlists := make([]spotify.SimplePlaylist, len(s.order))
+	for i, id := range s.order {
+		playlists[i] = s.playlists[id].simple()
+	}
+	writeJSON(w, http.StatusOK, newPage(r, playlists, limit, offset))
+}
+
+func (s *Server) getPlaylist(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.playlist(w, r)
+	if !ok {
+		return
+	}
+
+	// The first page of items comes inline, like on Spotify
+	writeJSON(w, http.StatusOK, struct {
+		spotify.SimplePlaylist
+		Description string                              `json:"description"`
+		Followers   spotify.Followers                   `json:"followers"`
+		Tracks      pageResponse[spotify.PlaylistTrack] `json:"tracks"`
+	}{
+		SimplePlaylist: p.simple(),
+		Description:    p.description,
+		Tracks:         newPage(r, p.items, 100, 0),
+	})
+}
+
+func (s *Server) updatePlaylist(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Name        *string `json:"name"`
+		Description *string `json:"description"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.playlist(w, r)
+	if !ok {
+		return
+	}
+	if body.Name != nil {
+		p.Name = *body.Name
+	}
+	if body.Description != nil {
+		p.description = *body.Description
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) playlistTracks(w http.ResponseWriter, r *http.Request) {
+	limit, offset, ok := pageParams(w, r, 100, 100)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.playlist(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, newPage(r, p.items, limit, offset))
+}
+
+func (s *Server) addPlaylistTracks(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		URIs     []string `json:"uris"`
+		Position *int     `json:"position"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.playlist(w, r)
+	if !ok {
+		return
+	}
+	items, ok := s.playlistItems(w, body.URIs)
+	if !ok {
+		return
+	}
+
+	position := len(p.items)
+	if body.Position != nil {
+		position = *body.Position
+	}
+	if position < 0 || position > len(p.items) {
+		writeError(w, http.StatusBadRequest, "Index out of bounds")
+		return
+	}
+
+	p.items = append(p.items[:position], append(items, p.items[position:]...)...)
+	s.writeSnapshot(w, p, http.StatusCreated)
+}
+
+func (s *Server) replacePlaylistTracks(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		URIs []string `json:"uris"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.playlist(w, r)
+	if !ok {
+		return
+	}
+	items, ok := s.playlistItems(w, body.URIs)
+	if !ok {
+		return
+	}
+
+	p.items = items
+	s.writeSnapshot(w, p, http.StatusOK)
+}
+
+// removePlaylistTracks removes every occurrence of the given tracks, or only those at
+// the given positions, which must then hold the track. Positions are relative to the
+// snapshot_id when given; the fake cannot replay later edits onto an older snapshot, so
+// it rejects any snapshot other than the current one.
+func (s *Server) removePlaylistTracks(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Tracks     []spotify.TrackToRemove `json:"tracks"`
+		SnapshotID string                  `json:"snapshot_id"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	if len(body.Tracks) == 0 || len(body.Tracks) > 100 {
+		writeError(w, http.StatusBadRequest, "Between 1 and 100 tracks must be given")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.playlist(w, r)
+	if !ok {
+		return
+	}
+	if body.SnapshotID != "" && body.SnapshotID != p.snapshotID() {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid snapshot id %s, the playlist is at %s", body.SnapshotID, p.snapshotID()))
+		return
+	}
+
+	remove := make(map[int]bool)
+	for _, track := range body.Tracks {
+		if track.Positions == nil {
+			for i, item := range p.items {
+				if string(item.Track.URI) == track.URI {
+					remove[i] = true
+				}
+			}
+			continue
+		}
+		for _, position := range track.Positions {
+			if position < 0 || position >= len(p.items) || string(p.items[position].Track.URI) != track.URI {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("Could not remove tracks, please check parameters: no %s at position %d", track.URI, position))
+				return
+			}
+			remove[position] = true
+		}
+	}
+
+	kept := make([]spotify.PlaylistTrack, 0, len(p.items))
+	for i, item := range p.items {
+		if !remove[i] {
+			kept = append(kept, item)
+		}
+	}
+	p.items = kept
+	s.writeSnapshot(w, p, http.StatusOK)
+}
+
+func (s *Server) getArtists(w http.ResponseWriter, r *http.Request) {
+	ids, ok := idsParam(w, r, 50)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Unknown IDs are answered with null, like Spotify does
+	artists := make([]*spotify.FullArtist, len(ids))
+	for i, id := range ids {
+		if artist, exists := s.artists[id]; exists {
+			artists[i] = &artist
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"artists": artists})
+}
+
+func (s *Server) getArtist(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	artist, ok := s.artists[spotify.ID(r.PathValue("id"))]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Non existing id")
+		return
+	}
+	writeJSON(w, http.StatusOK, artist)
+}
+
+func (s *Server) getTracks(w http.ResponseWriter, r *http.Request) {
+	ids, ok := idsParam(w, r, 50)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tracks := make([]*spotify.FullTrack, len(ids))
+	for i, id := range ids {
+		if track, exists := s.tracks[id]; exists {
+			tracks[i] = &track
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"tracks": tracks})
+}
+
+func (s *Server) getTrack(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	track, ok := s.tracks[spotify.ID(r.PathValue("id"))]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Non existing id")
+		return
+	}
+	writeJSON(w, http.StatusOK, track)
+}
+
+// playlist returns the playlist of the request path, answering 404 if unknown; s.mu must be held
+func (s *Server) playlist(w http.ResponseWriter, r *http.Request) (*playlist, bool) {
+	p, ok := s.playlists[spotify.ID(r.PathValue("id"))]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not found.")
+	}
+	return p, ok
+}
+
+// playlistItems resolves track URIs to new playlist items; s.mu must be held
+func (s *Server) playlistItems(w http.ResponseWriter, uris []string) ([]spotify.PlaylistTrack, bool) {
+	if len(uris) > 100 {
+		writeError(w, http.StatusBadRequest, "You can add a maximum of 100 tracks per request.")
+		return nil, false
+	}
+
+	addedAt := s.now().UTC().Format(time.RFC3339)
+	items := make([]spotify.PlaylistTrack, 0, len(uris))
+	for _, uri := range uris {
+		track, exists := s.tracks[spotify.ID(strings.TrimPrefix(uri, "spotify:track:"))]
+		if !exists {
+			writeError(w, http.StatusBadRequest, "Invalid track uri: "+uri)
+			return nil, false
+		}
+		items = append(items, spotify.PlaylistTrack{AddedAt: addedAt, AddedBy: s.user.User, Track: track})
+	}
+	return items, true
+}
+
+// writeSnapshot moves a playlist to a new snapshot and returns its ID; s.mu must be held
+func (s *Server) writeSnapshot(w http.ResponseWriter, p *playlist, status int) {
+	p.snapshot++
+	writeJSON(w, status, map[string]string{"snapshot_id": p.snapshotID()})
+}
+
+func (p *playlist) snapshotID() string {
+	return fmt.Sprintf("%s-snapshot-%d", p.ID, p.snapshot)
+}
+
+// simple returns the playlist as listed by /me/playlists
+func (p *playlist) simple() spotify.SimplePlaylist {
+	simple := p.SimplePlaylist
+	simple.SnapshotID = p.snapshotID()
+	simple.Tracks = spotify.PlaylistTracks{
+		Endpoint: "/v1/playlists/" + p.ID.String() + "/tracks",
+		Total:    uint(len(p.items)),
+	}
+	return simple
+}
+
+// pageParams parses limit and offset, answering 400 when out of Spotify's bounds
+func pageParams(w http.ResponseWriter, r *http.Request, defaultLimit, maxLimit int) (int, int, bool) {
+	limit, offset := defaultLimit, 0
+
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxLimit {
+			writeError(w, http.StatusBadRequest, "Invalid limit")
+			return 0, 0, false
+		}
+		limit = parsed
+	}
+	if value := r.URL.Query().Get("offset"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			writeError(w, http.StatusBadRequest, "Invalid offset")
+			return 0, 0, false
+		}
+		offset = parsed
+	}
+
+	return limit, offset, true
+}
+
+// idsParam parses the comma-separated ids parameter, answering 400 when there are
+// none or more than max
+func idsParam(w http.ResponseWriter, r *http.Request, max int) ([]spotify.ID, bool) {
+	value := r.URL.Query().Get("ids")
+	if value == "" {
+		writeError(w, http.StatusBadRequest, "Missing required field: ids")
+		return nil, false
+	}
+
+	parts := strings.Split(value, ",")
+	if len(parts) > max {
+		writeError(w, http.StatusBadRequest, "Too many ids requested")
+		return nil, false
+	}
+
+	ids := make([]spotify.ID, len(parts))
+	for i, part := range parts {
+		ids[i] = spotify.ID(part)
+	}
+	return ids, true
+}
+
+// pageResponse is a Web API paging object
+type pageResponse[T any] struct {
+	Endpoint string `json:"href"`
+	Items    []T    `json:"items"`
+	Limit    int    `json:"limit"`
+	Offset   int    `json:"offset"`
+	Total    int    `json:"total"`
+	Next     string `json:"next"`
+	Previous string `json:"previous"`
+}
+
+// newPage returns the page of items at offset, with its links
+func newPage[T any](r *http.Request, items []T, limit, offset int) pageResponse[T] {
+	p := pageResponse[T]{
+		Items:  window(items, limit, offset),
+		Limit:  limit,
+		Offset: offset,
+		Total:  len(items),
+	}
+
+	link := func(offset int) string {
+		query := r.URL.Query()
+		query.Set("limit", strconv.Itoa(p.Limit))
+		query.Set("offset", strconv.Itoa(offset))
+		return "http://" + r.Host + r.URL.Path + "?" + query.Encode()
+	}
+
+	p.Endpoint = link(p.Offset)
+	if p.Offset+p.Limit < p.Total {
+		p.Next = link(p.Offset + p.Limit)
+	}
+	if p.Offset > 0 {
+		p.Previous = link(max(p.Offset-p.Limit, 0))
+	}
+	return p
+}
+
+// window returns a copy of the items of a page
+func window[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return []T{}
+	}
+	end := min(offset+limit, len(items))
+	return append([]T(nil), items[offset:end]...)
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, target any) bool {
+	if err := json.NewDecoder(r.Body).Decode(target); err != nil {
+		writeError(w, http.StatusBadRequest, "Error parsing JSON.")
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// writeError writes a Web API error object
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]any{
+		"error": map[string]any{"status": status, "message": message},
+	})
+}
+
+// writeOAuthError writes an accounts service error, which uses the OAuth 2.0 format
+func writeOAuthError(w http.ResponseWriter, code string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": code})
+}
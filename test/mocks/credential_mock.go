@@ -0,0 +1,36 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/RubenPari/clear-songs/internal/domain/auth"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCredentialRepository is a mock implementation of auth.CredentialRepository
+type MockCredentialRepository struct {
+	mock.Mock
+}
+
+func (m *MockCredentialRepository) Save(ctx context.Context, credential *auth.SpotifyCredential) error {
+	args := m.Called(ctx, credential)
+	return args.Error(0)
+}
+
+func (m *MockCredentialRepository) Get(ctx context.Context, owner string) (*auth.SpotifyCredential, error) {
+	args := m.Called(ctx, owner)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth.SpotifyCredential), args.Error(1)
+}
+
+func (m *MockCredentialRepository) ListOwners(ctx context.Context) ([]string, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+var _ auth.CredentialRepository = (*MockCredentialRepository)(nil)
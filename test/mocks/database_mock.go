@@ -0,0 +1,39 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/RubenPari/clear-songs/internal/domain/backup"
+	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockDatabaseRepository is a mock implementation of DatabaseRepository
+type MockDatabaseRepository struct {
+	mock.Mock
+}
+
+func (m *MockDatabaseRepository) SaveTracksBackup(ctx context.Context, tracks []backup.TrackBackup) error {
+	args := m.Called(ctx, tracks)
+	return args.Error(0)
+}
+
+func (m *MockDatabaseRepository) GetTracksBackup(ctx context.Context, filter backup.Filter) ([]backup.TrackBackup, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]backup.TrackBackup), args.Error(1)
+}
+
+func (m *MockDatabaseRepository) MarkTracksRestored(ctx context.Context, owner string, backupIDs []string) error {
+	args := m.Called(ctx, owner, backupIDs)
+	return args.Error(0)
+}
+
+func (m *MockDatabaseRepository) ReassignTracksBackup(ctx context.Context, from, to string) (int, error) {
+	args := m.Called(ctx, from, to)
+	return args.Int(0), args.Error(1)
+}
+
+var _ shared.DatabaseRepository = (*MockDatabaseRepository)(nil)
@@ -0,0 +1,57 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/RubenPari/clear-songs/internal/domain/operation"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockOperationRepository is a mock implementation of operation.Repository
+type MockOperationRepository struct {
+	mock.Mock
+}
+
+func (m *MockOperationRepository) Create(ctx context.Context, op *operation.Operation) error {
+	args := m.Called(ctx, op)
+	return args.Error(0)
+}
+
+func (m *MockOperationRepository) GetByID(ctx context.Context, id string) (*operation.Operation, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*operation.Operation), args.Error(1)
+}
+
+func (m *MockOperationRepository) ListByOwner(ctx context.Context, owner string, limit int) ([]operation.Operation, error) {
+	args := m.Called(ctx, owner, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]operation.Operation), args.Error(1)
+}
+
+func (m *MockOperationRepository) ClaimUndo(ctx context.Context, id string, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (m *MockOperationRepository) ReleaseUndo(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockOperationRepository) MarkUndone(ctx context.Context, id string, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (m *MockOperationRepository) MarkPlaylistTracksRestored(ctx context.Context, id string, count int) error {
+	args := m.Called(ctx, id, count)
+	return args.Error(0)
+}
+
+var _ operation.Repository = (*MockOperationRepository)(nil)
@@ -0,0 +1,60 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/RubenPari/clear-songs/internal/domain/snapshot"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPlaylistSnapshotRepository is a mock implementation of snapshot.PlaylistRepository
+type MockPlaylistSnapshotRepository struct {
+	mock.Mock
+}
+
+func (m *MockPlaylistSnapshotRepository) Create(ctx context.Context, s *snapshot.PlaylistSnapshot) error {
+	args := m.Called(ctx, s)
+	return args.Error(0)
+}
+
+func (m *MockPlaylistSnapshotRepository) GetByID(ctx context.Context, id string) (*snapshot.PlaylistSnapshot, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*snapshot.PlaylistSnapshot), args.Error(1)
+}
+
+func (m *MockPlaylistSnapshotRepository) ListByPlaylist(ctx context.Context, owner, playlistID string, limit int) ([]snapshot.PlaylistSnapshot, error) {
+	args := m.Called(ctx, owner, playlistID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]snapshot.PlaylistSnapshot), args.Error(1)
+}
+
+// MockLibrarySnapshotRepository is a mock implementation of snapshot.LibraryRepository
+type MockLibrarySnapshotRepository struct {
+	mock.Mock
+}
+
+func (m *MockLibrarySnapshotRepository) Create(ctx context.Context, s *snapshot.LibrarySnapshot) error {
+	args := m.Called(ctx, s)
+	return args.Error(0)
+}
+
+func (m *MockLibrarySnapshotRepository) GetByID(ctx context.Context, id string) (*snapshot.LibrarySnapshot, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*snapshot.LibrarySnapshot), args.Error(1)
+}
+
+func (m *MockLibrarySnapshotRepository) ListByOwner(ctx context.Context, owner string, limit int) ([]snapshot.LibrarySnapshot, error) {
+	args := m.Called(ctx, owner, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]snapshot.LibrarySnapshot), args.Error(1)
+}
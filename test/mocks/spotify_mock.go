@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/RubenPari/clear-songs/internal/domain/shared"
+	"github.com/RubenPari/clear-songs/internal/domain/track"
 	"github.com/stretchr/testify/mock"
 	spotifyAPI "github.com/zmb3/spotify"
 	"golang.org/x/oauth2"
@@ -39,6 +40,14 @@ func (m *MockSpotifyRepository) GetArtist(ctx context.Context, id spotifyAPI.ID)
 	return args.Get(0).(*spotifyAPI.FullArtist), args.Error(1)
 }
 
+func (m *MockSpotifyRepository) GetArtists(ctx context.Context, ids []spotifyAPI.ID) ([]spotifyAPI.FullArtist, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]spotifyAPI.FullArtist), args.Error(1)
+}
+
 func (m *MockSpotifyRepository) GetTrack(ctx context.Context, id spotifyAPI.ID) (*spotifyAPI.FullTrack, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -47,8 +56,8 @@ func (m *MockSpotifyRepository) GetTrack(ctx context.Context, id spotifyAPI.ID)
 	return args.Get(0).(*spotifyAPI.FullTrack), args.Error(1)
 }
 
-func (m *MockSpotifyRepository) GetTrackIDsByArtist(ctx context.Context, id spotifyAPI.ID, tracks []spotifyAPI.SavedTrack) ([]spotifyAPI.ID, error) {
-	args := m.Called(ctx, id, tracks)
+func (m *MockSpotifyRepository) GetTrackIDsByArtist(ctx context.Context, filter track.ArtistFilter, tracks []spotifyAPI.SavedTrack) ([]spotifyAPI.ID, error) {
+	args := m.Called(ctx, filter, tracks)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -60,6 +69,11 @@ func (m *MockSpotifyRepository) DeleteTracksFromLibrary(ctx context.Context, ids
 	return args.Error(0)
 }
 
+func (m *MockSpotifyRepository) AddTracksToLibrary(ctx context.Context, ids []spotifyAPI.ID) error {
+	args := m.Called(ctx, ids)
+	return args.Error(0)
+}
+
 func (m *MockSpotifyRepository) GetAllUserPlaylists(ctx context.Context) ([]spotifyAPI.SimplePlaylist, error) {
 	args := m.Called(ctx)
 	if args.Get(0) == nil {
@@ -68,31 +82,117 @@ func (m *MockSpotifyRepository) GetAllUserPlaylists(ctx context.Context) ([]spot
 	return args.Get(0).([]spotifyAPI.SimplePlaylist), args.Error(1)
 }
 
-// Minimal behavior for remaining methods
-func (m *MockSpotifyRepository) GetUserTracks(ctx context.Context, limit, offset int) ([]spotifyAPI.SavedTrack, error) {
-	return nil, nil
-}
-func (m *MockSpotifyRepository) GetTracksByArtist(ctx context.Context, id spotifyAPI.ID, tracks []spotifyAPI.SavedTrack) ([]spotifyAPI.SavedTrack, error) {
-	return nil, nil
+func (m *MockSpotifyRepository) GetTracksByArtist(ctx context.Context, filter track.ArtistFilter, tracks []spotifyAPI.SavedTrack) ([]spotifyAPI.SavedTrack, error) {
+	args := m.Called(ctx, filter, tracks)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]spotifyAPI.SavedTrack), args.Error(1)
 }
+
 func (m *MockSpotifyRepository) GetPlaylist(ctx context.Context, id spotifyAPI.ID) (*spotifyAPI.FullPlaylist, error) {
-	return nil, nil
-}
-func (m *MockSpotifyRepository) GetPlaylistTracks(ctx context.Context, id spotifyAPI.ID, limit, offset int) ([]spotifyAPI.PlaylistTrack, error) {
-	return nil, nil
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*spotifyAPI.FullPlaylist), args.Error(1)
 }
+
 func (m *MockSpotifyRepository) GetAllPlaylistTracks(ctx context.Context, id spotifyAPI.ID) ([]spotifyAPI.PlaylistTrack, error) {
-	return nil, nil
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]spotifyAPI.PlaylistTrack), args.Error(1)
 }
+
 func (m *MockSpotifyRepository) DeletePlaylistTracks(ctx context.Context, id spotifyAPI.ID, ids []spotifyAPI.ID) error {
-	return nil
+	args := m.Called(ctx, id, ids)
+	return args.Error(0)
+}
+
+func (m *MockSpotifyRepository) DeletePlaylistTracksAt(ctx context.Context, id spotifyAPI.ID, snapshotID string, tracks []spotifyAPI.TrackToRemove) (string, error) {
+	args := m.Called(ctx, id, snapshotID, tracks)
+	return args.String(0), args.Error(1)
 }
+
+func (m *MockSpotifyRepository) GetAllUserAlbums(ctx context.Context) ([]spotifyAPI.SavedAlbum, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]spotifyAPI.SavedAlbum), args.Error(1)
+}
+
+func (m *MockSpotifyRepository) GetAlbum(ctx context.Context, id spotifyAPI.ID) (*spotifyAPI.FullAlbum, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*spotifyAPI.FullAlbum), args.Error(1)
+}
+
+func (m *MockSpotifyRepository) GetAlbumTracks(ctx context.Context, id spotifyAPI.ID) ([]spotifyAPI.SimpleTrack, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]spotifyAPI.SimpleTrack), args.Error(1)
+}
+
+func (m *MockSpotifyRepository) RemoveAlbumsFromLibrary(ctx context.Context, ids []spotifyAPI.ID) error {
+	args := m.Called(ctx, ids)
+	return args.Error(0)
+}
+
+func (m *MockSpotifyRepository) SaveAlbumsToLibrary(ctx context.Context, ids []spotifyAPI.ID) error {
+	args := m.Called(ctx, ids)
+	return args.Error(0)
+}
+
+func (m *MockSpotifyRepository) AddTracksToPlaylist(ctx context.Context, id spotifyAPI.ID, ids []spotifyAPI.ID, position int) error {
+	args := m.Called(ctx, id, ids, position)
+	return args.Error(0)
+}
+
+func (m *MockSpotifyRepository) ReplacePlaylistTracks(ctx context.Context, id spotifyAPI.ID, ids []spotifyAPI.ID) error {
+	args := m.Called(ctx, id, ids)
+	return args.Error(0)
+}
+
+func (m *MockSpotifyRepository) UpdatePlaylistDetails(ctx context.Context, id spotifyAPI.ID, name, description string) error {
+	args := m.Called(ctx, id, name, description)
+	return args.Error(0)
+}
+
+func (m *MockSpotifyRepository) GetUserTracks(ctx context.Context, limit, offset int) ([]spotifyAPI.SavedTrack, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]spotifyAPI.SavedTrack), args.Error(1)
+}
+
+func (m *MockSpotifyRepository) GetPlaylistTracks(ctx context.Context, id spotifyAPI.ID, limit, offset int) ([]spotifyAPI.PlaylistTrack, error) {
+	args := m.Called(ctx, id, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]spotifyAPI.PlaylistTrack), args.Error(1)
+}
+
+func (m *MockSpotifyRepository) SearchTracks(ctx context.Context, query string, limit int) ([]spotifyAPI.FullTrack, error) {
+	args := m.Called(ctx, query, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]spotifyAPI.FullTrack), args.Error(1)
+}
+
+// Minimal behavior for remaining methods
 func (m *MockSpotifyRepository) GetUserPlaylists(ctx context.Context, limit, offset int) ([]spotifyAPI.SimplePlaylist, error) {
 	return nil, nil
 }
-func (m *MockSpotifyRepository) SetAccessToken(token interface{}) error {
-	return nil
-}
 
 // MockCacheRepository is a mock implementation of CacheRepository
 type MockCacheRepository struct {
@@ -128,9 +228,13 @@ func (m *MockCacheRepository) InvalidateUserTracks(ctx context.Context) error {
 }
 
 // Minimal behavior for remaining methods
-func (m *MockCacheRepository) SetToken(ctx context.Context, token *oauth2.Token) error { return nil }
-func (m *MockCacheRepository) GetToken(ctx context.Context) (*oauth2.Token, error)     { return nil, nil }
-func (m *MockCacheRepository) ClearToken(ctx context.Context) error                    { return nil }
+func (m *MockCacheRepository) SetToken(ctx context.Context, sessionID string, token *oauth2.Token) error {
+	return nil
+}
+func (m *MockCacheRepository) GetToken(ctx context.Context, sessionID string) (*oauth2.Token, error) {
+	return nil, nil
+}
+func (m *MockCacheRepository) ClearToken(ctx context.Context, sessionID string) error { return nil }
 func (m *MockCacheRepository) GetPlaylistTracks(ctx context.Context, id spotifyAPI.ID) ([]spotifyAPI.PlaylistTrack, error) {
 	return nil, nil
 }
@@ -140,6 +244,19 @@ func (m *MockCacheRepository) SetPlaylistTracks(ctx context.Context, id spotifyA
 func (m *MockCacheRepository) InvalidatePlaylistTracks(ctx context.Context, id spotifyAPI.ID) error {
 	return nil
 }
+func (m *MockCacheRepository) GetArtists(ctx context.Context, ids []spotifyAPI.ID) (map[spotifyAPI.ID]spotifyAPI.FullArtist, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[spotifyAPI.ID]spotifyAPI.FullArtist), args.Error(1)
+}
+
+func (m *MockCacheRepository) SetArtists(ctx context.Context, artists []spotifyAPI.FullArtist, ttl time.Duration) error {
+	args := m.Called(ctx, artists, ttl)
+	return args.Error(0)
+}
+
 func (m *MockCacheRepository) Delete(ctx context.Context, key string) error { return nil }
 
 var _ shared.SpotifyRepository = (*MockSpotifyRepository)(nil)